@@ -47,3 +47,16 @@ func (w *asyncProcessor) run() {
 func (w *asyncProcessor) push(cb func()) bool {
 	return w.buffer.Push(cb)
 }
+
+// flush returns a channel that is closed once all the callbacks
+// queued before the call have been processed.
+func (w *asyncProcessor) flush() chan struct{} {
+	done := make(chan struct{})
+	ok := w.push(func() {
+		close(done)
+	})
+	if !ok {
+		close(done)
+	}
+	return done
+}
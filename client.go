@@ -8,6 +8,7 @@ package gortsplib
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -31,24 +32,12 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/sdp"
 )
 
-// convert an URL into an address, in particular:
-// * add default port
-// * handle IPv6 with or without square brackets.
-// Adapted from net/http:
-// https://cs.opensource.google/go/go/+/refs/tags/go1.20.5:src/net/http/transport.go;l=2747
-func canonicalAddr(u *base.URL) string {
-	addr := u.Hostname()
-
-	port := u.Port()
-	if port == "" {
-		if u.Scheme == "rtsp" {
-			port = "554"
-		} else { // rtsps
-			port = "322"
-		}
-	}
-
-	return net.JoinHostPort(addr, port)
+// avoid an int64 overflow and preserve resolution by splitting division into two parts:
+// first add the integer part, then the decimal part.
+func multiplyAndDivide(v, m, d int64) int64 {
+	secs := v / d
+	dec := v % d
+	return (secs*m + dec*m/d)
 }
 
 func isAnyPort(p int) bool {
@@ -110,6 +99,35 @@ func supportsGetParameter(header base.Header) bool {
 	return false
 }
 
+// maximum number of times a request is sent again after
+// the server reports that the nonce is stale.
+const clientMaxAuthStaleRetries = 3
+
+// nextDigestURIForm returns the first form that follows cur
+// and produces an uri that differs from the one of the request.
+func nextDigestURIForm(cur auth.DigestURIForm, u *base.URL) (auth.DigestURIForm, bool) {
+	requestURI := auth.DigestURIFormRequest.URI(u)
+
+	for f := cur + 1; f <= auth.DigestURIFormLowercaseHost; f++ {
+		if f.URI(u) != requestURI {
+			return f, true
+		}
+	}
+
+	return 0, false
+}
+
+func authenticateRealm(v base.HeaderValue) string {
+	for _, vi := range v {
+		var h headers.Authenticate
+		err := h.Unmarshal(base.HeaderValue{vi})
+		if err == nil {
+			return h.Realm
+		}
+	}
+	return ""
+}
+
 type clientState int
 
 const (
@@ -160,6 +178,14 @@ type setupReq struct {
 	res      chan clientRes
 }
 
+type setupAllReq struct {
+	baseURL   *base.URL
+	medias    []*description.Media
+	transport Transport
+	retry     bool
+	res       chan clientRes
+}
+
 type playReq struct {
 	ra  *headers.Range
 	res chan clientRes
@@ -173,8 +199,13 @@ type pauseReq struct {
 	res chan clientRes
 }
 
+type pingReq struct {
+	res chan clientRes
+}
+
 type clientRes struct {
 	sd  *description.Session // describe only
+	rtt time.Duration        // ping only
 	res *base.Response
 	err error
 }
@@ -188,18 +219,45 @@ type ClientOnResponseFunc func(*base.Response)
 // ClientOnTransportSwitchFunc is the prototype of Client.OnTransportSwitch.
 type ClientOnTransportSwitchFunc func(err error)
 
+// ClientOnDigestURIQuirkFunc is the prototype of Client.OnDigestURIQuirk.
+type ClientOnDigestURIQuirkFunc func(form auth.DigestURIForm)
+
+// ClientOnBeforePlayFunc is the prototype of Client.OnBeforePlay.
+type ClientOnBeforePlayFunc func(*description.Session)
+
 // ClientOnPacketLostFunc is the prototype of Client.OnPacketLost.
 type ClientOnPacketLostFunc func(err error)
 
 // ClientOnDecodeErrorFunc is the prototype of Client.OnDecodeError.
 type ClientOnDecodeErrorFunc func(err error)
 
+// ClientOnWriteQueueFullFunc is the prototype of Client.OnWriteQueueFull.
+type ClientOnWriteQueueFullFunc func(*description.Media)
+
+// ClientOnWriteErrorFunc is the prototype of Client.OnWriteError.
+type ClientOnWriteErrorFunc func(err error)
+
+// MediaPacket is a RTP packet that belongs to a media.
+type MediaPacket struct {
+	Media  *description.Media
+	Packet *rtp.Packet
+}
+
 // OnPacketRTPFunc is the prototype of the callback passed to OnPacketRTP().
 type OnPacketRTPFunc func(*rtp.Packet)
 
 // OnPacketRTPAnyFunc is the prototype of the callback passed to OnPacketRTP(Any).
 type OnPacketRTPAnyFunc func(*description.Media, format.Format, *rtp.Packet)
 
+// AccessUnitDecoder is a RTP decoder that returns access units,
+// like rtph264.Decoder, rtph265.Decoder and rtpmpeg4audio.Decoder.
+type AccessUnitDecoder interface {
+	Decode(*rtp.Packet) ([][]byte, error)
+}
+
+// OnPacketAccessUnitFunc is the prototype of the callback passed to OnPacketAccessUnit().
+type OnPacketAccessUnitFunc func(pts time.Duration, au [][]byte, err error)
+
 // OnPacketRTCPFunc is the prototype of the callback passed to OnPacketRTCP().
 type OnPacketRTCPFunc func(rtcp.Packet)
 
@@ -217,9 +275,25 @@ type Client struct {
 	// timeout of write operations.
 	// It defaults to 10 seconds.
 	WriteTimeout time.Duration
+	// timeout of dial operations.
+	// It defaults to ReadTimeout.
+	DialTimeout time.Duration
 	// a TLS configuration to connect to TLS (RTSPS) servers.
 	// It defaults to nil.
 	TLSConfig *tls.Config
+	// minimum TLS version.
+	// It overrides the one in TLSConfig when it is greater.
+	// It defaults to tls.VersionTLS12.
+	TLSMinVersion uint16
+	// maximum TLS version.
+	// If not zero, it overrides the one in TLSConfig.
+	// It defaults to zero, that is the maximum version supported by Go.
+	TLSMaxVersion uint16
+	// disable verification of the server certificate.
+	// This is a security issue, but it is needed with self-signed certificates.
+	// If true, it overrides the one in TLSConfig.
+	// It defaults to false.
+	TLSInsecureSkipVerify bool
 	// enable communication with servers which don't provide UDP server ports
 	// or use different server ports than the announced ones.
 	// This can be a security issue.
@@ -229,6 +303,11 @@ type Client struct {
 	// If nil, it is chosen automatically (first UDP, then, if it fails, TCP).
 	// It defaults to nil.
 	Transport *Transport
+	// when Transport is nil, offer both UDP and TCP in the first SETUP request
+	// and let the server choose, instead of switching to TCP after the server
+	// rejects UDP. This saves a round trip with servers that don't support UDP.
+	// It defaults to false.
+	OfferTransportAlternatives bool
 	// If the client is reading with UDP, it must receive
 	// at least a packet within this timeout, otherwise it switches to TCP.
 	// It defaults to 3 seconds.
@@ -245,12 +324,47 @@ type Client struct {
 	UserAgent string
 	// disable automatic RTCP sender reports.
 	DisableRTCPSenderReports bool
+	// disable RTCP entirely.
+	// When using the UDP transport protocol, the RTCP port is not opened
+	// and the RTP port is advertised to the server in its place.
+	DisableRTCP bool
+	// send and receive RTCP packets on the RTP port (RFC 5761).
+	// It can be used only with the UDP transport protocol
+	// and it requires a server that supports it.
+	RTCPOverRTP bool
 	// explicitly request back channels to the server.
 	RequestBackChannels bool
+	// when digest credentials are rejected, send the request again with
+	// alternative forms of the digest uri parameter (trailing slash, default port,
+	// path only, lowercase host), in order to authenticate with servers
+	// that compute the digest response with a different URL.
+	// It defaults to false.
+	DigestURIQuirks bool
 	// pointer to a variable that stores received bytes.
 	BytesReceived *uint64
 	// pointer to a variable that stores sent bytes.
 	BytesSent *uint64
+	// headers added to every outgoing request.
+	// They never override headers that are set by the library,
+	// like CSeq, Session and User-Agent.
+	ExtraHeaders base.Header
+	// function that returns headers to be added to a specific request.
+	// They take precedence over ExtraHeaders
+	// and they never override headers that are set by the library.
+	PerRequestHeaders func(req *base.Request) base.Header
+	// function that decides whether a media is setupped by SetupAll().
+	// Medias for which it returns false are skipped.
+	// It defaults to nil, that means all medias are setupped.
+	MediaFilter func(medi *description.Media) bool
+	// function that decides whether RTP packets of a format are processed when reading.
+	// Packets of formats for which it returns false are silently discarded.
+	// It defaults to nil, that means all formats are processed.
+	FormatFilter func(medi *description.Media, forma format.Format) bool
+	// function that inspects RTP packets when reading, before they are processed
+	// (loss detection, timing, OnPacketRTP callbacks).
+	// Packets for which it returns false are silently discarded.
+	// It defaults to nil, that means all packets are processed.
+	InspectRTPBeforeDecoding func(medi *description.Media, pkt *rtp.Packet) bool
 
 	//
 	// system functions (all optional)
@@ -275,10 +389,22 @@ type Client struct {
 	OnServerResponse ClientOnResponseFunc
 	// called when the transport protocol changes.
 	OnTransportSwitch ClientOnTransportSwitchFunc
+	// called when authentication succeeds with an alternative form
+	// of the digest uri parameter (see DigestURIQuirks).
+	OnDigestURIQuirk ClientOnDigestURIQuirkFunc
+	// called by StartPlayingWithFallback() and SetupAndPlayRobust() after medias
+	// have been set up and before playing. It can be used to set packet callbacks.
+	OnBeforePlay ClientOnBeforePlayFunc
 	// called when the client detects lost packets.
 	OnPacketLost ClientOnPacketLostFunc
 	// called when a non-fatal decode error occurs.
 	OnDecodeError ClientOnDecodeErrorFunc
+	// called by StartRecordingFromChannel() when a packet is discarded
+	// since the write queue is full.
+	OnWriteQueueFull ClientOnWriteQueueFullFunc
+	// called by StartRecordingFromChannel() when a packet can't be written
+	// because of an error different from a full write queue.
+	OnWriteError ClientOnWriteErrorFunc
 
 	//
 	// private
@@ -290,6 +416,7 @@ type Client struct {
 	checkTimeoutPeriod   time.Duration
 
 	connURL              *base.URL
+	activeURL            *base.URL
 	ctx                  context.Context
 	ctxCancel            func()
 	state                clientState
@@ -297,6 +424,10 @@ type Client struct {
 	conn                 *conn.Conn
 	session              string
 	sender               *auth.Sender
+	senderUser           string
+	senderPass           string
+	senderStaleRetries   int
+	senderURIQuirkUsed   bool
 	cseq                 int
 	optionsSent          bool
 	useGetParameter      bool
@@ -319,15 +450,19 @@ type Client struct {
 	timeDecoder          *rtptime.GlobalDecoder
 	timeDecoder2         *rtptime.GlobalDecoder2
 	mustClose            bool
+	channelQueueSize     int
+	channelDone          chan struct{}
 
 	// in
 	chOptions      chan optionsReq
 	chDescribe     chan describeReq
 	chAnnounce     chan announceReq
 	chSetup        chan setupReq
+	chSetupAll     chan setupAllReq
 	chPlay         chan playReq
 	chRecord       chan recordReq
 	chPause        chan pauseReq
+	chPing         chan pingReq
 	chReadError    chan error
 	chReadResponse chan *base.Response
 	chReadRequest  chan *base.Request
@@ -345,6 +480,15 @@ func (c *Client) Start(scheme string, host string) error {
 	if c.WriteTimeout == 0 {
 		c.WriteTimeout = 10 * time.Second
 	}
+	if c.DialTimeout == 0 {
+		c.DialTimeout = c.ReadTimeout
+	}
+	if c.TLSMinVersion == 0 {
+		c.TLSMinVersion = tls.VersionTLS12
+	}
+	if c.TLSMaxVersion != 0 && c.TLSMaxVersion < c.TLSMinVersion {
+		return fmt.Errorf("TLSMaxVersion must be greater or equal than TLSMinVersion")
+	}
 	if c.InitialUDPReadTimeout == 0 {
 		c.InitialUDPReadTimeout = 3 * time.Second
 	}
@@ -358,6 +502,9 @@ func (c *Client) Start(scheme string, host string) error {
 	} else if c.MaxPacketSize > udpMaxPayloadSize {
 		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
 	}
+	if c.DisableRTCP && c.RTCPOverRTP {
+		return fmt.Errorf("DisableRTCP and RTCPOverRTP can't be used together")
+	}
 	if c.UserAgent == "" {
 		c.UserAgent = "gortsplib"
 	}
@@ -398,6 +545,15 @@ func (c *Client) Start(scheme string, host string) error {
 			log.Println(err.Error())
 		}
 	}
+	if c.OnDigestURIQuirk == nil {
+		c.OnDigestURIQuirk = func(form auth.DigestURIForm) {
+			log.Println("digest authentication succeeded with uri form: " + form.String())
+		}
+	}
+	if c.OnBeforePlay == nil {
+		c.OnBeforePlay = func(*description.Session) {
+		}
+	}
 	if c.OnPacketLost == nil {
 		c.OnPacketLost = func(err error) {
 			log.Println(err.Error())
@@ -408,6 +564,16 @@ func (c *Client) Start(scheme string, host string) error {
 			log.Println(err.Error())
 		}
 	}
+	if c.OnWriteQueueFull == nil {
+		c.OnWriteQueueFull = func(*description.Media) {
+			log.Println(liberrors.ErrClientWriteQueueFull{}.Error())
+		}
+	}
+	if c.OnWriteError == nil {
+		c.OnWriteError = func(err error) {
+			log.Println(err.Error())
+		}
+	}
 
 	// private
 	if c.timeNow == nil {
@@ -439,9 +605,11 @@ func (c *Client) Start(scheme string, host string) error {
 	c.chDescribe = make(chan describeReq)
 	c.chAnnounce = make(chan announceReq)
 	c.chSetup = make(chan setupReq)
+	c.chSetupAll = make(chan setupAllReq)
 	c.chPlay = make(chan playReq)
 	c.chRecord = make(chan recordReq)
 	c.chPause = make(chan pauseReq)
+	c.chPing = make(chan pingReq)
 	c.chReadError = make(chan error)
 	c.chReadResponse = make(chan *base.Response)
 	c.chReadRequest = make(chan *base.Request)
@@ -470,7 +638,7 @@ func (c *Client) StartRecording(address string, desc *description.Session) error
 		return err
 	}
 
-	err = c.SetupAll(u, desc.Medias)
+	err = c.setupAll(u, desc.Medias)
 	if err != nil {
 		c.Close()
 		return err
@@ -485,6 +653,199 @@ func (c *Client) StartRecording(address string, desc *description.Session) error
 	return nil
 }
 
+// StartRecordingMulti connects to the address and starts publishing the medias
+// of multiple sessions, that are merged into a single one and announced together.
+// Setup errors are returned as ErrClientSessionMediaFailed.
+func (c *Client) StartRecordingMulti(address string, descs []*description.Session) error {
+	merged, err := mergeSessions(descs)
+	if err != nil {
+		return err
+	}
+
+	u, err := base.ParseURL(address)
+	if err != nil {
+		return err
+	}
+
+	err = c.Start(u.Scheme, u.Host)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Announce(u, merged)
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	for i, desc := range descs {
+		for j, medi := range desc.Medias {
+			_, err = c.Setup(u, medi, 0, 0)
+			if err != nil {
+				c.Close()
+				return liberrors.ErrClientSessionMediaFailed{Session: i, Media: j, Err: err}
+			}
+		}
+	}
+
+	_, err = c.Record()
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	return nil
+}
+
+// StartRecordingFromChannel connects to the address and starts publishing given media.
+// Packets are read from ch and written to the server in a dedicated routine.
+// When ch is closed, the session is paused.
+// queueSize is used in place of WriteQueueSize when it is not zero.
+// Packets that can't be written since the write queue is full are
+// reported with OnWriteQueueFull, other write errors with OnWriteError.
+// ch is not read anymore after the client is closed.
+func (c *Client) StartRecordingFromChannel(
+	address string,
+	desc *description.Session,
+	ch <-chan *MediaPacket,
+	queueSize int,
+) error {
+	if (queueSize & (queueSize - 1)) != 0 {
+		return fmt.Errorf("queueSize must be a power of two")
+	}
+	c.channelQueueSize = queueSize
+
+	err := c.StartRecording(address, desc)
+	if err != nil {
+		return err
+	}
+
+	c.channelDone = make(chan struct{})
+	go c.runRecordingFromChannel(ch)
+
+	return nil
+}
+
+func (c *Client) runRecordingFromChannel(ch <-chan *MediaPacket) {
+	defer close(c.channelDone)
+
+	for {
+		select {
+		case mp, ok := <-ch:
+			if !ok {
+				c.Pause() //nolint:errcheck
+				return
+			}
+
+			err := c.WritePacketRTP(mp.Media, mp.Packet)
+			if err != nil {
+				var queueFull liberrors.ErrClientWriteQueueFull
+				if errors.As(err, &queueFull) {
+					c.OnWriteQueueFull(mp.Media)
+				} else {
+					c.OnWriteError(err)
+				}
+			}
+
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func mergeSessions(descs []*description.Session) (*description.Session, error) {
+	if len(descs) == 0 {
+		return nil, fmt.Errorf("no sessions provided")
+	}
+
+	merged := &description.Session{
+		Title: descs[0].Title,
+	}
+	added := make(map[*description.Media]struct{})
+
+	for i, desc := range descs {
+		if desc == nil {
+			return nil, fmt.Errorf("session %d is nil", i)
+		}
+
+		for j, medi := range desc.Medias {
+			if _, ok := added[medi]; ok {
+				return nil, fmt.Errorf("session %d, media %d: media is already part of another session", i, j)
+			}
+			added[medi] = struct{}{}
+
+			merged.Medias = append(merged.Medias, medi)
+		}
+
+		merged.FECGroups = append(merged.FECGroups, desc.FECGroups...)
+	}
+
+	return merged, nil
+}
+
+// StartPlayingWithFallback connects to the first available URL among the given ones
+// and starts reading all its medias.
+// URLs are tried in order. If all of them fail, an error listing all failures is returned.
+// Packet callbacks can be set inside OnBeforePlay.
+func (c *Client) StartPlayingWithFallback(urls []*base.URL) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("no URLs provided")
+	}
+
+	errs := make([]error, len(urls))
+
+	for i, u := range urls {
+		errs[i] = c.startPlaying(u)
+		if errs[i] == nil {
+			c.activeURL = u
+			return nil
+		}
+	}
+
+	return liberrors.ErrClientAllURLsFailed{URLs: urls, Errs: errs}
+}
+
+func (c *Client) startPlaying(u *base.URL) error {
+	// allow the client to be started again after a failed attempt
+	c.resetState()
+	c.lastDescribeURL = nil
+	c.lastRange = nil
+	c.mustClose = false
+	c.closeError = nil
+
+	err := c.Start(u.Scheme, u.Host)
+	if err != nil {
+		return err
+	}
+
+	desc, _, err := c.Describe(u)
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	err = c.SetupAll(desc.BaseURL, desc.Medias)
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	c.OnBeforePlay(desc)
+
+	_, err = c.Play(nil)
+	if err != nil {
+		c.Close()
+		return err
+	}
+
+	return nil
+}
+
+// ActiveURL returns the URL chosen by StartPlayingWithFallback().
+func (c *Client) ActiveURL() *base.URL {
+	return c.activeURL
+}
+
 // ForceClose cancels context and returns, doesn't wait for close signal
 func (c *Client) ForceClose(){
 	c.ctxCancel()
@@ -494,6 +855,10 @@ func (c *Client) ForceClose(){
 func (c *Client) Close() {
 	c.ctxCancel()
 	<-c.done
+
+	if c.channelDone != nil {
+		<-c.channelDone
+	}
 }
 
 // Wait waits until all client resources are closed.
@@ -548,6 +913,14 @@ func (c *Client) runInner() error {
 				return err
 			}
 
+		case req := <-c.chSetupAll:
+			err := c.doSetupAllWithTransport(req.baseURL, req.medias, req.transport, req.retry)
+			req.res <- clientRes{err: err}
+
+			if c.mustClose {
+				return err
+			}
+
 		case req := <-c.chPlay:
 			res, err := c.doPlay(req.ra)
 			req.res <- clientRes{res: res, err: err}
@@ -572,6 +945,14 @@ func (c *Client) runInner() error {
 				return err
 			}
 
+		case req := <-c.chPing:
+			rtt, err := c.doPing()
+			req.res <- clientRes{rtt: rtt, err: err}
+
+			if c.mustClose {
+				return err
+			}
+
 		case <-c.checkTimeoutTimer.C:
 			err := c.doCheckTimeout()
 			if err != nil {
@@ -623,7 +1004,7 @@ func (c *Client) waitResponse(requestCseqStr string) (*base.Response, error) {
 			c.OnResponse(res)
 
 			// accept response if CSeq equals request CSeq, or if CSeq is not present
-			if cseq, ok := res.Header["CSeq"]; !ok || len(cseq) != 1 || strings.TrimSpace(cseq[0]) == requestCseqStr {
+			if cseq := res.Header.GetAll("CSeq"); len(cseq) != 1 || strings.TrimSpace(cseq[0]) == requestCseqStr {
 				return res, nil
 			}
 
@@ -650,7 +1031,7 @@ func (c *Client) handleServerRequest(req *base.Request) error {
 		"User-Agent": base.HeaderValue{c.UserAgent},
 	}
 
-	if cseq, ok := req.Header["CSeq"]; ok {
+	if cseq := req.Header.GetAll("CSeq"); cseq != nil {
 		h["CSeq"] = cseq
 	}
 
@@ -704,10 +1085,15 @@ func (c *Client) doClose() {
 
 func (c *Client) reset() {
 	c.doClose()
+	c.resetState()
+}
 
+func (c *Client) resetState() {
 	c.state = clientStateInitial
 	c.session = ""
 	c.sender = nil
+	c.senderStaleRetries = 0
+	c.senderURIQuirkUsed = false
 	c.cseq = 0
 	c.optionsSent = false
 	c.useGetParameter = false
@@ -719,6 +1105,21 @@ func (c *Client) reset() {
 	c.tcpCallbackByChannel = nil
 }
 
+// addExtraHeaders adds user-provided headers to a request header,
+// without overriding existing headers and headers managed by the client.
+func addExtraHeaders(dest base.Header, extra base.Header) {
+	for k, v := range extra {
+		switch strings.ToLower(k) {
+		case "cseq", "session", "user-agent":
+			continue
+		}
+
+		if _, ok := dest[k]; !ok {
+			dest[k] = v
+		}
+	}
+}
+
 func (c *Client) checkState(allowed map[clientState]struct{}) error {
 	if _, ok := allowed[c.state]; ok {
 		return nil
@@ -796,7 +1197,11 @@ func (c *Client) trySwitchingProtocol2(medi *description.Media, baseURL *base.UR
 func (c *Client) startReadRoutines() {
 	// allocate writer here because it's needed by RTCP receiver / sender
 	if c.state == clientStateRecord || c.backChannelSetupped {
-		c.writer.allocateBuffer(c.WriteQueueSize)
+		if c.channelQueueSize != 0 {
+			c.writer.allocateBuffer(c.channelQueueSize)
+		} else {
+			c.writer.allocateBuffer(c.WriteQueueSize)
+		}
 	} else {
 		// when reading, buffer is only used to send RTCP receiver reports,
 		// that are much smaller than RTP packets and are sent at a fixed interval.
@@ -870,22 +1275,16 @@ func (c *Client) connOpen() error {
 		return liberrors.ErrClientRTSPSTCP{}
 	}
 
-	dialCtx, dialCtxCancel := context.WithTimeout(c.ctx, c.ReadTimeout)
+	dialCtx, dialCtxCancel := context.WithTimeout(c.ctx, c.DialTimeout)
 	defer dialCtxCancel()
 
-	nconn, err := c.DialContext(dialCtx, "tcp", canonicalAddr(c.connURL))
+	nconn, err := c.DialContext(dialCtx, "tcp", c.connURL.HostWithDefaultPort())
 	if err != nil {
 		return err
 	}
 
 	if c.connURL.Scheme == "rtsps" {
-		tlsConfig := c.TLSConfig
-		if tlsConfig == nil {
-			tlsConfig = &tls.Config{}
-		}
-		tlsConfig.ServerName = c.connURL.Hostname()
-
-		nconn = tls.Client(nconn, tlsConfig)
+		nconn = tls.Client(nconn, c.tlsConfig())
 	}
 
 	c.nconn = nconn
@@ -899,6 +1298,27 @@ func (c *Client) connOpen() error {
 	return nil
 }
 
+func (c *Client) tlsConfig() *tls.Config {
+	var tlsConfig *tls.Config
+	if c.TLSConfig != nil {
+		tlsConfig = c.TLSConfig.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+	tlsConfig.ServerName = c.connURL.Hostname()
+	// never lower the minimum version of TLSConfig
+	if tlsConfig.MinVersion < c.TLSMinVersion {
+		tlsConfig.MinVersion = c.TLSMinVersion
+	}
+	if c.TLSMaxVersion != 0 {
+		tlsConfig.MaxVersion = c.TLSMaxVersion
+	}
+	if c.TLSInsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	return tlsConfig
+}
+
 func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error) {
 	if !c.optionsSent && req.Method != base.Options {
 		_, err := c.doOptions(req.URL)
@@ -911,8 +1331,13 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 		req.Header = make(base.Header)
 	}
 
+	if c.PerRequestHeaders != nil {
+		addExtraHeaders(req.Header, c.PerRequestHeaders(req))
+	}
+	addExtraHeaders(req.Header, c.ExtraHeaders)
+
 	if c.session != "" {
-		req.Header["Session"] = base.HeaderValue{c.session}
+		req.Header["Session"] = headers.Session{Session: c.session}.Marshal()
 	}
 
 	c.cseq++
@@ -944,7 +1369,7 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 	}
 
 	// get session from response
-	if v, ok := res.Header["Session"]; ok {
+	if v := res.Header.GetAll("Session"); v != nil {
 		var sx headers.Session
 		err := sx.Unmarshal(v)
 		if err != nil {
@@ -957,18 +1382,71 @@ func (c *Client) do(req *base.Request, skipResponse bool) (*base.Response, error
 		}
 	}
 
-	// send request again with authentication
-	if res.StatusCode == base.StatusUnauthorized && req.URL.User != nil && c.sender == nil {
-		pass, _ := req.URL.User.Password()
-		user := req.URL.User.Username()
+	if res.StatusCode == base.StatusUnauthorized {
+		// send request again with authentication
+		if req.URL.User != nil && c.sender == nil {
+			pass, _ := req.URL.User.Password()
+			user := req.URL.User.Username()
 
-		sender, err := auth.NewSender(res.Header["WWW-Authenticate"], user, pass)
-		if err != nil {
-			return nil, liberrors.ErrClientAuthSetup{Err: err}
+			sender, err := auth.NewSender(res.Header.GetAll("WWW-Authenticate"), user, pass)
+			if err != nil {
+				return nil, liberrors.ErrClientAuthSetup{Err: err}
+			}
+			c.sender = sender
+			c.senderUser = user
+			c.senderPass = pass
+
+			return c.do(req, skipResponse)
+		}
+
+		// nonce has expired, send request again with the new nonce
+		if c.sender != nil && auth.IsStale(res.Header.GetAll("WWW-Authenticate")) &&
+			c.senderStaleRetries < clientMaxAuthStaleRetries {
+			sender, err := auth.NewSender(res.Header.GetAll("WWW-Authenticate"), c.senderUser, c.senderPass)
+			if err != nil {
+				return nil, liberrors.ErrClientAuthSetup{Err: err}
+			}
+			sender.DigestURIForm = c.sender.DigestURIForm
+			c.sender = sender
+			c.senderStaleRetries++
+
+			return c.do(req, skipResponse)
+		}
+
+		// try alternative forms of the digest uri parameter.
+		// use the challenge of the last response, since servers may issue a new nonce
+		// with every 401.
+		if c.sender != nil && c.DigestURIQuirks && c.sender.IsDigest() {
+			if form, ok := nextDigestURIForm(c.sender.DigestURIForm, req.URL); ok {
+				sender, err := auth.NewSender(res.Header.GetAll("WWW-Authenticate"), c.senderUser, c.senderPass)
+				if err != nil {
+					return nil, liberrors.ErrClientAuthSetup{Err: err}
+				}
+				sender.DigestURIForm = form
+				c.sender = sender
+				c.senderURIQuirkUsed = true
+
+				return c.do(req, skipResponse)
+			}
+		}
+
+		// credentials have been rejected
+		if c.sender != nil {
+			c.sender.DigestURIForm = auth.DigestURIFormRequest
+			c.senderStaleRetries = 0
+			c.senderURIQuirkUsed = false
+			return nil, liberrors.ErrClientAuthFailed{
+				StatusCode: res.StatusCode,
+				Realm:      authenticateRealm(res.Header.GetAll("WWW-Authenticate")),
+			}
 		}
-		c.sender = sender
+	}
+
+	c.senderStaleRetries = 0
 
-		return c.do(req, skipResponse)
+	if c.senderURIQuirkUsed {
+		c.senderURIQuirkUsed = false
+		c.OnDigestURIQuirk(c.sender.DigestURIForm)
 	}
 
 	return res, nil
@@ -981,9 +1459,11 @@ func (c *Client) atLeastOneUDPPacketHasBeenReceived() bool {
 			return true
 		}
 
-		lft = atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime)
-		if lft != 0 {
-			return true
+		if ct.udpRTCPListener != nil {
+			lft = atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime)
+			if lft != 0 {
+				return true
+			}
 		}
 	}
 	return false
@@ -997,9 +1477,11 @@ func (c *Client) isInUDPTimeout() bool {
 			return false
 		}
 
-		lft = time.Unix(atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime), 0)
-		if now.Sub(lft) < c.ReadTimeout {
-			return false
+		if ct.udpRTCPListener != nil {
+			lft = time.Unix(atomic.LoadInt64(ct.udpRTCPListener.lastPacketTime), 0)
+			if now.Sub(lft) < c.ReadTimeout {
+				return false
+			}
 		}
 	}
 	return true
@@ -1116,7 +1598,7 @@ func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response,
 	}
 
 	header := base.Header{
-		"Accept": base.HeaderValue{"application/sdp"},
+		"Accept": base.HeaderValue{base.ContentTypeSDP},
 	}
 
 	if c.RequestBackChannels {
@@ -1165,19 +1647,21 @@ func (c *Client) doDescribe(u *base.URL) (*description.Session, *base.Response,
 		return nil, nil, liberrors.ErrClientContentTypeMissing{}
 	}
 
-	// strip encoding information from Content-Type header
-	ct = base.HeaderValue{strings.Split(ct[0], ";")[0]}
-
-	if ct[0] != "application/sdp" {
+	pct, err := base.ParseHeaderContentType(ct)
+	if err != nil || pct.MIMEType != base.ContentTypeSDP {
 		return nil, nil, liberrors.ErrClientContentTypeUnsupported{CT: ct}
 	}
 
 	var ssd sdp.SessionDescription
-	err = ssd.Unmarshal(res.Body)
+	warnings, err := ssd.UnmarshalWithWarnings(res.Body)
 	if err != nil {
 		return nil, nil, liberrors.ErrClientSDPInvalid{Err: err}
 	}
 
+	for _, w := range warnings {
+		c.OnDecodeError(liberrors.ErrClientSDPLineSkipped{Err: w})
+	}
+
 	var desc description.Session
 	err = desc.Unmarshal(&ssd)
 	if err != nil {
@@ -1232,7 +1716,7 @@ func (c *Client) doAnnounce(u *base.URL, desc *description.Session) (*base.Respo
 		Method: base.Announce,
 		URL:    u,
 		Header: base.Header{
-			"Content-Type": base.HeaderValue{"application/sdp"},
+			"Content-Type": base.HeaderValue{base.ContentTypeSDP},
 		},
 		Body: byts,
 	}, false)
@@ -1332,11 +1816,16 @@ func (c *Client) doSetup(
 			return nil, liberrors.ErrClientUDPPortsNotConsecutive{}
 		}
 
+		rtcpAddress := ""
+		if !c.DisableRTCP && !c.RTCPOverRTP {
+			rtcpAddress = net.JoinHostPort("", strconv.FormatInt(int64(rtcpPort), 10))
+		}
+
 		err = cm.allocateUDPListeners(
 			false,
 			nil,
 			net.JoinHostPort("", strconv.FormatInt(int64(rtpPort), 10)),
-			net.JoinHostPort("", strconv.FormatInt(int64(rtcpPort), 10)),
+			rtcpAddress,
 		)
 		if err != nil {
 			return nil, err
@@ -1345,7 +1834,15 @@ func (c *Client) doSetup(
 		v1 := headers.TransportDeliveryUnicast
 		th.Delivery = &v1
 		th.Protocol = headers.TransportProtocolUDP
-		th.ClientPorts = &[2]int{cm.udpRTPListener.port(), cm.udpRTCPListener.port()}
+
+		// when there's no RTCP listener, a single port is advertised
+		if cm.udpRTCPListener != nil {
+			th.ClientPorts = &[2]int{cm.udpRTPListener.port(), cm.udpRTCPListener.port()}
+		} else {
+			th.ClientPorts = &[2]int{cm.udpRTPListener.port(), cm.udpRTPListener.port()}
+		}
+
+		th.RTCPMux = c.RTCPOverRTP
 
 	case TransportUDPMulticast:
 		v1 := headers.TransportDeliveryMulticast
@@ -1370,6 +1867,25 @@ func (c *Client) doSetup(
 		"Transport": th.Marshal(),
 	}
 
+	// offer TCP as an alternative to UDP
+	offerTCP := c.OfferTransportAlternatives &&
+		c.effectiveTransport == nil &&
+		desiredTransport == TransportUDP
+
+	if offerTCP {
+		v1 := headers.TransportDeliveryUnicast
+		ch := c.findFreeChannelPair()
+		header["Transport"] = headers.Transports{
+			th,
+			{
+				Protocol:       headers.TransportProtocolTCP,
+				Delivery:       &v1,
+				Mode:           th.Mode,
+				InterleavedIDs: &[2]int{ch, ch + 1},
+			},
+		}.Marshal()
+	}
+
 	if medi.IsBackChannel {
 		header["Require"] = base.HeaderValue{"www.onvif.org/ver20/backchannel"}
 	}
@@ -1400,12 +1916,20 @@ func (c *Client) doSetup(
 	}
 
 	var thRes headers.Transport
-	err = thRes.Unmarshal(res.Header["Transport"])
+	err = thRes.Unmarshal(res.Header.GetAll("Transport"))
 	if err != nil {
 		cm.close()
 		return nil, liberrors.ErrClientTransportHeaderInvalid{Err: err}
 	}
 
+	// the server chose the TCP alternative
+	if offerTCP && thRes.Protocol == headers.TransportProtocolTCP {
+		cm.close()
+		cm.udpRTPListener = nil
+		cm.udpRTCPListener = nil
+		desiredTransport = TransportTCP
+	}
+
 	switch desiredTransport {
 	case TransportUDP, TransportUDPMulticast:
 		if thRes.Protocol == headers.TransportProtocolTCP {
@@ -1436,6 +1960,11 @@ func (c *Client) doSetup(
 			return nil, liberrors.ErrClientServerPortsNotProvided{}
 		}
 
+		if c.RTCPOverRTP && !thRes.RTCPMux {
+			cm.close()
+			return nil, liberrors.ErrClientRTCPMuxNotSupported{}
+		}
+
 		var readIP net.IP
 		if thRes.Source != nil {
 			readIP = *thRes.Source
@@ -1455,17 +1984,19 @@ func (c *Client) doSetup(
 		}
 		cm.udpRTPListener.readIP = readIP
 
-		if serverPortsValid {
-			if !c.AnyPortEnable {
-				cm.udpRTCPListener.readPort = thRes.ServerPorts[1]
-			}
-			cm.udpRTCPListener.writeAddr = &net.UDPAddr{
-				IP:   c.nconn.RemoteAddr().(*net.TCPAddr).IP,
-				Zone: c.nconn.RemoteAddr().(*net.TCPAddr).Zone,
-				Port: thRes.ServerPorts[1],
+		if cm.udpRTCPListener != nil {
+			if serverPortsValid {
+				if !c.AnyPortEnable {
+					cm.udpRTCPListener.readPort = thRes.ServerPorts[1]
+				}
+				cm.udpRTCPListener.writeAddr = &net.UDPAddr{
+					IP:   c.nconn.RemoteAddr().(*net.TCPAddr).IP,
+					Zone: c.nconn.RemoteAddr().(*net.TCPAddr).Zone,
+					Port: thRes.ServerPorts[1],
+				}
 			}
+			cm.udpRTCPListener.readIP = readIP
 		}
-		cm.udpRTCPListener.readIP = readIP
 
 	case TransportUDPMulticast:
 		if thRes.Delivery == nil || *thRes.Delivery != headers.TransportDeliveryMulticast {
@@ -1544,6 +2075,12 @@ func (c *Client) doSetup(
 	c.medias[medi] = cm
 	cm.setMedia(medi)
 
+	if c.FormatFilter != nil && c.state != clientStatePreRecord && !medi.IsBackChannel {
+		for _, cf := range cm.formats {
+			cf.filtered = !c.FormatFilter(medi, cf.format)
+		}
+	}
+
 	c.baseURL = baseURL
 	c.effectiveTransport = &desiredTransport
 
@@ -1604,7 +2141,27 @@ func (c *Client) Setup(
 }
 
 // SetupAll setups all the given medias.
+// Medias rejected by MediaFilter are skipped.
 func (c *Client) SetupAll(baseURL *base.URL, medias []*description.Media) error {
+	if c.MediaFilter != nil {
+		var filtered []*description.Media
+		for _, m := range medias {
+			if c.MediaFilter(m) {
+				filtered = append(filtered, m)
+			}
+		}
+
+		if filtered == nil {
+			return liberrors.ErrClientNoMediasAfterFilter{}
+		}
+
+		medias = filtered
+	}
+
+	return c.setupAll(baseURL, medias)
+}
+
+func (c *Client) setupAll(baseURL *base.URL, medias []*description.Media) error {
 	for _, m := range medias {
 		_, err := c.Setup(baseURL, m, 0, 0)
 		if err != nil {
@@ -1614,6 +2171,130 @@ func (c *Client) SetupAll(baseURL *base.URL, medias []*description.Media) error
 	return nil
 }
 
+func (c *Client) doSetupAllWithTransport(
+	baseURL *base.URL,
+	medias []*description.Media,
+	transport Transport,
+	retry bool,
+) error {
+	if retry {
+		prevConnURL := c.connURL
+
+		c.reset()
+
+		c.connURL = prevConnURL
+
+		// some Hikvision cameras require a describe before a setup
+		if c.lastDescribeURL != nil {
+			_, _, err := c.doDescribe(c.lastDescribeURL)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// TCP is always used with RTSPS
+	if c.connURL.Scheme != "rtsps" {
+		c.effectiveTransport = &transport
+	}
+
+	for _, medi := range medias {
+		_, err := c.doSetup(baseURL, medi, 0, 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) setupAllWithTransport(
+	baseURL *base.URL,
+	medias []*description.Media,
+	transport Transport,
+	retry bool,
+) error {
+	cres := make(chan clientRes)
+	select {
+	case c.chSetupAll <- setupAllReq{
+		baseURL:   baseURL,
+		medias:    medias,
+		transport: transport,
+		retry:     retry,
+		res:       cres,
+	}:
+		res := <-cres
+		return res.err
+
+	case <-c.done:
+		return c.closeError
+	}
+}
+
+// isTransportMismatch checks whether a SETUP error is caused by
+// a transport protocol that is not supported by the server.
+func isTransportMismatch(err error) bool {
+	switch err := err.(type) {
+	case liberrors.ErrClientBadStatusCode:
+		return err.Code == base.StatusBadRequest ||
+			err.Code == base.StatusNotAcceptable ||
+			err.Code == base.StatusUnsupportedTransport
+
+	case liberrors.ErrClientServerRequestedTCP,
+		liberrors.ErrClientServerRequestedUDP,
+		liberrors.ErrClientServerPortsNotProvided,
+		liberrors.ErrClientTransportHeaderInvalidDelivery:
+		return true
+	}
+
+	return false
+}
+
+// SetupAndPlayRobust setups all the medias of the given session and starts playing.
+// Transports are tried in order; the next one is tried when the server
+// rejects the current one (i.e. with 461 Unsupported Transport).
+// If transports is nil, UDP and then TCP are tried.
+// Packet callbacks can be set inside OnBeforePlay.
+func (c *Client) SetupAndPlayRobust(
+	ctx context.Context,
+	desc *description.Session,
+	transports []Transport,
+) error {
+	if transports == nil {
+		transports = []Transport{TransportUDP, TransportTCP}
+	}
+
+	if len(transports) == 0 {
+		return fmt.Errorf("no transports provided")
+	}
+
+	var err error
+
+	for i, transport := range transports {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		err = c.setupAllWithTransport(desc.BaseURL, desc.Medias, transport, i != 0)
+		if err == nil {
+			break
+		}
+
+		if !isTransportMismatch(err) {
+			return err
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.OnBeforePlay(desc)
+
+	_, err = c.Play(nil)
+	return err
+}
+
 func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 	err := c.checkState(map[clientState]struct{}{
 		clientStatePrePlay: {},
@@ -1661,6 +2342,20 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 		}
 	}
 
+	for _, cm := range c.medias {
+		cm.rtpInfo = nil
+	}
+
+	if v, ok := res.Header["RTP-Info"]; ok {
+		var ri headers.RTPInfo
+		err = ri.Unmarshal(v)
+		if err != nil {
+			c.OnDecodeError(liberrors.ErrClientRTPInfoHeaderInvalid{Err: err})
+		} else {
+			c.setRTPInfo(ri)
+		}
+	}
+
 	// open the firewall by sending empty packets to the counterpart.
 	// do this before sending the request.
 	// don't do this with multicast, otherwise the RTP packet is going to be broadcasted
@@ -1670,8 +2365,10 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 			byts, _ := (&rtp.Packet{Header: rtp.Header{Version: 2}}).Marshal()
 			cm.udpRTPListener.write(byts) //nolint:errcheck
 
-			byts, _ = (&rtcp.ReceiverReport{}).Marshal()
-			cm.udpRTCPListener.write(byts) //nolint:errcheck
+			if cm.udpRTCPListener != nil {
+				byts, _ = (&rtcp.ReceiverReport{}).Marshal()
+				cm.udpRTCPListener.write(byts) //nolint:errcheck
+			}
 		}
 	}
 
@@ -1681,6 +2378,49 @@ func (c *Client) doPlay(ra *headers.Range) (*base.Response, error) {
 	return res, nil
 }
 
+func findRTPInfoEntry(
+	ri headers.RTPInfo,
+	medi *description.Media,
+	baseURL *base.URL,
+) *headers.RTPInfoEntry {
+	mediaURL, err := medi.URL(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	// entry URLs are resolved like control attributes, since they can be relative
+	for _, e := range ri {
+		u, err := description.Media{Control: e.URL}.URL(baseURL)
+		if err == nil && u.Path == mediaURL.Path && u.RawQuery == mediaURL.RawQuery {
+			return e
+		}
+	}
+
+	// some servers return URLs that are different from the ones used in SETUP requests
+	if medi.Control != "" {
+		for _, e := range ri {
+			if strings.HasSuffix(e.URL, medi.Control) {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) setRTPInfo(ri headers.RTPInfo) {
+	if len(c.medias) == 1 && len(ri) == 1 {
+		for _, cm := range c.medias {
+			cm.rtpInfo = ri[0]
+		}
+		return
+	}
+
+	for _, cm := range c.medias {
+		cm.rtpInfo = findRTPInfoEntry(ri, cm.media, c.baseURL)
+	}
+}
+
 // Play sends a PLAY request.
 // This can be called only after Setup().
 func (c *Client) Play(ra *headers.Range) (*base.Response, error) {
@@ -1806,6 +2546,57 @@ func (c *Client) Seek(ra *headers.Range) (*base.Response, error) {
 	return c.Play(ra)
 }
 
+func (c *Client) doPing() (time.Duration, error) {
+	err := c.connOpen()
+	if err != nil {
+		return 0, err
+	}
+
+	u := c.baseURL
+	if u == nil {
+		u = c.connURL
+	}
+
+	// send OPTIONS in advance, in order not to include it into the round-trip time
+	if !c.optionsSent {
+		_, err = c.doOptions(u)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	start := time.Now()
+
+	res, err := c.do(&base.Request{
+		Method: base.GetParameter,
+		URL:    u,
+	}, false)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.StatusCode != base.StatusOK {
+		return 0, liberrors.ErrClientBadStatusCode{Code: res.StatusCode, Message: res.StatusMessage}
+	}
+
+	return time.Since(start), nil
+}
+
+// PingServer sends a GET_PARAMETER request with an empty body and
+// returns the round-trip time.
+// It can be called at any time, including during playing or recording.
+func (c *Client) PingServer() (time.Duration, error) {
+	cres := make(chan clientRes)
+	select {
+	case c.chPing <- pingReq{res: cres}:
+		res := <-cres
+		return res.rtt, res.err
+
+	case <-c.done:
+		return 0, c.closeError
+	}
+}
+
 // OnPacketRTPAny sets the callback that is called when a RTP packet is read from any setupped media.
 func (c *Client) OnPacketRTPAny(cb OnPacketRTPAnyFunc) {
 	for _, cm := range c.medias {
@@ -1835,6 +2626,39 @@ func (c *Client) OnPacketRTP(medi *description.Media, forma format.Format, cb On
 	ct.onPacketRTP = cb
 }
 
+// OnPacketAccessUnit sets a callback that is called when an access unit is decoded
+// from the RTP packets of a format, or when decoding fails.
+// Packets are decoded with decoder, and the PTS of access units is computed with PacketPTS2().
+// errMorePacketsNeeded is the error returned by decoder when an access unit
+// is incomplete (i.e. rtph264.ErrMorePacketsNeeded); it is not passed to the callback.
+// Packets that are received before the PTS can be computed are discarded.
+func (c *Client) OnPacketAccessUnit(
+	medi *description.Media,
+	forma format.Format,
+	decoder AccessUnitDecoder,
+	errMorePacketsNeeded error,
+	cb OnPacketAccessUnitFunc,
+) {
+	clockRate := int64(forma.ClockRate())
+
+	c.OnPacketRTP(medi, forma, func(pkt *rtp.Packet) {
+		pts, ok := c.PacketPTS2(medi, pkt)
+		if !ok || clockRate == 0 {
+			return
+		}
+
+		au, err := decoder.Decode(pkt)
+		if err != nil {
+			if !errors.Is(err, errMorePacketsNeeded) {
+				cb(0, nil, err)
+			}
+			return
+		}
+
+		cb(time.Duration(multiplyAndDivide(pts, int64(time.Second), clockRate)), au, nil)
+	})
+}
+
 // OnPacketRTCP sets the callback that is called when a RTCP packet is read.
 func (c *Client) OnPacketRTCP(medi *description.Media, cb OnPacketRTCPFunc) {
 	cm := c.medias[medi]
@@ -1868,6 +2692,9 @@ func (c *Client) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.Packet,
 }
 
 // WritePacketRTCP writes a RTCP packet to the server.
+// It is sent through the RTCP port when using UDP, or the RTCP channel when using TCP.
+// Multiple packets (i.e. a sender report and a source description) can be sent together
+// by passing a *rtcp.CompoundPacket.
 func (c *Client) WritePacketRTCP(medi *description.Media, pkt rtcp.Packet) error {
 	byts, err := pkt.Marshal()
 	if err != nil {
@@ -1884,6 +2711,30 @@ func (c *Client) WritePacketRTCP(medi *description.Media, pkt rtcp.Packet) error
 	return cm.writePacketRTCP(byts)
 }
 
+// WritePLI asks the server to send a key frame of a format through a RTCP Picture Loss Indication.
+// The server must have advertised the "nack pli" feedback capability for the format.
+func (c *Client) WritePLI(medi *description.Media, forma format.Format) error {
+	if !medi.HasRTCPFeedback(forma.PayloadType(), "nack pli") {
+		return liberrors.ErrClientRTCPFeedbackNotSupported{Feedback: "nack pli"}
+	}
+
+	cm := c.medias[medi]
+	cf := cm.formats[forma.PayloadType()]
+	return cf.writeKeyFrameRequest(false)
+}
+
+// WriteFIR asks the server to send a key frame of a format through a RTCP Full Intra Request.
+// The server must have advertised the "ccm fir" feedback capability for the format.
+func (c *Client) WriteFIR(medi *description.Media, forma format.Format) error {
+	if !medi.HasRTCPFeedback(forma.PayloadType(), "ccm fir") {
+		return liberrors.ErrClientRTCPFeedbackNotSupported{Feedback: "ccm fir"}
+	}
+
+	cm := c.medias[medi]
+	cf := cm.formats[forma.PayloadType()]
+	return cf.writeKeyFrameRequest(true)
+}
+
 // PacketPTS returns the PTS of an incoming RTP packet.
 // It is computed by decoding the packet timestamp and sychronizing it with other tracks.
 //
@@ -1902,6 +2753,17 @@ func (c *Client) PacketPTS2(medi *description.Media, pkt *rtp.Packet) (int64, bo
 	return c.timeDecoder2.Decode(ct.format, pkt)
 }
 
+// RTPInfo returns the RTP-Info entry of a media, received with the last PLAY response.
+// It contains the sequence number and the RTP timestamp of the first packet
+// that is sent after the PLAY request.
+func (c *Client) RTPInfo(medi *description.Media) (*headers.RTPInfoEntry, bool) {
+	cm := c.medias[medi]
+	if cm.rtpInfo == nil {
+		return nil, false
+	}
+	return cm.rtpInfo, true
+}
+
 // PacketNTP returns the NTP timestamp of an incoming RTP packet.
 // The NTP timestamp is computed from RTCP sender reports.
 func (c *Client) PacketNTP(medi *description.Media, pkt *rtp.Packet) (time.Time, bool) {
@@ -1910,6 +2772,15 @@ func (c *Client) PacketNTP(medi *description.Media, pkt *rtp.Packet) (time.Time,
 	return ct.rtcpReceiver.PacketNTP(pkt.Timestamp)
 }
 
+// PacketRecovered checks whether an incoming RTP packet has been recovered
+// through redundant (RED) or forward error correction (ULPFEC) data.
+// It must be called inside the OnPacketRTP callback.
+func (c *Client) PacketRecovered(medi *description.Media, pkt *rtp.Packet) bool {
+	cm := c.medias[medi]
+	ct := cm.formats[pkt.PayloadType]
+	return ct.isRecovered(pkt)
+}
+
 func (c *Client) readResponse(res *base.Response) {
 	c.chReadResponse <- res
 }
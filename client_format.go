@@ -1,12 +1,15 @@
 package gortsplib
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format"
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtpred"
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtpulpfec"
 	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 	"github.com/voicecom/gortsplib/v4/pkg/rtcpreceiver"
 	"github.com/voicecom/gortsplib/v4/pkg/rtcpsender"
@@ -14,15 +17,34 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/rtpreorderer"
 )
 
+const (
+	// maximum number of sequence numbers that are requested with a single NACK.
+	nackMaxLost = 64
+
+	// number of recovered packets that are remembered by each format.
+	// it must be a power of two and greater than the reorderer buffer size.
+	recoveredBufferSize = 128
+)
+
 type clientFormat struct {
 	cm          *clientMedia
 	format      format.Format
 	onPacketRTP OnPacketRTPFunc
+	filtered    bool // play, discarded by FormatFilter
+
+	rtxPrimary *clientFormat // RTX formats only
+	rtx        *clientFormat // formats with an associated RTX format
 
-	udpReorderer    *rtpreorderer.Reorderer       // play
-	tcpLossDetector *rtplossdetector.LossDetector // play
-	rtcpReceiver    *rtcpreceiver.RTCPReceiver    // play
-	rtcpSender      *rtcpsender.RTCPSender        // record or back channel
+	udpReorderer       *rtpreorderer.Reorderer       // play
+	tcpLossDetector    *rtplossdetector.LossDetector // play
+	rtcpReceiver       *rtcpreceiver.RTCPReceiver    // play
+	rtcpSender         *rtcpsender.RTCPSender        // record or back channel
+	nackInitialized    bool                          // play with RTX
+	nackExpectedSeqNum uint16                        // play with RTX
+	redDecoder         *rtpred.Decoder               // play, RED formats only
+	fecDecoder         *rtpulpfec.Decoder            // play, ULPFEC formats only
+	recoveredPackets   []*rtp.Packet                 // play
+	firSeqNum          uint32                        // play
 }
 
 func (cf *clientFormat) start() {
@@ -32,7 +54,7 @@ func (cf *clientFormat) start() {
 			cf.cm.c.senderReportPeriod,
 			cf.cm.c.timeNow,
 			func(pkt rtcp.Packet) {
-				if !cf.cm.c.DisableRTCPSenderReports {
+				if !cf.cm.c.DisableRTCPSenderReports && !cf.cm.c.DisableRTCP {
 					cf.cm.c.WritePacketRTCP(cf.cm.media, pkt) //nolint:errcheck
 				}
 			})
@@ -50,13 +72,29 @@ func (cf *clientFormat) start() {
 			cf.cm.c.receiverReportPeriod,
 			cf.cm.c.timeNow,
 			func(pkt rtcp.Packet) {
-				if cf.cm.udpRTPListener != nil {
+				if cf.cm.udpRTPListener != nil && !cf.cm.c.DisableRTCP {
 					cf.cm.c.WritePacketRTCP(cf.cm.media, pkt) //nolint:errcheck
 				}
 			})
 		if err != nil {
 			panic(err)
 		}
+
+		switch forma := cf.format.(type) {
+		case *format.RED:
+			cf.redDecoder, err = forma.CreateDecoder()
+			if err != nil {
+				panic(err)
+			}
+
+		case *format.ULPFEC:
+			cf.fecDecoder, err = forma.CreateDecoder()
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		cf.recoveredPackets = make([]*rtp.Packet, recoveredBufferSize)
 	}
 }
 
@@ -85,6 +123,38 @@ func (cf *clientFormat) writePacketRTP(byts []byte, pkt *rtp.Packet, ntp time.Ti
 }
 
 func (cf *clientFormat) readRTPUDP(pkt *rtp.Packet) {
+	if cf.rtx != nil {
+		cf.requestRetransmission(pkt)
+	}
+
+	// store packet as soon as possible, in order to recover missing packets
+	// while the reorderer is waiting for them.
+	if fec := cf.cm.fecFormat; fec != nil && fec != cf && cf.rtxPrimary == nil {
+		fec.fecDecoder.ProcessMedia(pkt)
+	}
+
+	// packets that carry other packets are processed as soon as they are received,
+	// since the packets they carry are reordered once extracted.
+	if cf.rtxPrimary != nil || cf.redDecoder != nil || cf.fecDecoder != nil {
+		err := cf.rtcpReceiver.ProcessPacket(pkt, cf.cm.c.timeNow(), cf.format.PTSEqualsDTS(pkt))
+		if err != nil {
+			cf.cm.c.OnDecodeError(err)
+			return
+		}
+
+		switch {
+		case cf.rtxPrimary != nil:
+			cf.readRTX(pkt, cf.rtxPrimary.readRTPUDP)
+
+		case cf.redDecoder != nil:
+			cf.readREDUDP(pkt)
+
+		default:
+			cf.readFEC(pkt)
+		}
+		return
+	}
+
 	packets, lost := cf.udpReorderer.Process(pkt)
 	if lost != 0 {
 		cf.cm.c.OnPacketLost(liberrors.ErrClientRTPPacketsLost{Lost: lost})
@@ -119,5 +189,175 @@ func (cf *clientFormat) readRTPTCP(pkt *rtp.Packet) {
 		return
 	}
 
-	cf.onPacketRTP(pkt)
+	switch {
+	// with TCP, packets are never reordered, therefore
+	// retransmitted packets are delivered as soon as they are received.
+	case cf.rtxPrimary != nil:
+		cf.readRTX(pkt, func(pkt *rtp.Packet) {
+			cf.rtxPrimary.onPacketRTP(pkt)
+		})
+
+	case cf.redDecoder != nil:
+		cf.readREDTCP(pkt)
+
+	// with TCP, packets are never lost, therefore FEC packets are useless.
+	case cf.fecDecoder != nil:
+
+	default:
+		cf.onPacketRTP(pkt)
+	}
+}
+
+// requestRetransmission detects gaps in the sequence numbers of incoming packets
+// and requests missing packets to the server through RTCP NACKs.
+func (cf *clientFormat) requestRetransmission(pkt *rtp.Packet) {
+	if !cf.nackInitialized {
+		cf.nackInitialized = true
+		cf.nackExpectedSeqNum = pkt.SequenceNumber + 1
+		return
+	}
+
+	diff := int16(pkt.SequenceNumber - cf.nackExpectedSeqNum)
+
+	// packet is a duplicate, a retransmission or is reordered
+	if diff < 0 {
+		return
+	}
+
+	if diff > 0 && diff <= nackMaxLost {
+		seqNums := make([]uint16, diff)
+		for i := range seqNums {
+			seqNums[i] = cf.nackExpectedSeqNum + uint16(i)
+		}
+
+		cf.cm.c.WritePacketRTCP(cf.cm.media, &rtcp.TransportLayerNack{ //nolint:errcheck
+			SenderSSRC: cf.rtcpReceiver.ReceiverSSRC(),
+			MediaSSRC:  pkt.SSRC,
+			Nacks:      rtcp.NackPairsFromSequenceNumbers(seqNums),
+		})
+	}
+
+	cf.nackExpectedSeqNum = pkt.SequenceNumber + 1
+}
+
+// readRTX unwraps a retransmitted packet and routes it to the associated format.
+func (cf *clientFormat) readRTX(pkt *rtp.Packet, cb func(*rtp.Packet)) {
+	ssrc, ok := cf.rtxPrimary.rtcpReceiver.SenderSSRC()
+	if !ok {
+		return
+	}
+
+	orig, err := cf.format.(*format.RTX).Unwrap(pkt)
+	if err != nil {
+		cf.cm.c.OnDecodeError(err)
+		return
+	}
+
+	orig.SSRC = ssrc
+	cb(orig)
+}
+
+// writeKeyFrameRequest asks the server to send a key frame,
+// through a RTCP Picture Loss Indication or a Full Intra Request.
+func (cf *clientFormat) writeKeyFrameRequest(fir bool) error {
+	if cf.rtcpReceiver == nil {
+		return liberrors.ErrClientSenderSSRCUnknown{}
+	}
+
+	ssrc, ok := cf.rtcpReceiver.SenderSSRC()
+	if !ok {
+		return liberrors.ErrClientSenderSSRCUnknown{}
+	}
+
+	if fir {
+		return cf.cm.c.WritePacketRTCP(cf.cm.media, &rtcp.FullIntraRequest{
+			SenderSSRC: cf.rtcpReceiver.ReceiverSSRC(),
+			MediaSSRC:  ssrc,
+			FIR: []rtcp.FIREntry{{
+				SSRC:           ssrc,
+				SequenceNumber: uint8(atomic.AddUint32(&cf.firSeqNum, 1)),
+			}},
+		})
+	}
+
+	return cf.cm.c.WritePacketRTCP(cf.cm.media, &rtcp.PictureLossIndication{
+		SenderSSRC: cf.rtcpReceiver.ReceiverSSRC(),
+		MediaSSRC:  ssrc,
+	})
+}
+
+func (cf *clientFormat) setRecovered(pkt *rtp.Packet) {
+	cf.recoveredPackets[pkt.SequenceNumber&(recoveredBufferSize-1)] = pkt
+}
+
+func (cf *clientFormat) isRecovered(pkt *rtp.Packet) bool {
+	if cf.recoveredPackets == nil {
+		return false
+	}
+	return cf.recoveredPackets[pkt.SequenceNumber&(recoveredBufferSize-1)] == pkt
+}
+
+// readREDUDP decodes a RED packet and routes its blocks to their formats.
+// Redundant blocks are used to fill gaps left by lost packets;
+// those corresponding to already-received packets are discarded by the reorderer.
+func (cf *clientFormat) readREDUDP(pkt *rtp.Packet) {
+	blocks, err := cf.redDecoder.Decode(pkt)
+	if err != nil {
+		cf.cm.c.OnDecodeError(err)
+		return
+	}
+
+	for i, block := range blocks {
+		target, ok := cf.cm.formats[block.PayloadType]
+		if !ok || target.redDecoder != nil {
+			cf.cm.c.OnDecodeError(liberrors.ErrClientRTPPacketUnknownPayloadType{PayloadType: block.PayloadType})
+			continue
+		}
+
+		if i != (len(blocks) - 1) {
+			target.setRecovered(block)
+		}
+
+		target.readRTPUDP(block)
+	}
+}
+
+// readREDTCP decodes a RED packet and routes its primary block to its format.
+// With TCP, packets are never lost, therefore redundant blocks are discarded.
+func (cf *clientFormat) readREDTCP(pkt *rtp.Packet) {
+	blocks, err := cf.redDecoder.Decode(pkt)
+	if err != nil {
+		cf.cm.c.OnDecodeError(err)
+		return
+	}
+
+	primary := blocks[len(blocks)-1]
+
+	target, ok := cf.cm.formats[primary.PayloadType]
+	if !ok || target.redDecoder != nil {
+		cf.cm.c.OnDecodeError(liberrors.ErrClientRTPPacketUnknownPayloadType{PayloadType: primary.PayloadType})
+		return
+	}
+
+	target.readRTPTCP(primary)
+}
+
+// readFEC uses a FEC packet to recover a missing packet, that is routed to its format.
+func (cf *clientFormat) readFEC(pkt *rtp.Packet) {
+	rec, err := cf.fecDecoder.Decode(pkt)
+	if err != nil {
+		if err != rtpulpfec.ErrNothingToRecover {
+			cf.cm.c.OnDecodeError(err)
+		}
+		return
+	}
+
+	target, ok := cf.cm.formats[rec.PayloadType]
+	if !ok || target == cf {
+		cf.cm.c.OnDecodeError(liberrors.ErrClientRTPPacketUnknownPayloadType{PayloadType: rec.PayloadType})
+		return
+	}
+
+	target.setRecovered(rec)
+	target.readRTPUDP(rec)
 }
@@ -10,15 +10,25 @@ import (
 
 	"github.com/voicecom/gortsplib/v4/pkg/base"
 	"github.com/voicecom/gortsplib/v4/pkg/description"
+	"github.com/voicecom/gortsplib/v4/pkg/format"
+	"github.com/voicecom/gortsplib/v4/pkg/headers"
 	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 )
 
+// isRTCP checks whether a packet received on a port shared by RTP and RTCP is a RTCP packet.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5761#section-4
+func isRTCP(payload []byte) bool {
+	return len(payload) >= 2 && payload[1] >= 192 && payload[1] <= 223
+}
+
 type clientMedia struct {
 	c            *Client
 	onPacketRTCP OnPacketRTCPFunc
 
 	media                  *description.Media
+	rtpInfo                *headers.RTPInfoEntry
 	formats                map[uint8]*clientFormat
+	fecFormat              *clientFormat
 	tcpChannel             int
 	udpRTPListener         *clientUDPListener
 	udpRTCPListener        *clientUDPListener
@@ -32,6 +42,8 @@ type clientMedia struct {
 func (cm *clientMedia) close() {
 	if cm.udpRTPListener != nil {
 		cm.udpRTPListener.close()
+	}
+	if cm.udpRTCPListener != nil {
 		cm.udpRTCPListener.close()
 	}
 }
@@ -42,7 +54,8 @@ func (cm *clientMedia) allocateUDPListeners(
 	rtpAddress string,
 	rtcpAddress string,
 ) error {
-	if rtpAddress != ":0" {
+	// when rtcpAddress is empty, only the RTP listener is allocated.
+	if rtpAddress != ":0" || rtcpAddress == "" {
 		l1 := &clientUDPListener{
 			c:                 cm.c,
 			multicastEnable:   multicastEnable,
@@ -54,6 +67,11 @@ func (cm *clientMedia) allocateUDPListeners(
 			return err
 		}
 
+		if rtcpAddress == "" {
+			cm.udpRTPListener = l1
+			return nil
+		}
+
 		l2 := &clientUDPListener{
 			c:                 cm.c,
 			multicastEnable:   multicastEnable,
@@ -86,6 +104,21 @@ func (cm *clientMedia) setMedia(medi *description.Media) {
 			onPacketRTP: func(*rtp.Packet) {},
 		}
 	}
+
+	// associate retransmission formats with their primary format
+	// and find the format used for forward error correction
+	for _, cf := range cm.formats {
+		switch forma := cf.format.(type) {
+		case *format.RTX:
+			if primary, ok := cm.formats[forma.APT]; ok {
+				cf.rtxPrimary = primary
+				primary.rtx = cf
+			}
+
+		case *format.ULPFEC:
+			cm.fecFormat = cf
+		}
+	}
 }
 
 func (cm *clientMedia) start() {
@@ -93,12 +126,29 @@ func (cm *clientMedia) start() {
 		cm.writePacketRTPInQueue = cm.writePacketRTPInQueueUDP
 		cm.writePacketRTCPInQueue = cm.writePacketRTCPInQueueUDP
 
+		var readRTP, readRTCP readFunc
 		if cm.c.state == clientStateRecord || cm.media.IsBackChannel {
-			cm.udpRTPListener.readFunc = cm.readRTPUDPRecord
-			cm.udpRTCPListener.readFunc = cm.readRTCPUDPRecord
+			readRTP, readRTCP = cm.readRTPUDPRecord, cm.readRTCPUDPRecord
 		} else {
-			cm.udpRTPListener.readFunc = cm.readRTPUDPPlay
-			cm.udpRTCPListener.readFunc = cm.readRTCPUDPPlay
+			readRTP, readRTCP = cm.readRTPUDPPlay, cm.readRTCPUDPPlay
+		}
+
+		switch {
+		case cm.udpRTCPListener != nil:
+			cm.udpRTPListener.readFunc = readRTP
+			cm.udpRTCPListener.readFunc = readRTCP
+
+		case cm.c.RTCPOverRTP:
+			cm.udpRTPListener.readFunc = func(payload []byte) {
+				if isRTCP(payload) {
+					readRTCP(payload)
+				} else {
+					readRTP(payload)
+				}
+			}
+
+		default:
+			cm.udpRTPListener.readFunc = readRTP
 		}
 	} else {
 		cm.writePacketRTPInQueue = cm.writePacketRTPInQueueTCP
@@ -127,6 +177,8 @@ func (cm *clientMedia) start() {
 
 	if cm.udpRTPListener != nil {
 		cm.udpRTPListener.start()
+	}
+	if cm.udpRTCPListener != nil {
 		cm.udpRTCPListener.start()
 	}
 }
@@ -134,6 +186,8 @@ func (cm *clientMedia) start() {
 func (cm *clientMedia) stop() {
 	if cm.udpRTPListener != nil {
 		cm.udpRTPListener.stop()
+	}
+	if cm.udpRTCPListener != nil {
 		cm.udpRTCPListener.stop()
 	}
 
@@ -158,8 +212,15 @@ func (cm *clientMedia) writePacketRTPInQueueUDP(payload []byte) {
 }
 
 func (cm *clientMedia) writePacketRTCPInQueueUDP(payload []byte) {
-	atomic.AddUint64(cm.c.BytesSent, uint64(len(payload)))
-	cm.udpRTCPListener.write(payload) //nolint:errcheck
+	switch {
+	case cm.udpRTCPListener != nil:
+		atomic.AddUint64(cm.c.BytesSent, uint64(len(payload)))
+		cm.udpRTCPListener.write(payload) //nolint:errcheck
+
+	case cm.c.RTCPOverRTP:
+		atomic.AddUint64(cm.c.BytesSent, uint64(len(payload)))
+		cm.udpRTPListener.write(payload) //nolint:errcheck
+	}
 }
 
 func (cm *clientMedia) writePacketRTPInQueueTCP(payload []byte) {
@@ -204,6 +265,14 @@ func (cm *clientMedia) readRTPTCPPlay(payload []byte) {
 		return
 	}
 
+	if forma.filtered {
+		return
+	}
+
+	if cm.c.InspectRTPBeforeDecoding != nil && !cm.c.InspectRTPBeforeDecoding(cm.media, pkt) {
+		return
+	}
+
 	forma.readRTPTCP(pkt)
 }
 
@@ -277,6 +346,14 @@ func (cm *clientMedia) readRTPUDPPlay(payload []byte) {
 		return
 	}
 
+	if forma.filtered {
+		return
+	}
+
+	if cm.c.InspectRTPBeforeDecoding != nil && !cm.c.InspectRTPBeforeDecoding(cm.media, pkt) {
+		return
+	}
+
 	forma.readRTPUDP(pkt)
 }
 
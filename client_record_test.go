@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
@@ -19,6 +20,7 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/description"
 	"github.com/voicecom/gortsplib/v4/pkg/format"
 	"github.com/voicecom/gortsplib/v4/pkg/headers"
+	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 	"github.com/voicecom/gortsplib/v4/pkg/sdp"
 )
 
@@ -638,6 +640,282 @@ func TestClientRecordPauseSerial(t *testing.T) {
 	}
 }
 
+func TestClientRecordFromChannel(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	frameReceived := make(chan struct{})
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Announce),
+					string(base.Setup),
+					string(base.Record),
+					string(base.Pause),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Announce, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Setup, req.Method)
+
+		var inTH headers.Transport
+		err2 = inTH.Unmarshal(req.Header["Transport"])
+		require.NoError(t, err2)
+
+		th := headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: inTH.InterleavedIDs,
+		}
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Transport": th.Marshal(),
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Record, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		f, err2 := conn.ReadInterleavedFrame()
+		require.NoError(t, err2)
+		require.Equal(t, 0, f.Channel)
+
+		var pkt rtp.Packet
+		err2 = pkt.Unmarshal(f.Payload)
+		require.NoError(t, err2)
+		require.Equal(t, testRTPPacket, pkt)
+
+		close(frameReceived)
+
+		req, err2 = readRequestIgnoreFrames(conn)
+		require.NoError(t, err2)
+		require.Equal(t, base.Pause, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = readRequestIgnoreFrames(conn)
+		require.NoError(t, err2)
+		require.Equal(t, base.Teardown, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+	}()
+
+	pauseSent := false
+	paused := make(chan struct{})
+	writeError := make(chan error, 1)
+
+	c := Client{
+		Transport: func() *Transport {
+			v := TransportTCP
+			return &v
+		}(),
+		OnRequest: func(req *base.Request) {
+			if req.Method == base.Pause {
+				pauseSent = true
+			}
+		},
+		OnResponse: func(_ *base.Response) {
+			if pauseSent {
+				pauseSent = false
+				close(paused)
+			}
+		},
+		OnWriteError: func(err error) {
+			writeError <- err
+		},
+	}
+
+	medi := testH264Media
+	ch := make(chan *MediaPacket)
+
+	err = c.StartRecordingFromChannel("rtsp://localhost:8554/teststream",
+		&description.Session{Medias: []*description.Media{medi}}, ch, 0)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// packets that can't be written are reported and skipped
+	ch <- &MediaPacket{Media: medi, Packet: &rtp.Packet{
+		Header:  testRTPPacket.Header,
+		Payload: make([]byte, 2000),
+	}}
+	require.Error(t, <-writeError)
+
+	ch <- &MediaPacket{Media: medi, Packet: &testRTPPacket}
+	<-frameReceived
+	close(ch)
+
+	<-paused
+}
+
+func TestClientRecordWritePacketRTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer l.Close()
+
+	sr := &rtcp.SenderReport{
+		SSRC:        0x38F27A2F,
+		NTPTime:     0xe8a4b9c000000000,
+		RTPTime:     1234,
+		PacketCount: 1,
+		OctetCount:  4,
+	}
+
+	sdes := &rtcp.SourceDescription{
+		Chunks: []rtcp.SourceDescriptionChunk{{
+			Source: 0x38F27A2F,
+			Items: []rtcp.SourceDescriptionItem{{
+				Type: rtcp.SDESCNAME,
+				Text: "myname",
+			}},
+		}},
+	}
+
+	rtcpReceived := make(chan struct{})
+	serverDone := make(chan struct{})
+	defer func() { <-serverDone }()
+	go func() {
+		defer close(serverDone)
+
+		nconn, err2 := l.Accept()
+		require.NoError(t, err2)
+		defer nconn.Close()
+		conn := conn.NewConn(nconn)
+
+		req, err2 := conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Options, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Public": base.HeaderValue{strings.Join([]string{
+					string(base.Announce),
+					string(base.Setup),
+					string(base.Record),
+				}, ", ")},
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Announce, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Setup, req.Method)
+
+		var inTH headers.Transport
+		err2 = inTH.Unmarshal(req.Header["Transport"])
+		require.NoError(t, err2)
+
+		th := headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: inTH.InterleavedIDs,
+		}
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+			Header: base.Header{
+				"Transport": th.Marshal(),
+			},
+		})
+		require.NoError(t, err2)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Record, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+
+		f, err2 := conn.ReadInterleavedFrame()
+		require.NoError(t, err2)
+		require.Equal(t, inTH.InterleavedIDs[1], f.Channel)
+
+		pkts, err2 := rtcp.Unmarshal(f.Payload)
+		require.NoError(t, err2)
+		require.Equal(t, []rtcp.Packet{sr, sdes}, pkts)
+		close(rtcpReceived)
+
+		req, err2 = conn.ReadRequest()
+		require.NoError(t, err2)
+		require.Equal(t, base.Teardown, req.Method)
+
+		err2 = conn.WriteResponse(&base.Response{
+			StatusCode: base.StatusOK,
+		})
+		require.NoError(t, err2)
+	}()
+
+	c := Client{
+		Transport:                transportPtr(TransportTCP),
+		DisableRTCPSenderReports: true,
+	}
+
+	err = record(&c, "rtsp://localhost:8554/teststream",
+		[]*description.Media{testH264Media}, nil)
+	require.NoError(t, err)
+	defer c.Close()
+
+	// SR and SDES in a single compound packet
+	err = c.WritePacketRTCP(testH264Media, &rtcp.CompoundPacket{sr, sdes})
+	require.NoError(t, err)
+
+	<-rtcpReceived
+}
+
 func TestClientRecordPauseParallel(t *testing.T) {
 	for _, transport := range []string{
 		"udp",
@@ -896,6 +1174,177 @@ func TestClientRecordAutomaticProtocol(t *testing.T) {
 	<-recv
 }
 
+func TestClientRecordMulti(t *testing.T) {
+	for _, ca := range []string{
+		"ok",
+		"setup error",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			l, err := net.Listen("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer l.Close()
+
+			serverDone := make(chan struct{})
+			defer func() { <-serverDone }()
+			go func() {
+				defer close(serverDone)
+
+				nconn, err2 := l.Accept()
+				require.NoError(t, err2)
+				defer nconn.Close()
+				conn := conn.NewConn(nconn)
+
+				req, err2 := conn.ReadRequest()
+				require.NoError(t, err2)
+				require.Equal(t, base.Options, req.Method)
+
+				err2 = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+					Header: base.Header{
+						"Public": base.HeaderValue{strings.Join([]string{
+							string(base.Announce),
+							string(base.Setup),
+							string(base.Record),
+						}, ", ")},
+					},
+				})
+				require.NoError(t, err2)
+
+				req, err2 = conn.ReadRequest()
+				require.NoError(t, err2)
+				require.Equal(t, base.Announce, req.Method)
+
+				var desc sdp.SessionDescription
+				err2 = desc.Unmarshal(req.Body)
+				require.NoError(t, err2)
+
+				var desc2 description.Session
+				err2 = desc2.Unmarshal(&desc)
+				require.NoError(t, err2)
+				require.Equal(t, 2, len(desc2.Medias))
+				require.Equal(t, description.MediaTypeVideo, desc2.Medias[0].Type)
+				require.Equal(t, description.MediaTypeAudio, desc2.Medias[1].Type)
+
+				err2 = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err2)
+
+				for i := 0; i < 2; i++ {
+					req, err2 = conn.ReadRequest()
+					require.NoError(t, err2)
+					require.Equal(t, base.Setup, req.Method)
+					require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream/trackID="+
+						strconv.Itoa(i)), req.URL)
+
+					if i == 0 {
+						require.Equal(t, base.HeaderValue(nil), req.Header["Session"])
+					} else {
+						require.Equal(t, base.HeaderValue{"ABCDE"}, req.Header["Session"])
+
+						if ca == "setup error" {
+							err2 = conn.WriteResponse(&base.Response{
+								StatusCode: base.StatusNotFound,
+							})
+							require.NoError(t, err2)
+							return
+						}
+					}
+
+					var inTH headers.Transport
+					err2 = inTH.Unmarshal(req.Header["Transport"])
+					require.NoError(t, err2)
+
+					th := headers.Transport{
+						Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+						Protocol:       headers.TransportProtocolTCP,
+						InterleavedIDs: inTH.InterleavedIDs,
+					}
+
+					err2 = conn.WriteResponse(&base.Response{
+						StatusCode: base.StatusOK,
+						Header: base.Header{
+							"Transport": th.Marshal(),
+							"Session":   base.HeaderValue{"ABCDE"},
+						},
+					})
+					require.NoError(t, err2)
+				}
+
+				req, err2 = conn.ReadRequest()
+				require.NoError(t, err2)
+				require.Equal(t, base.Record, req.Method)
+				require.Equal(t, base.HeaderValue{"ABCDE"}, req.Header["Session"])
+
+				err2 = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err2)
+
+				req, err2 = conn.ReadRequest()
+				require.NoError(t, err2)
+				require.Equal(t, base.Teardown, req.Method)
+
+				err2 = conn.WriteResponse(&base.Response{
+					StatusCode: base.StatusOK,
+				})
+				require.NoError(t, err2)
+			}()
+
+			c := Client{
+				Transport: transportPtr(TransportTCP),
+			}
+
+			err = c.StartRecordingMulti("rtsp://localhost:8554/teststream", []*description.Session{
+				{
+					Medias: []*description.Media{{
+						Type: description.MediaTypeVideo,
+						Formats: []format.Format{&format.H264{
+							PayloadTyp:        96,
+							PacketizationMode: 1,
+						}},
+					}},
+				},
+				{
+					Medias: []*description.Media{{
+						Type: description.MediaTypeAudio,
+						Formats: []format.Format{&format.G711{
+							PayloadTyp:   8,
+							MULaw:        false,
+							SampleRate:   8000,
+							ChannelCount: 1,
+						}},
+					}},
+				},
+			})
+
+			if ca == "ok" {
+				require.NoError(t, err)
+				c.Close()
+			} else {
+				var merr liberrors.ErrClientSessionMediaFailed
+				require.ErrorAs(t, err, &merr)
+				require.Equal(t, 1, merr.Session)
+				require.Equal(t, 0, merr.Media)
+			}
+		})
+	}
+}
+
+func TestClientRecordMultiDuplicateMedia(t *testing.T) {
+	medi := &description.Media{
+		Type:    description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+	}
+
+	c := Client{}
+	err := c.StartRecordingMulti("rtsp://localhost:8554/teststream", []*description.Session{
+		{Medias: []*description.Media{medi}},
+		{Medias: []*description.Media{medi}},
+	})
+	require.EqualError(t, err, "session 1, media 0: media is already part of another session")
+}
+
 func TestClientRecordDecodeErrors(t *testing.T) {
 	for _, ca := range []struct {
 		proto string
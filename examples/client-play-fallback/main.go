@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4"
+	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/description"
+	"github.com/voicecom/gortsplib/v4/pkg/format"
+)
+
+// This example shows how to
+// 1. connect to a primary camera or, if it is unavailable, to a backup camera
+// 2. read all media streams from the camera that is available.
+
+func main() {
+	c := gortsplib.Client{
+		// timeout of each connection attempt
+		DialTimeout: 3 * time.Second,
+	}
+
+	// parse URLs
+	var urls []*base.URL
+	for _, s := range []string{
+		"rtsp://primary-camera:8554/mystream",
+		"rtsp://backup-camera:8554/mystream",
+	} {
+		u, err := base.ParseURL(s)
+		if err != nil {
+			panic(err)
+		}
+		urls = append(urls, u)
+	}
+
+	// called after medias have been set up and before playing
+	c.OnBeforePlay = func(_ *description.Session) {
+		// called when a RTP packet arrives
+		c.OnPacketRTPAny(func(medi *description.Media, _ format.Format, _ *rtp.Packet) {
+			log.Printf("RTP packet from media %v\n", medi)
+		})
+	}
+
+	// connect to the first available camera and start playing
+	err := c.StartPlayingWithFallback(urls)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	log.Printf("reading from %v", c.ActiveURL())
+
+	// wait until a fatal error
+	panic(c.Wait())
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+	"github.com/voicecom/gortsplib/v4"
+	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/format"
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtph264"
+)
+
+// This example shows how to
+// 1. connect to a RTSP server
+// 2. check if there's an H264 format
+// 3. get access units of that format, without decoding RTP packets manually
+
+func main() {
+	c := gortsplib.Client{}
+
+	// parse URL
+	u, err := base.ParseURL("rtsp://localhost:8554/mystream")
+	if err != nil {
+		panic(err)
+	}
+
+	// connect to the server
+	err = c.Start(u.Scheme, u.Host)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+
+	// find available medias
+	desc, _, err := c.Describe(u)
+	if err != nil {
+		panic(err)
+	}
+
+	// find the H264 media and format
+	var forma *format.H264
+	medi := desc.FindFormat(&forma)
+	if medi == nil {
+		panic("media not found")
+	}
+
+	// setup RTP -> H264 decoder
+	rtpDec, err := forma.CreateDecoder()
+	if err != nil {
+		panic(err)
+	}
+
+	// setup a single media
+	_, err = c.Setup(desc.BaseURL, medi, 0, 0)
+	if err != nil {
+		panic(err)
+	}
+
+	// called when an access unit is decoded from RTP packets
+	c.OnPacketAccessUnit(medi, forma, rtpDec, rtph264.ErrMorePacketsNeeded, func(pts time.Duration, au [][]byte, err error) {
+		if err != nil {
+			log.Printf("ERR: %v", err)
+			return
+		}
+
+		log.Printf("received access unit with PTS %v, %d NALUs, IDR: %v",
+			pts, len(au), h264.IDRPresent(au))
+	})
+
+	// start playing
+	_, err = c.Play(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// wait until a fatal error
+	panic(c.Wait())
+}
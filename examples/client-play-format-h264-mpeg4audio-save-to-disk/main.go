@@ -93,7 +93,7 @@ func main() {
 		// extract access unit from RTP packets
 		au, err := h264RTPDec.Decode(pkt)
 		if err != nil {
-			if err != rtph264.ErrNonStartingPacketAndNoPrevious && err != rtph264.ErrMorePacketsNeeded {
+			if err != rtph264.ErrMorePacketsNeeded {
 				log.Printf("ERR: %v", err)
 			}
 			return
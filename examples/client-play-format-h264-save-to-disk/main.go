@@ -80,7 +80,7 @@ func main() {
 		// extract access unit from RTP packets
 		au, err := rtpDec.Decode(pkt)
 		if err != nil {
-			if err != rtph264.ErrNonStartingPacketAndNoPrevious && err != rtph264.ErrMorePacketsNeeded {
+			if err != rtph264.ErrMorePacketsNeeded {
 				log.Printf("ERR: %v", err)
 			}
 			return
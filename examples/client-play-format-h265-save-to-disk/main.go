@@ -81,7 +81,7 @@ func main() {
 		// extract access unit from RTP packets
 		au, err := rtpDec.Decode(pkt)
 		if err != nil {
-			if err != rtph265.ErrNonStartingPacketAndNoPrevious && err != rtph265.ErrMorePacketsNeeded {
+			if err != rtph265.ErrMorePacketsNeeded {
 				log.Printf("ERR: %v", err)
 			}
 			return
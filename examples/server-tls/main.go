@@ -1,7 +1,6 @@
 package main
 
 import (
-	"crypto/tls"
 	"log"
 	"sync"
 
@@ -138,23 +137,16 @@ func (sh *serverHandler) OnRecord(ctx *gortsplib.ServerHandlerOnRecordCtx) (*bas
 }
 
 func main() {
-	// setup certificates - they can be generated with
-	// openssl genrsa -out server.key 2048
-	// openssl req -new -x509 -sha256 -key server.key -out server.crt -days 3650
-	cert, err := tls.LoadX509KeyPair("server.crt", "server.key")
-	if err != nil {
-		panic(err)
-	}
-
 	// configure the server
 	h := &serverHandler{}
 	h.s = &gortsplib.Server{
-		Handler:     h,
-		TLSConfig:   &tls.Config{Certificates: []tls.Certificate{cert}},
-		RTSPAddress: ":8322",
+		Handler: h,
 	}
 
-	// start server and wait until a fatal error
+	// start server and wait until a fatal error.
+	// certificates can be generated with
+	// openssl genrsa -out server.key 2048
+	// openssl req -new -x509 -sha256 -key server.key -out server.crt -days 3650
 	log.Printf("server is ready")
-	panic(h.s.StartAndWait())
+	panic(h.s.ListenAndServeRTSPS(":8322", "server.crt", "server.key"))
 }
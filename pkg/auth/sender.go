@@ -2,16 +2,102 @@ package auth
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/voicecom/gortsplib/v4/pkg/base"
 	"github.com/voicecom/gortsplib/v4/pkg/headers"
 )
 
+// DigestURIForm is the form of the uri parameter of digest Authorization headers.
+type DigestURIForm int
+
+// digest uri forms.
+const (
+	// URL of the request.
+	DigestURIFormRequest DigestURIForm = iota
+
+	// URL of the request, with the trailing slash of the path added or removed.
+	DigestURIFormTrailingSlash
+
+	// URL of the request, with the default port added or removed.
+	DigestURIFormDefaultPort
+
+	// path and query of the request.
+	DigestURIFormPath
+
+	// URL of the request, with a lowercase host.
+	DigestURIFormLowercaseHost
+)
+
+// String implements fmt.Stringer.
+func (f DigestURIForm) String() string {
+	switch f {
+	case DigestURIFormRequest:
+		return "request"
+	case DigestURIFormTrailingSlash:
+		return "trailing slash"
+	case DigestURIFormDefaultPort:
+		return "default port"
+	case DigestURIFormPath:
+		return "path"
+	case DigestURIFormLowercaseHost:
+		return "lowercase host"
+	}
+	return "unknown"
+}
+
+func defaultPort(scheme string) string {
+	if scheme == "rtsps" {
+		return "322"
+	}
+	return "554"
+}
+
+// URI returns the uri parameter of a request URL in this form.
+func (f DigestURIForm) URI(u *base.URL) string {
+	u = u.CloneWithoutCredentials()
+
+	switch f {
+	case DigestURIFormTrailingSlash:
+		if strings.HasSuffix(u.Path, "/") {
+			u = u.WithPath(strings.TrimSuffix(u.Path, "/"))
+		} else {
+			u = u.WithPath(u.Path + "/")
+		}
+
+	case DigestURIFormDefaultPort:
+		switch u.Port() {
+		case "":
+			u.Host = u.HostWithDefaultPort()
+
+		case defaultPort(u.Scheme):
+			u.Host = strings.TrimSuffix(u.Host, ":"+u.Port())
+		}
+
+	case DigestURIFormPath:
+		return (*url.URL)(u).RequestURI()
+
+	case DigestURIFormLowercaseHost:
+		u.Host = strings.ToLower(u.Host)
+	}
+
+	return u.String()
+}
+
 // Sender allows to send credentials.
 type Sender struct {
+	// form of the uri parameter of digest Authorization headers (optional).
+	// It can be changed to authenticate with servers that compute
+	// digest responses with an URL that is different from the request one.
+	DigestURIForm DigestURIForm
+
 	user       string
 	pass       string
 	authHeader *headers.Authenticate
+	qop        bool
+	cnonce     string
+	nc         uint32
 }
 
 // NewSender allocates a Sender.
@@ -38,16 +124,40 @@ func NewSender(wwwAuth base.HeaderValue, user string, pass string) (*Sender, err
 		return nil, fmt.Errorf("no authentication methods available")
 	}
 
-	return &Sender{
+	se := &Sender{
 		user:       user,
 		pass:       pass,
 		authHeader: bestAuthHeader,
-	}, nil
+	}
+
+	if bestAuthHeader.Method == headers.AuthMethodDigest && bestAuthHeader.QOP != nil {
+		for _, v := range strings.Split(*bestAuthHeader.QOP, ",") {
+			if strings.TrimSpace(v) == "auth" {
+				se.qop = true
+				break
+			}
+		}
+
+		if se.qop {
+			var err error
+			se.cnonce, err = GenerateNonce()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return se, nil
+}
+
+// IsDigest checks whether the sender uses digest authentication.
+func (se *Sender) IsDigest() bool {
+	return se.authHeader.Method == headers.AuthMethodDigest
 }
 
 // AddAuthorization adds the Authorization header to a Request.
 func (se *Sender) AddAuthorization(req *base.Request) {
-	urStr := req.URL.CloneWithoutCredentials().String()
+	urStr := se.DigestURIForm.URI(req.URL)
 
 	h := headers.Authorization{
 		Method: se.authHeader.Method,
@@ -62,14 +172,19 @@ func (se *Sender) AddAuthorization(req *base.Request) {
 		h.Nonce = se.authHeader.Nonce
 		h.URI = urStr
 		h.Algorithm = se.authHeader.Algorithm
+		h.Opaque = se.authHeader.Opaque
 
-		if se.authHeader.Algorithm == nil || *se.authHeader.Algorithm == headers.AuthAlgorithmMD5 {
-			h.Response = md5Hex(md5Hex(se.user+":"+se.authHeader.Realm+":"+se.pass) + ":" +
-				se.authHeader.Nonce + ":" + md5Hex(string(req.Method)+":"+urStr))
-		} else { // sha256
-			h.Response = sha256Hex(sha256Hex(se.user+":"+se.authHeader.Realm+":"+se.pass) + ":" +
-				se.authHeader.Nonce + ":" + sha256Hex(string(req.Method)+":"+urStr))
+		if se.qop {
+			se.nc++
+			qop := "auth"
+			nc := fmt.Sprintf("%08x", se.nc)
+			cnonce := se.cnonce
+			h.QOP = &qop
+			h.NC = &nc
+			h.CNonce = &cnonce
 		}
+
+		h.Response = digestResponse(&h, se.pass, req.Method)
 	}
 
 	if req.Header == nil {
@@ -19,6 +19,34 @@ func md5Hex(in string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// digestResponse computes the response of a digest Authorization header.
+// Specification: RFC2617, section 3.2.2.1
+func digestResponse(h *headers.Authorization, pass string, method base.Method) string {
+	hash := md5Hex
+	if h.Algorithm != nil && *h.Algorithm == headers.AuthAlgorithmSHA256 {
+		hash = sha256Hex
+	}
+
+	return digestResponseHA1(h, hash(h.Username+":"+h.Realm+":"+pass), method)
+}
+
+// digestResponseHA1 computes the response of a digest Authorization header,
+// starting from HA1.
+func digestResponseHA1(h *headers.Authorization, ha1 string, method base.Method) string {
+	hash := md5Hex
+	if h.Algorithm != nil && *h.Algorithm == headers.AuthAlgorithmSHA256 {
+		hash = sha256Hex
+	}
+
+	ha2 := hash(string(method) + ":" + h.URI)
+
+	if h.QOP != nil {
+		return hash(ha1 + ":" + h.Nonce + ":" + *h.NC + ":" + *h.CNonce + ":" + *h.QOP + ":" + ha2)
+	}
+
+	return hash(ha1 + ":" + h.Nonce + ":" + ha2)
+}
+
 func sha256Hex(in string) string {
 	h := sha256.New()
 	h.Write([]byte(in))
@@ -75,7 +103,7 @@ func Validate(
 	}
 
 	var auth headers.Authorization
-	err := auth.Unmarshal(req.Header["Authorization"])
+	err := auth.Unmarshal(req.Header.GetAll("Authorization"))
 	if err != nil {
 		return err
 	}
@@ -102,17 +130,11 @@ func Validate(
 			return fmt.Errorf("wrong URL")
 		}
 
-		var response string
-
-		if auth.Algorithm == nil || *auth.Algorithm == headers.AuthAlgorithmMD5 {
-			response = md5Hex(md5Hex(user+":"+realm+":"+pass) +
-				":" + nonce + ":" + md5Hex(string(req.Method)+":"+auth.URI))
-		} else { // sha256
-			response = sha256Hex(sha256Hex(user+":"+realm+":"+pass) +
-				":" + nonce + ":" + sha256Hex(string(req.Method)+":"+auth.URI))
+		if auth.QOP != nil && *auth.QOP != "auth" {
+			return fmt.Errorf("unsupported qop: %v", *auth.QOP)
 		}
 
-		if auth.Response != response {
+		if auth.Response != digestResponse(&auth, pass, req.Method) {
 			return fmt.Errorf("authentication failed")
 		}
 
@@ -41,6 +41,14 @@ var casesValidate = []struct {
 				"algorithm=\"SHA-256\"",
 		},
 	},
+	{
+		"digest md5 qop",
+		base.HeaderValue{
+			"Digest username=\"myuser\", realm=\"myrealm\", nonce=\"f49ac6dd0ba708d4becddc9692d1f2ce\", " +
+				"uri=\"rtsp://myhost/mypath?key=val/trackID=3\", response=\"5a219084ebaf9c60cf8d12700a8de831\", " +
+				"qop=auth, nc=00000001, cnonce=\"0a4f113b\"",
+		},
+	},
 	{
 		"digest vlc",
 		base.HeaderValue{
@@ -0,0 +1,307 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/headers"
+)
+
+const (
+	validatorDefaultNonceTTL  = 1 * time.Minute
+	validatorDefaultMaxNonces = 1024
+)
+
+// ErrStaleNonce is returned by Validator.Validate when credentials are
+// correct but the nonce is expired. In this case, a challenge with stale=true
+// must be sent to the client, that can retry without asking for credentials again.
+var ErrStaleNonce = errors.New("nonce is expired")
+
+type validatorNonce struct {
+	opaque  string
+	expires time.Time
+
+	// nonce-count and cnonce combinations that have already been used.
+	used map[string]struct{}
+}
+
+// Validator validates credentials of requests sent by clients.
+// It issues nonces, expires them after a certain time and
+// protects against replayed Authorization headers.
+//
+// Replay protection requires clients to use the "auth" quality of protection,
+// that is offered in challenges. Clients that don't use it can reuse a nonce
+// until it expires.
+type Validator struct {
+	// realm.
+	Realm string
+
+	// allowed methods.
+	// It defaults to all methods.
+	Methods []ValidateMethod
+
+	// lifetime of nonces.
+	// It defaults to 1 minute.
+	NonceTTL time.Duration
+
+	// maximum number of nonces that are stored at the same time.
+	// When the limit is reached, the oldest nonce is discarded.
+	// It defaults to 1024.
+	MaxNonces int
+
+	// returns the password of a user.
+	// Either this or GetHA1 must be provided.
+	GetPassword func(user string) (string, bool)
+
+	// returns HA1 of a user, that is the hash of "user:realm:password"
+	// computed with the hash function of method
+	// (ValidateMethodDigestMD5 or ValidateMethodSHA256).
+	// It can be used in place of GetPassword to avoid storing plain passwords.
+	GetHA1 func(user string, method ValidateMethod) (string, bool)
+
+	timeNow func() time.Time
+
+	mutex  sync.Mutex
+	nonces map[string]*validatorNonce
+}
+
+func (v *Validator) now() time.Time {
+	if v.timeNow != nil {
+		return v.timeNow()
+	}
+	return time.Now()
+}
+
+func (v *Validator) nonceTTL() time.Duration {
+	if v.NonceTTL != 0 {
+		return v.NonceTTL
+	}
+	return validatorDefaultNonceTTL
+}
+
+func (v *Validator) maxNonces() int {
+	if v.MaxNonces != 0 {
+		return v.MaxNonces
+	}
+	return validatorDefaultMaxNonces
+}
+
+// pruneNonces removes nonces that can't be used even to emit stale=true.
+func (v *Validator) pruneNonces(now time.Time) {
+	for k, n := range v.nonces {
+		if now.Sub(n.expires) >= v.nonceTTL() {
+			delete(v.nonces, k)
+		}
+	}
+}
+
+// removeOldestNonce removes the nonce that expires first.
+func (v *Validator) removeOldestNonce() {
+	var oldestKey string
+	var oldest *validatorNonce
+
+	for k, n := range v.nonces {
+		if oldest == nil || n.expires.Before(oldest.expires) {
+			oldestKey = k
+			oldest = n
+		}
+	}
+
+	delete(v.nonces, oldestKey)
+}
+
+func (v *Validator) methods() []ValidateMethod {
+	if v.Methods != nil {
+		return v.Methods
+	}
+	return []ValidateMethod{ValidateMethodBasic, ValidateMethodDigestMD5, ValidateMethodSHA256}
+}
+
+func (v *Validator) ha1(user string, method ValidateMethod) (string, bool) {
+	if v.GetHA1 != nil {
+		return v.GetHA1(user, method)
+	}
+
+	if v.GetPassword != nil {
+		pass, ok := v.GetPassword(user)
+		if !ok {
+			return "", false
+		}
+
+		if method == ValidateMethodSHA256 {
+			return sha256Hex(user + ":" + v.Realm + ":" + pass), true
+		}
+		return md5Hex(user + ":" + v.Realm + ":" + pass), true
+	}
+
+	return "", false
+}
+
+// Challenge generates a new nonce and returns a WWW-Authenticate header
+// that must be sent to the client in a 401 response.
+// stale must be true when Validate() returned ErrStaleNonce.
+func (v *Validator) Challenge(stale bool) (base.HeaderValue, error) {
+	nonce, err := GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	opaque, err := GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	now := v.now()
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if v.nonces == nil {
+		v.nonces = make(map[string]*validatorNonce)
+	}
+
+	v.pruneNonces(now)
+
+	for len(v.nonces) >= v.maxNonces() {
+		v.removeOldestNonce()
+	}
+
+	v.nonces[nonce] = &validatorNonce{
+		opaque:  opaque,
+		expires: now.Add(v.nonceTTL()),
+		used:    make(map[string]struct{}),
+	}
+
+	var staleStr *string
+	if stale {
+		tmp := "true"
+		staleStr = &tmp
+	}
+
+	qop := "auth"
+
+	var ret base.HeaderValue
+
+	for _, m := range v.methods() {
+		var a base.HeaderValue
+
+		switch m {
+		case ValidateMethodBasic:
+			a = headers.Authenticate{
+				Method: headers.AuthMethodBasic,
+				Realm:  v.Realm,
+			}.Marshal()
+
+		default:
+			aa := headers.AuthAlgorithmMD5
+			if m == ValidateMethodSHA256 {
+				aa = headers.AuthAlgorithmSHA256
+			}
+
+			a = headers.Authenticate{
+				Method:    headers.AuthMethodDigest,
+				Realm:     v.Realm,
+				Nonce:     nonce,
+				Opaque:    &opaque,
+				Stale:     staleStr,
+				Algorithm: &aa,
+				QOP:       &qop,
+			}.Marshal()
+		}
+
+		ret = append(ret, a...)
+	}
+
+	return ret, nil
+}
+
+// Validate validates a request sent by a client.
+func (v *Validator) Validate(req *base.Request) error {
+	methods := v.methods()
+
+	var auth headers.Authorization
+	err := auth.Unmarshal(req.Header.GetAll("Authorization"))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case auth.Method == headers.AuthMethodDigest &&
+		(contains(methods, ValidateMethodDigestMD5) &&
+			(auth.Algorithm == nil || *auth.Algorithm == headers.AuthAlgorithmMD5) ||
+			contains(methods, ValidateMethodSHA256) &&
+				auth.Algorithm != nil && *auth.Algorithm == headers.AuthAlgorithmSHA256):
+		return v.validateDigest(req, &auth)
+
+	case auth.Method == headers.AuthMethodBasic && contains(methods, ValidateMethodBasic):
+		ha1, ok := v.ha1(auth.BasicUser, ValidateMethodDigestMD5)
+		if !ok || subtle.ConstantTimeCompare(
+			[]byte(md5Hex(auth.BasicUser+":"+v.Realm+":"+auth.BasicPass)), []byte(ha1)) != 1 {
+			return fmt.Errorf("authentication failed")
+		}
+
+	default:
+		return fmt.Errorf("no supported authentication methods found")
+	}
+
+	return nil
+}
+
+func (v *Validator) validateDigest(req *base.Request, auth *headers.Authorization) error {
+	if auth.Realm != v.Realm {
+		return fmt.Errorf("wrong realm")
+	}
+
+	if !urlMatches(req.URL.String(), auth.URI, req.Method == base.Setup) {
+		return fmt.Errorf("wrong URL")
+	}
+
+	if auth.QOP != nil && (*auth.QOP != "auth" || auth.NC == nil || auth.CNonce == nil) {
+		return fmt.Errorf("unsupported qop: %v", *auth.QOP)
+	}
+
+	method := ValidateMethodDigestMD5
+	if auth.Algorithm != nil && *auth.Algorithm == headers.AuthAlgorithmSHA256 {
+		method = ValidateMethodSHA256
+	}
+
+	ha1, ok := v.ha1(auth.Username, method)
+	if !ok || subtle.ConstantTimeCompare(
+		[]byte(digestResponseHA1(auth, ha1, req.Method)), []byte(auth.Response)) != 1 {
+		return fmt.Errorf("authentication failed")
+	}
+
+	now := v.now()
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.pruneNonces(now)
+
+	n, ok := v.nonces[auth.Nonce]
+	if !ok {
+		return fmt.Errorf("wrong nonce")
+	}
+
+	if auth.Opaque == nil || *auth.Opaque != n.opaque {
+		return fmt.Errorf("wrong opaque")
+	}
+
+	if !now.Before(n.expires) {
+		return ErrStaleNonce
+	}
+
+	if auth.QOP != nil {
+		key := *auth.NC + ":" + *auth.CNonce
+		if _, ok := n.used[key]; ok {
+			return fmt.Errorf("nonce-count and cnonce already used")
+		}
+		n.used[key] = struct{}{}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/headers"
+)
+
+func TestValidator(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		methods []ValidateMethod
+		ha1     bool
+	}{
+		{
+			"basic",
+			[]ValidateMethod{ValidateMethodBasic},
+			false,
+		},
+		{
+			"basic ha1",
+			[]ValidateMethod{ValidateMethodBasic},
+			true,
+		},
+		{
+			"digest md5",
+			[]ValidateMethod{ValidateMethodDigestMD5},
+			false,
+		},
+		{
+			"digest sha256",
+			[]ValidateMethod{ValidateMethodSHA256},
+			false,
+		},
+		{
+			"digest sha256 ha1",
+			[]ValidateMethod{ValidateMethodSHA256},
+			true,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			v := &Validator{
+				Realm:   "myrealm",
+				Methods: ca.methods,
+			}
+
+			if ca.ha1 {
+				v.GetHA1 = func(user string, method ValidateMethod) (string, bool) {
+					if user != "myuser" {
+						return "", false
+					}
+					if method == ValidateMethodSHA256 {
+						return sha256Hex("myuser:myrealm:mypass"), true
+					}
+					return md5Hex("myuser:myrealm:mypass"), true
+				}
+			} else {
+				v.GetPassword = func(user string) (string, bool) {
+					return "mypass", user == "myuser"
+				}
+			}
+
+			wwwAuth, err := v.Challenge(false)
+			require.NoError(t, err)
+
+			for _, pass := range []string{"mypass", "wrongpass"} {
+				se, err := NewSender(wwwAuth, "myuser", pass)
+				require.NoError(t, err)
+
+				req := &base.Request{
+					Method: base.Describe,
+					URL:    mustParseURL("rtsp://myhost/mypath"),
+				}
+				se.AddAuthorization(req)
+
+				err = v.Validate(req)
+				if pass == "mypass" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, "authentication failed")
+				}
+			}
+		})
+	}
+}
+
+func TestValidatorReplay(t *testing.T) {
+	v := &Validator{
+		Realm:   "myrealm",
+		Methods: []ValidateMethod{ValidateMethodDigestMD5},
+		GetPassword: func(_ string) (string, bool) {
+			return "mypass", true
+		},
+	}
+
+	wwwAuth, err := v.Challenge(false)
+	require.NoError(t, err)
+
+	se, err := NewSender(wwwAuth, "myuser", "mypass")
+	require.NoError(t, err)
+
+	req := &base.Request{
+		Method: base.Describe,
+		URL:    mustParseURL("rtsp://myhost/mypath"),
+	}
+	se.AddAuthorization(req)
+
+	err = v.Validate(req)
+	require.NoError(t, err)
+
+	err = v.Validate(req)
+	require.EqualError(t, err, "nonce-count and cnonce already used")
+
+	se.AddAuthorization(req)
+
+	err = v.Validate(req)
+	require.NoError(t, err)
+}
+
+func TestValidatorStaleNonce(t *testing.T) {
+	now := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	v := &Validator{
+		Realm:    "myrealm",
+		Methods:  []ValidateMethod{ValidateMethodDigestMD5},
+		NonceTTL: 10 * time.Second,
+		GetPassword: func(_ string) (string, bool) {
+			return "mypass", true
+		},
+		timeNow: func() time.Time {
+			return now
+		},
+	}
+
+	wwwAuth, err := v.Challenge(false)
+	require.NoError(t, err)
+	require.False(t, IsStale(wwwAuth))
+
+	se, err := NewSender(wwwAuth, "myuser", "mypass")
+	require.NoError(t, err)
+
+	req := &base.Request{
+		Method: base.Describe,
+		URL:    mustParseURL("rtsp://myhost/mypath"),
+	}
+	se.AddAuthorization(req)
+
+	now = now.Add(15 * time.Second)
+
+	err = v.Validate(req)
+	require.Equal(t, ErrStaleNonce, err)
+
+	wwwAuth, err = v.Challenge(true)
+	require.NoError(t, err)
+
+	var h headers.Authenticate
+	err = h.Unmarshal(wwwAuth)
+	require.NoError(t, err)
+	require.Equal(t, "true", *h.Stale)
+	require.True(t, IsStale(wwwAuth))
+
+	// expired nonces are eventually forgotten
+	now = now.Add(15 * time.Second)
+
+	err = v.Validate(req)
+	require.EqualError(t, err, "wrong nonce")
+}
+
+func TestValidatorMaxNonces(t *testing.T) {
+	now := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	v := &Validator{
+		Realm:     "myrealm",
+		Methods:   []ValidateMethod{ValidateMethodDigestMD5},
+		MaxNonces: 2,
+		GetPassword: func(_ string) (string, bool) {
+			return "mypass", true
+		},
+		timeNow: func() time.Time {
+			return now
+		},
+	}
+
+	var reqs []*base.Request
+
+	for i := 0; i < 3; i++ {
+		wwwAuth, err := v.Challenge(false)
+		require.NoError(t, err)
+
+		se, err := NewSender(wwwAuth, "myuser", "mypass")
+		require.NoError(t, err)
+
+		req := &base.Request{
+			Method: base.Describe,
+			URL:    mustParseURL("rtsp://myhost/mypath"),
+		}
+		se.AddAuthorization(req)
+		reqs = append(reqs, req)
+
+		now = now.Add(1 * time.Second)
+	}
+
+	require.Len(t, v.nonces, 2)
+
+	err := v.Validate(reqs[0])
+	require.EqualError(t, err, "wrong nonce")
+
+	err = v.Validate(reqs[1])
+	require.NoError(t, err)
+
+	err = v.Validate(reqs[2])
+	require.NoError(t, err)
+}
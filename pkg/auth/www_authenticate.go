@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"strings"
+
 	"github.com/voicecom/gortsplib/v4/pkg/base"
 	"github.com/voicecom/gortsplib/v4/pkg/headers"
 )
@@ -47,3 +49,19 @@ func GenerateWWWAuthenticate(methods []ValidateMethod, realm string, nonce strin
 
 	return ret
 }
+
+// IsStale checks whether a WWW-Authenticate header contains a digest challenge
+// with stale=true, that is sent by servers when the nonce used by the client
+// has expired but credentials are valid.
+// In this case, the request can be sent again with a Sender built on the new challenge.
+func IsStale(wwwAuth base.HeaderValue) bool {
+	for _, v := range wwwAuth {
+		var h headers.Authenticate
+		err := h.Unmarshal(base.HeaderValue{v})
+		if err == nil && h.Method == headers.AuthMethodDigest &&
+			h.Stale != nil && strings.EqualFold(*h.Stale, "true") {
+			return true
+		}
+	}
+	return false
+}
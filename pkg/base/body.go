@@ -11,9 +11,20 @@ const (
 	rtspMaxContentLength = 128 * 1024
 )
 
+// ErrBodyTooLarge is returned when the body of a message exceeds the maximum size.
+type ErrBodyTooLarge struct {
+	Length    uint64
+	MaxLength int64
+}
+
+// Error implements the error interface.
+func (e ErrBodyTooLarge) Error() string {
+	return fmt.Sprintf("Content-Length exceeds %d (it's %d)", e.MaxLength, e.Length)
+}
+
 type body []byte
 
-func (b *body) unmarshal(header Header, rb *bufio.Reader) error {
+func (b *body) unmarshal(header Header, rb *bufio.Reader, maxLength int64) error {
 	cls, ok := header["Content-Length"]
 	if !ok || len(cls) != 1 {
 		*b = nil
@@ -25,13 +36,12 @@ func (b *body) unmarshal(header Header, rb *bufio.Reader) error {
 		return fmt.Errorf("invalid Content-Length")
 	}
 
-	if cl > rtspMaxContentLength {
-		return fmt.Errorf("Content-Length exceeds %d (it's %d)",
-			rtspMaxContentLength, cl)
+	if cl > uint64(maxLength) {
+		return ErrBodyTooLarge{Length: cl, MaxLength: maxLength}
 	}
 
 	*b = make([]byte, cl)
-	n, err := io.ReadFull(rb, *b)
+	n, err := io.ReadFull(&io.LimitedReader{R: rb, N: maxLength}, *b)
 	if err != nil && n != len(*b) {
 		return err
 	}
@@ -26,13 +26,24 @@ func TestBodyUnmarshal(t *testing.T) {
 	for _, ca := range casesBody {
 		t.Run(ca.name, func(t *testing.T) {
 			var p body
-			err := p.unmarshal(ca.h, bufio.NewReader(bytes.NewReader(ca.byts)))
+			err := p.unmarshal(ca.h, bufio.NewReader(bytes.NewReader(ca.byts)), rtspMaxContentLength)
 			require.NoError(t, err)
 			require.Equal(t, ca.byts, []byte(p))
 		})
 	}
 }
 
+func TestBodyUnmarshalTooLarge(t *testing.T) {
+	var p body
+	err := p.unmarshal(
+		Header{
+			"Content-Length": HeaderValue{"5"},
+		},
+		bufio.NewReader(bytes.NewReader([]byte{0x01, 0x02, 0x03, 0x04, 0x05})),
+		4)
+	require.Equal(t, ErrBodyTooLarge{Length: 5, MaxLength: 4}, err)
+}
+
 func TestBodyMarshal(t *testing.T) {
 	for _, ca := range casesBody {
 		t.Run(ca.name, func(t *testing.T) {
@@ -53,7 +64,8 @@ func FuzzBodyUnmarshal(f *testing.F) {
 			Header{
 				"Content-Length": HeaderValue{a},
 			},
-			bufio.NewReader(bytes.NewReader(b)))
+			bufio.NewReader(bytes.NewReader(b)),
+			rtspMaxContentLength)
 		if err == nil {
 			p.marshal()
 		}
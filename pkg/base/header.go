@@ -146,3 +146,62 @@ func (h Header) marshal() []byte {
 	h.marshalTo(buf)
 	return buf
 }
+
+// find returns the key under which values associated with the given key are stored.
+// Keys of parsed headers are already normalized, while keys of headers
+// that are filled manually can have any case.
+func (h Header) find(key string) (string, bool) {
+	nkey := headerKeyNormalize(key)
+	if _, ok := h[nkey]; ok {
+		return nkey, true
+	}
+
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+
+	return nkey, false
+}
+
+// Get returns the first value associated with the given key.
+// The lookup is case-insensitive.
+// If there are no values associated with the key, Get returns "", false.
+func (h Header) Get(key string) (string, bool) {
+	k, _ := h.find(key)
+	v := h[k]
+	if len(v) == 0 {
+		return "", false
+	}
+	return v[0], true
+}
+
+// GetAll returns all values associated with the given key.
+// The lookup is case-insensitive.
+func (h Header) GetAll(key string) []string {
+	k, _ := h.find(key)
+	return h[k]
+}
+
+// Set sets the header entries associated with key to the single element value.
+// It replaces any existing values associated with key, regardless of their case.
+func (h Header) Set(key, value string) {
+	h.Del(key)
+	h[headerKeyNormalize(key)] = HeaderValue{value}
+}
+
+// Add adds the value to key. It appends to any existing values associated with key.
+func (h Header) Add(key, value string) {
+	k, _ := h.find(key)
+	h[k] = append(h[k], value)
+}
+
+// Del deletes the values associated with key, regardless of their case.
+func (h Header) Del(key string) {
+	for k := range h {
+		if strings.EqualFold(k, key) {
+			delete(h, k)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package base
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+)
+
+// MIME types of the Content-Type header.
+const (
+	ContentTypeSDP        = "application/sdp"
+	ContentTypeParams     = "text/parameters"
+	ContentTypeBinaryData = "application/octet-stream"
+)
+
+// HeaderContentType is a Content-Type header.
+type HeaderContentType struct {
+	// MIME type, in lower case.
+	MIMEType string
+
+	// parameters (i.e. charset).
+	Parameters map[string]string
+}
+
+// ParseHeaderContentType parses a Content-Type header.
+// Invalid parameters are discarded, while the MIME type is kept.
+func ParseHeaderContentType(hv HeaderValue) (*HeaderContentType, error) {
+	if len(hv) == 0 {
+		return nil, fmt.Errorf("value not provided")
+	}
+
+	if len(hv) > 1 {
+		return nil, fmt.Errorf("value provided multiple times (%v)", hv)
+	}
+
+	mimeType, params, err := mime.ParseMediaType(hv[0])
+	if err != nil {
+		// some servers send sloppy parameters, like "application/sdp; charset"
+		if !errors.Is(err, mime.ErrInvalidMediaParameter) {
+			return nil, err
+		}
+		params = map[string]string{}
+	}
+
+	return &HeaderContentType{
+		MIMEType:   mimeType,
+		Parameters: params,
+	}, nil
+}
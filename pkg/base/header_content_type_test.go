@@ -0,0 +1,91 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHeaderContentType(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   HeaderValue
+		h    *HeaderContentType
+	}{
+		{
+			"sdp",
+			HeaderValue{"application/sdp"},
+			&HeaderContentType{
+				MIMEType:   ContentTypeSDP,
+				Parameters: map[string]string{},
+			},
+		},
+		{
+			"sdp with charset",
+			HeaderValue{"application/sdp;charset=UTF-8"},
+			&HeaderContentType{
+				MIMEType:   ContentTypeSDP,
+				Parameters: map[string]string{"charset": "UTF-8"},
+			},
+		},
+		{
+			"sloppy parameter",
+			HeaderValue{"application/sdp; charset"},
+			&HeaderContentType{
+				MIMEType:   ContentTypeSDP,
+				Parameters: map[string]string{},
+			},
+		},
+		{
+			"empty parameter",
+			HeaderValue{"application/sdp;;charset=utf8"},
+			&HeaderContentType{
+				MIMEType:   ContentTypeSDP,
+				Parameters: map[string]string{},
+			},
+		},
+		{
+			"upper case",
+			HeaderValue{"Text/Parameters; charset=utf-8"},
+			&HeaderContentType{
+				MIMEType:   ContentTypeParams,
+				Parameters: map[string]string{"charset": "utf-8"},
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			h, err := ParseHeaderContentType(ca.hv)
+			require.NoError(t, err)
+			require.Equal(t, ca.h, h)
+		})
+	}
+}
+
+func TestParseHeaderContentTypeErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		hv   HeaderValue
+		err  string
+	}{
+		{
+			"empty",
+			HeaderValue{},
+			"value not provided",
+		},
+		{
+			"2 values",
+			HeaderValue{"application/sdp", "application/sdp"},
+			"value provided multiple times ([application/sdp application/sdp])",
+		},
+		{
+			"invalid",
+			HeaderValue{"application/"},
+			"mime: expected token after slash",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			_, err := ParseHeaderContentType(ca.hv)
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
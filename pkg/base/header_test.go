@@ -144,3 +144,53 @@ func FuzzHeaderUnmarshal(f *testing.F) {
 		}
 	})
 }
+
+func TestHeaderAccessors(t *testing.T) {
+	h := make(Header)
+
+	_, ok := h.Get("CSeq")
+	require.Equal(t, false, ok)
+
+	h.Set("cseq", "1")
+	v, ok := h.Get("CSeq")
+	require.Equal(t, true, ok)
+	require.Equal(t, "1", v)
+
+	h.Set("CSeq", "2")
+	require.Equal(t, Header{"CSeq": HeaderValue{"2"}}, h)
+
+	h.Add("www-authenticate", "Basic realm=\"a\"")
+	h.Add("WWW-Authenticate", "Digest realm=\"a\"")
+	require.Equal(t, []string{"Basic realm=\"a\"", "Digest realm=\"a\""}, h.GetAll("www-authenticate"))
+
+	v, ok = h.Get("WWW-Authenticate")
+	require.Equal(t, true, ok)
+	require.Equal(t, "Basic realm=\"a\"", v)
+
+	h.Del("cseq")
+	_, ok = h.Get("CSeq")
+	require.Equal(t, false, ok)
+}
+
+func TestHeaderAccessorsNotNormalized(t *testing.T) {
+	h := Header{
+		"cseq":             HeaderValue{"1"},
+		"Www-Authenticate": HeaderValue{"Basic realm=\"a\""},
+	}
+
+	v, ok := h.Get("CSeq")
+	require.Equal(t, true, ok)
+	require.Equal(t, "1", v)
+
+	h.Add("WWW-Authenticate", "Digest realm=\"a\"")
+	require.Equal(t, []string{"Basic realm=\"a\"", "Digest realm=\"a\""}, h.GetAll("WWW-Authenticate"))
+
+	h.Set("CSEQ", "2")
+	require.Equal(t, Header{
+		"CSeq":             HeaderValue{"2"},
+		"Www-Authenticate": HeaderValue{"Basic realm=\"a\"", "Digest realm=\"a\""},
+	}, h)
+
+	h.Del("www-authenticate")
+	require.Equal(t, Header{"CSeq": HeaderValue{"2"}}, h)
+}
@@ -11,6 +11,17 @@ const (
 	InterleavedFrameMagicByte = 0x24
 )
 
+// ErrPayloadTooLarge is returned when the payload of an interleaved frame exceeds the maximum size.
+type ErrPayloadTooLarge struct {
+	Length    int
+	MaxLength int
+}
+
+// Error implements the error interface.
+func (e ErrPayloadTooLarge) Error() string {
+	return fmt.Sprintf("payload size (%d) is greater than maximum allowed (%d)", e.Length, e.MaxLength)
+}
+
 // InterleavedFrame is an interleaved frame, and allows to transfer binary data
 // within RTSP/TCP connections. It is used to send and receive RTP and RTCP packets with TCP.
 type InterleavedFrame struct {
@@ -23,6 +34,13 @@ type InterleavedFrame struct {
 
 // Unmarshal decodes an interleaved frame.
 func (f *InterleavedFrame) Unmarshal(br *bufio.Reader) error {
+	return f.UnmarshalWithMaxPayloadSize(br, 65535)
+}
+
+// UnmarshalWithMaxPayloadSize decodes an interleaved frame.
+// It returns ErrPayloadTooLarge if the payload is bigger than maxPayloadSize.
+// In this case, the payload is not read.
+func (f *InterleavedFrame) UnmarshalWithMaxPayloadSize(br *bufio.Reader, maxPayloadSize int) error {
 	var header [4]byte
 	_, err := io.ReadFull(br, header[:])
 	if err != nil {
@@ -33,10 +51,13 @@ func (f *InterleavedFrame) Unmarshal(br *bufio.Reader) error {
 		return fmt.Errorf("invalid magic byte (0x%.2x)", header[0])
 	}
 
-	// it's useless to check payloadLen since it's limited to 65535
 	payloadLen := int(uint16(header[2])<<8 | uint16(header[3]))
 
 	f.Channel = int(header[1])
+
+	if payloadLen > maxPayloadSize {
+		return ErrPayloadTooLarge{Length: payloadLen, MaxLength: maxPayloadSize}
+	}
 	f.Payload = make([]byte, payloadLen)
 
 	_, err = io.ReadFull(br, f.Payload)
@@ -44,6 +44,14 @@ func TestInterleavedFrameUnmarshal(t *testing.T) {
 	}
 }
 
+func TestInterleavedFrameUnmarshalMaxPayloadSize(t *testing.T) {
+	var f InterleavedFrame
+	err := f.UnmarshalWithMaxPayloadSize(bufio.NewReader(bytes.NewBuffer(
+		[]byte{0x24, 0x6, 0x0, 0x4, 0x1, 0x2, 0x3, 0x4})), 3)
+	require.Equal(t, ErrPayloadTooLarge{Length: 4, MaxLength: 3}, err)
+	require.Nil(t, f.Payload)
+}
+
 func TestInterleavedFrameMarshal(t *testing.T) {
 	for _, ca := range casesInterleavedFrame {
 		t.Run(ca.name, func(t *testing.T) {
@@ -48,6 +48,13 @@ type Request struct {
 
 // Unmarshal reads a request.
 func (req *Request) Unmarshal(br *bufio.Reader) error {
+	return req.UnmarshalWithMaxBodySize(br, rtspMaxContentLength)
+}
+
+// UnmarshalWithMaxBodySize reads a request.
+// It returns ErrBodyTooLarge if the body is bigger than maxBodySize.
+// In this case, the method, URL and header are filled in anyway.
+func (req *Request) UnmarshalWithMaxBodySize(br *bufio.Reader, maxBodySize int64) error {
 	byts, err := readBytesLimited(br, ' ', requestMaxMethodLength)
 	if err != nil {
 		return err
@@ -95,7 +102,7 @@ func (req *Request) Unmarshal(br *bufio.Reader) error {
 		return err
 	}
 
-	err = (*body)(&req.Body).unmarshal(req.Header, br)
+	err = (*body)(&req.Body).unmarshal(req.Header, br, maxBodySize)
 	if err != nil {
 		return err
 	}
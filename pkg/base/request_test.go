@@ -45,6 +45,21 @@ var casesRequest = []struct {
 			},
 		},
 	},
+	{
+		"describe with ipv6 zone",
+		[]byte("DESCRIBE rtsp://[fe80::1%25eth0]:554/media.mp4 RTSP/1.0\r\n" +
+			"Accept: application/sdp\r\n" +
+			"CSeq: 2\r\n" +
+			"\r\n"),
+		Request{
+			Method: "DESCRIBE",
+			URL:    mustParseURL("rtsp://[fe80::1%25eth0]:554/media.mp4"),
+			Header: Header{
+				"Accept": HeaderValue{"application/sdp"},
+				"CSeq":   HeaderValue{"2"},
+			},
+		},
+	},
 	{
 		"describe with special chars",
 		[]byte("DESCRIBE rtsp://192.168.1.99:554/user=tmp&password=BagRep1!&channel=1&stream=0.sdp RTSP/1.0\r\n" +
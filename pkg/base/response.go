@@ -60,6 +60,26 @@ const (
 	StatusProxyUnavailable                   StatusCode = 553
 )
 
+// IsSuccess checks whether the status code is a success (2xx).
+func (c StatusCode) IsSuccess() bool {
+	return c >= 200 && c <= 299
+}
+
+// IsRedirect checks whether the status code is a redirect (3xx).
+func (c StatusCode) IsRedirect() bool {
+	return c >= 300 && c <= 399
+}
+
+// IsClientError checks whether the status code is a client error (4xx).
+func (c StatusCode) IsClientError() bool {
+	return c >= 400 && c <= 499
+}
+
+// IsServerError checks whether the status code is a server error (5xx).
+func (c StatusCode) IsServerError() bool {
+	return c >= 500 && c <= 599
+}
+
 // StatusMessages contains the status messages associated with each status code.
 var StatusMessages = statusMessages
 
@@ -132,6 +152,26 @@ type Response struct {
 	Body []byte
 }
 
+// IsSuccess checks whether the response status code is a success (2xx).
+func (res Response) IsSuccess() bool {
+	return res.StatusCode.IsSuccess()
+}
+
+// IsRedirect checks whether the response status code is a redirect (3xx).
+func (res Response) IsRedirect() bool {
+	return res.StatusCode.IsRedirect()
+}
+
+// IsClientError checks whether the response status code is a client error (4xx).
+func (res Response) IsClientError() bool {
+	return res.StatusCode.IsClientError()
+}
+
+// IsServerError checks whether the response status code is a server error (5xx).
+func (res Response) IsServerError() bool {
+	return res.StatusCode.IsServerError()
+}
+
 // Unmarshal reads a response.
 func (res *Response) Unmarshal(br *bufio.Reader) error {
 	byts, err := readBytesLimited(br, ' ', 255)
@@ -176,7 +216,7 @@ func (res *Response) Unmarshal(br *bufio.Reader) error {
 		return err
 	}
 
-	err = (*body)(&res.Body).unmarshal(res.Header, br)
+	err = (*body)(&res.Body).unmarshal(res.Header, br, rtspMaxContentLength)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package base
 import (
 	"bufio"
 	"bytes"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -155,6 +156,32 @@ func TestResponseString(t *testing.T) {
 	require.Equal(t, string(byts), res.String())
 }
 
+func TestResponseStatusClass(t *testing.T) {
+	for _, ca := range []struct {
+		code        StatusCode
+		success     bool
+		redirect    bool
+		clientError bool
+		serverError bool
+	}{
+		{StatusContinue, false, false, false, false},
+		{StatusOK, true, false, false, false},
+		{StatusMovedPermanently, false, true, false, false},
+		{StatusNotFound, false, false, true, false},
+		{StatusUnsupportedTransport, false, false, true, false},
+		{StatusInternalServerError, false, false, false, true},
+		{StatusProxyUnavailable, false, false, false, true},
+	} {
+		t.Run(strconv.Itoa(int(ca.code)), func(t *testing.T) {
+			res := Response{StatusCode: ca.code}
+			require.Equal(t, ca.success, res.IsSuccess())
+			require.Equal(t, ca.redirect, res.IsRedirect())
+			require.Equal(t, ca.clientError, res.IsClientError())
+			require.Equal(t, ca.serverError, res.IsServerError())
+		})
+	}
+}
+
 func FuzzResponseUnmarshal(f *testing.F) {
 	for _, ca := range casesResponse {
 		f.Add(ca.byts)
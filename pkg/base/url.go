@@ -2,8 +2,8 @@ package base
 
 import (
 	"fmt"
+	"net"
 	"net/url"
-	"regexp"
 	"strings"
 )
 
@@ -12,19 +12,82 @@ import (
 // control attributes.
 type URL url.URL
 
-var escapeRegexp = regexp.MustCompile(`^(.+?)://(.*?)@(.*?)/(.*?)$`)
+// escapeZone escapes the zone of IPv6 literals, if it is not escaped already.
+// https://github.com/golang/go/issues/30611
+func escapeZone(s string) string {
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return s
+	}
+	start := i + 3
 
-// ParseURL parses a RTSP URL.
-func ParseURL(s string) (*URL, error) {
-	// https://github.com/golang/go/issues/30611
-	m := escapeRegexp.FindStringSubmatch(s)
-	if m != nil {
-		m[3] = strings.ReplaceAll(m[3], "%25", "%")
-		m[3] = strings.ReplaceAll(m[3], "%", "%25")
-		s = m[1] + "://" + m[2] + "@" + m[3] + "/" + m[4]
+	end := strings.IndexAny(s[start:], "/?#")
+	if end < 0 {
+		end = len(s)
+	} else {
+		end += start
+	}
+
+	if i := strings.LastIndex(s[start:end], "@"); i >= 0 {
+		start += i + 1
+	}
+
+	host := s[start:end]
+	if !strings.HasPrefix(host, "[") {
+		return s
+	}
+
+	host = strings.ReplaceAll(host, "%25", "%")
+	host = strings.ReplaceAll(host, "%", "%25")
+	return s[:start] + host + s[end:]
+}
+
+// escapeUserinfo escapes reserved characters of credentials, that are
+// allowed by some cameras but are not valid in URLs (i.e. '@', '/', '#', ' ').
+// The last '@' before the query is considered the end of credentials.
+func escapeUserinfo(s string) (string, bool) {
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return "", false
+	}
+	start := i + 3
+
+	// URL has credentials only if the authority, delimited in the standard way,
+	// contains a '@'.
+	authEnd := strings.IndexAny(s[start:], "/?#")
+	if authEnd < 0 {
+		authEnd = len(s) - start
+	}
+	if !strings.Contains(s[start:start+authEnd], "@") {
+		return "", false
+	}
+
+	queryStart := strings.Index(s[start:], "?")
+	if queryStart < 0 {
+		queryStart = len(s) - start
+	}
+
+	end := start + strings.LastIndex(s[start:start+queryStart], "@")
+
+	unescape := func(v string) string {
+		if v2, err := url.PathUnescape(v); err == nil {
+			return v2
+		}
+		return v
+	}
+
+	var user *url.Userinfo
+	if username, password, ok := strings.Cut(s[start:end], ":"); ok {
+		user = url.UserPassword(unescape(username), unescape(password))
+	} else {
+		user = url.User(unescape(username))
 	}
 
-	u, err := url.Parse(s)
+	return s[:start] + user.String() + s[end:], true
+}
+
+func parseURL(s string) (*URL, error) {
+	u, err := url.Parse(escapeZone(s))
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +107,19 @@ func ParseURL(s string) (*URL, error) {
 	return (*URL)(u), nil
 }
 
+// ParseURL parses a RTSP URL.
+// IPv6 literals can contain a zone, either escaped (%25) or not.
+// Credentials can be either escaped or not; the last '@' before the query
+// is considered the end of credentials, therefore paths of URLs with credentials
+// can't contain '@'.
+func ParseURL(s string) (*URL, error) {
+	if s2, ok := escapeUserinfo(s); ok {
+		s = s2
+	}
+
+	return parseURL(s)
+}
+
 // String implements fmt.Stringer.
 func (u *URL) String() string {
 	return (*url.URL)(u).String()
@@ -74,6 +150,29 @@ func (u *URL) CloneWithoutCredentials() *URL {
 	})
 }
 
+// WithCredentials returns a copy of the URL with the given credentials.
+func (u *URL) WithCredentials(user string, password string) *URL {
+	ret := u.Clone()
+	ret.User = url.UserPassword(user, password)
+	return ret
+}
+
+// WithoutCredentials returns a copy of the URL without credentials.
+func (u *URL) WithoutCredentials() *URL {
+	return u.CloneWithoutCredentials()
+}
+
+// WithPath returns a copy of the URL with the given path.
+func (u *URL) WithPath(path string) *URL {
+	ret := u.Clone()
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	ret.Path = path
+	ret.RawPath = ""
+	return ret
+}
+
 // RTSPPathAndQuery returns the path and query of a RTSP URL.
 //
 // Deprecated: not useful anymore.
@@ -105,3 +204,19 @@ func (u *URL) Hostname() string {
 func (u *URL) Port() string {
 	return (*url.URL)(u).Port()
 }
+
+// HostWithDefaultPort returns u.Host in the host:port form, filling in the
+// default port of the scheme (554 for rtsp, 322 for rtsps) when u.Host
+// doesn't contain one. IPv6 literals are returned in square brackets.
+func (u *URL) HostWithDefaultPort() string {
+	port := u.Port()
+	if port == "" {
+		if u.Scheme == "rtsps" {
+			port = "322"
+		} else {
+			port = "554"
+		}
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -14,6 +14,14 @@ const (
 
 // Conn is a RTSP connection.
 type Conn struct {
+	// maximum size of request bodies.
+	// It defaults to 128 KiB.
+	MaxBodySize int64
+
+	// maximum size of interleaved frame payloads.
+	// It defaults to 65535.
+	MaxPayloadSize int
+
 	w  io.Writer
 	br *bufio.Reader
 
@@ -58,7 +66,12 @@ func (c *Conn) Read() (interface{}, error) {
 // ReadRequest reads a Request.
 func (c *Conn) ReadRequest() (*base.Request, error) {
 	var req base.Request
-	err := req.Unmarshal(c.br)
+	var err error
+	if c.MaxBodySize != 0 {
+		err = req.UnmarshalWithMaxBodySize(c.br, c.MaxBodySize)
+	} else {
+		err = req.Unmarshal(c.br)
+	}
 	return &req, err
 }
 
@@ -71,7 +84,12 @@ func (c *Conn) ReadResponse() (*base.Response, error) {
 
 // ReadInterleavedFrame reads a InterleavedFrame.
 func (c *Conn) ReadInterleavedFrame() (*base.InterleavedFrame, error) {
-	err := c.fr.Unmarshal(c.br)
+	var err error
+	if c.MaxPayloadSize != 0 {
+		err = c.fr.UnmarshalWithMaxPayloadSize(c.br, c.MaxPayloadSize)
+	} else {
+		err = c.fr.Unmarshal(c.br)
+	}
 	return &c.fr, err
 }
 
@@ -83,6 +83,14 @@ func TestReadError(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestReadInterleavedFrameMaxPayloadSize(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{0x24, 0x6, 0x0, 0x4, 0x1, 0x2, 0x3, 0x4})
+	conn := NewConn(buf)
+	conn.MaxPayloadSize = 3
+	_, err := conn.Read()
+	require.Equal(t, base.ErrPayloadTooLarge{Length: 4, MaxLength: 3}, err)
+}
+
 func TestWriteRequest(t *testing.T) {
 	var buf bytes.Buffer
 	conn := NewConn(&buf)
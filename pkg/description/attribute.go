@@ -0,0 +1,95 @@
+package description
+
+import (
+	psdp "github.com/pion/sdp/v3"
+)
+
+// Attribute is a SDP attribute that is not decoded into other fields.
+type Attribute struct {
+	Key   string
+	Value string
+
+	// key of the closest decoded attribute that precedes this one (optional).
+	// It is used to encode the attribute in its original position.
+	// When empty, the attribute is encoded before decoded attributes.
+	After string
+}
+
+func isSuppressed(key string, suppressed []string) bool {
+	for _, k := range suppressed {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func unmarshalAttributes(attributes []psdp.Attribute, isDecoded func(psdp.Attribute) bool) []Attribute {
+	var ret []Attribute
+	after := ""
+
+	for _, attr := range attributes {
+		if isDecoded(attr) {
+			after = attr.Key
+			continue
+		}
+
+		ret = append(ret, Attribute{
+			Key:   attr.Key,
+			Value: attr.Value,
+			After: after,
+		})
+	}
+
+	return ret
+}
+
+// marshalAttributes inserts attributes into decoded attributes, after the last
+// decoded attribute with key equal to After.
+func marshalAttributes(
+	decoded []psdp.Attribute,
+	attributes []Attribute,
+	suppressed []string,
+) []psdp.Attribute {
+	if len(attributes) == 0 {
+		return decoded
+	}
+
+	lastPos := make(map[string]int)
+	for i, attr := range decoded {
+		lastPos[attr.Key] = i
+	}
+
+	var ret []psdp.Attribute
+	done := make([]bool, len(attributes))
+
+	appendAfter := func(after string) {
+		for i, attr := range attributes {
+			if !done[i] && attr.After == after {
+				done[i] = true
+				if !isSuppressed(attr.Key, suppressed) {
+					ret = append(ret, psdp.Attribute{Key: attr.Key, Value: attr.Value})
+				}
+			}
+		}
+	}
+
+	appendAfter("")
+
+	for i, attr := range decoded {
+		ret = append(ret, attr)
+
+		if lastPos[attr.Key] == i {
+			appendAfter(attr.Key)
+		}
+	}
+
+	// attributes whose preceding attribute is not present anymore
+	for i, attr := range attributes {
+		if !done[i] && !isSuppressed(attr.Key, suppressed) {
+			ret = append(ret, psdp.Attribute{Key: attr.Key, Value: attr.Value})
+		}
+	}
+
+	return ret
+}
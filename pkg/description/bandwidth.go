@@ -0,0 +1,60 @@
+package description
+
+import (
+	"strings"
+
+	psdp "github.com/pion/sdp/v3"
+)
+
+// BandwidthType is the type of a bandwidth.
+type BandwidthType string
+
+// bandwidth types.
+const (
+	// application-specific maximum, in kilobits per second.
+	BandwidthTypeAS BandwidthType = "AS"
+
+	// transport independent application-specific maximum, in bits per second (RFC3890).
+	BandwidthTypeTIAS BandwidthType = "TIAS"
+)
+
+// Bandwidth is a bandwidth (b=) line.
+type Bandwidth struct {
+	Type  BandwidthType
+	Value uint64
+}
+
+func unmarshalBandwidths(in []psdp.Bandwidth) []Bandwidth {
+	var ret []Bandwidth
+
+	for _, b := range in {
+		typ := b.Type
+		if b.Experimental {
+			typ = "X-" + typ
+		}
+
+		ret = append(ret, Bandwidth{
+			Type:  BandwidthType(typ),
+			Value: b.Bandwidth,
+		})
+	}
+
+	return ret
+}
+
+func marshalBandwidths(in []Bandwidth) []psdp.Bandwidth {
+	var ret []psdp.Bandwidth
+
+	for _, b := range in {
+		typ := string(b.Type)
+		experimental := strings.HasPrefix(typ, "X-")
+
+		ret = append(ret, psdp.Bandwidth{
+			Experimental: experimental,
+			Type:         strings.TrimPrefix(typ, "X-"),
+			Bandwidth:    b.Value,
+		})
+	}
+
+	return ret
+}
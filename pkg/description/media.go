@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	psdp "github.com/pion/sdp/v3"
@@ -45,13 +45,15 @@ func getAttribute(attributes []psdp.Attribute, key string) string {
 	return ""
 }
 
-func isBackChannel(attributes []psdp.Attribute) bool {
+func getDirection(attributes []psdp.Attribute) MediaDirection {
 	for _, attr := range attributes {
-		if attr.Key == "sendonly" {
-			return true
+		switch MediaDirection(attr.Key) {
+		case MediaDirectionSendOnly, MediaDirectionRecvOnly,
+			MediaDirectionSendRecv, MediaDirectionInactive:
+			return MediaDirection(attr.Key)
 		}
 	}
-	return false
+	return MediaDirectionUnset
 }
 
 func getFormatAttribute(attributes []psdp.Attribute, payloadType uint8, key string) string {
@@ -68,49 +70,210 @@ func getFormatAttribute(attributes []psdp.Attribute, payloadType uint8, key stri
 	return ""
 }
 
-func decodeFMTP(enc string) map[string]string {
-	if enc == "" {
-		return nil
+// attributes that start with a number but are not bound to formats,
+// or that are decoded separately.
+var mediaLevelAttributes = map[string]struct{}{
+	"rtpmap":     {},
+	"fmtp":       {},
+	"rtcp-fb":    {},
+	"rtcp":       {},
+	"extmap":     {},
+	"ssrc":       {},
+	"ssrc-group": {},
+	"candidate":  {},
+	"crypto":     {},
+	"rid":        {},
+	"label":      {},
+	"sctpmap":    {},
+}
+
+func getGenericAttributes(attributes []psdp.Attribute, payloadType uint8) []format.GenericAttribute {
+	var ret []format.GenericAttribute
+	pt := strconv.FormatUint(uint64(payloadType), 10)
+
+	for _, attr := range attributes {
+		if _, ok := mediaLevelAttributes[attr.Key]; ok {
+			continue
+		}
+
+		v := strings.TrimSpace(attr.Value)
+		if v == pt {
+			ret = append(ret, format.GenericAttribute{Key: attr.Key})
+		} else if strings.HasPrefix(v, pt+" ") {
+			ret = append(ret, format.GenericAttribute{Key: attr.Key, Value: strings.TrimSpace(v[len(pt)+1:])})
+		}
 	}
 
-	ret := make(map[string]string)
+	return ret
+}
 
-	for _, kv := range strings.Split(enc, ";") {
-		kv = strings.Trim(kv, " ")
+func isAlphaNumeric(v string) bool {
+	for _, r := range v {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
+			return false
+		}
+	}
+	return true
+}
 
-		if len(kv) == 0 {
+// associateRED links T.140 formats to the RED format that carries them.
+func associateRED(formats []format.Format) {
+	for _, forma := range formats {
+		red, ok := forma.(*format.RED)
+		if !ok || len(red.PayloadTypes) == 0 {
 			continue
 		}
 
-		tmp := strings.SplitN(kv, "=", 2)
-		if len(tmp) != 2 {
+		for _, forma2 := range formats {
+			if t140, ok2 := forma2.(*format.T140); ok2 && t140.PayloadTyp == red.PayloadTypes[0] {
+				v := red.PayloadTyp
+				t140.REDPayloadType = &v
+			}
+		}
+	}
+}
+
+func parsePacketTime(v string) (time.Duration, error) {
+	tmp, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || tmp <= 0 {
+		return 0, fmt.Errorf("invalid packet time: %v", v)
+	}
+	return time.Duration(tmp * float64(time.Millisecond)), nil
+}
+
+func marshalPacketTime(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+}
+
+// packetTimes returns pointers to the fields of a format that are filled
+// with the ptime and maxptime attributes.
+func packetTimes(forma format.Format) (*time.Duration, *time.Duration) {
+	switch tforma := forma.(type) {
+	case *format.LPCM:
+		return &tforma.PacketDuration, &tforma.MaxPacketDuration
+
+	case *format.G711:
+		return &tforma.PacketDuration, &tforma.MaxPacketDuration
+
+	case *format.G722:
+		return &tforma.PacketDuration, &tforma.MaxPacketDuration
+
+	case *format.Opus:
+		return &tforma.PreferredPacketDuration, &tforma.MaxPacketDuration
+	}
+
+	return nil, nil
+}
+
+// packetTimeIsValid checks whether a packet duration can be used by a format.
+// Opus packets last between 2.5ms and 120ms.
+func packetTimeIsValid(forma format.Format, d time.Duration) bool {
+	if _, ok := forma.(*format.Opus); ok {
+		return d >= 2500*time.Microsecond && d <= 120*time.Millisecond
+	}
+	return true
+}
+
+// applyPacketTimes fills packet durations of Opus, LPCM, G711 and G722 formats
+// with the ptime and maxptime attributes of the media.
+// Invalid attributes are ignored, since they are not essential to read the stream.
+func applyPacketTimes(formats []format.Format, attributes []psdp.Attribute) {
+	for _, forma := range formats {
+		packetDuration, maxPacketDuration := packetTimes(forma)
+		if packetDuration == nil {
 			continue
 		}
 
-		ret[strings.ToLower(tmp[0])] = tmp[1]
+		if v := getAttribute(attributes, "ptime"); v != "" {
+			d, err := parsePacketTime(v)
+			if err == nil && packetTimeIsValid(forma, d) {
+				*packetDuration = d
+			}
+		}
+
+		if v := getAttribute(attributes, "maxptime"); v != "" {
+			d, err := parsePacketTime(v)
+			if err == nil && packetTimeIsValid(forma, d) {
+				*maxPacketDuration = d
+			}
+		}
+	}
+}
+
+func parseFrameSize(v string, sep string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(v), sep)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid frame size: %v", v)
 	}
 
-	return ret
+	width, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil || width == 0 {
+		return 0, 0, fmt.Errorf("invalid frame size: %v", v)
+	}
+
+	height, err := strconv.ParseUint(parts[1], 10, 16)
+	if err != nil || height == 0 {
+		return 0, 0, fmt.Errorf("invalid frame size: %v", v)
+	}
+
+	return int(width), int(height), nil
 }
 
-func sortedKeys(fmtp map[string]string) []string {
-	keys := make([]string, len(fmtp))
-	i := 0
-	for key := range fmtp {
-		keys[i] = key
-		i++
+// applyFrameSizes fills the image size of M-JPEG formats
+// with the framesize or x-dimensions attributes of the media.
+func applyFrameSizes(formats []format.Format, attributes []psdp.Attribute) error {
+	for _, forma := range formats {
+		mjpeg, ok := forma.(*format.MJPEG)
+		if !ok {
+			continue
+		}
+
+		if v := getFormatAttribute(attributes, mjpeg.PayloadType(), "framesize"); v != "" {
+			width, height, err := parseFrameSize(v, "-")
+			if err != nil {
+				return err
+			}
+			mjpeg.Width, mjpeg.Height = width, height
+		} else if v := getAttribute(attributes, "x-dimensions"); v != "" {
+			width, height, err := parseFrameSize(v, ",")
+			if err != nil {
+				return err
+			}
+			mjpeg.Width, mjpeg.Height = width, height
+		}
 	}
-	sort.Strings(keys)
-	return keys
+
+	return nil
 }
 
-func isAlphaNumeric(v string) bool {
-	for _, r := range v {
-		if !unicode.IsLetter(r) && !unicode.IsNumber(r) {
-			return false
+// getRTCPFeedback returns the RTCP feedback capabilities of formats,
+// indexed by payload type. Wildcard entries are applied to every format.
+func getRTCPFeedback(attributes []psdp.Attribute, formats []format.Format) map[uint8][]string {
+	var ret map[uint8][]string
+
+	for _, attr := range attributes {
+		if attr.Key != "rtcp-fb" {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSpace(attr.Value), " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		fb := strings.TrimSpace(parts[1])
+
+		for _, forma := range formats {
+			if parts[0] == "*" || parts[0] == strconv.FormatUint(uint64(forma.PayloadType()), 10) {
+				if ret == nil {
+					ret = make(map[uint8][]string)
+				}
+				ret[forma.PayloadType()] = append(ret[forma.PayloadType()], fb)
+			}
 		}
 	}
-	return true
+
+	return ret
 }
 
 // MediaType is the type of a media stream.
@@ -121,6 +284,19 @@ const (
 	MediaTypeVideo       MediaType = "video"
 	MediaTypeAudio       MediaType = "audio"
 	MediaTypeApplication MediaType = "application"
+	MediaTypeText        MediaType = "text"
+)
+
+// MediaDirection is the direction of a media stream.
+type MediaDirection string
+
+// media directions.
+const (
+	MediaDirectionUnset    MediaDirection = ""
+	MediaDirectionSendOnly MediaDirection = "sendonly"
+	MediaDirectionRecvOnly MediaDirection = "recvonly"
+	MediaDirectionSendRecv MediaDirection = "sendrecv"
+	MediaDirectionInactive MediaDirection = "inactive"
 )
 
 // Media is a media stream.
@@ -135,11 +311,26 @@ type Media struct {
 	// Whether this media is a back channel.
 	IsBackChannel bool
 
+	// Direction of the media (optional).
+	// When it is set, it takes precedence over IsBackChannel in SDP.
+	Direction MediaDirection
+
 	// Control attribute.
 	Control string
 
 	// Formats contained into the media.
 	Formats []format.Format
+
+	// RTCP feedback capabilities of formats (optional),
+	// indexed by payload type (i.e. "nack", "nack pli", "ccm fir").
+	RTCPFeedback map[uint8][]string
+
+	// bandwidths of the media (optional).
+	Bandwidths []Bandwidth
+
+	// attributes that are not decoded into other fields (optional).
+	// They are encoded again by Marshal().
+	Attributes []Attribute
 }
 
 // Unmarshal decodes the media from the SDP format.
@@ -151,7 +342,8 @@ func (m *Media) Unmarshal(md *psdp.MediaDescription) error {
 		return fmt.Errorf("invalid mid: %v", m.ID)
 	}
 
-	m.IsBackChannel = isBackChannel(md.Attributes)
+	m.Direction = getDirection(md.Attributes)
+	m.IsBackChannel = (m.Direction == MediaDirectionSendOnly)
 	m.Control = getAttribute(md.Attributes, "control")
 
 	m.Formats = nil
@@ -165,30 +357,98 @@ func (m *Media) Unmarshal(md *psdp.MediaDescription) error {
 		payloadTypeInt := uint8(tmp)
 
 		rtpMap := getFormatAttribute(md.Attributes, payloadTypeInt, "rtpmap")
-		fmtp := decodeFMTP(getFormatAttribute(md.Attributes, payloadTypeInt, "fmtp"))
+		fmtpRaw := getFormatAttribute(md.Attributes, payloadTypeInt, "fmtp")
 
-		format, err := format.Unmarshal(string(m.Type), payloadTypeInt, rtpMap, fmtp)
+		forma, err := format.Unmarshal(string(m.Type), payloadTypeInt, rtpMap, format.UnmarshalFMTP(fmtpRaw))
 		if err != nil {
 			return err
 		}
 
-		m.Formats = append(m.Formats, format)
+		if generic, ok := forma.(*format.Generic); ok {
+			generic.FMTPRaw = fmtpRaw
+			generic.Attributes = getGenericAttributes(md.Attributes, payloadTypeInt)
+		}
+
+		m.Formats = append(m.Formats, forma)
 	}
 
 	if m.Formats == nil {
 		return fmt.Errorf("no formats found")
 	}
 
+	associateRED(m.Formats)
+
+	m.RTCPFeedback = getRTCPFeedback(md.Attributes, m.Formats)
+
+	applyPacketTimes(m.Formats, md.Attributes)
+
+	err := applyFrameSizes(m.Formats, md.Attributes)
+	if err != nil {
+		return err
+	}
+
+	m.Bandwidths = unmarshalBandwidths(md.Bandwidth)
+	m.Attributes = unmarshalAttributes(md.Attributes, m.isDecodedAttribute)
+
 	return nil
 }
 
+// isDecodedAttribute checks whether an attribute has been decoded
+// into other fields, or is generated again by Marshal().
+func (m *Media) isDecodedAttribute(attr psdp.Attribute) bool {
+	switch attr.Key {
+	case "mid", "control", "rtpmap", "fmtp", "rtcp-fb":
+		return true
+
+	case string(MediaDirectionSendOnly), string(MediaDirectionRecvOnly),
+		string(MediaDirectionSendRecv), string(MediaDirectionInactive):
+		return true
+
+	case "ptime", "maxptime":
+		for _, forma := range m.Formats {
+			if packetDuration, _ := packetTimes(forma); packetDuration != nil {
+				return true
+			}
+		}
+
+	case "framesize", "x-dimensions":
+		for _, forma := range m.Formats {
+			if _, ok := forma.(*format.MJPEG); ok {
+				return true
+			}
+		}
+	}
+
+	if _, ok := mediaLevelAttributes[attr.Key]; ok {
+		return false
+	}
+
+	v := strings.TrimSpace(attr.Value)
+
+	for _, forma := range m.Formats {
+		if _, ok := forma.(*format.Generic); ok {
+			pt := strconv.FormatUint(uint64(forma.PayloadType()), 10)
+			if v == pt || strings.HasPrefix(v, pt+" ") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // Marshal encodes the media in SDP format.
 func (m Media) Marshal() *psdp.MediaDescription {
+	return m.marshal(nil)
+}
+
+func (m Media) marshal(suppressedAttributes []string) *psdp.MediaDescription {
 	md := &psdp.MediaDescription{
 		MediaName: psdp.MediaName{
 			Media:  string(m.Type),
 			Protos: []string{"RTP", "AVP"},
 		},
+		Bandwidth: marshalBandwidths(m.Bandwidths),
 	}
 
 	if m.ID != "" {
@@ -198,9 +458,15 @@ func (m Media) Marshal() *psdp.MediaDescription {
 		})
 	}
 
-	if m.IsBackChannel {
+	switch {
+	case m.Direction != MediaDirectionUnset:
 		md.Attributes = append(md.Attributes, psdp.Attribute{
-			Key: "sendonly",
+			Key: string(m.Direction),
+		})
+
+	case m.IsBackChannel:
+		md.Attributes = append(md.Attributes, psdp.Attribute{
+			Key: string(MediaDirectionSendOnly),
 		})
 	}
 
@@ -221,20 +487,78 @@ func (m Media) Marshal() *psdp.MediaDescription {
 			})
 		}
 
+		generic, isGeneric := forma.(*format.Generic)
+
 		fmtp := forma.FMTP()
-		if len(fmtp) != 0 {
-			tmp := make([]string, len(fmtp))
-			for i, key := range sortedKeys(fmtp) {
-				tmp[i] = key + "=" + fmtp[key]
+		if isGeneric && generic.FMTPRaw != "" {
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "fmtp",
+				Value: typ + " " + generic.FMTPRaw,
+			})
+		} else if len(fmtp) != 0 {
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "fmtp",
+				Value: typ + " " + format.MarshalFMTP(fmtp),
+			})
+		}
+
+		for _, fb := range m.RTCPFeedback[forma.PayloadType()] {
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "rtcp-fb",
+				Value: typ + " " + fb,
+			})
+		}
+
+		if mjpeg, ok := forma.(*format.MJPEG); ok && mjpeg.Width != 0 && mjpeg.Height != 0 {
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "framesize",
+				Value: typ + " " + strconv.Itoa(mjpeg.Width) + "-" + strconv.Itoa(mjpeg.Height),
+			})
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "x-dimensions",
+				Value: strconv.Itoa(mjpeg.Width) + "," + strconv.Itoa(mjpeg.Height),
+			})
+		}
+
+		if isGeneric {
+			for _, attr := range generic.Attributes {
+				v := typ
+				if attr.Value != "" {
+					v += " " + attr.Value
+				}
+
+				md.Attributes = append(md.Attributes, psdp.Attribute{
+					Key:   attr.Key,
+					Value: v,
+				})
 			}
+		}
+	}
+
+	for _, forma := range m.Formats {
+		packetDuration, maxPacketDuration := packetTimes(forma)
+		if packetDuration == nil {
+			continue
+		}
 
+		if *packetDuration != 0 {
 			md.Attributes = append(md.Attributes, psdp.Attribute{
-				Key:   "fmtp",
-				Value: typ + " " + strings.Join(tmp, "; "),
+				Key:   "ptime",
+				Value: marshalPacketTime(*packetDuration),
 			})
 		}
+
+		if *maxPacketDuration != 0 {
+			md.Attributes = append(md.Attributes, psdp.Attribute{
+				Key:   "maxptime",
+				Value: marshalPacketTime(*maxPacketDuration),
+			})
+		}
+		break
 	}
 
+	md.Attributes = marshalAttributes(md.Attributes, m.Attributes, suppressedAttributes)
+
 	return md
 }
 
@@ -276,6 +600,54 @@ func (m Media) URL(contentBase *base.URL) (*base.URL, error) {
 	return ur, nil
 }
 
+// HasRTCPFeedback checks whether a format supports a certain RTCP feedback (i.e. "nack pli").
+func (m Media) HasRTCPFeedback(payloadType uint8, feedback string) bool {
+	for _, fb := range m.RTCPFeedback[payloadType] {
+		if fb == feedback {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatByPayloadType returns the format with the given payload type.
+func (m Media) FormatByPayloadType(payloadType uint8) (format.Format, bool) {
+	for _, forma := range m.Formats {
+		if forma.PayloadType() == payloadType {
+			return forma, true
+		}
+	}
+	return nil, false
+}
+
+// PayloadTypes returns the payload types of all the formats in the media,
+// in the same order in which they appear in the SDP (that is, by priority).
+func (m Media) PayloadTypes() []uint8 {
+	ret := make([]uint8, len(m.Formats))
+	for i, forma := range m.Formats {
+		ret[i] = forma.PayloadType()
+	}
+	return ret
+}
+
+// PreferredFormat returns the format with the highest priority, that is the first one.
+func (m Media) PreferredFormat() (format.Format, bool) {
+	if len(m.Formats) == 0 {
+		return nil, false
+	}
+	return m.Formats[0], true
+}
+
+// ClockRate returns the clock rate of the preferred format.
+// It returns zero when the media has no formats.
+func (m Media) ClockRate() int {
+	forma, ok := m.PreferredFormat()
+	if !ok {
+		return 0
+	}
+	return forma.ClockRate()
+}
+
 // FindFormat finds a certain format among all the formats in the media.
 func (m Media) FindFormat(forma interface{}) bool {
 	for _, formak := range m.Formats {
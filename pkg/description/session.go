@@ -2,11 +2,13 @@ package description
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	psdp "github.com/pion/sdp/v3"
 
 	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/format"
 	"github.com/voicecom/gortsplib/v4/pkg/sdp"
 )
 
@@ -40,6 +42,27 @@ func hasMediaWithID(medias []*Media, id string) bool {
 // SessionFECGroup is a FEC group.
 type SessionFECGroup []string
 
+// SessionOrigin is the origin of a session (o= line).
+type SessionOrigin struct {
+	// session ID.
+	SessionID uint64
+
+	// session version.
+	// It should be increased every time the session description changes.
+	SessionVersion uint64
+
+	// unicast address of the host that created the session.
+	// When empty, 127.0.0.1 is used.
+	Address string
+}
+
+func addressType(address string) string {
+	if strings.Contains(address, ":") {
+		return "IP6"
+	}
+	return "IP4"
+}
+
 // Session is the description of a RTSP stream.
 type Session struct {
 	// Base URL of the stream (read only).
@@ -53,6 +76,27 @@ type Session struct {
 
 	// Media streams.
 	Medias []*Media
+
+	// bandwidths of the session (optional).
+	Bandwidths []Bandwidth
+
+	// attributes that are not decoded into other fields (optional).
+	// They are encoded again by Marshal().
+	Attributes []Attribute
+
+	// keys of attributes of the session and of medias
+	// that must not be encoded by Marshal() (optional).
+	SuppressedAttributes []string
+
+	// origin of the session, encoded by Marshal() (optional).
+	// When nil, a default origin is used.
+	Origin *SessionOrigin
+
+	// address of the connection line, encoded by Marshal() (optional).
+	// When empty, 0.0.0.0 is used.
+	// It is ignored when Marshal() is called with multicast = true,
+	// since 224.1.0.0 is used in that case.
+	ConnectionAddress string
 }
 
 // FindFormat finds a certain format among all the formats in all the medias of the stream.
@@ -67,6 +111,61 @@ func (d *Session) FindFormat(forma interface{}) *Media {
 	return nil
 }
 
+// FindFormats finds all the formats of a certain type in all the medias of the stream.
+// forma must be a pointer to a slice of formats, i.e. *[]*format.H264.
+// Found formats are inserted into forma, and their medias are returned
+// in the same order.
+func (d *Session) FindFormats(forma interface{}) []*Media {
+	out := reflect.ValueOf(forma).Elem()
+	typ := out.Type().Elem()
+	out.Set(reflect.Zero(out.Type()))
+
+	var medias []*Media
+
+	for _, media := range d.Medias {
+		for _, formak := range media.Formats {
+			if reflect.TypeOf(formak) == typ {
+				out.Set(reflect.Append(out, reflect.ValueOf(formak)))
+				medias = append(medias, media)
+			}
+		}
+	}
+
+	return medias
+}
+
+// FindMediasByType returns all the medias of a certain type.
+func (d *Session) FindMediasByType(typ MediaType) []*Media {
+	var ret []*Media
+	for _, media := range d.Medias {
+		if media.Type == typ {
+			ret = append(ret, media)
+		}
+	}
+	return ret
+}
+
+// FindMediaByControl returns the media with the given control attribute.
+func (d *Session) FindMediaByControl(control string) *Media {
+	for _, media := range d.Medias {
+		if media.Control == control {
+			return media
+		}
+	}
+	return nil
+}
+
+// FormatByPayloadType returns the format with the given payload type, and its media.
+// If several medias contain a format with the same payload type, the first one is returned.
+func (d *Session) FormatByPayloadType(payloadType uint8) (*Media, format.Format, bool) {
+	for _, media := range d.Medias {
+		if forma, ok := media.FormatByPayloadType(payloadType); ok {
+			return media, forma, true
+		}
+	}
+	return nil, nil, false
+}
+
 // Unmarshal decodes the description from SDP.
 func (d *Session) Unmarshal(ssd *sdp.SessionDescription) error {
 	d.Title = string(ssd.SessionName)
@@ -108,6 +207,12 @@ func (d *Session) Unmarshal(ssd *sdp.SessionDescription) error {
 		}
 	}
 
+	d.Bandwidths = unmarshalBandwidths(ssd.Bandwidth)
+
+	d.Attributes = unmarshalAttributes(ssd.Attributes, func(attr psdp.Attribute) bool {
+		return attr.Key == "group" && strings.HasPrefix(attr.Value, "FEC ")
+	})
+
 	return nil
 }
 
@@ -123,26 +228,41 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 	}
 
 	var address string
-	if multicast {
+	switch {
+	case multicast:
 		address = "224.1.0.0"
-	} else {
+	case d.ConnectionAddress != "":
+		address = d.ConnectionAddress
+	default:
 		address = "0.0.0.0"
 	}
 
+	origin := psdp.Origin{
+		Username:       "-",
+		NetworkType:    "IN",
+		AddressType:    "IP4",
+		UnicastAddress: "127.0.0.1",
+	}
+
+	if d.Origin != nil {
+		origin.SessionID = d.Origin.SessionID
+		origin.SessionVersion = d.Origin.SessionVersion
+		if d.Origin.Address != "" {
+			origin.AddressType = addressType(d.Origin.Address)
+			origin.UnicastAddress = d.Origin.Address
+		}
+	}
+
 	sout := &sdp.SessionDescription{
 		SessionName: sessionName,
-		Origin: psdp.Origin{
-			Username:       "-",
-			NetworkType:    "IN",
-			AddressType:    "IP4",
-			UnicastAddress: "127.0.0.1",
-		},
+		Origin:      origin,
 		// required by Darwin Sessioning Server
 		ConnectionInformation: &psdp.ConnectionInformation{
 			NetworkType: "IN",
-			AddressType: "IP4",
+			AddressType: addressType(address),
 			Address:     &psdp.Address{Address: address},
 		},
+		Bandwidth: marshalBandwidths(d.Bandwidths),
 		TimeDescriptions: []psdp.TimeDescription{
 			{Timing: psdp.Timing{StartTime: 0, StopTime: 0}},
 		},
@@ -150,7 +270,7 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 	}
 
 	for i, media := range d.Medias {
-		sout.MediaDescriptions[i] = media.Marshal()
+		sout.MediaDescriptions[i] = media.marshal(d.SuppressedAttributes)
 	}
 
 	for _, group := range d.FECGroups {
@@ -160,5 +280,7 @@ func (d Session) Marshal(multicast bool) ([]byte, error) {
 		})
 	}
 
+	sout.Attributes = marshalAttributes(sout.Attributes, d.Attributes, d.SuppressedAttributes)
+
 	return sout.Marshal()
 }
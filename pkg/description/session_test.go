@@ -2,6 +2,7 @@ package description
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -9,6 +10,14 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/sdp"
 )
 
+func uint8Ptr(v uint8) *uint8 {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
 var casesSession = []struct {
 	name string
 	in   string
@@ -45,15 +54,25 @@ var casesSession = []struct {
 			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
 			"s=Media Presentation\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
+			"b=AS:2632\r\n" +
 			"t=0 0\r\n" +
+			"a=control:rtsp://10.0.100.50/profile5/media.smp\r\n" +
+			"a=range:npt=now-\r\n" +
 			"m=video 0 RTP/AVP 97\r\n" +
+			"b=AS:2560\r\n" +
 			"a=control:rtsp://10.0.100.50/profile5/media.smp/trackID=v\r\n" +
+			"a=cliprect:0,0,1080,1920\r\n" +
+			"a=framesize:97 1920-1080\r\n" +
+			"a=framerate:30.0\r\n" +
 			"a=rtpmap:97 H264/90000\r\n" +
 			"a=fmtp:97 packetization-mode=1; profile-level-id=640028; sprop-parameter-sets=Z2QAKKy0A8ARPyo=,aO4Bniw=\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"b=AS:64\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://10.0.100.50/profile5/media.smp/trackID=a\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=application 0 RTP/AVP 107\r\n" +
+			"b=AS:8\r\n" +
 			"a=control\r\n",
 		Session{
 			Title: `Media Presentation`,
@@ -67,22 +86,66 @@ var casesSession = []struct {
 						SPS:               []byte{0x67, 0x64, 0x00, 0x28, 0xac, 0xb4, 0x03, 0xc0, 0x11, 0x3f, 0x2a},
 						PPS:               []byte{0x68, 0xee, 0x01, 0x9e, 0x2c},
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 2560,
+					}},
+					Attributes: []Attribute{
+						{
+							Key:   "cliprect",
+							Value: "0,0,1080,1920",
+							After: "control",
+						},
+						{
+							Key:   "framesize",
+							Value: "97 1920-1080",
+							After: "control",
+						},
+						{
+							Key:   "framerate",
+							Value: "30.0",
+							After: "control",
+						},
+					},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "rtsp://10.0.100.50/profile5/media.smp/trackID=a",
+					Type:      MediaTypeAudio,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "rtsp://10.0.100.50/profile5/media.smp/trackID=a",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
 						SampleRate:   8000,
 						ChannelCount: 1,
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 64,
+					}},
 				},
 				{
 					Type: MediaTypeApplication,
 					Formats: []format.Format{&format.Generic{
 						PayloadTyp: 107,
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 8,
+					}},
+				},
+			},
+			Bandwidths: []Bandwidth{{
+				Type:  BandwidthTypeAS,
+				Value: 2632,
+			}},
+			Attributes: []Attribute{
+				{
+					Key:   "control",
+					Value: "rtsp://10.0.100.50/profile5/media.smp",
+				},
+				{
+					Key:   "range",
+					Value: "npt=now-",
 				},
 			},
 		},
@@ -116,15 +179,24 @@ var casesSession = []struct {
 			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
 			"s=Media Presentation\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
+			"b=AS:2632\r\n" +
 			"t=0 0\r\n" +
+			"a=range:npt=now-\r\n" +
 			"m=video 0 RTP/AVP 97\r\n" +
+			"b=AS:2560\r\n" +
 			"a=control:trackID=1\r\n" +
+			"a=cliprect:0,0,1080,1920\r\n" +
+			"a=framesize:97 1920-1080\r\n" +
+			"a=framerate:30.0\r\n" +
 			"a=rtpmap:97 H264/90000\r\n" +
 			"a=fmtp:97 packetization-mode=1; profile-level-id=640028; sprop-parameter-sets=Z2QAKKy0A8ARPyo=,aO4Bniw=\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"b=AS:64\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:trackID=2\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=application 0 RTP/AVP 107\r\n" +
+			"b=AS:8\r\n" +
 			"a=control\r\n",
 		Session{
 			Title: `Media Presentation`,
@@ -138,22 +210,62 @@ var casesSession = []struct {
 						SPS:               []byte{0x67, 0x64, 0x00, 0x28, 0xac, 0xb4, 0x03, 0xc0, 0x11, 0x3f, 0x2a},
 						PPS:               []byte{0x68, 0xee, 0x01, 0x9e, 0x2c},
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 2560,
+					}},
+					Attributes: []Attribute{
+						{
+							Key:   "cliprect",
+							Value: "0,0,1080,1920",
+							After: "control",
+						},
+						{
+							Key:   "framesize",
+							Value: "97 1920-1080",
+							After: "control",
+						},
+						{
+							Key:   "framerate",
+							Value: "30.0",
+							After: "control",
+						},
+					},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "trackID=2",
+					Type:      MediaTypeAudio,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "trackID=2",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
 						SampleRate:   8000,
 						ChannelCount: 1,
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 64,
+					}},
 				},
 				{
 					Type: MediaTypeApplication,
 					Formats: []format.Format{&format.Generic{
 						PayloadTyp: 107,
 					}},
+					Bandwidths: []Bandwidth{{
+						Type:  BandwidthTypeAS,
+						Value: 8,
+					}},
+				},
+			},
+			Bandwidths: []Bandwidth{{
+				Type:  BandwidthTypeAS,
+				Value: 2632,
+			}},
+			Attributes: []Attribute{
+				{
+					Key:   "range",
+					Value: "npt=now-",
 				},
 			},
 		},
@@ -265,12 +377,25 @@ var casesSession = []struct {
 			"s= \r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=group:BUNDLE audio video\r\n" +
+			"a=msid-semantic: WMS mediaSessionLocal\r\n" +
 			"m=audio 0 RTP/AVP 111 103 104 9 102 0 8 106 105 13 110 112 113 126\r\n" +
+			"a=rtcp:9 IN IP4 0.0.0.0\r\n" +
+			"a=ice-ufrag:0D6Y\r\n" +
+			"a=ice-pwd:V3YEqLGAJJhUDUa13C/pKbWe\r\n" +
+			"a=ice-options:trickle renomination\r\n" +
+			"a=fingerprint:sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8\r\n" +
+			"a=setup:actpass\r\n" +
 			"a=mid:audio\r\n" +
+			"a=extmap:1 urn:ietf:params:rtp-hdrext:ssrc-audio-level\r\n" +
+			"a=extmap:2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time\r\n" +
+			"a=extmap:3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01\r\n" +
 			"a=sendonly\r\n" +
+			"a=rtcp-mux\r\n" +
 			"a=control\r\n" +
 			"a=rtpmap:111 opus/48000/2\r\n" +
-			"a=fmtp:111 sprop-stereo=0\r\n" +
+			"a=fmtp:111 minptime=10; sprop-stereo=0; useinbandfec=1\r\n" +
+			"a=rtcp-fb:111 transport-cc\r\n" +
 			"a=rtpmap:103 ISAC/16000\r\n" +
 			"a=rtpmap:104 ISAC/32000\r\n" +
 			"a=rtpmap:9 G722/8000\r\n" +
@@ -284,23 +409,68 @@ var casesSession = []struct {
 			"a=rtpmap:112 telephone-event/32000\r\n" +
 			"a=rtpmap:113 telephone-event/16000\r\n" +
 			"a=rtpmap:126 telephone-event/8000\r\n" +
+			"a=ssrc:3754810229 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:3754810229 msid:mediaSessionLocal 101\r\n" +
+			"a=ssrc:3754810229 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:3754810229 label:101\r\n" +
 			"m=video 0 RTP/AVP 96 97 98 99 100 101 127 124 125\r\n" +
+			"a=rtcp:9 IN IP4 0.0.0.0\r\n" +
+			"a=ice-ufrag:0D6Y\r\n" +
+			"a=ice-pwd:V3YEqLGAJJhUDUa13C/pKbWe\r\n" +
+			"a=ice-options:trickle renomination\r\n" +
+			"a=fingerprint:sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8\r\n" +
+			"a=setup:actpass\r\n" +
 			"a=mid:video\r\n" +
+			"a=extmap:14 urn:ietf:params:rtp-hdrext:toffset\r\n" +
+			"a=extmap:2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time\r\n" +
+			"a=extmap:13 urn:3gpp:video-orientation\r\n" +
+			"a=extmap:3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01\r\n" +
+			"a=extmap:5 http://www.webrtc.org/experiments/rtp-hdrext/playout-delay\r\n" +
+			"a=extmap:6 http://www.webrtc.org/experiments/rtp-hdrext/video-content-type\r\n" +
+			"a=extmap:7 http://www.webrtc.org/experiments/rtp-hdrext/video-timing\r\n" +
+			"a=extmap:8 http://www.webrtc.org/experiments/rtp-hdrext/color-space\r\n" +
 			"a=sendonly\r\n" +
+			"a=rtcp-mux\r\n" +
+			"a=rtcp-rsize\r\n" +
 			"a=control\r\n" +
 			"a=rtpmap:96 VP8/90000\r\n" +
+			"a=rtcp-fb:96 goog-remb\r\n" +
+			"a=rtcp-fb:96 transport-cc\r\n" +
+			"a=rtcp-fb:96 ccm fir\r\n" +
+			"a=rtcp-fb:96 nack\r\n" +
+			"a=rtcp-fb:96 nack pli\r\n" +
 			"a=rtpmap:97 rtx/90000\r\n" +
 			"a=fmtp:97 apt=96\r\n" +
 			"a=rtpmap:98 VP9/90000\r\n" +
+			"a=rtcp-fb:98 goog-remb\r\n" +
+			"a=rtcp-fb:98 transport-cc\r\n" +
+			"a=rtcp-fb:98 ccm fir\r\n" +
+			"a=rtcp-fb:98 nack\r\n" +
+			"a=rtcp-fb:98 nack pli\r\n" +
 			"a=rtpmap:99 rtx/90000\r\n" +
 			"a=fmtp:99 apt=98\r\n" +
 			"a=rtpmap:100 H264/90000\r\n" +
 			"a=fmtp:100 packetization-mode=1\r\n" +
+			"a=rtcp-fb:100 goog-remb\r\n" +
+			"a=rtcp-fb:100 transport-cc\r\n" +
+			"a=rtcp-fb:100 ccm fir\r\n" +
+			"a=rtcp-fb:100 nack\r\n" +
+			"a=rtcp-fb:100 nack pli\r\n" +
 			"a=rtpmap:101 rtx/90000\r\n" +
 			"a=fmtp:101 apt=100\r\n" +
 			"a=rtpmap:127 red/90000\r\n" +
 			"a=rtpmap:124 rtx/90000\r\n" +
-			"a=fmtp:124 apt=127\r\na=rtpmap:125 ulpfec/90000\r\n",
+			"a=fmtp:124 apt=127\r\n" +
+			"a=rtpmap:125 ulpfec/90000\r\n" +
+			"a=ssrc-group:FID 2712436124 1733091158\r\n" +
+			"a=ssrc:2712436124 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:2712436124 msid:mediaSessionLocal 100\r\n" +
+			"a=ssrc:2712436124 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:2712436124 label:100\r\n" +
+			"a=ssrc:1733091158 cname:CvU1TYqkVsjj5XOt\r\n" +
+			"a=ssrc:1733091158 msid:mediaSessionLocal 100\r\n" +
+			"a=ssrc:1733091158 mslabel:mediaSessionLocal\r\n" +
+			"a=ssrc:1733091158 label:100\r\n",
 		Session{
 			Title: ``,
 			Medias: []*Media{
@@ -308,11 +478,16 @@ var casesSession = []struct {
 					ID:            "audio",
 					Type:          MediaTypeAudio,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
 					Formats: []format.Format{
 						&format.Opus{
 							PayloadTyp:   111,
 							IsStereo:     false,
 							ChannelCount: 1,
+							InBandFEC:    true,
+							OtherFMTP: map[string]string{
+								"minptime": "10",
+							},
 						},
 						&format.Generic{
 							PayloadTyp: 103,
@@ -378,65 +553,254 @@ var casesSession = []struct {
 							ClockRat:   8000,
 						},
 					},
+					RTCPFeedback: map[uint8][]string{
+						111: {"transport-cc"},
+					},
+					Attributes: []Attribute{
+						{
+							Key:   "rtcp",
+							Value: "9 IN IP4 0.0.0.0",
+						},
+						{
+							Key:   "ice-ufrag",
+							Value: "0D6Y",
+						},
+						{
+							Key:   "ice-pwd",
+							Value: "V3YEqLGAJJhUDUa13C/pKbWe",
+						},
+						{
+							Key:   "ice-options",
+							Value: "trickle renomination",
+						},
+						{
+							Key:   "fingerprint",
+							Value: "sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8",
+						},
+						{
+							Key:   "setup",
+							Value: "actpass",
+						},
+						{
+							Key:   "extmap",
+							Value: "1 urn:ietf:params:rtp-hdrext:ssrc-audio-level",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01",
+							After: "mid",
+						},
+						{
+							Key:   "rtcp-mux",
+							After: "sendonly",
+						},
+						{
+							Key:   "ssrc",
+							Value: "3754810229 cname:CvU1TYqkVsjj5XOt",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "3754810229 msid:mediaSessionLocal 101",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "3754810229 mslabel:mediaSessionLocal",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "3754810229 label:101",
+							After: "rtpmap",
+						},
+					},
 				},
 				{
 					ID:            "video",
 					Type:          MediaTypeVideo,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
 					Formats: []format.Format{
 						&format.VP8{
 							PayloadTyp: 96,
 						},
-						&format.Generic{
+						&format.RTX{
 							PayloadTyp: 97,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "96",
-							},
-							ClockRat: 90000,
+							ClockRat:   90000,
+							APT:        96,
 						},
 						&format.VP9{
 							PayloadTyp: 98,
 						},
-						&format.Generic{
+						&format.RTX{
 							PayloadTyp: 99,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "98",
-							},
-							ClockRat: 90000,
+							ClockRat:   90000,
+							APT:        98,
 						},
 						&format.H264{
 							PayloadTyp:        100,
 							PacketizationMode: 1,
 						},
-						&format.Generic{
+						&format.RTX{
 							PayloadTyp: 101,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "100",
-							},
-							ClockRat: 90000,
+							ClockRat:   90000,
+							APT:        100,
 						},
-						&format.Generic{
+						&format.RED{
 							PayloadTyp: 127,
-							RTPMa:      "red/90000",
 							ClockRat:   90000,
 						},
-						&format.Generic{
+						&format.RTX{
 							PayloadTyp: 124,
-							RTPMa:      "rtx/90000",
-							FMT: map[string]string{
-								"apt": "127",
-							},
-							ClockRat: 90000,
+							ClockRat:   90000,
+							APT:        127,
 						},
-						&format.Generic{
+						&format.ULPFEC{
 							PayloadTyp: 125,
-							RTPMa:      "ulpfec/90000",
 							ClockRat:   90000,
 						},
 					},
+					RTCPFeedback: map[uint8][]string{
+						96:  {"goog-remb", "transport-cc", "ccm fir", "nack", "nack pli"},
+						98:  {"goog-remb", "transport-cc", "ccm fir", "nack", "nack pli"},
+						100: {"goog-remb", "transport-cc", "ccm fir", "nack", "nack pli"},
+					},
+					Attributes: []Attribute{
+						{
+							Key:   "rtcp",
+							Value: "9 IN IP4 0.0.0.0",
+						},
+						{
+							Key:   "ice-ufrag",
+							Value: "0D6Y",
+						},
+						{
+							Key:   "ice-pwd",
+							Value: "V3YEqLGAJJhUDUa13C/pKbWe",
+						},
+						{
+							Key:   "ice-options",
+							Value: "trickle renomination",
+						},
+						{
+							Key:   "fingerprint",
+							Value: "sha-256 5E:B5:97:8B:B4:D8:AE:2B:89:F6:82:44:47:69:77:83:05:29:C5:C8:EE:67:50:C3:77:6B:A7:BA:10:E3:08:B8",
+						},
+						{
+							Key:   "setup",
+							Value: "actpass",
+						},
+						{
+							Key:   "extmap",
+							Value: "14 urn:ietf:params:rtp-hdrext:toffset",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "2 http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "13 urn:3gpp:video-orientation",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "3 http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "5 http://www.webrtc.org/experiments/rtp-hdrext/playout-delay",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "6 http://www.webrtc.org/experiments/rtp-hdrext/video-content-type",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "7 http://www.webrtc.org/experiments/rtp-hdrext/video-timing",
+							After: "mid",
+						},
+						{
+							Key:   "extmap",
+							Value: "8 http://www.webrtc.org/experiments/rtp-hdrext/color-space",
+							After: "mid",
+						},
+						{
+							Key:   "rtcp-mux",
+							After: "sendonly",
+						},
+						{
+							Key:   "rtcp-rsize",
+							After: "sendonly",
+						},
+						{
+							Key:   "ssrc-group",
+							Value: "FID 2712436124 1733091158",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "2712436124 cname:CvU1TYqkVsjj5XOt",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "2712436124 msid:mediaSessionLocal 100",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "2712436124 mslabel:mediaSessionLocal",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "2712436124 label:100",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "1733091158 cname:CvU1TYqkVsjj5XOt",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "1733091158 msid:mediaSessionLocal 100",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "1733091158 mslabel:mediaSessionLocal",
+							After: "rtpmap",
+						},
+						{
+							Key:   "ssrc",
+							Value: "1733091158 label:100",
+							After: "rtpmap",
+						},
+					},
+				},
+			},
+			Attributes: []Attribute{
+				{
+					Key:   "group",
+					Value: "BUNDLE audio video",
+				},
+				{
+					Key:   "msid-semantic",
+					Value: " WMS mediaSessionLocal",
 				},
 			},
 		},
@@ -461,9 +825,9 @@ var casesSession = []struct {
 			"m=video 0 RTP/AVP 96 98\r\n" +
 			"a=control\r\n" +
 			"a=rtpmap:96 H264/90000\r\n" +
-			"a=fmtp:96 packetization-mode=1; profile-level-id=4D002A; " +
-			"sprop-parameter-sets=Z00AKp2oHgCJ+WbgICAgQA==,aO48gA==\r\n" +
-			"a=rtpmap:98 MetaData\r\n",
+			"a=fmtp:96 packetization-mode=1; profile-level-id=4D002A; sprop-parameter-sets=Z00AKp2oHgCJ+WbgICAgQA==,aO48gA==\r\n" +
+			"a=rtpmap:98 MetaData\r\n" +
+			"a=rtcp-mux\r\n",
 		Session{
 			Title: `-`,
 			Medias: []*Media{
@@ -484,6 +848,12 @@ var casesSession = []struct {
 							RTPMa:      "MetaData",
 						},
 					},
+					Attributes: []Attribute{
+						{
+							Key:   "rtcp-mux",
+							After: "rtpmap",
+						},
+					},
 				},
 			},
 		},
@@ -509,9 +879,11 @@ var casesSession = []struct {
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
 			"m=video 0 RTP/AVP 26\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://192.168.0.1/video\r\n" +
 			"a=rtpmap:26 JPEG/90000\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
+			"a=recvonly\r\n" +
 			"a=control:rtsp://192.168.0.1/audio\r\n" +
 			"a=rtpmap:0 PCMU/8000\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
@@ -522,13 +894,15 @@ var casesSession = []struct {
 			Title: `RTSP Session with audiobackchannel`,
 			Medias: []*Media{
 				{
-					Type:    MediaTypeVideo,
-					Control: "rtsp://192.168.0.1/video",
-					Formats: []format.Format{&format.MJPEG{}},
+					Type:      MediaTypeVideo,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "rtsp://192.168.0.1/video",
+					Formats:   []format.Format{&format.MJPEG{}},
 				},
 				{
-					Type:    MediaTypeAudio,
-					Control: "rtsp://192.168.0.1/audio",
+					Type:      MediaTypeAudio,
+					Direction: MediaDirectionRecvOnly,
+					Control:   "rtsp://192.168.0.1/audio",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
 						MULaw:        true,
@@ -539,6 +913,7 @@ var casesSession = []struct {
 				{
 					Type:          MediaTypeAudio,
 					IsBackChannel: true,
+					Direction:     MediaDirectionSendOnly,
 					Control:       "rtsp://192.168.0.1/audioback",
 					Formats: []format.Format{&format.G711{
 						PayloadTyp:   0,
@@ -595,6 +970,7 @@ var casesSession = []struct {
 			"s=Session streamed by \"TP-LINK RTSP Server\"\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=smart_encoder:virtualIFrame=1\r\n" +
 			"m=application/tp-link 0 RTP/AVP 95\r\n" +
 			"a=control:track3\r\n" +
 			"a=rtpmap:95 tp-link/25000\r\n",
@@ -611,6 +987,12 @@ var casesSession = []struct {
 					Control: "track3",
 				},
 			},
+			Attributes: []Attribute{
+				{
+					Key:   "smart_encoder",
+					Value: "virtualIFrame=1",
+				},
+			},
 		},
 	},
 	{
@@ -627,6 +1009,7 @@ var casesSession = []struct {
 			"s=Session streamed by \"MERCURY RTSP Server\"\r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
+			"a=smart_encoder:virtualIFrame=1\r\n" +
 			"m=application/MERCURY 0 RTP/AVP 95\r\n" +
 			"a=control\r\n" +
 			"a=rtpmap:95 MERCURY/90000\r\n",
@@ -642,6 +1025,12 @@ var casesSession = []struct {
 					}},
 				},
 			},
+			Attributes: []Attribute{
+				{
+					Key:   "smart_encoder",
+					Value: "virtualIFrame=1",
+				},
+			},
 		},
 	},
 	{
@@ -678,70 +1067,633 @@ var casesSession = []struct {
 		},
 	},
 	{
-		"ulpfec rfc5109",
-		"v=0\r\n" +
-			"o=adam 289083124 289083124 IN IP4 host.example.com\r\n" +
-			"s=ULP FEC Seminar\r\n" +
-			"t=0 0\r\n" +
-			"c=IN IP4 224.2.17.12/127\r\n" +
-			"a=group:FEC 1 2\r\n" +
-			"a=group:FEC 3 4\r\n" +
-			"m=audio 30000 RTP/AVP 0\r\n" +
-			"a=mid:1\r\n" +
-			"m=application 30002 RTP/AVP 100\r\n" +
-			"a=rtpmap:100 ulpfec/8000\r\n" +
-			"a=mid:2\r\n" +
-			"m=video 30004 RTP/AVP 31\r\n" +
-			"a=mid:3\r\n" +
-			"m=application 30004 RTP/AVP 101\r\n" +
-			"c=IN IP4 224.2.17.13/127\r\n" +
-			"a=rtpmap:101 ulpfec/8000\r\n" +
-			"a=mid:4\r\n",
+		"uppercase keys and invalid lines",
+		"v=0\n" +
+			"o=- 0 0 IN IP4 192.168.1.10\n" +
+			"c=IN\n" +
+			"t=0 0\n" +
+			"m=video 0 RTP/AVP 96\n" +
+			"A=RTPMAP:96 H264/90000 \n" +
+			"A=CONTROL:trackID=1\n" +
+			"garbage\n" +
+			"m=audio 0 RTP/AVP 0\n" +
+			"a=rtpmap:0 PCMU/8000\n" +
+			"a=control:trackID=2\n",
 		"v=0\r\n" +
 			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
-			"s=ULP FEC Seminar\r\n" +
+			"s= \r\n" +
 			"c=IN IP4 0.0.0.0\r\n" +
 			"t=0 0\r\n" +
-			"a=group:FEC 1 2\r\n" +
-			"a=group:FEC 3 4\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=control:trackID=1\r\n" +
+			"a=rtpmap:96 H264/90000\r\n" +
 			"m=audio 0 RTP/AVP 0\r\n" +
-			"a=mid:1\r\n" +
-			"a=control\r\n" +
-			"a=rtpmap:0 PCMU/8000\r\n" +
-			"m=application 0 RTP/AVP 100\r\n" +
-			"a=mid:2\r\n" +
-			"a=control\r\n" +
-			"a=rtpmap:100 ulpfec/8000\r\n" +
-			"m=video 0 RTP/AVP 31\r\n" +
-			"a=mid:3\r\n" +
-			"a=control\r\n" +
-			"m=application 0 RTP/AVP 101\r\n" +
-			"a=mid:4\r\n" +
-			"a=control\r\n" +
-			"a=rtpmap:101 ulpfec/8000\r\n",
+			"a=control:trackID=2\r\n" +
+			"a=rtpmap:0 PCMU/8000\r\n",
 		Session{
-			Title: "ULP FEC Seminar",
-			FECGroups: []SessionFECGroup{
-				{"1", "2"},
-				{"3", "4"},
-			},
 			Medias: []*Media{
 				{
-					ID:   "1",
-					Type: MediaTypeAudio,
-					Formats: []format.Format{&format.G711{
-						PayloadTyp:   0,
-						MULaw:        true,
-						SampleRate:   8000,
-						ChannelCount: 1,
-					}},
+					Type:    MediaTypeVideo,
+					Control: "trackID=1",
+					Formats: []format.Format{
+						&format.H264{
+							PayloadTyp: 96,
+						},
+					},
 				},
 				{
-					ID:   "2",
-					Type: MediaTypeApplication,
-					Formats: []format.Format{&format.Generic{
-						PayloadTyp: 100,
-						RTPMa:      "ulpfec/8000",
+					Type:    MediaTypeAudio,
+					Control: "trackID=2",
+					Formats: []format.Format{
+						&format.G711{
+							PayloadTyp:   0,
+							MULaw:        true,
+							SampleRate:   8000,
+							ChannelCount: 1,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"bandwidths",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"b=TIAS:2000000\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"b=AS:2000\r\n" +
+			"b=X-YZ:128\r\n" +
+			"a=rtpmap:96 H264/90000\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Stream\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"b=TIAS:2000000\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"b=AS:2000\r\n" +
+			"b=X-YZ:128\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:96 H264/90000\r\n",
+		Session{
+			Title: "Stream",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{&format.H264{
+						PayloadTyp: 96,
+					}},
+					Bandwidths: []Bandwidth{
+						{
+							Type:  BandwidthTypeAS,
+							Value: 2000,
+						},
+						{
+							Type:  "X-YZ",
+							Value: 128,
+						},
+					},
+				},
+			},
+			Bandwidths: []Bandwidth{{
+				Type:  BandwidthTypeTIAS,
+				Value: 2000000,
+			}},
+		},
+	},
+	{
+		"raw video with flag parameter",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Example\r\n" +
+			"t=0 0\r\n" +
+			"m=video 50000 RTP/AVP 112\r\n" +
+			"a=rtpmap:112 raw/90000\r\n" +
+			"a=fmtp:112 sampling=YCbCr-4:2:2; width=1920; height=1080; " +
+			"depth=10; colorimetry=BT709; interlace\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Example\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 112\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:112 raw/90000\r\n" +
+			"a=fmtp:112 colorimetry=BT709; depth=10; height=1080; interlace; " +
+			"sampling=YCbCr-4:2:2; width=1920\r\n",
+		Session{
+			Title: "Example",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.RawVideo{
+							PayloadTyp:  112,
+							Sampling:    "YCbCr-4:2:2",
+							BitDepth:    10,
+							Width:       1920,
+							Height:      1080,
+							Colorimetry: "BT709",
+							Interlaced:  true,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"t140 with redundancy",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Text\r\n" +
+			"t=0 0\r\n" +
+			"m=text 11000 RTP/AVP 100 98\r\n" +
+			"a=rtpmap:98 t140/1000\r\n" +
+			"a=rtpmap:100 red/1000\r\n" +
+			"a=fmtp:100 98/98/98\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Text\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=text 0 RTP/AVP 100 98\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:100 red/1000\r\n" +
+			"a=fmtp:100 98/98/98\r\n" +
+			"a=rtpmap:98 t140/1000\r\n",
+		Session{
+			Title: "Text",
+			Medias: []*Media{
+				{
+					Type: MediaTypeText,
+					Formats: []format.Format{
+						&format.RED{
+							PayloadTyp:   100,
+							ClockRat:     1000,
+							PayloadTypes: []uint8{98, 98, 98},
+						},
+						&format.T140{
+							PayloadTyp:     98,
+							REDPayloadType: uint8Ptr(100),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"opus with packet times",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Voice\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 11000 RTP/AVP 111\r\n" +
+			"a=rtpmap:111 opus/48000/2\r\n" +
+			"a=fmtp:111 sprop-stereo=0\r\n" +
+			"a=ptime:20\r\n" +
+			"a=maxptime:2.5\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Voice\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 111\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:111 opus/48000/2\r\n" +
+			"a=fmtp:111 sprop-stereo=0\r\n" +
+			"a=ptime:20\r\n" +
+			"a=maxptime:2.5\r\n",
+		Session{
+			Title: "Voice",
+			Medias: []*Media{
+				{
+					Type: MediaTypeAudio,
+					Formats: []format.Format{
+						&format.Opus{
+							PayloadTyp:              111,
+							ChannelCount:            1,
+							PreferredPacketDuration: 20 * time.Millisecond,
+							MaxPacketDuration:       2500 * time.Microsecond,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"generic with format attributes",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Custom\r\n" +
+			"t=0 0\r\n" +
+			"m=video 11000 RTP/AVP 97\r\n" +
+			"a=extmap:97 urn:ietf:params:rtp-hdrext:toffset\r\n" +
+			"a=rtpmap:97 X-Custom/90000\r\n" +
+			"a=fmtp:97 Zeta=1;alpha=2\r\n" +
+			"a=rtcp-fb:97 nack\r\n" +
+			"a=rtcp-fb:97 nack pli\r\n" +
+			"a=framesize:97 1920-1080\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Custom\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 97\r\n" +
+			"a=extmap:97 urn:ietf:params:rtp-hdrext:toffset\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:97 X-Custom/90000\r\n" +
+			"a=fmtp:97 Zeta=1;alpha=2\r\n" +
+			"a=rtcp-fb:97 nack\r\n" +
+			"a=rtcp-fb:97 nack pli\r\n" +
+			"a=framesize:97 1920-1080\r\n",
+		Session{
+			Title: "Custom",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.Generic{
+							PayloadTyp: 97,
+							RTPMa:      "X-Custom/90000",
+							FMT: map[string]string{
+								"zeta":  "1",
+								"alpha": "2",
+							},
+							FMTPRaw: "Zeta=1;alpha=2",
+							Attributes: []format.GenericAttribute{
+								{Key: "framesize", Value: "1920-1080"},
+							},
+							ClockRat: 90000,
+						},
+					},
+					RTCPFeedback: map[uint8][]string{
+						97: {"nack", "nack pli"},
+					},
+					Attributes: []Attribute{
+						{
+							Key:   "extmap",
+							Value: "97 urn:ietf:params:rtp-hdrext:toffset",
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"lpcm with packet time",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Broadcast\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 5004 RTP/AVP 98\r\n" +
+			"a=rtpmap:98 L24/48000/2\r\n" +
+			"a=ptime:1\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Broadcast\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 98\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:98 L24/48000/2\r\n" +
+			"a=ptime:1\r\n",
+		Session{
+			Title: "Broadcast",
+			Medias: []*Media{
+				{
+					Type: MediaTypeAudio,
+					Formats: []format.Format{
+						&format.LPCM{
+							PayloadTyp:     98,
+							BitDepth:       24,
+							SampleRate:     48000,
+							ChannelCount:   2,
+							PacketDuration: time.Millisecond,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"rtcp feedback with wildcard",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Camera\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96 97\r\n" +
+			"a=rtpmap:96 VP8/90000\r\n" +
+			"a=rtpmap:97 VP9/90000\r\n" +
+			"a=rtcp-fb:* nack\r\n" +
+			"a=rtcp-fb:97 ccm fir\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Camera\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96 97\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:96 VP8/90000\r\n" +
+			"a=rtcp-fb:96 nack\r\n" +
+			"a=rtpmap:97 VP9/90000\r\n" +
+			"a=rtcp-fb:97 nack\r\n" +
+			"a=rtcp-fb:97 ccm fir\r\n",
+		Session{
+			Title: "Camera",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.VP8{
+							PayloadTyp: 96,
+						},
+						&format.VP9{
+							PayloadTyp: 97,
+						},
+					},
+					RTCPFeedback: map[uint8][]string{
+						96: {"nack"},
+						97: {"nack", "ccm fir"},
+					},
+				},
+			},
+		},
+	},
+	{
+		"vp9 with profile-id",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Gateway\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 98\r\n" +
+			"a=rtpmap:98 VP9/90000\r\n" +
+			"a=fmtp:98 profile-id=2;max-fr=30;max-fs=8160\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Gateway\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 98\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:98 VP9/90000\r\n" +
+			"a=fmtp:98 max-fr=30; max-fs=8160; profile-id=2\r\n",
+		Session{
+			Title: "Gateway",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.VP9{
+							PayloadTyp: 98,
+							MaxFR:      intPtr(30),
+							MaxFS:      intPtr(8160),
+							ProfileID:  intPtr(2),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"av1 with codec parameters",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Relay\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=rtpmap:96 AV1/90000\r\n" +
+			"a=fmtp:96 profile=1;level-idx=12;tier=1\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Relay\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 96\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:96 AV1/90000\r\n" +
+			"a=fmtp:96 level-idx=12; profile=1; tier=1\r\n",
+		Session{
+			Title: "Relay",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.AV1{
+							PayloadTyp: 96,
+							LevelIdx:   intPtr(12),
+							Profile:    intPtr(1),
+							Tier:       intPtr(1),
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"g711 multichannel with packet time",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Bridge\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 96\r\n" +
+			"a=rtpmap:96 PCMA/16000/2\r\n" +
+			"a=ptime:20\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Bridge\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 96\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:96 PCMA/16000/2\r\n" +
+			"a=ptime:20\r\n",
+		Session{
+			Title: "Bridge",
+			Medias: []*Media{
+				{
+					Type: MediaTypeAudio,
+					Formats: []format.Format{
+						&format.G711{
+							PayloadTyp:     96,
+							MULaw:          false,
+							SampleRate:     16000,
+							ChannelCount:   2,
+							PacketDuration: 20 * time.Millisecond,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"g722 with packet time and max packet time",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Bridge\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 9\r\n" +
+			"a=rtpmap:9 G722/8000\r\n" +
+			"a=ptime:40\r\n" +
+			"a=maxptime:60\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Bridge\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 0 RTP/AVP 9\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:9 G722/8000\r\n" +
+			"a=ptime:40\r\n" +
+			"a=maxptime:60\r\n",
+		Session{
+			Title: "Bridge",
+			Medias: []*Media{
+				{
+					Type: MediaTypeAudio,
+					Formats: []format.Format{
+						&format.G722{
+							PacketDuration:    40 * time.Millisecond,
+							MaxPacketDuration: 60 * time.Millisecond,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"mjpeg with framesize",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Camera\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 26\r\n" +
+			"a=rtpmap:26 JPEG/90000\r\n" +
+			"a=framesize:26 2560-1440\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Camera\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 26\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:26 JPEG/90000\r\n" +
+			"a=framesize:26 2560-1440\r\n" +
+			"a=x-dimensions:2560,1440\r\n",
+		Session{
+			Title: "Camera",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.MJPEG{
+							Width:  2560,
+							Height: 1440,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"mjpeg with x-dimensions",
+		"v=0\r\n" +
+			"o=- 123456 11 IN IP4 192.168.100.2\r\n" +
+			"s=Camera\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 26\r\n" +
+			"a=x-dimensions:2560,1440\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=Camera\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=video 0 RTP/AVP 26\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:26 JPEG/90000\r\n" +
+			"a=framesize:26 2560-1440\r\n" +
+			"a=x-dimensions:2560,1440\r\n",
+		Session{
+			Title: "Camera",
+			Medias: []*Media{
+				{
+					Type: MediaTypeVideo,
+					Formats: []format.Format{
+						&format.MJPEG{
+							Width:  2560,
+							Height: 1440,
+						},
+					},
+				},
+			},
+		},
+	},
+	{
+		"ulpfec rfc5109",
+		"v=0\r\n" +
+			"o=adam 289083124 289083124 IN IP4 host.example.com\r\n" +
+			"s=ULP FEC Seminar\r\n" +
+			"t=0 0\r\n" +
+			"c=IN IP4 224.2.17.12/127\r\n" +
+			"a=group:FEC 1 2\r\n" +
+			"a=group:FEC 3 4\r\n" +
+			"m=audio 30000 RTP/AVP 0\r\n" +
+			"a=mid:1\r\n" +
+			"m=application 30002 RTP/AVP 100\r\n" +
+			"a=rtpmap:100 ulpfec/8000\r\n" +
+			"a=mid:2\r\n" +
+			"m=video 30004 RTP/AVP 31\r\n" +
+			"a=mid:3\r\n" +
+			"m=application 30004 RTP/AVP 101\r\n" +
+			"c=IN IP4 224.2.17.13/127\r\n" +
+			"a=rtpmap:101 ulpfec/8000\r\n" +
+			"a=mid:4\r\n",
+		"v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=ULP FEC Seminar\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"a=group:FEC 1 2\r\n" +
+			"a=group:FEC 3 4\r\n" +
+			"m=audio 0 RTP/AVP 0\r\n" +
+			"a=mid:1\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:0 PCMU/8000\r\n" +
+			"m=application 0 RTP/AVP 100\r\n" +
+			"a=mid:2\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:100 ulpfec/8000\r\n" +
+			"m=video 0 RTP/AVP 31\r\n" +
+			"a=mid:3\r\n" +
+			"a=control\r\n" +
+			"m=application 0 RTP/AVP 101\r\n" +
+			"a=mid:4\r\n" +
+			"a=control\r\n" +
+			"a=rtpmap:101 ulpfec/8000\r\n",
+		Session{
+			Title: "ULP FEC Seminar",
+			FECGroups: []SessionFECGroup{
+				{"1", "2"},
+				{"3", "4"},
+			},
+			Medias: []*Media{
+				{
+					ID:   "1",
+					Type: MediaTypeAudio,
+					Formats: []format.Format{&format.G711{
+						PayloadTyp:   0,
+						MULaw:        true,
+						SampleRate:   8000,
+						ChannelCount: 1,
+					}},
+				},
+				{
+					ID:   "2",
+					Type: MediaTypeApplication,
+					Formats: []format.Format{&format.ULPFEC{
+						PayloadTyp: 100,
 						ClockRat:   8000,
 					}},
 				},
@@ -756,9 +1708,8 @@ var casesSession = []struct {
 				{
 					ID:   "4",
 					Type: MediaTypeApplication,
-					Formats: []format.Format{&format.Generic{
+					Formats: []format.Format{&format.ULPFEC{
 						PayloadTyp: 101,
-						RTPMa:      "ulpfec/8000",
 						ClockRat:   8000,
 					}},
 				},
@@ -792,6 +1743,40 @@ func TestSessionMarshal(t *testing.T) {
 	}
 }
 
+func TestSessionMarshalOrigin(t *testing.T) {
+	desc := Session{
+		Title: "my stream",
+		Origin: &SessionOrigin{
+			SessionID:      123456,
+			SessionVersion: 2,
+			Address:        "192.168.1.10",
+		},
+		ConnectionAddress: "fd00::1",
+		Medias: []*Media{{
+			Type:    MediaTypeVideo,
+			Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+		}},
+	}
+
+	byts, err := desc.Marshal(false)
+	require.NoError(t, err)
+	require.Equal(t, "v=0\r\n"+
+		"o=- 123456 2 IN IP4 192.168.1.10\r\n"+
+		"s=my stream\r\n"+
+		"c=IN IP6 fd00::1\r\n"+
+		"t=0 0\r\n"+
+		"m=video 0 RTP/AVP 96\r\n"+
+		"a=control\r\n"+
+		"a=rtpmap:96 H264/90000\r\n"+
+		"a=fmtp:96 packetization-mode=1\r\n",
+		string(byts))
+
+	// the multicast address takes precedence over ConnectionAddress
+	byts, err = desc.Marshal(true)
+	require.NoError(t, err)
+	require.Contains(t, string(byts), "c=IN IP4 224.1.0.0\r\n")
+}
+
 func TestSessionFindFormat(t *testing.T) {
 	tr := &format.Generic{
 		PayloadTyp: 97,
@@ -837,6 +1822,84 @@ func TestSessionFindFormat(t *testing.T) {
 	require.Equal(t, tr, forma)
 }
 
+func TestSessionFindMultiple(t *testing.T) {
+	main := &format.H264{PayloadTyp: 96}
+	sub := &format.H264{PayloadTyp: 97}
+
+	mainMedia := &Media{
+		Type:    MediaTypeVideo,
+		Control: "trackID=0",
+		Formats: []format.Format{main},
+	}
+	subMedia := &Media{
+		Type:    MediaTypeVideo,
+		Control: "trackID=1",
+		Formats: []format.Format{sub},
+	}
+	audioMedia := &Media{
+		Type:    MediaTypeAudio,
+		Control: "trackID=2",
+		Formats: []format.Format{&format.G711{
+			PayloadTyp:   8,
+			SampleRate:   8000,
+			ChannelCount: 1,
+		}},
+	}
+
+	desc := &Session{
+		Medias: []*Media{mainMedia, audioMedia, subMedia},
+	}
+
+	var formats []*format.H264
+	medias := desc.FindFormats(&formats)
+	require.Equal(t, []*Media{mainMedia, subMedia}, medias)
+	require.Equal(t, []*format.H264{main, sub}, formats)
+
+	var opusFormats []*format.Opus
+	medias = desc.FindFormats(&opusFormats)
+	require.Nil(t, medias)
+	require.Nil(t, opusFormats)
+
+	require.Equal(t, []*Media{mainMedia, subMedia}, desc.FindMediasByType(MediaTypeVideo))
+	require.Equal(t, []*Media{audioMedia}, desc.FindMediasByType(MediaTypeAudio))
+	require.Nil(t, desc.FindMediasByType(MediaTypeApplication))
+
+	require.Equal(t, subMedia, desc.FindMediaByControl("trackID=1"))
+	require.Nil(t, desc.FindMediaByControl("trackID=5"))
+}
+
+func TestSessionFormatByPayloadType(t *testing.T) {
+	h264 := &format.H264{PayloadTyp: 96}
+	opus := &format.Opus{PayloadTyp: 111, ChannelCount: 2}
+	g711 := &format.G711{PayloadTyp: 96, MULaw: true, SampleRate: 8000, ChannelCount: 1}
+
+	videoMedia := &Media{
+		Type:    MediaTypeVideo,
+		Formats: []format.Format{h264},
+	}
+	audioMedia := &Media{
+		Type:    MediaTypeAudio,
+		Formats: []format.Format{opus, g711},
+	}
+
+	desc := &Session{
+		Medias: []*Media{videoMedia, audioMedia},
+	}
+
+	medi, forma, ok := desc.FormatByPayloadType(111)
+	require.True(t, ok)
+	require.Equal(t, audioMedia, medi)
+	require.Equal(t, opus, forma)
+
+	medi, forma, ok = desc.FormatByPayloadType(96)
+	require.True(t, ok)
+	require.Equal(t, videoMedia, medi)
+	require.Equal(t, h264, forma)
+
+	_, _, ok = desc.FormatByPayloadType(8)
+	require.False(t, ok)
+}
+
 func FuzzSessionUnmarshal(f *testing.F) {
 	for _, ca := range casesSession {
 		f.Add(ca.in)
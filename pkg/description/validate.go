@@ -0,0 +1,50 @@
+package description
+
+import (
+	"fmt"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format"
+)
+
+// ValidateSession checks a session for common mistakes that would produce an invalid SDP,
+// like codecs with static payload types or duplicate payload types.
+// It returns all the detected problems.
+func ValidateSession(desc *Session) []error {
+	var errs []error
+
+	if len(desc.Medias) == 0 {
+		errs = append(errs, fmt.Errorf("session has no medias"))
+	}
+
+	controls := make(map[string]struct{})
+
+	for i, medi := range desc.Medias {
+		if len(medi.Formats) == 0 {
+			errs = append(errs, fmt.Errorf("media %d: no formats", i))
+		}
+
+		if medi.Control != "" {
+			if _, ok := controls[medi.Control]; ok {
+				errs = append(errs, fmt.Errorf("media %d: duplicate control attribute '%s'", i, medi.Control))
+			}
+			controls[medi.Control] = struct{}{}
+		}
+
+		payloadTypes := make(map[uint8]struct{})
+
+		for _, forma := range medi.Formats {
+			err := format.ValidatePayloadType(forma)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("media %d: %w", i, err))
+			}
+
+			pt := forma.PayloadType()
+			if _, ok := payloadTypes[pt]; ok {
+				errs = append(errs, fmt.Errorf("media %d: duplicate payload type %d", i, pt))
+			}
+			payloadTypes[pt] = struct{}{}
+		}
+	}
+
+	return errs
+}
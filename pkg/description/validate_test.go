@@ -0,0 +1,85 @@
+package description
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format"
+)
+
+func TestValidateSession(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		desc *Session
+		errs []string
+	}{
+		{
+			"valid",
+			&Session{
+				Medias: []*Media{
+					{
+						Type:    MediaTypeVideo,
+						Control: "trackID=0",
+						Formats: []format.Format{&format.H264{PayloadTyp: 96, PacketizationMode: 1}},
+					},
+					{
+						Type:    MediaTypeAudio,
+						Control: "trackID=1",
+						Formats: []format.Format{&format.G711{
+							PayloadTyp:   0,
+							MULaw:        true,
+							SampleRate:   8000,
+							ChannelCount: 1,
+						}},
+					},
+				},
+			},
+			nil,
+		},
+		{
+			"no medias",
+			&Session{},
+			[]string{"session has no medias"},
+		},
+		{
+			"invalid",
+			&Session{
+				Medias: []*Media{
+					{
+						Type:    MediaTypeVideo,
+						Control: "trackID=0",
+						Formats: []format.Format{
+							&format.H264{PayloadTyp: 26, PacketizationMode: 1},
+							&format.VP8{PayloadTyp: 26},
+						},
+					},
+					{
+						Type:    MediaTypeAudio,
+						Control: "trackID=0",
+					},
+				},
+			},
+			[]string{
+				"media 0: H264: payload type 26 is not dynamic (96-127)",
+				"media 0: VP8: payload type 26 is not dynamic (96-127)",
+				"media 0: duplicate payload type 26",
+				"media 1: no formats",
+				"media 1: duplicate control attribute 'trackID=0'",
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			errs := ValidateSession(ca.desc)
+			strs := make([]string, len(errs))
+			for i, err := range errs {
+				strs[i] = err.Error()
+			}
+			if ca.errs == nil {
+				require.Empty(t, strs)
+			} else {
+				require.Equal(t, ca.errs, strs)
+			}
+		})
+	}
+}
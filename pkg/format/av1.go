@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/av1"
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format/rtpav1"
@@ -13,9 +14,15 @@ import (
 // Specification: https://aomediacodec.github.io/av1-rtp-spec/
 type AV1 struct {
 	PayloadTyp uint8
-	LevelIdx   *int
-	Profile    *int
-	Tier       *int
+
+	// level index, between 0 and 31 (optional).
+	LevelIdx *int
+
+	// profile, between 0 and 2 (optional).
+	Profile *int
+
+	// tier, 0 (main) or 1 (high) (optional).
+	Tier *int
 }
 
 func (f *AV1) unmarshal(ctx *unmarshalContext) error {
@@ -25,7 +32,7 @@ func (f *AV1) unmarshal(ctx *unmarshalContext) error {
 		switch key {
 		case "level-idx":
 			n, err := strconv.ParseUint(val, 10, 31)
-			if err != nil {
+			if err != nil || n > 31 {
 				return fmt.Errorf("invalid level-idx: %v", val)
 			}
 
@@ -34,7 +41,7 @@ func (f *AV1) unmarshal(ctx *unmarshalContext) error {
 
 		case "profile":
 			n, err := strconv.ParseUint(val, 10, 31)
-			if err != nil {
+			if err != nil || n > 2 {
 				return fmt.Errorf("invalid profile: %v", val)
 			}
 
@@ -43,7 +50,7 @@ func (f *AV1) unmarshal(ctx *unmarshalContext) error {
 
 		case "tier":
 			n, err := strconv.ParseUint(val, 10, 31)
-			if err != nil {
+			if err != nil || n > 1 {
 				return fmt.Errorf("invalid tier: %v", val)
 			}
 
@@ -97,6 +104,13 @@ func (f *AV1) PTSEqualsDTS(*rtp.Packet) bool {
 	return true
 }
 
+// IsRandomAccess checks whether a temporal unit can be decoded
+// independently from previous ones (i.e. it contains a key frame).
+func (f *AV1) IsRandomAccess(tu [][]byte) bool {
+	ok, err := av1.ContainsKeyFrame(tu)
+	return err == nil && ok
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *AV1) CreateDecoder() (*rtpav1.Decoder, error) {
 	d := &rtpav1.Decoder{}
@@ -34,6 +34,28 @@ func TestAV1DecEncoder(t *testing.T) {
 	require.Equal(t, [][]byte{{0x01, 0x02, 0x03, 0x04}}, byts)
 }
 
+func TestAV1IsRandomAccess(t *testing.T) {
+	format := &AV1{}
+
+	require.Equal(t, true, format.IsRandomAccess([][]byte{
+		{ // sequence header
+			0x0a, 0x0e, 0x00, 0x00, 0x00, 0x4a, 0xab, 0xbf,
+			0xc3, 0x77, 0x6b, 0xe4, 0x40, 0x40, 0x40, 0x41,
+		},
+		{ // frame
+			0x32, 0x12, 0x10, 0x00, 0x40,
+		},
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([][]byte{
+		{ // frame
+			0x32, 0x12, 0x30, 0x03, 0xc0,
+		},
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([][]byte{}))
+}
+
 func FuzzUnmarshalAV1(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
@@ -2,7 +2,9 @@
 package format
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pion/rtp"
 )
@@ -16,6 +18,65 @@ func getCodecAndClock(rtpMap string) (string, string) {
 	return strings.ToLower(parts2[0]), parts2[1]
 }
 
+// encoderPacketDuration returns the packet duration that encoders must use,
+// given the ptime and maxptime attributes.
+func encoderPacketDuration(packetDuration time.Duration, maxPacketDuration time.Duration) time.Duration {
+	if packetDuration == 0 || (maxPacketDuration != 0 && maxPacketDuration < packetDuration) {
+		return maxPacketDuration
+	}
+	return packetDuration
+}
+
+// MarshalFMTP encodes fmtp parameters into the value of a fmtp attribute.
+func MarshalFMTP(fmtp map[string]string) string {
+	keys := make([]string, 0, len(fmtp))
+	for key := range fmtp {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tmp := make([]string, len(keys))
+	for i, key := range keys {
+		if fmtp[key] == "" {
+			tmp[i] = key
+		} else {
+			tmp[i] = key + "=" + fmtp[key]
+		}
+	}
+
+	return strings.Join(tmp, "; ")
+}
+
+// UnmarshalFMTP decodes the value of a fmtp attribute into fmtp parameters.
+// It returns nil when the value is empty.
+func UnmarshalFMTP(enc string) map[string]string {
+	if enc == "" {
+		return nil
+	}
+
+	ret := make(map[string]string)
+
+	for _, kv := range strings.Split(enc, ";") {
+		kv = strings.Trim(kv, " ")
+
+		if len(kv) == 0 {
+			continue
+		}
+
+		tmp := strings.SplitN(kv, "=", 2)
+
+		// parameters without a value are flags
+		if len(tmp) != 2 {
+			ret[strings.ToLower(tmp[0])] = ""
+			continue
+		}
+
+		ret[strings.ToLower(tmp[0])] = tmp[1]
+	}
+
+	return ret
+}
+
 type unmarshalContext struct {
 	mediaType   string
 	payloadType uint8
@@ -107,6 +168,9 @@ func Unmarshal(mediaType string, payloadType uint8, rtpMap string, fmtp map[stri
 			case codec == "h264" && clock == "90000":
 				return &H264{}
 
+			case codec == "raw" && clock == "90000":
+				return &RawVideo{}
+
 			case codec == "mp4v-es" && clock == "90000":
 				return &MPEG4Video{}
 
@@ -142,6 +206,22 @@ func Unmarshal(mediaType string, payloadType uint8, rtpMap string, fmtp map[stri
 
 			case codec == "l8", codec == "l16", codec == "l24":
 				return &LPCM{}
+
+			// text
+
+			case codec == "t140" && clock == "1000":
+				return &T140{}
+
+			// other
+
+			case codec == "rtx":
+				return &RTX{}
+
+			case codec == "red":
+				return &RED{}
+
+			case codec == "ulpfec":
+				return &ULPFEC{}
 			}
 		}
 
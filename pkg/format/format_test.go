@@ -1,6 +1,8 @@
 package format
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -654,6 +656,67 @@ var casesFormat = []struct {
 			"sprop-stereo": "1",
 		},
 	},
+	{
+		"audio opus with fmtp extensions",
+		"audio",
+		111,
+		"opus/48000/2",
+		map[string]string{
+			"sprop-stereo":      "1",
+			"useinbandfec":      "1",
+			"usedtx":            "1",
+			"maxaveragebitrate": "64000",
+			"maxplaybackrate":   "16000",
+			"minptime":          "10",
+		},
+		&Opus{
+			PayloadTyp:        111,
+			IsStereo:          true,
+			ChannelCount:      2,
+			InBandFEC:         true,
+			DTX:               true,
+			MaxAverageBitrate: 64000,
+			MaxPlaybackRate:   16000,
+			OtherFMTP: map[string]string{
+				"minptime": "10",
+			},
+		},
+		"opus/48000/2",
+		map[string]string{
+			"sprop-stereo":      "1",
+			"useinbandfec":      "1",
+			"usedtx":            "1",
+			"maxaveragebitrate": "64000",
+			"maxplaybackrate":   "16000",
+			"minptime":          "10",
+		},
+	},
+	{
+		"audio opus with invalid fmtp extensions",
+		"audio",
+		111,
+		"opus/48000/2",
+		map[string]string{
+			"sprop-stereo":      "1",
+			"maxaveragebitrate": "1000000",
+			"maxplaybackrate":   "abc",
+		},
+		&Opus{
+			PayloadTyp:   111,
+			IsStereo:     true,
+			ChannelCount: 2,
+			OtherFMTP: map[string]string{
+				"maxaveragebitrate": "1000000",
+				"maxplaybackrate":   "abc",
+			},
+		},
+		"opus/48000/2",
+		map[string]string{
+			"sprop-stereo":      "1",
+			"maxaveragebitrate": "1000000",
+			"maxplaybackrate":   "abc",
+		},
+	},
 	{
 		"audio opus 5.1",
 		"audio",
@@ -665,8 +728,11 @@ var casesFormat = []struct {
 			"channel_mapping": "0,4,1,2,3,5",
 		},
 		&Opus{
-			PayloadTyp:   96,
-			ChannelCount: 6,
+			PayloadTyp:         96,
+			ChannelCount:       6,
+			StreamCount:        4,
+			CoupledStreamCount: 2,
+			ChannelMapping:     []int{0, 4, 1, 2, 3, 5},
 		},
 		"multiopus/48000/6",
 		map[string]string{
@@ -676,6 +742,31 @@ var casesFormat = []struct {
 			"sprop-maxcapturerate": "48000",
 		},
 	},
+	{
+		"audio multiopus ambisonic",
+		"audio",
+		96,
+		"multiopus/48000/6",
+		map[string]string{
+			"num_streams":     "6",
+			"coupled_streams": "0",
+			"channel_mapping": "0,1,2,3,4,5",
+		},
+		&Opus{
+			PayloadTyp:         96,
+			ChannelCount:       6,
+			StreamCount:        6,
+			CoupledStreamCount: 0,
+			ChannelMapping:     []int{0, 1, 2, 3, 4, 5},
+		},
+		"multiopus/48000/6",
+		map[string]string{
+			"channel_mapping":      "0,1,2,3,4,5",
+			"coupled_streams":      "0",
+			"num_streams":          "6",
+			"sprop-maxcapturerate": "48000",
+		},
+	},
 	{
 		"audio ac3",
 		"audio",
@@ -714,6 +805,25 @@ var casesFormat = []struct {
 		"JPEG/90000",
 		nil,
 	},
+	{
+		"video jpeg with quantization tables",
+		"video",
+		26,
+		"JPEG/90000",
+		map[string]string{
+			"x-quantization-tables": strings.Repeat("01", 64) + strings.Repeat("02", 64),
+		},
+		&MJPEG{
+			QuantizationTables: [][]byte{
+				bytes.Repeat([]byte{0x01}, 64),
+				bytes.Repeat([]byte{0x02}, 64),
+			},
+		},
+		"JPEG/90000",
+		map[string]string{
+			"x-quantization-tables": strings.Repeat("01", 64) + strings.Repeat("02", 64),
+		},
+	},
 	{
 		"video mpeg1 video",
 		"video",
@@ -918,6 +1028,26 @@ var casesFormat = []struct {
 			"packetization-mode": "1",
 		},
 	},
+	{
+		"video h264 interleaved",
+		"video",
+		96,
+		"H264/90000",
+		map[string]string{
+			"packetization-mode":       "2",
+			"sprop-interleaving-depth": "4",
+		},
+		&H264{
+			PayloadTyp:        96,
+			PacketizationMode: 2,
+			InterleavingDepth: 4,
+		},
+		"H264/90000",
+		map[string]string{
+			"packetization-mode":       "2",
+			"sprop-interleaving-depth": "4",
+		},
+	},
 	{
 		"video h265",
 		"video",
@@ -991,6 +1121,38 @@ var casesFormat = []struct {
 			"sprop-pps": "RAHgdrAmQA==",
 		},
 	},
+	{
+		"video raw",
+		"video",
+		96,
+		"raw/90000",
+		map[string]string{
+			"sampling":    "YCbCr-4:2:2",
+			"width":       "1920",
+			"height":      "1080",
+			"depth":       "10",
+			"colorimetry": "BT709",
+			"interlace":   "",
+		},
+		&RawVideo{
+			PayloadTyp:  96,
+			Sampling:    "YCbCr-4:2:2",
+			BitDepth:    10,
+			Width:       1920,
+			Height:      1080,
+			Colorimetry: "BT709",
+			Interlaced:  true,
+		},
+		"raw/90000",
+		map[string]string{
+			"sampling":    "YCbCr-4:2:2",
+			"width":       "1920",
+			"height":      "1080",
+			"depth":       "10",
+			"colorimetry": "BT709",
+			"interlace":   "",
+		},
+	},
 	{
 		"video vp8",
 		"video",
@@ -1057,6 +1219,89 @@ var casesFormat = []struct {
 			"tier":      "1",
 		},
 	},
+	{
+		"video rtx",
+		"video",
+		97,
+		"rtx/90000",
+		map[string]string{
+			"apt":      "96",
+			"rtx-time": "3000",
+		},
+		&RTX{
+			PayloadTyp: 97,
+			ClockRat:   90000,
+			APT:        96,
+			RTXTime:    intPtr(3000),
+		},
+		"rtx/90000",
+		map[string]string{
+			"apt":      "96",
+			"rtx-time": "3000",
+		},
+	},
+	{
+		"audio red",
+		"audio",
+		100,
+		"red/48000/2",
+		map[string]string{},
+		&RED{
+			PayloadTyp:   100,
+			ClockRat:     48000,
+			ChannelCount: 2,
+		},
+		"red/48000/2",
+		nil,
+	},
+	{
+		"text t140",
+		"text",
+		98,
+		"t140/1000",
+		map[string]string{
+			"cps": "30",
+		},
+		&T140{
+			PayloadTyp: 98,
+			CPS:        intPtr(30),
+		},
+		"t140/1000",
+		map[string]string{
+			"cps": "30",
+		},
+	},
+	{
+		"text red",
+		"text",
+		100,
+		"red/1000",
+		map[string]string{
+			"98/98/98": "",
+		},
+		&RED{
+			PayloadTyp:   100,
+			ClockRat:     1000,
+			PayloadTypes: []uint8{98, 98, 98},
+		},
+		"red/1000",
+		map[string]string{
+			"98/98/98": "",
+		},
+	},
+	{
+		"video ulpfec",
+		"video",
+		127,
+		"ulpfec/90000",
+		nil,
+		&ULPFEC{
+			PayloadTyp: 127,
+			ClockRat:   90000,
+		},
+		"ulpfec/90000",
+		nil,
+	},
 	{
 		"application",
 		"application",
@@ -1123,4 +1368,42 @@ func TestUnmarshalErrors(t *testing.T) {
 		_, err := Unmarshal("video", 96, "", map[string]string{})
 		require.Error(t, err)
 	})
+
+	t.Run("invalid mjpeg quantization tables", func(t *testing.T) {
+		_, err := Unmarshal("video", 26, "JPEG/90000", map[string]string{
+			"x-quantization-tables": "0102",
+		})
+		require.EqualError(t, err, "invalid x-quantization-tables: 0102")
+	})
+
+	t.Run("invalid av1 profile", func(t *testing.T) {
+		_, err := Unmarshal("video", 96, "AV1/90000", map[string]string{
+			"profile": "3",
+		})
+		require.EqualError(t, err, "invalid profile: 3")
+	})
+
+	t.Run("invalid av1 level-idx", func(t *testing.T) {
+		_, err := Unmarshal("video", 96, "AV1/90000", map[string]string{
+			"level-idx": "32",
+		})
+		require.EqualError(t, err, "invalid level-idx: 32")
+	})
+
+	t.Run("invalid av1 tier", func(t *testing.T) {
+		_, err := Unmarshal("video", 96, "AV1/90000", map[string]string{
+			"tier": "2",
+		})
+		require.EqualError(t, err, "invalid tier: 2")
+	})
+
+	t.Run("invalid g711 sample rate", func(t *testing.T) {
+		_, err := Unmarshal("audio", 96, "PCMA/0", nil)
+		require.EqualError(t, err, "invalid sample rate: '0'")
+	})
+
+	t.Run("invalid g711 channel count", func(t *testing.T) {
+		_, err := Unmarshal("audio", 96, "PCMU/16000/0", nil)
+		require.EqualError(t, err, "invalid channel count: '0'")
+	})
 }
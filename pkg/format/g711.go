@@ -1,8 +1,10 @@
 package format
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pion/rtp"
 
@@ -12,10 +14,20 @@ import (
 // G711 is the RTP format for the G711 codec, encoded with mu-law or A-law.
 // Specification: https://datatracker.ietf.org/doc/html/rfc3551
 type G711 struct {
-	PayloadTyp   uint8
-	MULaw        bool
+	PayloadTyp uint8
+	MULaw      bool
+
+	// sample rate and channel count.
+	// They are always 8000 and 1 with static payload types (0 and 8),
+	// while they are read from the rtpmap with dynamic payload types.
 	SampleRate   int
 	ChannelCount int
+
+	// duration of each packet, filled with the ptime attribute (optional).
+	PacketDuration time.Duration
+
+	// maximum duration of each packet, filled with the maxptime attribute (optional).
+	MaxPacketDuration time.Duration
 }
 
 func (f *G711) unmarshal(ctx *unmarshalContext) error {
@@ -40,15 +52,15 @@ func (f *G711) unmarshal(ctx *unmarshalContext) error {
 	tmp := strings.SplitN(ctx.clock, "/", 2)
 
 	tmp1, err := strconv.ParseUint(tmp[0], 10, 31)
-	if err != nil {
-		return err
+	if err != nil || tmp1 == 0 {
+		return fmt.Errorf("invalid sample rate: '%s'", tmp[0])
 	}
 	f.SampleRate = int(tmp1)
 
 	if len(tmp) >= 2 {
 		tmp1, err := strconv.ParseUint(tmp[1], 10, 31)
-		if err != nil {
-			return err
+		if err != nil || tmp1 == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
 		}
 		f.ChannelCount = int(tmp1)
 	} else {
@@ -107,6 +119,7 @@ func (f *G711) CreateDecoder() (*rtplpcm.Decoder, error) {
 	d := &rtplpcm.Decoder{
 		BitDepth:     8,
 		ChannelCount: f.ChannelCount,
+		SampleRate:   f.SampleRate,
 	}
 
 	err := d.Init()
@@ -120,9 +133,11 @@ func (f *G711) CreateDecoder() (*rtplpcm.Decoder, error) {
 // CreateEncoder creates an encoder able to encode the content of the format.
 func (f *G711) CreateEncoder() (*rtplpcm.Encoder, error) {
 	e := &rtplpcm.Encoder{
-		PayloadType:  f.PayloadType(),
-		BitDepth:     8,
-		ChannelCount: f.ChannelCount,
+		PayloadType:    f.PayloadType(),
+		BitDepth:       8,
+		ChannelCount:   f.ChannelCount,
+		SampleRate:     f.SampleRate,
+		PacketDuration: encoderPacketDuration(f.PacketDuration, f.MaxPacketDuration),
 	}
 
 	err := e.Init()
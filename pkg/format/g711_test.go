@@ -1,7 +1,9 @@
 package format
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -74,3 +76,38 @@ func TestG711DecEncoder(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, byts)
 }
+
+func TestG711DecEncoderMultichannel(t *testing.T) {
+	format := &G711{
+		PayloadTyp:     96,
+		MULaw:          false,
+		SampleRate:     16000,
+		ChannelCount:   2,
+		PacketDuration: 20 * time.Millisecond,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	samples := bytes.Repeat([]byte{0x01, 0x02}, 640)
+
+	pkts, err := enc.Encode(samples)
+	require.NoError(t, err)
+	require.Len(t, pkts, 2)
+	require.Equal(t, uint32(320), pkts[1].Timestamp-pkts[0].Timestamp)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	var decoded []byte
+
+	for _, pkt := range pkts {
+		require.Len(t, pkt.Payload, 320*2)
+
+		byts, err := dec.Decode(pkt)
+		require.NoError(t, err)
+		decoded = append(decoded, byts...)
+	}
+
+	require.Equal(t, samples, decoded)
+}
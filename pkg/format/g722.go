@@ -1,6 +1,8 @@
 package format
 
 import (
+	"time"
+
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format/rtpsimpleaudio"
@@ -8,7 +10,13 @@ import (
 
 // G722 is the RTP format for the G722 codec.
 // Specification: https://datatracker.ietf.org/doc/html/rfc3551
-type G722 struct{}
+type G722 struct {
+	// duration of each packet, filled with the ptime attribute (optional).
+	PacketDuration time.Duration
+
+	// maximum duration of each packet, filled with the maxptime attribute (optional).
+	MaxPacketDuration time.Duration
+}
 
 func (f *G722) unmarshal(_ *unmarshalContext) error {
 	return nil
@@ -53,18 +53,38 @@ func findClockRate(payloadType uint8, rtpMap string, isApplication bool) (int, e
 	return 0, fmt.Errorf("clock rate not found")
 }
 
+// GenericAttribute is an attribute bound to a generic format,
+// in the form a=<key>:<payload type> <value>.
+type GenericAttribute struct {
+	Key   string
+	Value string
+}
+
 // Generic is a generic RTP format.
 type Generic struct {
 	PayloadTyp uint8
 	RTPMa      string
 	FMT        map[string]string
 
+	// fmtp attribute as it appears in the SDP (optional).
+	// When filled, it is used in place of FMT when marshaling, in order
+	// to preserve order and case of parameters.
+	FMTPRaw string
+
+	// additional attributes bound to the format (optional).
+	Attributes []GenericAttribute
+
 	// clock rate of the format. Filled when calling Init().
+	// When the rtpmap is absent, it can be filled by the caller.
 	ClockRat int
 }
 
 // Init computes the clock rate of the format. It is mandatory to call it.
 func (f *Generic) Init() error {
+	if f.RTPMa == "" && f.ClockRat != 0 {
+		return nil
+	}
+
 	var err error
 	f.ClockRat, err = findClockRate(f.PayloadTyp, f.RTPMa, true)
 	return err
@@ -100,12 +120,41 @@ func (f *Generic) RTPMap() string {
 	return f.RTPMa
 }
 
+// SetRTPMap sets payload type, rtpmap and clock rate of the format,
+// building the rtpmap in the form <encoding name>/<clock rate>[/<encoding parameters>].
+func (f *Generic) SetRTPMap(pt uint8, encodingName string, clockRate int, params string) {
+	f.PayloadTyp = pt
+	f.RTPMa = encodingName + "/" + strconv.FormatInt(int64(clockRate), 10)
+	if params != "" {
+		f.RTPMa += "/" + params
+	}
+	f.ClockRat = clockRate
+}
+
 // FMTP implements Format.
 func (f *Generic) FMTP() map[string]string {
 	return f.FMT
 }
 
+// ChannelCount returns the channel count contained in the rtpmap attribute.
+// It returns zero if the channel count is not present.
+func (f *Generic) ChannelCount() int {
+	tmp := strings.Split(f.RTPMa, "/")
+	if len(tmp) >= 3 {
+		v, err := strconv.ParseUint(tmp[2], 10, 31)
+		if err == nil {
+			return int(v)
+		}
+	}
+	return 0
+}
+
 // PTSEqualsDTS implements Format.
 func (f *Generic) PTSEqualsDTS(*rtp.Packet) bool {
 	return true
 }
+
+// Payload returns the payload of a RTP packet, without parsing it.
+func (f *Generic) Payload(pkt *rtp.Packet) []byte {
+	return pkt.Payload
+}
@@ -25,3 +25,48 @@ func TestGenericAttributes(t *testing.T) {
 	require.Equal(t, 90000, format.ClockRate())
 	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
 }
+
+func TestGenericChannelCount(t *testing.T) {
+	format := &Generic{
+		PayloadTyp: 97,
+		RTPMa:      "L16/44100/2",
+	}
+	err := format.Init()
+	require.NoError(t, err)
+	require.Equal(t, 2, format.ChannelCount())
+
+	format = &Generic{
+		PayloadTyp: 98,
+		RTPMa:      "X-Custom/90000",
+	}
+	err = format.Init()
+	require.NoError(t, err)
+	require.Equal(t, 0, format.ChannelCount())
+}
+
+func TestGenericClockRateWithoutRTPMap(t *testing.T) {
+	format := &Generic{
+		PayloadTyp: 98,
+		ClockRat:   16000,
+	}
+	err := format.Init()
+	require.NoError(t, err)
+	require.Equal(t, 16000, format.ClockRate())
+}
+
+func TestGenericSetRTPMap(t *testing.T) {
+	format := &Generic{}
+	format.SetRTPMap(97, "X-Custom", 48000, "2")
+	require.Equal(t, uint8(97), format.PayloadType())
+	require.Equal(t, "X-Custom/48000/2", format.RTPMap())
+	require.Equal(t, 48000, format.ClockRate())
+	require.Equal(t, 2, format.ChannelCount())
+
+	err := format.Init()
+	require.NoError(t, err)
+	require.Equal(t, 48000, format.ClockRate())
+
+	format.SetRTPMap(98, "X-Other", 90000, "")
+	require.Equal(t, "X-Other/90000", format.RTPMap())
+	require.Equal(t, []byte{1, 2, 3}, format.Payload(&rtp.Packet{Payload: []byte{1, 2, 3}}))
+}
@@ -12,9 +12,176 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format/rtph264"
+	"github.com/bluenviron/mediacommon/pkg/bits"
 	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
 )
 
+// ErrInvalidSPS is returned when a H264 SPS is missing or invalid.
+type ErrInvalidSPS struct {
+	SPS []byte
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrInvalidSPS) Error() string {
+	return fmt.Sprintf("invalid SPS (%x): %v", e.SPS, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrInvalidSPS) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidPPS is returned when a H264 PPS is missing or invalid.
+type ErrInvalidPPS struct {
+	PPS []byte
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrInvalidPPS) Error() string {
+	return fmt.Sprintf("invalid PPS (%x): %v", e.PPS, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrInvalidPPS) Unwrap() error {
+	return e.Err
+}
+
+// h264MarshalSpropParameterSets encodes SPS and PPS into the value of sprop-parameter-sets.
+func h264MarshalSpropParameterSets(sps []byte, pps []byte) string {
+	var tmp []string
+	if sps != nil {
+		tmp = append(tmp, base64.StdEncoding.EncodeToString(sps))
+	}
+	if pps != nil {
+		tmp = append(tmp, base64.StdEncoding.EncodeToString(pps))
+	}
+	return strings.Join(tmp, ",")
+}
+
+// h264UnmarshalSpropParameterSets decodes SPS and PPS from the value of sprop-parameter-sets.
+// It returns nil parameters if the value doesn't contain both.
+func h264UnmarshalSpropParameterSets(val string) ([]byte, []byte, error) {
+	tmp := strings.Split(val, ",")
+	if len(tmp) < 2 {
+		return nil, nil, nil
+	}
+
+	sps, err := base64.StdEncoding.DecodeString(tmp[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sprop-parameter-sets (%v)", val)
+	}
+
+	// some cameras ship parameters with Annex-B prefix
+	sps = bytes.TrimPrefix(sps, []byte{0, 0, 0, 1})
+
+	pps, err := base64.StdEncoding.DecodeString(tmp[1])
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sprop-parameter-sets (%v)", val)
+	}
+
+	// some cameras ship parameters with Annex-B prefix
+	pps = bytes.TrimPrefix(pps, []byte{0, 0, 0, 1})
+
+	return sps, pps, nil
+}
+
+// h264MarshalProfileLevelID returns the value of profile-level-id,
+// that is made of the three bytes that follow the NALU header of the SPS.
+func h264MarshalProfileLevelID(sps []byte) string {
+	return strings.ToUpper(hex.EncodeToString(sps[1:4]))
+}
+
+// profile_idc values allowed by the H264 specification.
+var h264ValidProfiles = map[uint8]struct{}{
+	44: {}, 66: {}, 77: {}, 83: {}, 86: {}, 88: {}, 100: {}, 110: {},
+	118: {}, 122: {}, 128: {}, 134: {}, 135: {}, 138: {}, 139: {}, 244: {},
+}
+
+// level_idc values allowed by the H264 specification.
+var h264ValidLevels = map[uint8]struct{}{
+	9: {}, 10: {}, 11: {}, 12: {}, 13: {}, 20: {}, 21: {}, 22: {}, 30: {}, 31: {},
+	32: {}, 40: {}, 41: {}, 42: {}, 50: {}, 51: {}, 52: {}, 60: {}, 61: {}, 62: {},
+}
+
+func validateH264SPS(buf []byte) error {
+	if len(buf) == 0 {
+		return fmt.Errorf("SPS is missing")
+	}
+
+	if typ := h264.NALUType(buf[0] & 0x1F); typ != h264.NALUTypeSPS {
+		return fmt.Errorf("wrong NALU type: %v", typ)
+	}
+
+	var sps h264.SPS
+	err := sps.Unmarshal(buf)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := h264ValidProfiles[sps.ProfileIdc]; !ok {
+		return fmt.Errorf("unsupported profile_idc: %d", sps.ProfileIdc)
+	}
+
+	if _, ok := h264ValidLevels[sps.LevelIdc]; !ok {
+		return fmt.Errorf("unsupported level_idc: %d", sps.LevelIdc)
+	}
+
+	if sps.ID > 31 {
+		return fmt.Errorf("seq_parameter_set_id out of range: %d", sps.ID)
+	}
+
+	return nil
+}
+
+func validateH264PPS(buf []byte) error {
+	if len(buf) == 0 {
+		return fmt.Errorf("PPS is missing")
+	}
+
+	if typ := h264.NALUType(buf[0] & 0x1F); typ != h264.NALUTypePPS {
+		return fmt.Errorf("wrong NALU type: %v", typ)
+	}
+
+	buf = h264.EmulationPreventionRemove(buf[1:])
+	pos := 0
+
+	id, err := bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if id > 255 {
+		return fmt.Errorf("pic_parameter_set_id out of range: %d", id)
+	}
+
+	spsID, err := bits.ReadGolombUnsigned(buf, &pos)
+	if err != nil {
+		return err
+	}
+
+	if spsID > 31 {
+		return fmt.Errorf("seq_parameter_set_id out of range: %d", spsID)
+	}
+
+	return nil
+}
+
+// H264Profile is a H264 profile, as defined by profile_idc.
+type H264Profile int
+
+// H264 profiles.
+const (
+	H264ProfileBaseline H264Profile = 66
+	H264ProfileMain     H264Profile = 77
+	H264ProfileExtended H264Profile = 88
+	H264ProfileHigh     H264Profile = 100
+	H264ProfileHigh10   H264Profile = 110
+	H264ProfileHigh422  H264Profile = 122
+	H264ProfileHigh444  H264Profile = 244
+)
+
 // H264 is the RTP format for the H264 codec.
 // Specification: https://datatracker.ietf.org/doc/html/rfc6184
 type H264 struct {
@@ -23,6 +190,10 @@ type H264 struct {
 	PPS               []byte
 	PacketizationMode int
 
+	// value of sprop-interleaving-depth.
+	// It is used only when PacketizationMode is 2.
+	InterleavingDepth int
+
 	mutex sync.RWMutex
 }
 
@@ -32,34 +203,24 @@ func (f *H264) unmarshal(ctx *unmarshalContext) error {
 	for key, val := range ctx.fmtp {
 		switch key {
 		case "sprop-parameter-sets":
-			tmp := strings.Split(val, ",")
-			if len(tmp) >= 2 {
-				sps, err := base64.StdEncoding.DecodeString(tmp[0])
-				if err != nil {
-					return fmt.Errorf("invalid sprop-parameter-sets (%v)", val)
-				}
-
-				// some cameras ship parameters with Annex-B prefix
-				sps = bytes.TrimPrefix(sps, []byte{0, 0, 0, 1})
-
-				pps, err := base64.StdEncoding.DecodeString(tmp[1])
-				if err != nil {
-					return fmt.Errorf("invalid sprop-parameter-sets (%v)", val)
-				}
-
-				// some cameras ship parameters with Annex-B prefix
-				pps = bytes.TrimPrefix(pps, []byte{0, 0, 0, 1})
-
-				var spsp h264.SPS
-				err = spsp.Unmarshal(sps)
-				if err != nil {
-					continue
-				}
-
-				f.SPS = sps
-				f.PPS = pps
+			sps, pps, err := h264UnmarshalSpropParameterSets(val)
+			if err != nil {
+				return err
 			}
 
+			if sps == nil {
+				continue
+			}
+
+			var spsp h264.SPS
+			err = spsp.Unmarshal(sps)
+			if err != nil {
+				continue
+			}
+
+			f.SPS = sps
+			f.PPS = pps
+
 		case "packetization-mode":
 			tmp, err := strconv.ParseUint(val, 10, 31)
 			if err != nil {
@@ -67,6 +228,14 @@ func (f *H264) unmarshal(ctx *unmarshalContext) error {
 			}
 
 			f.PacketizationMode = int(tmp)
+
+		case "sprop-interleaving-depth":
+			tmp, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid sprop-interleaving-depth (%v)", val)
+			}
+
+			f.InterleavingDepth = int(tmp)
 		}
 	}
 
@@ -104,23 +273,49 @@ func (f *H264) FMTP() map[string]string {
 		fmtp["packetization-mode"] = strconv.FormatInt(int64(f.PacketizationMode), 10)
 	}
 
-	var tmp []string
-	if f.SPS != nil {
-		tmp = append(tmp, base64.StdEncoding.EncodeToString(f.SPS))
-	}
-	if f.PPS != nil {
-		tmp = append(tmp, base64.StdEncoding.EncodeToString(f.PPS))
+	if f.InterleavingDepth != 0 {
+		fmtp["sprop-interleaving-depth"] = strconv.FormatInt(int64(f.InterleavingDepth), 10)
 	}
-	if tmp != nil {
-		fmtp["sprop-parameter-sets"] = strings.Join(tmp, ",")
+
+	if f.SPS != nil || f.PPS != nil {
+		fmtp["sprop-parameter-sets"] = h264MarshalSpropParameterSets(f.SPS, f.PPS)
 	}
 	if len(f.SPS) >= 4 {
-		fmtp["profile-level-id"] = strings.ToUpper(hex.EncodeToString(f.SPS[1:4]))
+		fmtp["profile-level-id"] = h264MarshalProfileLevelID(f.SPS)
 	}
 
 	return fmtp
 }
 
+// MarshalFmtp returns the value of the fmtp attribute of the format,
+// that contains packetization-mode, sprop-parameter-sets and profile-level-id.
+func (f *H264) MarshalFmtp() string {
+	return MarshalFMTP(f.FMTP())
+}
+
+// UnmarshalFmtp sets the parameters of the format from the value of a fmtp attribute.
+// Parameters that are not present in the value are reset.
+func (f *H264) UnmarshalFmtp(s string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var tmp H264
+	err := tmp.unmarshal(&unmarshalContext{
+		payloadType: f.PayloadTyp,
+		fmtp:        UnmarshalFMTP(s),
+	})
+	if err != nil {
+		return err
+	}
+
+	f.SPS = tmp.SPS
+	f.PPS = tmp.PPS
+	f.PacketizationMode = tmp.PacketizationMode
+	f.InterleavingDepth = tmp.InterleavingDepth
+
+	return nil
+}
+
 // PTSEqualsDTS implements Format.
 func (f *H264) PTSEqualsDTS(pkt *rtp.Packet) bool {
 	if len(pkt.Payload) == 0 {
@@ -182,10 +377,23 @@ func (f *H264) PTSEqualsDTS(pkt *rtp.Packet) bool {
 	return false
 }
 
+// IsRandomAccess checks whether an access unit can be decoded
+// independently from previous ones (i.e. it contains an IDR).
+func (f *H264) IsRandomAccess(au [][]byte) bool {
+	return h264.IDRPresent(au)
+}
+
+// IDROnlyFilter returns a filter that keeps only the first packet of each
+// IDR access unit. It can be used to forward keyframes only.
+func (f *H264) IDROnlyFilter() rtph264.PacketFilter {
+	return rtph264.NewIDROnlyFilter()
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *H264) CreateDecoder() (*rtph264.Decoder, error) {
 	d := &rtph264.Decoder{
 		PacketizationMode: f.PacketizationMode,
+		InterleavingDepth: f.InterleavingDepth,
 	}
 
 	err := d.Init()
@@ -196,6 +404,25 @@ func (f *H264) CreateDecoder() (*rtph264.Decoder, error) {
 	return d, nil
 }
 
+// CreateDecoderSafe creates a decoder able to decode the content of the format,
+// after checking that SPS and PPS are present and valid.
+// It returns ErrInvalidSPS or ErrInvalidPPS otherwise.
+func (f *H264) CreateDecoderSafe() (*rtph264.Decoder, error) {
+	sps, pps := f.SafeParams()
+
+	err := validateH264SPS(sps)
+	if err != nil {
+		return nil, ErrInvalidSPS{SPS: sps, Err: err}
+	}
+
+	err = validateH264PPS(pps)
+	if err != nil {
+		return nil, ErrInvalidPPS{PPS: pps, Err: err}
+	}
+
+	return f.CreateDecoder()
+}
+
 // CreateEncoder creates an encoder able to encode the content of the format.
 func (f *H264) CreateEncoder() (*rtph264.Encoder, error) {
 	e := &rtph264.Encoder{
@@ -225,3 +452,111 @@ func (f *H264) SafeParams() ([]byte, []byte) {
 	defer f.mutex.RUnlock()
 	return f.SPS, f.PPS
 }
+
+// SafeSetParamsFromRTP sets the codec parameters with the SPS and PPS
+// contained inside a RTP packet, if any.
+// It returns true if parameters have changed.
+func (f *H264) SafeSetParamsFromRTP(pkt *rtp.Packet) bool {
+	var sps []byte
+	var pps []byte
+
+	collect := func(nalu []byte) {
+		switch h264.NALUType(nalu[0] & 0x1F) {
+		case h264.NALUTypeSPS:
+			sps = nalu
+		case h264.NALUTypePPS:
+			pps = nalu
+		}
+	}
+
+	if len(pkt.Payload) == 0 {
+		return false
+	}
+
+	if pkt.Payload[0]&0x1F == 24 { // STAP-A
+		payload := pkt.Payload[1:]
+
+		for len(payload) >= 2 {
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			payload = payload[2:]
+
+			if size == 0 || int(size) > len(payload) {
+				return false
+			}
+
+			collect(payload[:size])
+			payload = payload[size:]
+		}
+	} else {
+		collect(pkt.Payload)
+	}
+
+	if sps == nil && pps == nil {
+		return false
+	}
+
+	if sps != nil {
+		var spsp h264.SPS
+		err := spsp.Unmarshal(sps)
+		if err != nil {
+			return false
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	changed := false
+
+	if sps != nil && !bytes.Equal(sps, f.SPS) {
+		f.SPS = append([]byte(nil), sps...)
+		changed = true
+	}
+
+	if pps != nil && !bytes.Equal(pps, f.PPS) {
+		f.PPS = append([]byte(nil), pps...)
+		changed = true
+	}
+
+	return changed
+}
+
+func (f *H264) parseSPS() (*h264.SPS, bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if f.SPS == nil {
+		return nil, false
+	}
+
+	var sps h264.SPS
+	err := sps.Unmarshal(f.SPS)
+	if err != nil {
+		return nil, false
+	}
+
+	return &sps, true
+}
+
+// Profile returns the profile of the stream, parsed from the SPS.
+// It returns false if the SPS is not available or invalid.
+func (f *H264) Profile() (H264Profile, bool) {
+	sps, ok := f.parseSPS()
+	if !ok {
+		return 0, false
+	}
+
+	return H264Profile(sps.ProfileIdc), true
+}
+
+// Level returns the level of the stream, parsed from the SPS.
+// The level is expressed as level_idc, that is, the level number multiplied by 10 (i.e. 31 for level 3.1).
+// It returns false if the SPS is not available or invalid.
+func (f *H264) Level() (int, bool) {
+	sps, ok := f.parseSPS()
+	if !ok {
+		return 0, false
+	}
+
+	return int(sps.LevelIdc), true
+}
@@ -1,6 +1,7 @@
 package format
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/pion/rtp"
@@ -28,6 +29,127 @@ func TestH264Attributes(t *testing.T) {
 	require.Equal(t, []byte{0x09, 0x0A}, pps)
 }
 
+func TestH264MarshalFmtp(t *testing.T) {
+	sps := []byte{
+		0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+		0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+		0x00, 0x03, 0x00, 0x3d, 0x08,
+	}
+
+	format := &H264{
+		PayloadTyp:        96,
+		SPS:               sps,
+		PPS:               []byte{0x68, 0xee, 0x3c, 0x80},
+		PacketizationMode: 1,
+	}
+
+	fmtp := format.MarshalFmtp()
+	require.Equal(t, "packetization-mode=1; profile-level-id=64000C; "+
+		"sprop-parameter-sets=Z2QADKw7ULBLQgAAAwACAAADAD0I,aO48gA==", fmtp)
+
+	format2 := &H264{
+		PayloadTyp: 96,
+	}
+	err := format2.UnmarshalFmtp(fmtp)
+	require.NoError(t, err)
+	require.Equal(t, format.SPS, format2.SPS)
+	require.Equal(t, format.PPS, format2.PPS)
+	require.Equal(t, format.PacketizationMode, format2.PacketizationMode)
+
+	err = format2.UnmarshalFmtp("")
+	require.NoError(t, err)
+	require.Equal(t, []byte(nil), format2.SPS)
+	require.Equal(t, []byte(nil), format2.PPS)
+	require.Equal(t, 0, format2.PacketizationMode)
+
+	err = format2.UnmarshalFmtp("sprop-parameter-sets=Z2QADKw7ULBLQgAAAwACAAADAD0I,aO4$gA==")
+	require.EqualError(t, err, "invalid sprop-parameter-sets (Z2QADKw7ULBLQgAAAwACAAADAD0I,aO4$gA==)")
+}
+
+func TestH264SafeSetParamsFromRTP(t *testing.T) {
+	sps := []byte{
+		0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+		0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+		0x00, 0x03, 0x00, 0x3d, 0x08,
+	}
+
+	format := &H264{
+		PayloadTyp:        96,
+		PacketizationMode: 1,
+	}
+
+	changed := format.SafeSetParamsFromRTP(&rtp.Packet{
+		Payload: []byte{0x05, 0x01},
+	})
+	require.Equal(t, false, changed)
+
+	stapa := []byte{0x18, 0x00, byte(len(sps))}
+	stapa = append(stapa, sps...)
+	stapa = append(stapa, 0x00, 0x02, 0x68, 0x01)
+
+	changed = format.SafeSetParamsFromRTP(&rtp.Packet{
+		Payload: stapa,
+	})
+	require.Equal(t, true, changed)
+
+	sps2, pps2 := format.SafeParams()
+	require.Equal(t, sps, sps2)
+	require.Equal(t, []byte{0x68, 0x01}, pps2)
+	require.Equal(t, "Z2QADKw7ULBLQgAAAwACAAADAD0I,aAE=", format.FMTP()["sprop-parameter-sets"])
+
+	changed = format.SafeSetParamsFromRTP(&rtp.Packet{
+		Payload: []byte{0x68, 0x01},
+	})
+	require.Equal(t, false, changed)
+
+	changed = format.SafeSetParamsFromRTP(&rtp.Packet{
+		Payload: []byte{0x68, 0x02},
+	})
+	require.Equal(t, true, changed)
+
+	_, pps2 = format.SafeParams()
+	require.Equal(t, []byte{0x68, 0x02}, pps2)
+
+	// invalid SPS
+	changed = format.SafeSetParamsFromRTP(&rtp.Packet{
+		Payload: []byte{0x67, 0x64},
+	})
+	require.Equal(t, false, changed)
+}
+
+func TestH264ProfileLevel(t *testing.T) {
+	format := &H264{
+		PayloadTyp: 96,
+		SPS: []byte{
+			0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+			0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+			0x00, 0x03, 0x00, 0x3d, 0x08,
+		},
+		PacketizationMode: 1,
+	}
+
+	profile, ok := format.Profile()
+	require.Equal(t, true, ok)
+	require.Equal(t, H264ProfileHigh, profile)
+
+	level, ok := format.Level()
+	require.Equal(t, true, ok)
+	require.Equal(t, 12, level)
+
+	format.SPS = nil
+
+	_, ok = format.Profile()
+	require.Equal(t, false, ok)
+
+	_, ok = format.Level()
+	require.Equal(t, false, ok)
+
+	format.SPS = []byte{0x67, 0x64}
+
+	_, ok = format.Profile()
+	require.Equal(t, false, ok)
+}
+
 func TestH264PTSEqualsDTS(t *testing.T) {
 	format := &H264{
 		PayloadTyp:        96,
@@ -62,6 +184,120 @@ func TestH264DecEncoder(t *testing.T) {
 	require.Equal(t, [][]byte{{0x01, 0x02, 0x03, 0x04}}, byts)
 }
 
+func TestH264CreateDecoderSafe(t *testing.T) {
+	sps := []byte{
+		0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+		0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+		0x00, 0x03, 0x00, 0x3d, 0x08,
+	}
+	pps := []byte{0x68, 0xce, 0x3c, 0x80}
+
+	format := &H264{
+		PayloadTyp:        96,
+		SPS:               sps,
+		PPS:               pps,
+		PacketizationMode: 1,
+	}
+
+	_, err := format.CreateDecoderSafe()
+	require.NoError(t, err)
+
+	withByte := func(buf []byte, i int, v byte) []byte {
+		buf = append([]byte(nil), buf...)
+		buf[i] = v
+		return buf
+	}
+
+	for _, ca := range []struct {
+		name string
+		sps  []byte
+		pps  []byte
+		err  string
+	}{
+		{
+			"missing sps",
+			nil,
+			pps,
+			"invalid SPS (): SPS is missing",
+		},
+		{
+			"sps with wrong nalu type",
+			withByte(sps, 0, 0x68),
+			pps,
+			"invalid SPS (6864000cac3b50b04b420000030002000003003d08): wrong NALU type: PPS",
+		},
+		{
+			"sps with unsupported profile",
+			withByte(sps, 1, 0x01),
+			pps,
+			"invalid SPS (6701000cac3b50b04b420000030002000003003d08): unsupported profile_idc: 1",
+		},
+		{
+			"sps with unsupported level",
+			withByte(sps, 3, 0x63),
+			pps,
+			"invalid SPS (67640063ac3b50b04b420000030002000003003d08): unsupported level_idc: 99",
+		},
+		{
+			"missing pps",
+			sps,
+			nil,
+			"invalid PPS (): PPS is missing",
+		},
+		{
+			"pps with wrong nalu type",
+			sps,
+			withByte(pps, 0, 0x67),
+			"invalid PPS (67ce3c80): wrong NALU type: SPS",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			format := &H264{
+				PayloadTyp:        96,
+				SPS:               ca.sps,
+				PPS:               ca.pps,
+				PacketizationMode: 1,
+			}
+
+			_, err := format.CreateDecoderSafe()
+			require.EqualError(t, err, ca.err)
+
+			var spsErr ErrInvalidSPS
+			var ppsErr ErrInvalidPPS
+			if errors.As(err, &spsErr) {
+				require.Equal(t, ca.sps, spsErr.SPS)
+			} else {
+				require.True(t, errors.As(err, &ppsErr))
+				require.Equal(t, ca.pps, ppsErr.PPS)
+			}
+		})
+	}
+}
+
+func TestH264IsRandomAccess(t *testing.T) {
+	format := &H264{}
+
+	require.Equal(t, true, format.IsRandomAccess([][]byte{
+		{ // SPS
+			0x67, 0x64, 0x00, 0x0c, 0xac, 0x3b, 0x50, 0xb0,
+			0x4b, 0x42, 0x00, 0x00, 0x03, 0x00, 0x02, 0x00,
+			0x00, 0x03, 0x00, 0x3d, 0x08,
+		},
+		{ // PPS
+			0x68, 0xee, 0x3c, 0x80,
+		},
+		{ // IDR
+			0x65, 0x88, 0x84, 0x00, 0x33, 0xff,
+		},
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([][]byte{
+		{ // non-IDR
+			0x41, 0x9a, 0x24, 0x6c, 0x41, 0x4f, 0xfe, 0xd6,
+		},
+	}))
+}
+
 func FuzzUnmarshalH264(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
@@ -194,6 +194,12 @@ func (f *H265) PTSEqualsDTS(pkt *rtp.Packet) bool {
 	return false
 }
 
+// IsRandomAccess checks whether an access unit can be decoded
+// independently from previous ones (i.e. it contains an IRAP).
+func (f *H265) IsRandomAccess(au [][]byte) bool {
+	return h265.IsRandomAccess(au)
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *H265) CreateDecoder() (*rtph265.Decoder, error) {
 	d := &rtph265.Decoder{
@@ -238,3 +244,117 @@ func (f *H265) SafeParams() ([]byte, []byte, []byte) {
 	defer f.mutex.RUnlock()
 	return f.VPS, f.SPS, f.PPS
 }
+
+// SafeSetParamsFromRTP sets the codec parameters with the VPS, SPS and PPS
+// contained inside a RTP packet, if any.
+// It returns true if parameters have changed.
+func (f *H265) SafeSetParamsFromRTP(pkt *rtp.Packet) bool {
+	var vps []byte
+	var sps []byte
+	var pps []byte
+
+	collect := func(nalu []byte) {
+		if len(nalu) < 2 {
+			return
+		}
+
+		switch h265.NALUType((nalu[0] >> 1) & 0b111111) {
+		case h265.NALUType_VPS_NUT:
+			vps = nalu
+		case h265.NALUType_SPS_NUT:
+			sps = nalu
+		case h265.NALUType_PPS_NUT:
+			pps = nalu
+		}
+	}
+
+	if len(pkt.Payload) < 2 {
+		return false
+	}
+
+	// MaxDONDiff is read without locking, like in CreateDecoder()
+	hasDON := f.MaxDONDiff != 0
+
+	switch h265.NALUType((pkt.Payload[0] >> 1) & 0b111111) {
+	case h265.NALUType_AggregationUnit:
+		payload := pkt.Payload[2:]
+
+		if hasDON {
+			if len(payload) < 2 {
+				return false
+			}
+			payload = payload[2:] // DONL
+		}
+
+		for {
+			if len(payload) < 2 {
+				return false
+			}
+
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			payload = payload[2:]
+
+			if size == 0 || int(size) > len(payload) {
+				return false
+			}
+
+			collect(payload[:size])
+			payload = payload[size:]
+
+			if len(payload) == 0 {
+				break
+			}
+
+			if hasDON {
+				payload = payload[1:] // DOND
+			}
+		}
+
+	case h265.NALUType_FragmentationUnit:
+		return false
+
+	default:
+		if hasDON {
+			if len(pkt.Payload) < 4 {
+				return false
+			}
+			collect(append(append([]byte(nil), pkt.Payload[:2]...), pkt.Payload[4:]...))
+		} else {
+			collect(pkt.Payload)
+		}
+	}
+
+	if vps == nil && sps == nil && pps == nil {
+		return false
+	}
+
+	if sps != nil {
+		var spsp h265.SPS
+		err := spsp.Unmarshal(sps)
+		if err != nil {
+			return false
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	changed := false
+
+	if vps != nil && !bytes.Equal(vps, f.VPS) {
+		f.VPS = append([]byte(nil), vps...)
+		changed = true
+	}
+
+	if sps != nil && !bytes.Equal(sps, f.SPS) {
+		f.SPS = append([]byte(nil), sps...)
+		changed = true
+	}
+
+	if pps != nil && !bytes.Equal(pps, f.PPS) {
+		f.PPS = append([]byte(nil), pps...)
+		changed = true
+	}
+
+	return changed
+}
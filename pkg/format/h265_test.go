@@ -31,6 +31,79 @@ func TestH265Attributes(t *testing.T) {
 	require.Equal(t, []byte{0x0B, 0x0C}, pps)
 }
 
+func TestH265SafeSetParamsFromRTP(t *testing.T) {
+	vps := []byte{
+		0x40, 0x01, 0x0c, 0x01, 0xff, 0xff, 0x01, 0x60,
+		0x00, 0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x03, 0x00, 0x96, 0xac, 0x09,
+	}
+	sps := []byte{
+		0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+		0x00, 0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x00,
+		0x03, 0x00, 0x96, 0xa0, 0x05, 0xa2, 0x01, 0xe1,
+		0x63, 0x6b, 0x92, 0x4c, 0x9a, 0xe5, 0x99,
+	}
+	pps := []byte{
+		0x44, 0x01, 0xe0, 0x76, 0xb0, 0x26, 0x40,
+	}
+
+	t.Run("aggregation unit", func(t *testing.T) {
+		format := &H265{
+			PayloadTyp: 96,
+		}
+
+		payload := []byte{0x60, 0x01}
+		for _, nalu := range [][]byte{vps, sps, pps} {
+			payload = append(payload, byte(len(nalu)>>8), byte(len(nalu)))
+			payload = append(payload, nalu...)
+		}
+
+		changed := format.SafeSetParamsFromRTP(&rtp.Packet{Payload: payload})
+		require.Equal(t, true, changed)
+
+		vps2, sps2, pps2 := format.SafeParams()
+		require.Equal(t, vps, vps2)
+		require.Equal(t, sps, sps2)
+		require.Equal(t, pps, pps2)
+
+		changed = format.SafeSetParamsFromRTP(&rtp.Packet{Payload: payload})
+		require.Equal(t, false, changed)
+	})
+
+	t.Run("single with DON", func(t *testing.T) {
+		format := &H265{
+			PayloadTyp: 96,
+			MaxDONDiff: 2,
+		}
+
+		payload := append([]byte{pps[0], pps[1], 0x00, 0x01}, pps[2:]...)
+
+		changed := format.SafeSetParamsFromRTP(&rtp.Packet{Payload: payload})
+		require.Equal(t, true, changed)
+
+		_, _, pps2 := format.SafeParams()
+		require.Equal(t, pps, pps2)
+	})
+
+	t.Run("invalid SPS", func(t *testing.T) {
+		format := &H265{
+			PayloadTyp: 96,
+		}
+
+		changed := format.SafeSetParamsFromRTP(&rtp.Packet{Payload: []byte{0x42, 0x01, 0x01}})
+		require.Equal(t, false, changed)
+	})
+
+	t.Run("other NALU", func(t *testing.T) {
+		format := &H265{
+			PayloadTyp: 96,
+		}
+
+		changed := format.SafeSetParamsFromRTP(&rtp.Packet{Payload: []byte{0x26, 0x01, 0xaf}})
+		require.Equal(t, false, changed)
+	})
+}
+
 func TestH265PTSEqualsDTS(t *testing.T) {
 	format := &H265{
 		PayloadTyp: 96,
@@ -71,6 +144,28 @@ func TestH265DecEncoder(t *testing.T) {
 	require.Equal(t, [][]byte{{0x01, 0x02, 0x03, 0x04}}, byts)
 }
 
+func TestH265IsRandomAccess(t *testing.T) {
+	format := &H265{}
+
+	require.Equal(t, true, format.IsRandomAccess([][]byte{
+		{ // IDR_W_RADL
+			0x26, 0x01, 0xaf, 0x08, 0x42, 0x23, 0x10, 0x5d,
+		},
+	}))
+
+	require.Equal(t, true, format.IsRandomAccess([][]byte{
+		{ // CRA
+			0x2a, 0x01, 0xad, 0x1e, 0x90, 0x44, 0x34,
+		},
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([][]byte{
+		{ // TRAIL_R
+			0x02, 0x01, 0xd0, 0x09, 0x7e, 0x10, 0xa1,
+		},
+	}))
+}
+
 func FuzzUnmarshalH265(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
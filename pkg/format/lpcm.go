@@ -3,6 +3,7 @@ package format
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pion/rtp"
 
@@ -17,6 +18,12 @@ type LPCM struct {
 	BitDepth     int
 	SampleRate   int
 	ChannelCount int
+
+	// duration of each packet, filled with the ptime attribute (optional).
+	PacketDuration time.Duration
+
+	// maximum duration of each packet, filled with the maxptime attribute (optional).
+	MaxPacketDuration time.Duration
 }
 
 func (f *LPCM) unmarshal(ctx *unmarshalContext) error {
@@ -116,6 +123,7 @@ func (f *LPCM) CreateDecoder() (*rtplpcm.Decoder, error) {
 	d := &rtplpcm.Decoder{
 		BitDepth:     f.BitDepth,
 		ChannelCount: f.ChannelCount,
+		SampleRate:   f.SampleRate,
 	}
 
 	err := d.Init()
@@ -129,9 +137,11 @@ func (f *LPCM) CreateDecoder() (*rtplpcm.Decoder, error) {
 // CreateEncoder creates an encoder able to encode the content of the format.
 func (f *LPCM) CreateEncoder() (*rtplpcm.Encoder, error) {
 	e := &rtplpcm.Encoder{
-		PayloadType:  f.PayloadTyp,
-		BitDepth:     f.BitDepth,
-		ChannelCount: f.ChannelCount,
+		PayloadType:    f.PayloadTyp,
+		BitDepth:       f.BitDepth,
+		ChannelCount:   f.ChannelCount,
+		SampleRate:     f.SampleRate,
+		PacketDuration: encoderPacketDuration(f.PacketDuration, f.MaxPacketDuration),
 	}
 
 	err := e.Init()
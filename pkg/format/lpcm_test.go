@@ -1,7 +1,9 @@
 package format
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -42,6 +44,60 @@ func TestLPCMDecEncoder(t *testing.T) {
 	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, byts)
 }
 
+func TestLPCMDecEncoderPacketDuration(t *testing.T) {
+	format := &LPCM{
+		PayloadTyp:     96,
+		BitDepth:       24,
+		SampleRate:     48000,
+		ChannelCount:   2,
+		PacketDuration: time.Millisecond,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	samples := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 96)
+
+	pkts, err := enc.Encode(samples)
+	require.NoError(t, err)
+	require.Len(t, pkts, 2)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	var decoded []byte
+
+	for _, pkt := range pkts {
+		require.Len(t, pkt.Payload, 48*6)
+
+		byts, err := dec.Decode(pkt)
+		require.NoError(t, err)
+		decoded = append(decoded, byts...)
+
+		require.Equal(t, time.Millisecond, dec.PacketDuration(pkt))
+	}
+
+	require.Equal(t, samples, decoded)
+}
+
+func TestLPCMDecEncoderMaxPacketDuration(t *testing.T) {
+	format := &LPCM{
+		PayloadTyp:        96,
+		BitDepth:          24,
+		SampleRate:        48000,
+		ChannelCount:      2,
+		PacketDuration:    2 * time.Millisecond,
+		MaxPacketDuration: time.Millisecond,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkts, err := enc.Encode(bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 96))
+	require.NoError(t, err)
+	require.Len(t, pkts, 2)
+}
+
 func FuzzUnmarshalLPCM(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a string) {
 		fo, err := Unmarshal("audio", 96, "L16/"+a, nil)
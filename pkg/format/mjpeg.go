@@ -1,6 +1,9 @@
 package format //nolint:dupl
 
 import (
+	"encoding/hex"
+	"fmt"
+
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format/rtpmjpeg"
@@ -8,9 +11,31 @@ import (
 
 // MJPEG is the RTP format for the Motion-JPEG codec.
 // Specification: https://datatracker.ietf.org/doc/html/rfc2435
-type MJPEG struct{}
+type MJPEG struct {
+	// image size, filled with the framesize or x-dimensions attributes (optional).
+	Width  int
+	Height int
+
+	// static quantization tables (optional).
+	// They are used to decode packets that do not contain them.
+	QuantizationTables [][]byte
+}
+
+func (f *MJPEG) unmarshal(ctx *unmarshalContext) error {
+	for key, val := range ctx.fmtp {
+		if key == "x-quantization-tables" {
+			byts, err := hex.DecodeString(val)
+			if err != nil || len(byts) == 0 || (len(byts)%64) != 0 {
+				return fmt.Errorf("invalid x-quantization-tables: %v", val)
+			}
+
+			f.QuantizationTables = make([][]byte, len(byts)/64)
+			for i := range f.QuantizationTables {
+				f.QuantizationTables[i] = byts[i*64 : (i+1)*64]
+			}
+		}
+	}
 
-func (f *MJPEG) unmarshal(_ *unmarshalContext) error {
 	return nil
 }
 
@@ -36,7 +61,18 @@ func (f *MJPEG) RTPMap() string {
 
 // FMTP implements Format.
 func (f *MJPEG) FMTP() map[string]string {
-	return nil
+	if f.QuantizationTables == nil {
+		return nil
+	}
+
+	var byts []byte
+	for _, table := range f.QuantizationTables {
+		byts = append(byts, table...)
+	}
+
+	return map[string]string{
+		"x-quantization-tables": hex.EncodeToString(byts),
+	}
 }
 
 // PTSEqualsDTS implements Format.
@@ -46,7 +82,11 @@ func (f *MJPEG) PTSEqualsDTS(*rtp.Packet) bool {
 
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *MJPEG) CreateDecoder() (*rtpmjpeg.Decoder, error) {
-	d := &rtpmjpeg.Decoder{}
+	d := &rtpmjpeg.Decoder{
+		QuantizationTables: f.QuantizationTables,
+		Width:              f.Width,
+		Height:             f.Height,
+	}
 
 	err := d.Init()
 	if err != nil {
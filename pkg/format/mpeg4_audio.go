@@ -32,8 +32,10 @@ type MPEG4Audio struct {
 	IndexDeltaLength int
 
 	// LATM only
-	Bitrate         *int
-	CPresent        bool
+	Bitrate  *int
+	CPresent bool
+	// when CPresent is true, StreamMuxConfig is not part of the SDP,
+	// but it can be filled in order to be transmitted in-band by encoders.
 	StreamMuxConfig *mpeg4audio.StreamMuxConfig
 	SBREnabled      *bool
 }
@@ -303,6 +305,8 @@ func (f *MPEG4Audio) CreateDecoder() (*rtpmpeg4audio.Decoder, error) {
 		SizeLength:       f.SizeLength,
 		IndexLength:      f.IndexLength,
 		IndexDeltaLength: f.IndexDeltaLength,
+		CPresent:         f.LATM && f.CPresent,
+		StreamMuxConfig:  f.StreamMuxConfig,
 	}
 
 	err := d.Init()
@@ -321,6 +325,8 @@ func (f *MPEG4Audio) CreateEncoder() (*rtpmpeg4audio.Encoder, error) {
 		SizeLength:       f.SizeLength,
 		IndexLength:      f.IndexLength,
 		IndexDeltaLength: f.IndexDeltaLength,
+		CPresent:         f.LATM && f.CPresent,
+		StreamMuxConfig:  f.StreamMuxConfig,
 	}
 
 	err := e.Init()
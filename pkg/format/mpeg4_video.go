@@ -92,6 +92,19 @@ func (f *MPEG4Video) PTSEqualsDTS(*rtp.Packet) bool {
 	return true
 }
 
+// IsRandomAccess checks whether a frame can be decoded
+// independently from previous ones (i.e. it contains an intra-coded VOP).
+func (f *MPEG4Video) IsRandomAccess(frame []byte) bool {
+	for i := 0; i < (len(frame) - 4); i++ {
+		if frame[i] == 0 && frame[i+1] == 0 && frame[i+2] == 1 &&
+			mpeg4video.StartCode(frame[i+3]) == mpeg4video.VOPStartCode {
+			// vop_coding_type, 0 is I-VOP
+			return (frame[i+4] >> 6) == 0
+		}
+	}
+	return false
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *MPEG4Video) CreateDecoder() (*rtpmpeg4video.Decoder, error) {
 	d := &rtpmpeg4video.Decoder{}
@@ -38,6 +38,23 @@ func TestMPEG4VideoDecEncoder(t *testing.T) {
 	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, byts)
 }
 
+func TestMPEG4VideoIsRandomAccess(t *testing.T) {
+	format := &MPEG4Video{}
+
+	require.Equal(t, true, format.IsRandomAccess([]byte{
+		0x00, 0x00, 0x01, 0xb3, 0x00, 0x10, 0x07, // group of VOP
+		0x00, 0x00, 0x01, 0xb6, 0x10, 0x60, 0x51, 0x82, // I-VOP
+		0x3d, 0xb7, 0xef,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{
+		0x00, 0x00, 0x01, 0xb6, 0x56, 0x1e, 0x50, 0x8b, // P-VOP
+		0xe1, 0x8c,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{0x01, 0x02, 0x03}))
+}
+
 func FuzzUnmarshalMPEG4Video(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pion/rtp"
 
@@ -17,6 +18,37 @@ type Opus struct {
 	PayloadTyp   uint8
 	ChannelCount int
 
+	// multichannel Opus (ChannelCount > 2) only.
+	// When ChannelMapping is nil, the default libwebrtc layout
+	// for the channel count is used.
+	StreamCount        int
+	CoupledStreamCount int
+	ChannelMapping     []int
+
+	// preferred packet duration (optional).
+	// It is transmitted with the ptime attribute of the media.
+	PreferredPacketDuration time.Duration
+
+	// maximum packet duration (optional), between 2.5ms and 120ms.
+	// It is transmitted with the maxptime attribute of the media.
+	MaxPacketDuration time.Duration
+
+	// whether the decoder supports in-band forward error correction (optional).
+	InBandFEC bool
+
+	// whether the decoder supports discontinuous transmission (optional).
+	DTX bool
+
+	// maximum average bitrate, in bits per second (optional).
+	MaxAverageBitrate int
+
+	// maximum output sampling rate of the decoder, in Hz (optional).
+	MaxPlaybackRate int
+
+	// other fmtp parameters (optional).
+	// They are preserved as they are.
+	OtherFMTP map[string]string
+
 	// Deprecated: replaced by ChannelCount.
 	IsStereo bool
 }
@@ -45,11 +77,15 @@ func (f *Opus) unmarshal(ctx *unmarshalContext) error {
 		f.IsStereo = false
 
 		for key, val := range ctx.fmtp {
-			if key == "sprop-stereo" {
+			switch key {
+			case "sprop-stereo":
 				if val == "1" {
 					f.ChannelCount = 2
 					f.IsStereo = true
 				}
+
+			default:
+				f.unmarshalCommonFMTP(key, val)
 			}
 		}
 	} else {
@@ -69,11 +105,91 @@ func (f *Opus) unmarshal(ctx *unmarshalContext) error {
 		}
 
 		f.ChannelCount = int(channelCount)
+
+		for key, val := range ctx.fmtp {
+			switch key {
+			case "num_streams":
+				n, err := strconv.ParseUint(val, 10, 8)
+				if err != nil {
+					return fmt.Errorf("invalid num_streams: %v", val)
+				}
+				f.StreamCount = int(n)
+
+			case "coupled_streams":
+				n, err := strconv.ParseUint(val, 10, 8)
+				if err != nil {
+					return fmt.Errorf("invalid coupled_streams: %v", val)
+				}
+				f.CoupledStreamCount = int(n)
+
+			case "channel_mapping":
+				tmp := strings.Split(val, ",")
+				f.ChannelMapping = make([]int, len(tmp))
+
+				for i, entry := range tmp {
+					n, err := strconv.ParseUint(entry, 10, 8)
+					if err != nil {
+						return fmt.Errorf("invalid channel_mapping: %v", val)
+					}
+					f.ChannelMapping[i] = int(n)
+				}
+
+			case "sprop-maxcapturerate":
+
+			default:
+				f.unmarshalCommonFMTP(key, val)
+			}
+		}
+
+		if f.ChannelMapping != nil {
+			if len(f.ChannelMapping) != f.ChannelCount {
+				return fmt.Errorf("channel_mapping has %d entries, while channel count is %d",
+					len(f.ChannelMapping), f.ChannelCount)
+			}
+
+			if f.StreamCount == 0 || f.CoupledStreamCount > f.StreamCount {
+				return fmt.Errorf("invalid num_streams or coupled_streams")
+			}
+		}
 	}
 
 	return nil
 }
 
+// unmarshalCommonFMTP decodes parameters shared by Opus and multiopus.
+// Invalid or out of range values are not decoded and are kept in OtherFMTP,
+// in order not to prevent reading streams with slightly wrong SDPs.
+func (f *Opus) unmarshalCommonFMTP(key string, val string) {
+	switch key {
+	case "useinbandfec":
+		f.InBandFEC = (val == "1")
+		return
+
+	case "usedtx":
+		f.DTX = (val == "1")
+		return
+
+	case "maxaveragebitrate":
+		n, err := strconv.ParseUint(val, 10, 31)
+		if err == nil && n >= 6000 && n <= 510000 {
+			f.MaxAverageBitrate = int(n)
+			return
+		}
+
+	case "maxplaybackrate":
+		n, err := strconv.ParseUint(val, 10, 31)
+		if err == nil && n >= 8000 {
+			f.MaxPlaybackRate = int(n)
+			return
+		}
+	}
+
+	if f.OtherFMTP == nil {
+		f.OtherFMTP = make(map[string]string)
+	}
+	f.OtherFMTP[key] = val
+}
+
 // Codec implements Format.
 func (f *Opus) Codec() string {
 	return "Opus"
@@ -104,6 +220,32 @@ func (f *Opus) RTPMap() string {
 
 // FMTP implements Format.
 func (f *Opus) FMTP() map[string]string {
+	fmtp := f.channelFMTP()
+
+	for key, val := range f.OtherFMTP {
+		fmtp[key] = val
+	}
+
+	if f.InBandFEC {
+		fmtp["useinbandfec"] = "1"
+	}
+
+	if f.DTX {
+		fmtp["usedtx"] = "1"
+	}
+
+	if f.MaxAverageBitrate != 0 {
+		fmtp["maxaveragebitrate"] = strconv.FormatInt(int64(f.MaxAverageBitrate), 10)
+	}
+
+	if f.MaxPlaybackRate != 0 {
+		fmtp["maxplaybackrate"] = strconv.FormatInt(int64(f.MaxPlaybackRate), 10)
+	}
+
+	return fmtp
+}
+
+func (f *Opus) channelFMTP() map[string]string {
 	if f.ChannelCount <= 2 {
 		return map[string]string{
 			"sprop-stereo": func() string {
@@ -115,6 +257,20 @@ func (f *Opus) FMTP() map[string]string {
 		}
 	}
 
+	if f.ChannelMapping != nil {
+		tmp := make([]string, len(f.ChannelMapping))
+		for i, v := range f.ChannelMapping {
+			tmp[i] = strconv.FormatInt(int64(v), 10)
+		}
+
+		return map[string]string{
+			"num_streams":          strconv.FormatInt(int64(f.StreamCount), 10),
+			"coupled_streams":      strconv.FormatInt(int64(f.CoupledStreamCount), 10),
+			"channel_mapping":      strings.Join(tmp, ","),
+			"sprop-maxcapturerate": "48000",
+		}
+	}
+
 	switch f.ChannelCount {
 	case 3:
 		return map[string]string{
@@ -47,6 +47,22 @@ func FuzzUnmarshalOpus(f *testing.F) {
 	})
 }
 
+func TestOpusMultiUnmarshalErrors(t *testing.T) {
+	_, err := Unmarshal("audio", 96, "multiopus/48000/6", map[string]string{
+		"num_streams":     "4",
+		"coupled_streams": "2",
+		"channel_mapping": "0,4,1,2,3",
+	})
+	require.EqualError(t, err, "channel_mapping has 5 entries, while channel count is 6")
+
+	_, err = Unmarshal("audio", 96, "multiopus/48000/2", map[string]string{
+		"num_streams":     "1",
+		"coupled_streams": "2",
+		"channel_mapping": "0,1",
+	})
+	require.EqualError(t, err, "invalid num_streams or coupled_streams")
+}
+
 func FuzzUnmarshalOpusMulti(f *testing.F) {
 	f.Add("48000/a")
 
@@ -58,3 +74,17 @@ func FuzzUnmarshalOpusMulti(f *testing.F) {
 		}
 	})
 }
+
+func FuzzUnmarshalOpusMultiFMTP(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a string, b string, c string) {
+		fo, err := Unmarshal("audio", 96, "multiopus/48000/6", map[string]string{
+			"num_streams":     a,
+			"coupled_streams": b,
+			"channel_mapping": c,
+		})
+		if err == nil {
+			fo.RTPMap()
+			fo.FMTP()
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtprawvideo"
+)
+
+// RawVideo is the RTP format for uncompressed video.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+type RawVideo struct {
+	PayloadTyp  uint8
+	Sampling    string
+	BitDepth    int
+	Width       int
+	Height      int
+	Colorimetry string
+	Interlaced  bool
+}
+
+func (f *RawVideo) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "sampling":
+			f.Sampling = val
+
+		case "depth":
+			n, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid depth: %v", val)
+			}
+			f.BitDepth = int(n)
+
+		case "width":
+			n, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid width: %v", val)
+			}
+			f.Width = int(n)
+
+		case "height":
+			n, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid height: %v", val)
+			}
+			f.Height = int(n)
+
+		case "colorimetry":
+			f.Colorimetry = val
+
+		case "interlace":
+			// the parameter is a flag, but some implementations set it to 0 or 1.
+			f.Interlaced = (val != "0")
+		}
+	}
+
+	if f.Sampling == "" {
+		return fmt.Errorf("sampling is missing")
+	}
+
+	if f.BitDepth == 0 {
+		return fmt.Errorf("depth is missing")
+	}
+
+	if f.Width == 0 {
+		return fmt.Errorf("width is missing")
+	}
+
+	if f.Height == 0 {
+		return fmt.Errorf("height is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RawVideo) Codec() string {
+	return "Raw video"
+}
+
+// ClockRate implements Format.
+func (f *RawVideo) ClockRate() int {
+	return 90000
+}
+
+// PayloadType implements Format.
+func (f *RawVideo) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RawVideo) RTPMap() string {
+	return "raw/90000"
+}
+
+// FMTP implements Format.
+func (f *RawVideo) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"sampling": f.Sampling,
+		"depth":    strconv.FormatInt(int64(f.BitDepth), 10),
+		"width":    strconv.FormatInt(int64(f.Width), 10),
+		"height":   strconv.FormatInt(int64(f.Height), 10),
+	}
+
+	if f.Colorimetry != "" {
+		fmtp["colorimetry"] = f.Colorimetry
+	}
+
+	if f.Interlaced {
+		fmtp["interlace"] = ""
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RawVideo) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *RawVideo) CreateDecoder() (*rtprawvideo.Decoder, error) {
+	d := &rtprawvideo.Decoder{
+		Sampling:   f.Sampling,
+		BitDepth:   f.BitDepth,
+		Width:      f.Width,
+		Height:     f.Height,
+		Interlaced: f.Interlaced,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *RawVideo) CreateEncoder() (*rtprawvideo.Encoder, error) {
+	e := &rtprawvideo.Encoder{
+		PayloadType: f.PayloadTyp,
+		Sampling:    f.Sampling,
+		BitDepth:    f.BitDepth,
+		Width:       f.Width,
+		Height:      f.Height,
+		Interlaced:  f.Interlaced,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,80 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawVideoAttributes(t *testing.T) {
+	format := &RawVideo{
+		PayloadTyp: 96,
+		Sampling:   "YCbCr-4:2:2",
+		BitDepth:   10,
+		Width:      1920,
+		Height:     1080,
+	}
+	require.Equal(t, "Raw video", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestRawVideoDecEncoder(t *testing.T) {
+	format := &RawVideo{
+		PayloadTyp: 96,
+		Sampling:   "YCbCr-4:2:2",
+		BitDepth:   10,
+		Width:      2,
+		Height:     2,
+		Interlaced: true,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	frame := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a}
+
+	pkts, err := enc.Encode(frame)
+	require.NoError(t, err)
+	require.Equal(t, format.PayloadType(), pkts[0].PayloadType)
+	require.Equal(t, 2, len(pkts))
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	_, err = dec.Decode(pkts[0])
+	require.Error(t, err)
+
+	byts, err := dec.Decode(pkts[1])
+	require.NoError(t, err)
+	require.Equal(t, frame, byts)
+}
+
+func FuzzUnmarshalRawVideo(f *testing.F) {
+	f.Fuzz(func(
+		_ *testing.T,
+		a string,
+		b string,
+		c string,
+		d string,
+		e bool,
+	) {
+		ma := map[string]string{
+			"sampling": a,
+			"depth":    b,
+			"width":    c,
+			"height":   d,
+		}
+
+		if e {
+			ma["interlace"] = ""
+		}
+
+		fo, err := Unmarshal("video", 96, "raw/90000", ma)
+		if err == nil {
+			fo.RTPMap()
+			fo.FMTP()
+		}
+	})
+}
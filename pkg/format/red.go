@@ -0,0 +1,121 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtpred"
+)
+
+// RED is the RTP format for redundant audio data.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2198
+type RED struct {
+	PayloadTyp   uint8
+	ClockRat     int
+	ChannelCount int
+
+	// payload types of blocks, from the oldest redundant one to the primary one (optional).
+	PayloadTypes []uint8
+}
+
+func (f *RED) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	tmp := strings.SplitN(ctx.clock, "/", 2)
+
+	clockRate, err := strconv.ParseUint(tmp[0], 10, 31)
+	if err != nil || clockRate == 0 {
+		return fmt.Errorf("invalid clock rate: '%s'", tmp[0])
+	}
+	f.ClockRat = int(clockRate)
+
+	if len(tmp) == 2 {
+		channelCount, err := strconv.ParseUint(tmp[1], 10, 31)
+		if err != nil || channelCount == 0 {
+			return fmt.Errorf("invalid channel count: '%s'", tmp[1])
+		}
+		f.ChannelCount = int(channelCount)
+	}
+
+	// fmtp is in the form "98/98/98"
+	for key := range ctx.fmtp {
+		if !strings.Contains(key, "/") {
+			continue
+		}
+
+		parts := strings.Split(key, "/")
+		f.PayloadTypes = make([]uint8, len(parts))
+
+		for i, part := range parts {
+			n, err := strconv.ParseUint(part, 10, 7)
+			if err != nil {
+				return fmt.Errorf("invalid payload types: '%s'", key)
+			}
+			f.PayloadTypes[i] = uint8(n)
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RED) Codec() string {
+	return "RED"
+}
+
+// ClockRate implements Format.
+func (f *RED) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *RED) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RED) RTPMap() string {
+	ret := "red/" + strconv.FormatInt(int64(f.ClockRat), 10)
+
+	if f.ChannelCount != 0 {
+		ret += "/" + strconv.FormatInt(int64(f.ChannelCount), 10)
+	}
+
+	return ret
+}
+
+// FMTP implements Format.
+func (f *RED) FMTP() map[string]string {
+	if f.PayloadTypes == nil {
+		return nil
+	}
+
+	parts := make([]string, len(f.PayloadTypes))
+	for i, pt := range f.PayloadTypes {
+		parts[i] = strconv.FormatUint(uint64(pt), 10)
+	}
+
+	return map[string]string{
+		strings.Join(parts, "/"): "",
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RED) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *RED) CreateDecoder() (*rtpred.Decoder, error) {
+	d := &rtpred.Decoder{}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
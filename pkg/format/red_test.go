@@ -0,0 +1,65 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestREDAttributes(t *testing.T) {
+	format := &RED{
+		PayloadTyp: 100,
+		ClockRat:   8000,
+	}
+	require.Equal(t, "RED", format.Codec())
+	require.Equal(t, 8000, format.ClockRate())
+	require.Equal(t, "red/8000", format.RTPMap())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestREDDecoder(t *testing.T) {
+	format := &RED{
+		PayloadTyp: 100,
+		ClockRat:   8000,
+	}
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	pkts, err := dec.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			PayloadType:    100,
+			SequenceNumber: 10,
+			Timestamp:      1000,
+		},
+		Payload: []byte{0x80, 0x00, 0x50, 0x01, 0x00, 0x01, 0x02},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				SequenceNumber: 9,
+				Timestamp:      1000 - 20,
+			},
+			Payload: []byte{0x01},
+		},
+		{
+			Header: rtp.Header{
+				SequenceNumber: 10,
+				Timestamp:      1000,
+			},
+			Payload: []byte{0x02},
+		},
+	}, pkts)
+}
+
+func FuzzUnmarshalRED(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a string) {
+		fo, err := Unmarshal("audio", 96, "red/"+a, nil)
+		if err == nil {
+			fo.RTPMap()
+			fo.FMTP()
+		}
+	})
+}
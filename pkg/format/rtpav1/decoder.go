@@ -34,6 +34,7 @@ type Decoder struct {
 	firstPacketReceived bool
 	fragmentsSize       int
 	fragments           [][]byte
+	stats               DecoderStats
 
 	// for Decode()
 	frameBuffer     [][]byte
@@ -41,17 +42,64 @@ type Decoder struct {
 	frameBufferSize int
 }
 
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete OBUs or temporal units that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
+}
+
 // Init initializes the decoder.
 func (d *Decoder) Init() error {
 	return nil
 }
 
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) discardFragments() {
+	if len(d.fragments) != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+		d.fragments = d.fragments[:0]
+	}
+	d.fragmentsSize = 0
+}
+
+func (d *Decoder) discardTemporalUnit() {
+	if d.frameBufferLen != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.frameBufferSize)
+	}
+
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+}
+
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+
+		// this is normal when decoding a stream that is already running
+		if !errors.Is(err, ErrNonStartingPacketAndNoPrevious) {
+			d.stats.PacketsRejected++
+		}
+	}
+}
+
 func (d *Decoder) decodeOBUs(pkt *rtp.Packet) ([][]byte, error) {
 	var av1header codecs.AV1Packet
 	_, err := av1header.Unmarshal(pkt.Payload)
 	if err != nil {
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.fragmentsSize = 0
+		d.discardFragments()
 		return nil, fmt.Errorf("invalid header: %w", err)
 	}
 
@@ -64,15 +112,20 @@ func (d *Decoder) decodeOBUs(pkt *rtp.Packet) ([][]byte, error) {
 			return nil, fmt.Errorf("received a subsequent fragment without previous fragments")
 		}
 
-		d.fragmentsSize += len(av1header.OBUElements[0])
-		if d.fragmentsSize > av1.MaxTemporalUnitSize {
-			d.fragments = d.fragments[:0]
-			d.fragmentsSize = 0
-			return nil, fmt.Errorf("OBU size (%d) is too big, maximum is %d", d.fragmentsSize, av1.MaxTemporalUnitSize)
+		if (d.fragmentsSize + len(av1header.OBUElements[0])) > av1.MaxTemporalUnitSize {
+			size := d.fragmentsSize + len(av1header.OBUElements[0])
+			d.discardFragments()
+			return nil, fmt.Errorf("OBU size (%d) is too big, maximum is %d", size, av1.MaxTemporalUnitSize)
 		}
 
+		d.fragmentsSize += len(av1header.OBUElements[0])
+
 		d.fragments = append(d.fragments, av1header.OBUElements[0])
 		av1header.OBUElements = av1header.OBUElements[1:]
+	} else if len(d.fragments) != 0 {
+		// the packet that continues the pending OBU has been lost:
+		// discard the incomplete OBU.
+		d.discardFragments()
 	}
 
 	d.firstPacketReceived = true
@@ -80,7 +133,7 @@ func (d *Decoder) decodeOBUs(pkt *rtp.Packet) ([][]byte, error) {
 	var obus [][]byte
 
 	if len(av1header.OBUElements) > 0 {
-		if d.fragmentsSize != 0 {
+		if len(d.fragments) != 0 {
 			obus = append(obus, joinFragments(d.fragments, d.fragmentsSize))
 			d.fragments = d.fragments[:0]
 			d.fragmentsSize = 0
@@ -89,13 +142,14 @@ func (d *Decoder) decodeOBUs(pkt *rtp.Packet) ([][]byte, error) {
 		if av1header.Y {
 			elementCount := len(av1header.OBUElements)
 
-			d.fragmentsSize += len(av1header.OBUElements[elementCount-1])
-			if d.fragmentsSize > av1.MaxTemporalUnitSize {
-				d.fragments = d.fragments[:0]
-				d.fragmentsSize = 0
-				return nil, fmt.Errorf("OBU size (%d) is too big, maximum is %d", d.fragmentsSize, av1.MaxTemporalUnitSize)
+			if (d.fragmentsSize + len(av1header.OBUElements[elementCount-1])) > av1.MaxTemporalUnitSize {
+				size := d.fragmentsSize + len(av1header.OBUElements[elementCount-1])
+				d.discardFragments()
+				return nil, fmt.Errorf("OBU size (%d) is too big, maximum is %d", size, av1.MaxTemporalUnitSize)
 			}
 
+			d.fragmentsSize += len(av1header.OBUElements[elementCount-1])
+
 			d.fragments = append(d.fragments, av1header.OBUElements[elementCount-1])
 			av1header.OBUElements = av1header.OBUElements[:elementCount-1]
 		}
@@ -116,6 +170,12 @@ func (d *Decoder) decodeOBUs(pkt *rtp.Packet) ([][]byte, error) {
 
 // Decode decodes a temporal unit from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	tu, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return tu, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([][]byte, error) {
 	obus, err := d.decodeOBUs(pkt)
 	if err != nil {
 		return nil, err
@@ -123,9 +183,7 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	l := len(obus)
 
 	if (d.frameBufferLen + l) > av1.MaxOBUsPerTemporalUnit {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		d.discardTemporalUnit()
 		return nil, fmt.Errorf("OBU count exceeds maximum allowed (%d)",
 			av1.MaxOBUsPerTemporalUnit)
 	}
@@ -137,11 +195,10 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	}
 
 	if (d.frameBufferSize + addSize) > av1.MaxTemporalUnitSize {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		size := d.frameBufferSize + addSize
+		d.discardTemporalUnit()
 		return nil, fmt.Errorf("temporal unit size (%d) is too big, maximum is %d",
-			d.frameBufferSize+addSize, av1.MaxOBUsPerTemporalUnit)
+			size, av1.MaxTemporalUnitSize)
 	}
 
 	d.frameBuffer = append(d.frameBuffer, obus...)
@@ -33,6 +33,115 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeFragments(t *testing.T) {
+	for _, ca := range []struct {
+		name     string
+		payloads [][]byte
+		obus     [][]byte
+	}{
+		{
+			"z=0 y=0",
+			[][]byte{
+				{0x00, 0x02, 0x01, 0x02, 0x01, 0x03},
+			},
+			[][]byte{{0x01, 0x02}, {0x03}},
+		},
+		{
+			"z=0 y=1, z=1 y=1, z=1 y=0",
+			[][]byte{
+				{0x40, 0x02, 0x01, 0x02},
+				{0xc0, 0x02, 0x03, 0x04},
+				{0xc0, 0x02, 0x05, 0x06},
+				{0x80, 0x02, 0x07, 0x08},
+			},
+			[][]byte{{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}},
+		},
+		{
+			"z=1 y=1 with multiple elements",
+			[][]byte{
+				{0x40, 0x01, 0x0a},
+				{0xc0, 0x01, 0x0b, 0x01, 0x0c, 0x01, 0x0d},
+				{0x80, 0x01, 0x0e, 0x01, 0x0f},
+			},
+			[][]byte{{0x0a, 0x0b}, {0x0c}, {0x0d, 0x0e}, {0x0f}},
+		},
+		{
+			"w field",
+			[][]byte{
+				{0x60, 0x01, 0x0a, 0x0b, 0x0c},
+				{0x90, 0x0d},
+			},
+			[][]byte{{0x0a}, {0x0b, 0x0c, 0x0d}},
+		},
+		{
+			"lost continuation",
+			[][]byte{
+				{0x40, 0x01, 0x0a},
+				{0x00, 0x01, 0x0b},
+			},
+			[][]byte{{0x0b}},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var obus [][]byte
+
+			for i, payload := range ca.payloads {
+				obus, err = d.Decode(&rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						Marker:         i == (len(ca.payloads) - 1),
+						PayloadType:    96,
+						SequenceNumber: 17645 + uint16(i),
+						Timestamp:      2289527317,
+						SSRC:           0x9dbb7812,
+					},
+					Payload: payload,
+				})
+				if i != (len(ca.payloads) - 1) {
+					require.Equal(t, ErrMorePacketsNeeded, err)
+				}
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, ca.obus, obus)
+		})
+	}
+}
+
+func TestDecoderStats(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	for _, payload := range [][]byte{
+		{0x40, 0x01, 0x0a},
+		{0x00, 0x01, 0x0b}, // continuation has been lost
+		{0x00, 0x05},       // invalid element size
+	} {
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: payload,
+		})
+	}
+
+	require.Equal(t, DecoderStats{
+		PacketsRejected:     1,
+		IncompleteDiscarded: 1,
+		BytesDropped:        3,
+	}, d.Stats())
+}
+
 func TestDecoderErrorLimit(t *testing.T) {
 	d := &Decoder{}
 	err := d.Init()
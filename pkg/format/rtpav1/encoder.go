@@ -2,9 +2,13 @@ package rtpav1
 
 import (
 	"crypto/rand"
+	"fmt"
 
 	"github.com/bluenviron/mediacommon/pkg/codecs/av1"
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpfragment"
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -39,11 +43,39 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// maximum number of OBU elements per packet (optional).
+	// When it's between 1 and 3, the W field is filled and
+	// the length of the last OBU element is omitted.
+	// It defaults to unlimited.
+	MaxOBUsPerPacket int
+
+	// fragment OBUs only when they don't fit into an empty packet (optional).
+	// By default, OBUs are fragmented in order to fill packets entirely.
+	AvoidFragmentation bool
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
+	if e.MaxOBUsPerPacket < 0 {
+		return fmt.Errorf("invalid MaxOBUsPerPacket (%d)", e.MaxOBUsPerPacket)
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -69,73 +101,83 @@ func (e *Encoder) Init() error {
 
 // Encode encodes OBUs into RTP packets.
 func (e *Encoder) Encode(obus [][]byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(obus)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(obus [][]byte) ([]*rtp.Packet, error) {
 	isKeyFrame, err := av1.ContainsKeyFrame(obus)
 	if err != nil {
 		return nil, err
 	}
 
-	var curPacket *rtp.Packet
-	var packets []*rtp.Packet
-	curPayloadLen := 0
+	var encPackets []encoderPacket
+	cur := encoderPacket{}
 
-	createNewPacket := func(z bool) {
-		curPacket = &rtp.Packet{
-			Header: rtp.Header{
-				Version:        rtpVersion,
-				PayloadType:    e.PayloadType,
-				SequenceNumber: e.sequenceNumber,
-				SSRC:           *e.SSRC,
-			},
-			Payload: []byte{0},
-		}
-		e.sequenceNumber++
-		packets = append(packets, curPacket)
-		curPayloadLen = 1
+	// elements of all packets share the same backing slice
+	var elements [][]byte
 
-		if z {
-			curPacket.Payload[0] |= 1 << 7
-		}
+	addToCurPacket := func(element []byte) {
+		elements = append(elements, element)
+		cur.elements = elements[len(elements)-len(cur.elements)-1:]
+		cur.size += av1.LEB128MarshalSize(uint(len(element))) + len(element)
 	}
 
 	finalizeCurPacket := func(y bool) {
-		if y {
-			curPacket.Payload[0] |= 1 << 6
-		}
+		cur.y = y
+		encPackets = append(encPackets, cur)
 	}
 
-	createNewPacket(false)
-
 	for _, obu := range obus {
 		for {
-			avail := e.PayloadMaxSize - curPayloadLen
-			obuLen := len(obu)
-			needed := obuLen + 2
-
-			if needed <= avail {
-				buf := make([]byte, av1.LEB128MarshalSize(uint(obuLen)))
-				av1.LEB128MarshalTo(uint(obuLen), buf)
-				curPacket.Payload = append(curPacket.Payload, buf...)
-				curPacket.Payload = append(curPacket.Payload, obu...)
-				curPayloadLen += len(buf) + obuLen
+			if e.MaxOBUsPerPacket != 0 && len(cur.elements) == e.MaxOBUsPerPacket {
+				finalizeCurPacket(false)
+				cur = encoderPacket{}
+			}
+
+			if e.sizeWithElement(&cur, len(obu)) <= e.PayloadMaxSize {
+				addToCurPacket(obu)
 				break
 			}
 
-			if avail > 2 {
-				fragmentLen := avail - 2
-				buf := make([]byte, av1.LEB128MarshalSize(uint(fragmentLen)))
-				av1.LEB128MarshalTo(uint(fragmentLen), buf)
-				curPacket.Payload = append(curPacket.Payload, buf...)
-				curPacket.Payload = append(curPacket.Payload, obu[:fragmentLen]...)
-				obu = obu[fragmentLen:]
+			// start a new packet instead of fragmenting the OBU
+			if e.AvoidFragmentation && len(cur.elements) != 0 {
+				finalizeCurPacket(false)
+				cur = encoderPacket{}
+				continue
 			}
 
+			fragmentLen := e.maxFragmentLen(&cur)
+
+			// there's no space left for a fragment
+			if fragmentLen <= 0 {
+				if len(cur.elements) == 0 {
+					return nil, fmt.Errorf("PayloadMaxSize is too small")
+				}
+
+				finalizeCurPacket(false)
+				cur = encoderPacket{}
+				continue
+			}
+
+			addToCurPacket(obu[:fragmentLen])
+			obu = obu[fragmentLen:]
+
 			finalizeCurPacket(true)
-			createNewPacket(true)
+			cur = encoderPacket{z: true}
 		}
 	}
 
 	finalizeCurPacket(false)
 
+	packets := e.marshalPackets(encPackets)
+
 	if isKeyFrame {
 		packets[0].Payload[0] |= 1 << 3
 	}
@@ -144,3 +186,97 @@ func (e *Encoder) Encode(obus [][]byte) ([]*rtp.Packet, error) {
 
 	return packets, nil
 }
+
+type encoderPacket struct {
+	z        bool
+	y        bool
+	elements [][]byte
+	size     int // size of elements, including their length fields
+}
+
+// useW returns whether the W field is used,
+// allowing to omit the length of the last OBU element.
+func (e *Encoder) useW() bool {
+	return e.MaxOBUsPerPacket >= 1 && e.MaxOBUsPerPacket <= 3
+}
+
+// sizeWithElement returns the payload size of a packet
+// after an OBU element of the given length is appended.
+func (e *Encoder) sizeWithElement(p *encoderPacket, le int) int {
+	n := 1 + p.size + le
+	if !e.useW() {
+		n += av1.LEB128MarshalSize(uint(le))
+	}
+	return n
+}
+
+// maxFragmentLen returns the maximum length of a fragment
+// that can be appended to a packet.
+func (e *Encoder) maxFragmentLen(p *encoderPacket) int {
+	avail := e.PayloadMaxSize - 1 - p.size
+
+	if e.useW() {
+		return avail
+	}
+
+	n := avail - 1
+	for n > 0 && (av1.LEB128MarshalSize(uint(n))+n) > avail {
+		n--
+	}
+	return n
+}
+
+// marshalPackets marshals packets, using a single buffer for all payloads.
+func (e *Encoder) marshalPackets(encPackets []encoderPacket) []*rtp.Packet {
+	size := 0
+	for i := range encPackets {
+		size += e.sizeWithElement(&encPackets[i], 0)
+	}
+
+	buf := make([]byte, size)
+	packets := rtpfragment.NewPackets(len(encPackets))
+
+	for i := range encPackets {
+		n := e.marshalPayload(&encPackets[i], buf)
+
+		*packets[i] = rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+			},
+			Payload: buf[:n:n],
+		}
+		e.sequenceNumber++
+
+		buf = buf[n:]
+	}
+
+	return packets
+}
+
+func (e *Encoder) marshalPayload(p *encoderPacket, buf []byte) int {
+	buf[0] = 0
+
+	if p.z {
+		buf[0] |= 1 << 7
+	}
+	if p.y {
+		buf[0] |= 1 << 6
+	}
+	if e.useW() {
+		buf[0] |= byte(len(p.elements)) << 4
+	}
+
+	n := 1
+
+	for i, element := range p.elements {
+		if !e.useW() || i != (len(p.elements)-1) {
+			n += av1.LEB128MarshalTo(uint(len(element)), buf[n:])
+		}
+		n += copy(buf[n:], element)
+	}
+
+	return n
+}
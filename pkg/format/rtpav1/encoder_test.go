@@ -1,6 +1,8 @@
 package rtpav1
 
 import (
+	"errors"
+	"strconv"
 	"testing"
 
 	"github.com/pion/rtp"
@@ -1001,3 +1003,158 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func TestEncodeOptions(t *testing.T) {
+	for _, ca := range []struct {
+		name               string
+		maxOBUsPerPacket   int
+		avoidFragmentation bool
+		obus               [][]byte
+		payloads           [][]byte
+	}{
+		{
+			"max obus per packet",
+			2,
+			false,
+			[][]byte{
+				{0x32, 0x01},
+				{0x32, 0x02},
+				{0x32, 0x03},
+			},
+			[][]byte{
+				{0x20, 0x02, 0x32, 0x01, 0x32, 0x02},
+				{0x10, 0x32, 0x03},
+			},
+		},
+		{
+			"fragmentation",
+			0,
+			false,
+			[][]byte{
+				{0x32, 0x01, 0x02, 0x03, 0x04},
+				{0x32, 0x05, 0x06, 0x07, 0x08, 0x09},
+			},
+			[][]byte{
+				{0x40, 0x05, 0x32, 0x01, 0x02, 0x03, 0x04, 0x02, 0x32, 0x05},
+				{0x80, 0x04, 0x06, 0x07, 0x08, 0x09},
+			},
+		},
+		{
+			"avoid fragmentation",
+			0,
+			true,
+			[][]byte{
+				{0x32, 0x01, 0x02, 0x03, 0x04},
+				{0x32, 0x05, 0x06, 0x07, 0x08, 0x09},
+			},
+			[][]byte{
+				{0x00, 0x05, 0x32, 0x01, 0x02, 0x03, 0x04},
+				{0x00, 0x06, 0x32, 0x05, 0x06, 0x07, 0x08, 0x09},
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:        96,
+				SSRC:               uint32Ptr(0x9dbb7812),
+				PayloadMaxSize:     10,
+				MaxOBUsPerPacket:   ca.maxOBUsPerPacket,
+				AvoidFragmentation: ca.avoidFragmentation,
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.obus)
+			require.NoError(t, err)
+
+			payloads := make([][]byte, len(pkts))
+			for i, pkt := range pkts {
+				payloads[i] = pkt.Payload
+				require.Equal(t, i == len(pkts)-1, pkt.Marker)
+			}
+			require.Equal(t, ca.payloads, payloads)
+		})
+	}
+}
+
+func TestEncodeDecodeLargeOBU(t *testing.T) {
+	largeOBU := make([]byte, 5000)
+	largeOBU[0] = 0x32
+	for i := 1; i < len(largeOBU); i++ {
+		largeOBU[i] = byte(i)
+	}
+
+	obus := [][]byte{
+		{0x12, 0x00},
+		largeOBU,
+		{0x32, 0x01, 0x02},
+	}
+
+	for _, maxOBUsPerPacket := range []int{0, 1, 3} {
+		t.Run(strconv.Itoa(maxOBUsPerPacket), func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:      96,
+				MaxOBUsPerPacket: maxOBUsPerPacket,
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(obus)
+			require.NoError(t, err)
+			require.Greater(t, len(pkts), 3)
+
+			for i, pkt := range pkts {
+				require.LessOrEqual(t, len(pkt.Payload), e.PayloadMaxSize)
+
+				// Y of a packet must be equal to Z of the next one
+				if i != (len(pkts) - 1) {
+					require.Equal(t, (pkt.Payload[0]&(1<<6)) != 0, (pkts[i+1].Payload[0]&(1<<7)) != 0)
+				}
+			}
+
+			d := &Decoder{}
+			err = d.Init()
+			require.NoError(t, err)
+
+			var decoded [][]byte
+
+			for _, pkt := range pkts {
+				decoded, err = d.Decode(pkt)
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, obus, decoded)
+		})
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	largeOBU := make([]byte, 100000)
+	largeOBU[0] = 0x32
+
+	obus := [][]byte{
+		{0x12, 0x00},
+		largeOBU,
+	}
+
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err = e.Encode(obus)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -15,8 +15,9 @@ var ErrMorePacketsNeeded = errors.New("need more packets")
 
 // ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
 // packet of a fragmented NALU and we didn't received anything before.
-// It's normal to receive this when decoding a stream that has been already
-// running for some time.
+//
+// Deprecated: the decoder doesn't return this error anymore, since
+// fragments that can't be decoded are skipped and counted by DiscardedFragments().
 var ErrNonStartingPacketAndNoPrevious = errors.New(
 	"received a non-starting fragment without any previous starting fragment")
 
@@ -44,28 +45,116 @@ type Decoder struct {
 	// indicates the packetization mode.
 	PacketizationMode int
 
-	firstPacketReceived bool
+	// value of sprop-interleaving-depth.
+	// It is used only when PacketizationMode is 2.
+	InterleavingDepth int
+
 	fragmentsSize       int
 	fragments           [][]byte
+	fragmentsNextSeqNum uint16
+	discardedFragments  uint64
 	annexBMode          bool
+	stats               DecoderStats
+
+	// for Decode() and DecodeInterleaved()
+	frameBuffer          [][]byte
+	frameBufferLen       int
+	frameBufferSize      int
+	frameBufferTimestamp uint32
+
+	// for DecodeInterleaved()
+	fragmentsDON     uint16
+	donReceived      bool
+	lastDON          uint16
+	lastAbsDON       int64
+	outputReceived   bool
+	lastOutputAbsDON int64
+	deintBuffer      []interleavedNALU
+	deintVCLCount    int
+	readyAUs         []AccessUnit
+}
+
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete NALUs or access units that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
+}
+
+// DiscardedFragments returns the number of fragments that have been discarded
+// since some other fragments of the same NALU were lost.
+func (d *Decoder) DiscardedFragments() uint64 {
+	return d.discardedFragments
+}
 
-	// for Decode()
-	frameBuffer     [][]byte
-	frameBufferLen  int
-	frameBufferSize int
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) discardFragments() {
+	if len(d.fragments) != 0 {
+		// the starting fragment is stored in two parts
+		d.discardedFragments += uint64(len(d.fragments) - 1)
+		d.dropFragments()
+	}
+}
+
+// dropFragments drops pending fragments and counts them as an incomplete discarded unit.
+func (d *Decoder) dropFragments() {
+	if len(d.fragments) != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+		d.fragments = d.fragments[:0]
+	}
+}
+
+func (d *Decoder) discardAccessUnit() {
+	if d.frameBufferLen != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.frameBufferSize)
+	}
+
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+}
+
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.PacketsRejected++
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+	}
 }
 
 // Init initializes the decoder.
 func (d *Decoder) Init() error {
-	if d.PacketizationMode >= 2 {
-		return fmt.Errorf("PacketizationMode >= 2 is not supported")
+	if d.PacketizationMode > 2 {
+		return fmt.Errorf("PacketizationMode > 2 is not supported")
 	}
+
+	if d.PacketizationMode == 2 {
+		if d.InterleavingDepth < 0 {
+			return fmt.Errorf("invalid sprop-interleaving-depth (%d)", d.InterleavingDepth)
+		}
+
+		if d.InterleavingDepth > maxInterleavingDepth {
+			return fmt.Errorf("sprop-interleaving-depth of %d is not supported (maximum is %d)",
+				d.InterleavingDepth, maxInterleavingDepth)
+		}
+	}
+
 	return nil
 }
 
 func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 	if len(pkt.Payload) < 1 {
-		d.fragments = d.fragments[:0] // discard pending fragments
+		d.dropFragments()
 		return nil, fmt.Errorf("payload is too short")
 	}
 
@@ -82,7 +171,7 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 		end := (pkt.Payload[1] >> 6) & 0x01
 
 		if start == 1 {
-			d.fragments = d.fragments[:0] // discard pending fragments
+			d.discardFragments()
 
 			if end != 0 {
 				return nil, fmt.Errorf("invalid FU-A packet (can't contain both a start and end bit)")
@@ -92,27 +181,30 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			typ := pkt.Payload[1] & 0x1F
 			d.fragmentsSize = len(pkt.Payload[1:])
 			d.fragments = append(d.fragments, []byte{(nri << 5) | typ}, pkt.Payload[2:])
-			d.firstPacketReceived = true
+			d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
 
 			return nil, ErrMorePacketsNeeded
 		}
 
-		if len(d.fragments) == 0 {
-			if !d.firstPacketReceived {
-				return nil, ErrNonStartingPacketAndNoPrevious
-			}
+		// a previous fragment has been lost:
+		// skip fragments until the next NALU.
+		if len(d.fragments) == 0 || pkt.SequenceNumber != d.fragmentsNextSeqNum {
+			d.discardFragments()
+			d.discardedFragments++
+			d.stats.BytesDropped += uint64(len(pkt.Payload))
+			return nil, ErrMorePacketsNeeded
+		}
 
-			return nil, fmt.Errorf("invalid FU-A packet (non-starting)")
+		if (d.fragmentsSize + len(pkt.Payload[2:])) > h264.MaxAccessUnitSize {
+			size := d.fragmentsSize + len(pkt.Payload[2:])
+			d.dropFragments()
+			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", size, h264.MaxAccessUnitSize)
 		}
 
 		d.fragmentsSize += len(pkt.Payload[2:])
 
-		if d.fragmentsSize > h264.MaxAccessUnitSize {
-			d.fragments = d.fragments[:0]
-			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", d.fragmentsSize, h264.MaxAccessUnitSize)
-		}
-
 		d.fragments = append(d.fragments, pkt.Payload[2:])
+		d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
 
 		if end != 1 {
 			return nil, ErrMorePacketsNeeded
@@ -122,7 +214,7 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 		d.fragments = d.fragments[:0]
 
 	case h264.NALUTypeSTAPA:
-		d.fragments = d.fragments[:0] // discard pending fragments
+		d.dropFragments()
 
 		payload := pkt.Payload[1:]
 
@@ -155,17 +247,13 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			return nil, fmt.Errorf("STAP-A packet doesn't contain any NALU")
 		}
 
-		d.firstPacketReceived = true
-
 	case h264.NALUTypeSTAPB, h264.NALUTypeMTAP16,
 		h264.NALUTypeMTAP24, h264.NALUTypeFUB:
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.firstPacketReceived = true
+		d.dropFragments()
 		return nil, fmt.Errorf("packet type not supported (%v)", typ)
 
 	default:
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.firstPacketReceived = true
+		d.dropFragments()
 		nalus = [][]byte{pkt.Payload}
 	}
 
@@ -179,6 +267,16 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 
 // Decode decodes an access unit from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	if d.PacketizationMode == 2 {
+		return nil, fmt.Errorf("streams with packetization-mode=2 must be decoded with DecodeInterleaved()")
+	}
+
+	au, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return au, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([][]byte, error) {
 	nalus, err := d.decodeNALUs(pkt)
 	if err != nil {
 		return nil, err
@@ -186,9 +284,7 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	l := len(nalus)
 
 	if (d.frameBufferLen + l) > h264.MaxNALUsPerAccessUnit {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		d.discardAccessUnit()
 		return nil, fmt.Errorf("NALU count exceeds maximum allowed (%d)",
 			h264.MaxNALUsPerAccessUnit)
 	}
@@ -200,11 +296,10 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	}
 
 	if (d.frameBufferSize + addSize) > h264.MaxAccessUnitSize {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		size := d.frameBufferSize + addSize
+		d.discardAccessUnit()
 		return nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
-			d.frameBufferSize+addSize, h264.MaxAccessUnitSize)
+			size, h264.MaxAccessUnitSize)
 	}
 
 	d.frameBuffer = append(d.frameBuffer, nalus...)
@@ -225,6 +320,32 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	return ret, nil
 }
 
+// FlushAccessUnit returns the NALUs of the access unit that is currently
+// being decoded, and resets the decoder state.
+// It can be used when the stream ends, in order to retrieve the last access
+// unit, whose packet with the marker bit may have never been received.
+// NALUs that are still fragmented are discarded.
+func (d *Decoder) FlushAccessUnit() ([][]byte, error) {
+	if d.PacketizationMode == 2 {
+		return nil, fmt.Errorf("streams with packetization-mode=2 can't be flushed")
+	}
+
+	d.dropFragments()
+
+	if d.frameBufferLen == 0 {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	ret := d.frameBuffer
+
+	// do not reuse frameBuffer to avoid race conditions
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+
+	return ret, nil
+}
+
 // some cameras / servers wrap NALUs into Annex-B
 func (d *Decoder) removeAnnexB(nalus [][]byte) ([][]byte, error) {
 	if len(nalus) == 1 {
@@ -0,0 +1,348 @@
+package rtph264
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+)
+
+const (
+	// maximum supported value of sprop-interleaving-depth.
+	maxInterleavingDepth = 32
+
+	// maximum number of NALUs that can be waiting to be put in decoding order.
+	maxDeinterleavingBufferSize = (maxInterleavingDepth + 1) * h264.MaxNALUsPerAccessUnit
+)
+
+// AccessUnit is an access unit decoded by DecodeInterleaved().
+type AccessUnit struct {
+	// RTP timestamp of the access unit.
+	Timestamp uint32
+
+	// NALUs of the access unit.
+	NALUs [][]byte
+}
+
+type interleavedNALU struct {
+	absDON    int64
+	timestamp uint32
+	nalu      []byte
+}
+
+func isVCL(nalu []byte) bool {
+	typ := h264.NALUType(nalu[0] & 0x1F)
+	return typ >= h264.NALUTypeNonIDR && typ <= h264.NALUTypeIDR
+}
+
+func (d *Decoder) absDON(don uint16) int64 {
+	if !d.donReceived {
+		d.donReceived = true
+		d.lastDON = don
+		d.lastAbsDON = int64(don)
+		return d.lastAbsDON
+	}
+
+	d.lastAbsDON += int64(int16(don - d.lastDON))
+	d.lastDON = don
+	return d.lastAbsDON
+}
+
+func (d *Decoder) decodeInterleavedNALUs(pkt *rtp.Packet) ([]interleavedNALU, error) {
+	if len(pkt.Payload) < 1 {
+		d.dropFragments()
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	typ := h264.NALUType(pkt.Payload[0] & 0x1F)
+	var nalus []interleavedNALU
+
+	switch typ {
+	case h264.NALUTypeFUB:
+		d.discardFragments()
+
+		if len(pkt.Payload) < 4 {
+			return nil, fmt.Errorf("invalid FU-B packet (invalid size)")
+		}
+
+		start := pkt.Payload[1] >> 7
+		end := (pkt.Payload[1] >> 6) & 0x01
+
+		if start != 1 {
+			return nil, fmt.Errorf("invalid FU-B packet (non-starting)")
+		}
+
+		if end != 0 {
+			return nil, fmt.Errorf("invalid FU-B packet (can't contain both a start and end bit)")
+		}
+
+		nri := (pkt.Payload[0] >> 5) & 0x03
+		typ := pkt.Payload[1] & 0x1F
+		d.fragmentsDON = uint16(pkt.Payload[2])<<8 | uint16(pkt.Payload[3])
+		d.fragmentsSize = 1 + len(pkt.Payload[4:])
+		d.fragments = append(d.fragments, []byte{(nri << 5) | typ}, pkt.Payload[4:])
+		d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
+
+		return nil, ErrMorePacketsNeeded
+
+	case h264.NALUTypeFUA:
+		if len(pkt.Payload) < 2 {
+			return nil, fmt.Errorf("invalid FU-A packet (invalid size)")
+		}
+
+		start := pkt.Payload[1] >> 7
+		end := (pkt.Payload[1] >> 6) & 0x01
+
+		if start == 1 {
+			d.dropFragments()
+			return nil, fmt.Errorf("invalid FU-A packet (starting fragments must be sent with FU-B in interleaved mode)")
+		}
+
+		// a previous fragment has been lost:
+		// skip fragments until the next NALU.
+		if len(d.fragments) == 0 || pkt.SequenceNumber != d.fragmentsNextSeqNum {
+			d.discardFragments()
+			d.discardedFragments++
+			d.stats.BytesDropped += uint64(len(pkt.Payload))
+			return nil, ErrMorePacketsNeeded
+		}
+
+		if (d.fragmentsSize + len(pkt.Payload[2:])) > h264.MaxAccessUnitSize {
+			size := d.fragmentsSize + len(pkt.Payload[2:])
+			d.dropFragments()
+			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", size, h264.MaxAccessUnitSize)
+		}
+
+		d.fragmentsSize += len(pkt.Payload[2:])
+
+		d.fragments = append(d.fragments, pkt.Payload[2:])
+		d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
+
+		if end != 1 {
+			return nil, ErrMorePacketsNeeded
+		}
+
+		nalus = []interleavedNALU{{
+			absDON:    d.absDON(d.fragmentsDON),
+			timestamp: pkt.Timestamp,
+			nalu:      joinFragments(d.fragments, d.fragmentsSize),
+		}}
+		d.fragments = d.fragments[:0]
+
+	case h264.NALUTypeSTAPB:
+		d.dropFragments()
+
+		if len(pkt.Payload) < 3 {
+			return nil, fmt.Errorf("invalid STAP-B packet (invalid size)")
+		}
+
+		don := uint16(pkt.Payload[1])<<8 | uint16(pkt.Payload[2])
+		payload := pkt.Payload[3:]
+
+		for {
+			if len(payload) < 2 {
+				return nil, fmt.Errorf("invalid STAP-B packet (invalid size)")
+			}
+
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			payload = payload[2:]
+
+			if size == 0 || int(size) > len(payload) {
+				return nil, fmt.Errorf("invalid STAP-B packet (invalid size)")
+			}
+
+			nalus = append(nalus, interleavedNALU{
+				absDON:    d.absDON(don),
+				timestamp: pkt.Timestamp,
+				nalu:      payload[:size],
+			})
+			payload = payload[size:]
+			don++
+
+			if len(payload) == 0 {
+				break
+			}
+		}
+
+	case h264.NALUTypeMTAP16, h264.NALUTypeMTAP24:
+		d.dropFragments()
+
+		tsOffsetSize := 2
+		if typ == h264.NALUTypeMTAP24 {
+			tsOffsetSize = 3
+		}
+
+		if len(pkt.Payload) < 3 {
+			return nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+		}
+
+		donb := uint16(pkt.Payload[1])<<8 | uint16(pkt.Payload[2])
+		payload := pkt.Payload[3:]
+
+		for {
+			if len(payload) < (3 + tsOffsetSize) {
+				return nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+			}
+
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			dond := payload[2]
+
+			var tsOffset uint32
+			for _, b := range payload[3 : 3+tsOffsetSize] {
+				tsOffset = tsOffset<<8 | uint32(b)
+			}
+
+			payload = payload[3+tsOffsetSize:]
+
+			if size == 0 || int(size) > len(payload) {
+				return nil, fmt.Errorf("invalid %v packet (invalid size)", typ)
+			}
+
+			nalus = append(nalus, interleavedNALU{
+				absDON:    d.absDON(donb + uint16(dond)),
+				timestamp: pkt.Timestamp + tsOffset,
+				nalu:      payload[:size],
+			})
+			payload = payload[size:]
+
+			if len(payload) == 0 {
+				break
+			}
+		}
+
+	default:
+		d.dropFragments()
+		return nil, fmt.Errorf("packet type not allowed in interleaved mode (%v)", typ)
+	}
+
+	return nalus, nil
+}
+
+func (d *Decoder) resetAccessUnit() {
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+}
+
+func (d *Decoder) addToAccessUnit(n interleavedNALU) error {
+	// NALUs are in decoding order, therefore a timestamp change
+	// means that the current access unit is complete.
+	if d.frameBufferLen != 0 && n.timestamp != d.frameBufferTimestamp {
+		d.readyAUs = append(d.readyAUs, AccessUnit{
+			Timestamp: d.frameBufferTimestamp,
+			NALUs:     d.frameBuffer,
+		})
+
+		// do not reuse frameBuffer to avoid race conditions
+		d.resetAccessUnit()
+	}
+
+	if (d.frameBufferLen + 1) > h264.MaxNALUsPerAccessUnit {
+		d.discardAccessUnit()
+		return fmt.Errorf("NALU count exceeds maximum allowed (%d)",
+			h264.MaxNALUsPerAccessUnit)
+	}
+
+	if (d.frameBufferSize + len(n.nalu)) > h264.MaxAccessUnitSize {
+		size := d.frameBufferSize + len(n.nalu)
+		d.discardAccessUnit()
+		return fmt.Errorf("access unit size (%d) is too big, maximum is %d",
+			size, h264.MaxAccessUnitSize)
+	}
+
+	d.frameBuffer = append(d.frameBuffer, n.nalu)
+	d.frameBufferLen++
+	d.frameBufferSize += len(n.nalu)
+	d.frameBufferTimestamp = n.timestamp
+
+	return nil
+}
+
+func (d *Decoder) deinterleave(n interleavedNALU) error {
+	if d.outputReceived && n.absDON < d.lastOutputAbsDON {
+		return fmt.Errorf("NALU received too late, interleaving depth exceeds sprop-interleaving-depth (%d)",
+			d.InterleavingDepth)
+	}
+
+	if len(d.deintBuffer) >= maxDeinterleavingBufferSize {
+		d.stats.IncompleteDiscarded++
+		for _, n := range d.deintBuffer {
+			d.stats.BytesDropped += uint64(len(n.nalu))
+		}
+		d.deintBuffer = nil
+		d.deintVCLCount = 0
+		return fmt.Errorf("deinterleaving buffer is full")
+	}
+
+	i := sort.Search(len(d.deintBuffer), func(i int) bool {
+		return d.deintBuffer[i].absDON > n.absDON
+	})
+	d.deintBuffer = append(d.deintBuffer, interleavedNALU{})
+	copy(d.deintBuffer[i+1:], d.deintBuffer[i:])
+	d.deintBuffer[i] = n
+
+	if isVCL(n.nalu) {
+		d.deintVCLCount++
+	}
+
+	// output NALUs in decoding order as soon as the buffer contains
+	// more VCL NALUs than the interleaving depth.
+	for d.deintVCLCount > d.InterleavingDepth {
+		out := d.deintBuffer[0]
+		d.deintBuffer = d.deintBuffer[1:]
+
+		if isVCL(out.nalu) {
+			d.deintVCLCount--
+		}
+
+		d.outputReceived = true
+		d.lastOutputAbsDON = out.absDON
+
+		err := d.addToAccessUnit(out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeInterleaved decodes access units from a RTP packet of a stream
+// that uses the interleaved packetization mode (packetization-mode=2).
+// NALUs are put in decoding order by using their decoding order number (DON),
+// therefore access units are returned with a delay that depends on
+// sprop-interleaving-depth.
+func (d *Decoder) DecodeInterleaved(pkt *rtp.Packet) ([]AccessUnit, error) {
+	if d.PacketizationMode != 2 {
+		return nil, fmt.Errorf("DecodeInterleaved() can be used only when PacketizationMode is 2")
+	}
+
+	aus, err := d.decodeInterleaved(pkt)
+	d.countRejected(pkt, err)
+	return aus, err
+}
+
+func (d *Decoder) decodeInterleaved(pkt *rtp.Packet) ([]AccessUnit, error) {
+	nalus, err := d.decodeInterleavedNALUs(pkt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range nalus {
+		err = d.deinterleave(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.readyAUs) == 0 {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	ret := d.readyAUs
+	d.readyAUs = nil
+
+	return ret, nil
+}
@@ -0,0 +1,200 @@
+package rtph264
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInterleaved(t *testing.T) {
+	d := &Decoder{
+		PacketizationMode: 2,
+		InterleavingDepth: 1,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// MTAP16 with SPS (DON 0) and a non-IDR slice of the second access unit (DON 2)
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      1000,
+		},
+		Payload: mergeBytes(
+			[]byte{0x1a, 0x00, 0x00},
+			[]byte{0x00, 0x02, 0x00, 0x00, 0x00, 0x67, 0x01},
+			[]byte{0x00, 0x02, 0x02, 0x0b, 0xb8, 0x41, 0x02},
+		),
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// FU-B and FU-A with an IDR slice of the first access unit (DON 1)
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      1000,
+		},
+		Payload: []byte{0x7d, 0x85, 0x00, 0x01, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      1000,
+		},
+		Payload: []byte{0x7c, 0x45, 0x03, 0x04},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// STAP-B with a non-IDR slice of the third access unit (DON 3)
+	aus, err := d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      7000,
+		},
+		Payload: []byte{0x19, 0x00, 0x03, 0x00, 0x02, 0x41, 0x03},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AccessUnit{{
+		Timestamp: 1000,
+		NALUs: [][]byte{
+			{0x67, 0x01},
+			{0x65, 0x01, 0x02, 0x03, 0x04},
+		},
+	}}, aus)
+
+	// MTAP24 with a non-IDR slice of the fourth access unit (DON 4)
+	aus, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17649,
+			Timestamp:      10000,
+		},
+		Payload: []byte{0x1b, 0x00, 0x04, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x41, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AccessUnit{{
+		Timestamp: 4000,
+		NALUs:     [][]byte{{0x41, 0x02}},
+	}}, aus)
+}
+
+func TestDecodeInterleavedErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts [][]byte
+		err  string
+	}{
+		{
+			"single NALU",
+			[][]byte{{0x65, 0x01}},
+			"packet type not allowed in interleaved mode (IDR)",
+		},
+		{
+			"FU-A starting fragment",
+			[][]byte{{0x7c, 0x85, 0x01}},
+			"invalid FU-A packet (starting fragments must be sent with FU-B in interleaved mode)",
+		},
+		{
+			"STAP-B invalid size",
+			[][]byte{{0x19, 0x00, 0x00, 0x00, 0x05, 0x41}},
+			"invalid STAP-B packet (invalid size)",
+		},
+		{
+			"MTAP16 invalid size",
+			[][]byte{{0x1a, 0x00, 0x00, 0x00, 0x02, 0x00}},
+			"invalid MTAP-16 packet (invalid size)",
+		},
+		{
+			"late NALU",
+			[][]byte{
+				{0x19, 0x00, 0x05, 0x00, 0x02, 0x41, 0x01},
+				{0x19, 0x00, 0x06, 0x00, 0x02, 0x41, 0x02},
+				{0x19, 0x00, 0x04, 0x00, 0x02, 0x41, 0x03},
+			},
+			"NALU received too late, interleaving depth exceeds sprop-interleaving-depth (0)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				PacketizationMode: 2,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			for _, payload := range ca.pkts {
+				_, err = d.DecodeInterleaved(&rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+					},
+					Payload: payload,
+				})
+			}
+
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestDecodeInterleavedUnsupportedDepth(t *testing.T) {
+	d := &Decoder{
+		PacketizationMode: 2,
+		InterleavingDepth: 33,
+	}
+	err := d.Init()
+	require.EqualError(t, err, "sprop-interleaving-depth of 33 is not supported (maximum is 32)")
+}
+
+func TestDecodeInterleavedWrongMethod(t *testing.T) {
+	d := &Decoder{
+		PacketizationMode: 2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{Payload: []byte{0x65, 0x01}})
+	require.EqualError(t, err, "streams with packetization-mode=2 must be decoded with DecodeInterleaved()")
+
+	d = &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	_, err = d.DecodeInterleaved(&rtp.Packet{Payload: []byte{0x65, 0x01}})
+	require.EqualError(t, err, "DecodeInterleaved() can be used only when PacketizationMode is 2")
+}
+
+func FuzzDecoderInterleaved(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
+		d := &Decoder{
+			PacketizationMode: 2,
+			InterleavingDepth: 2,
+		}
+		d.Init() //nolint:errcheck
+
+		for _, payload := range [][]byte{a, b} {
+			d.DecodeInterleaved(&rtp.Packet{ //nolint:errcheck
+				Header: rtp.Header{
+					Version:        2,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+				},
+				Payload: payload,
+			})
+		}
+	})
+}
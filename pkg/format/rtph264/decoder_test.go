@@ -190,6 +190,148 @@ func TestDecodeAccessUnit(t *testing.T) {
 	require.Equal(t, [][]byte{{0x01, 0x02}, {0x01, 0x02}}, nalus)
 }
 
+func TestDecodeFlushAccessUnit(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.FlushAccessUnit()
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      2289531307,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// starting fragment of a FU-A
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      2289531307,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x1c, 0x85, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	nalus, err := d.FlushAccessUnit()
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x01, 0x02}}, nalus)
+	require.Equal(t, DecoderStats{
+		IncompleteDiscarded: 1,
+		BytesDropped:        3,
+	}, d.Stats())
+
+	_, err = d.FlushAccessUnit()
+	require.Equal(t, ErrMorePacketsNeeded, err)
+}
+
+func TestDecodeFragmentLoss(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// non-starting fragment without any previous fragment
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x1c, 0x05, 0x01},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+	require.Equal(t, uint64(1), d.DiscardedFragments())
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x7c, 0x85, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// ending fragment after a lost one
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x7c, 0x45, 0x03, 0x04},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+	require.Equal(t, uint64(3), d.DiscardedFragments())
+
+	nalus, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17649,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x41, 0x01},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x41, 0x01}}, nalus)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17650,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x7c, 0x85, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	nalus, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17651,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x7c, 0x45, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x65, 0x01, 0x02, 0x03, 0x04}}, nalus)
+	require.Equal(t, uint64(3), d.DiscardedFragments())
+	require.Equal(t, DecoderStats{
+		IncompleteDiscarded: 1,
+		BytesDropped:        10,
+	}, d.Stats())
+}
+
 func TestDecoderErrorLimit(t *testing.T) {
 	d := &Decoder{}
 	err := d.Init()
@@ -210,6 +352,11 @@ func TestDecoderErrorLimit(t *testing.T) {
 	}
 
 	require.EqualError(t, err, "NALU count exceeds maximum allowed (25)")
+	require.Equal(t, DecoderStats{
+		PacketsRejected:     1,
+		IncompleteDiscarded: 1,
+		BytesDropped:        104,
+	}, d.Stats())
 }
 
 func FuzzDecoder(f *testing.F) {
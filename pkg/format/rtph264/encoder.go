@@ -7,6 +7,9 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpfragment"
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -39,14 +42,6 @@ func lenAggregated(nalus [][]byte, addNALU []byte) int {
 	return n
 }
 
-func packetCount(avail, le int) int {
-	n := le / avail
-	if (le % avail) != 0 {
-		n++
-	}
-	return n
-}
-
 // Encoder is a RTP/H264 encoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc6184
 type Encoder struct {
@@ -67,11 +62,29 @@ type Encoder struct {
 
 	PacketizationMode int
 
+	// disable aggregation of small NALUs into STAP-A packets (optional).
+	// This is needed by receivers that don't support STAP-A.
+	DisableAggregation bool
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.PacketizationMode >= 2 {
 		return fmt.Errorf("PacketizationMode >= 2 is not supported")
 	}
@@ -101,12 +114,23 @@ func (e *Encoder) Init() error {
 
 // Encode encodes an access unit into RTP/H264 packets.
 func (e *Encoder) Encode(au [][]byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(au)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(au [][]byte) ([]*rtp.Packet, error) {
 	var rets []*rtp.Packet
 	var batch [][]byte
 
 	// split NALUs into batches
 	for _, nalu := range au {
-		if lenAggregated(batch, nalu) <= e.PayloadMaxSize {
+		if !e.DisableAggregation && lenAggregated(batch, nalu) <= e.PayloadMaxSize {
 			// add to existing batch
 			batch = append(batch, nalu)
 		} else {
@@ -169,38 +193,39 @@ func (e *Encoder) writeSingle(nalu []byte, marker bool) ([]*rtp.Packet, error) {
 func (e *Encoder) writeFragmented(nalu []byte, marker bool) ([]*rtp.Packet, error) {
 	// use only FU-A, not FU-B, since we always use non-interleaved mode
 	// (packetization-mode=1)
-	avail := e.PayloadMaxSize - 2
-	le := len(nalu) - 1
-	packetCount := packetCount(avail, le)
-
-	ret := make([]*rtp.Packet, packetCount)
+	f := rtpfragment.Fragmenter{
+		PayloadMaxSize: e.PayloadMaxSize,
+		FirstPrefixLen: 2,
+		PrefixLen:      2,
+	}
 
 	nri := (nalu[0] >> 5) & 0x03
 	typ := nalu[0] & 0x1F
-	nalu = nalu[1:] // remove header
-	le = avail
+
+	fragments, err := f.Fragment(nil, nalu[1:]) // remove header
+	if err != nil {
+		return nil, err
+	}
+
+	ret := rtpfragment.NewPackets(len(fragments))
 	start := uint8(1)
 	end := uint8(0)
 
-	for i := range ret {
-		if i == (packetCount - 1) {
+	for i, data := range fragments {
+		if i == (len(fragments) - 1) {
 			end = 1
-			le = len(nalu)
 		}
 
-		data := make([]byte, 2+le)
 		data[0] = (nri << 5) | uint8(h264.NALUTypeFUA)
 		data[1] = (start << 7) | (end << 6) | typ
-		copy(data[2:], nalu)
-		nalu = nalu[le:]
 
-		ret[i] = &rtp.Packet{
+		*ret[i] = rtp.Packet{
 			Header: rtp.Header{
 				Version:        rtpVersion,
 				PayloadType:    e.PayloadType,
 				SequenceNumber: e.sequenceNumber,
 				SSRC:           *e.SSRC,
-				Marker:         (i == (packetCount-1) && marker),
+				Marker:         (i == (len(fragments)-1) && marker),
 			},
 			Payload: data,
 		}
@@ -318,6 +318,103 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeDisableAggregation(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		DisableAggregation:    true,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{
+		{0x67, 0x42, 0xc0, 0x28}, // SPS
+		{0x68, 0xce, 0x3c, 0x80}, // PPS
+		{0x65, 0x88, 0x84, 0x00}, // IDR
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x67, 0x42, 0xc0, 0x28},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x68, 0xce, 0x3c, 0x80},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17647,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x65, 0x88, 0x84, 0x00},
+		},
+	}, pkts)
+}
+
+func TestEncodeMarkerAndPadding(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		DisableAggregation:    true,
+		MarkerFunc: func(i int, _ int, _ bool) bool {
+			return i == 0
+		},
+		PaddingAlignment: 8,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{
+		{0x67, 0x42, 0xc0, 0x28}, // SPS
+		{0x65, 0x88, 0x84, 0x00}, // IDR
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Padding:        true,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload:     []byte{0x67, 0x42, 0xc0, 0x28},
+			PaddingSize: 4,
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Padding:        true,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				SSRC:           0x9dbb7812,
+			},
+			Payload:     []byte{0x65, 0x88, 0x84, 0x00},
+			PaddingSize: 4,
+		},
+	}, pkts)
+}
+
 func TestEncodeRandomInitialState(t *testing.T) {
 	e := &Encoder{
 		PayloadType: 96,
@@ -327,3 +424,42 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func TestEncodeInvalidPaddingAlignment(t *testing.T) {
+	e := &Encoder{
+		PayloadType:      96,
+		PaddingAlignment: 1000,
+	}
+	err := e.Init()
+	require.EqualError(t, err, "invalid PaddingAlignment (1000)")
+}
+
+func BenchmarkEncode(b *testing.B) {
+	idr := make([]byte, 100000)
+	idr[0] = 0x65
+
+	au := [][]byte{
+		{0x67, 0x42, 0xc0, 0x28},
+		{0x68, 0xce, 0x3c, 0x80},
+		idr,
+	}
+
+	e := &Encoder{
+		PayloadType:       96,
+		PacketizationMode: 1,
+	}
+	err := e.Init()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err = e.Encode(au)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package rtph264
+
+import (
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/h264"
+)
+
+// PacketFilter is a function that decides whether a RTP packet must be kept.
+type PacketFilter func(pkt *rtp.Packet) bool
+
+// startsIDR checks whether a RTP/H264 packet contains an IDR NALU
+// or the first fragment of an IDR NALU.
+func startsIDR(payload []byte) bool {
+	if len(payload) == 0 {
+		return false
+	}
+
+	switch h264.NALUType(payload[0] & 0x1F) {
+	case h264.NALUTypeIDR:
+		return true
+
+	case h264.NALUTypeSTAPA:
+		payload = payload[1:]
+
+		for len(payload) >= 2 {
+			size := int(uint16(payload[0])<<8 | uint16(payload[1]))
+			payload = payload[2:]
+
+			if size == 0 || size > len(payload) {
+				return false
+			}
+
+			if h264.NALUType(payload[0]&0x1F) == h264.NALUTypeIDR {
+				return true
+			}
+
+			payload = payload[size:]
+		}
+
+	case h264.NALUTypeFUA:
+		if len(payload) < 2 {
+			return false
+		}
+
+		start := payload[1] >> 7
+		return start == 1 && h264.NALUType(payload[1]&0x1F) == h264.NALUTypeIDR
+	}
+
+	return false
+}
+
+// NewIDROnlyFilter returns a PacketFilter that returns true only for the first
+// packet of each IDR access unit, that is the first packet that contains an IDR
+// NALU, or the starting fragment of an IDR NALU, with a new timestamp.
+// Packets are inspected without being reassembled.
+// Packets of packetization mode 2 (STAP-B, MTAP, FU-B) are never kept.
+func NewIDROnlyFilter() PacketFilter {
+	lastTimestamp := uint32(0)
+	lastTimestampValid := false
+
+	return func(pkt *rtp.Packet) bool {
+		if !startsIDR(pkt.Payload) {
+			return false
+		}
+
+		if lastTimestampValid && pkt.Timestamp == lastTimestamp {
+			return false
+		}
+
+		lastTimestamp = pkt.Timestamp
+		lastTimestampValid = true
+		return true
+	}
+}
+
+// IDROnlyDecoder is a RTP/H264 decoder that returns only IDR access units.
+type IDROnlyDecoder struct {
+	// wrapped decoder.
+	Decoder *Decoder
+}
+
+// Decode decodes an access unit from a RTP packet.
+// Access units that don't contain an IDR are discarded, and
+// ErrMorePacketsNeeded is returned in their place.
+func (d *IDROnlyDecoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	au, err := d.Decoder.Decode(pkt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h264.IDRPresent(au) {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	return au, nil
+}
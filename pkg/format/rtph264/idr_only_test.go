@@ -0,0 +1,77 @@
+package rtph264
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDROnly(t *testing.T) {
+	e := &Encoder{
+		PayloadType:    96,
+		PayloadMaxSize: 1000,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	aus := [][][]byte{
+		{ // SPS, PPS and a fragmented IDR
+			{0x67, 0x42, 0xc0, 0x28},
+			{0x68, 0xce, 0x3c, 0x80},
+			append([]byte{0x65}, bytes.Repeat([]byte{0x01}, 3000)...),
+		},
+		{ // non-IDR
+			{0x41, 0x9a, 0x02},
+		},
+		{ // fragmented non-IDR
+			append([]byte{0x41}, bytes.Repeat([]byte{0x02}, 3000)...),
+		},
+		{ // IDR made of two slices
+			{0x65, 0x88, 0x84},
+			{0x65, 0x88, 0x85},
+		},
+		{ // non-IDR
+			{0x41, 0x9a, 0x03},
+		},
+	}
+
+	var pkts []*rtp.Packet
+	for i, au := range aus {
+		var auPkts []*rtp.Packet
+		auPkts, err = e.Encode(au)
+		require.NoError(t, err)
+
+		for _, pkt := range auPkts {
+			pkt.Timestamp = uint32(i) * 3000
+		}
+		pkts = append(pkts, auPkts...)
+	}
+
+	filter := NewIDROnlyFilter()
+	var kept []uint32
+	for _, pkt := range pkts {
+		if filter(pkt) {
+			kept = append(kept, pkt.Timestamp)
+		}
+	}
+	require.Equal(t, []uint32{0, 9000}, kept)
+
+	d := &IDROnlyDecoder{Decoder: &Decoder{}}
+	err = d.Decoder.Init()
+	require.NoError(t, err)
+
+	var decoded [][][]byte
+	for _, pkt := range pkts {
+		var au [][]byte
+		au, err = d.Decode(pkt)
+		if err == ErrMorePacketsNeeded {
+			require.Nil(t, au)
+			continue
+		}
+		require.NoError(t, err)
+		decoded = append(decoded, au)
+	}
+	require.Equal(t, [][][]byte{aus[0], aus[3]}, decoded)
+}
@@ -14,8 +14,9 @@ var ErrMorePacketsNeeded = errors.New("need more packets")
 
 // ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
 // packet of a fragmented NALU and we didn't received anything before.
-// It's normal to receive this when decoding a stream that has been already
-// running for some time.
+//
+// Deprecated: the decoder doesn't return this error anymore, since
+// fragments that can't be decoded are skipped and counted by DiscardedFragments().
 var ErrNonStartingPacketAndNoPrevious = errors.New(
 	"received a non-starting fragment without any previous starting fragment")
 
@@ -34,27 +35,105 @@ type Decoder struct {
 	// indicates that NALUs have an additional field that specifies the decoding order.
 	MaxDONDiff int
 
-	firstPacketReceived bool
 	fragmentsSize       int
 	fragments           [][]byte
+	fragmentsNextSeqNum uint16
+	discardedFragments  uint64
+	stats               DecoderStats
+
+	// for Decode() and DecodeInterleaved()
+	frameBuffer          [][]byte
+	frameBufferLen       int
+	frameBufferSize      int
+	frameBufferTimestamp uint32
+
+	// for DecodeInterleaved()
+	fragmentsDON     uint16
+	donReceived      bool
+	lastDON          uint16
+	lastAbsDON       int64
+	highestAbsDON    int64
+	outputReceived   bool
+	lastOutputAbsDON int64
+	deintBuffer      []interleavedNALU
+	readyAUs         []AccessUnit
+}
+
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete NALUs or access units that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
+}
+
+// DiscardedFragments returns the number of fragments that have been discarded
+// since some other fragments of the same NALU were lost.
+func (d *Decoder) DiscardedFragments() uint64 {
+	return d.discardedFragments
+}
+
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) discardFragments() {
+	if len(d.fragments) != 0 {
+		// the starting fragment is stored in two parts
+		d.discardedFragments += uint64(len(d.fragments) - 1)
+		d.dropFragments()
+	}
+}
+
+// dropFragments drops pending fragments and counts them as an incomplete discarded unit.
+func (d *Decoder) dropFragments() {
+	if len(d.fragments) != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+		d.fragments = d.fragments[:0]
+	}
+}
+
+func (d *Decoder) discardAccessUnit() {
+	if d.frameBufferLen != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.frameBufferSize)
+	}
+
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+}
 
-	// for Decode()
-	frameBuffer     [][]byte
-	frameBufferLen  int
-	frameBufferSize int
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.PacketsRejected++
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+	}
 }
 
 // Init initializes the decoder.
 func (d *Decoder) Init() error {
-	if d.MaxDONDiff != 0 {
-		return fmt.Errorf("MaxDONDiff != 0 is not supported (yet)")
+	if d.MaxDONDiff < 0 {
+		return fmt.Errorf("invalid sprop-max-don-diff (%d)", d.MaxDONDiff)
+	}
+
+	if d.MaxDONDiff > maxMaxDONDiff {
+		return fmt.Errorf("sprop-max-don-diff of %d is not supported (maximum is %d)",
+			d.MaxDONDiff, maxMaxDONDiff)
 	}
+
 	return nil
 }
 
 func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 	if len(pkt.Payload) < 2 {
-		d.fragments = d.fragments[:0] // discard pending fragments
+		d.dropFragments()
 		return nil, fmt.Errorf("payload is too short")
 	}
 
@@ -63,7 +142,7 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 
 	switch typ {
 	case h265.NALUType_AggregationUnit:
-		d.fragments = d.fragments[:0] // discard pending fragments
+		d.dropFragments()
 
 		payload := pkt.Payload[2:]
 
@@ -91,11 +170,9 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			return nil, fmt.Errorf("aggregation unit doesn't contain any NALU")
 		}
 
-		d.firstPacketReceived = true
-
 	case h265.NALUType_FragmentationUnit:
 		if len(pkt.Payload) < 3 {
-			d.fragments = d.fragments[:0] // discard pending fragments
+			d.dropFragments()
 			return nil, fmt.Errorf("payload is too short")
 		}
 
@@ -103,7 +180,7 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 		end := (pkt.Payload[2] >> 6) & 0x01
 
 		if start == 1 {
-			d.fragments = d.fragments[:0] // discard pending fragments
+			d.discardFragments()
 
 			if end != 0 {
 				return nil, fmt.Errorf("invalid fragmentation unit (can't contain both a start and end bit)")
@@ -113,26 +190,30 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 			head := uint16(pkt.Payload[0]&0b10000001)<<8 | uint16(typ)<<9 | uint16(pkt.Payload[1])
 			d.fragmentsSize = len(pkt.Payload[1:])
 			d.fragments = append(d.fragments, []byte{byte(head >> 8), byte(head)}, pkt.Payload[3:])
-			d.firstPacketReceived = true
+			d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
 
 			return nil, ErrMorePacketsNeeded
 		}
 
-		if len(d.fragments) == 0 {
-			if !d.firstPacketReceived {
-				return nil, ErrNonStartingPacketAndNoPrevious
-			}
+		// a previous fragment has been lost:
+		// skip fragments until the next NALU.
+		if len(d.fragments) == 0 || pkt.SequenceNumber != d.fragmentsNextSeqNum {
+			d.discardFragments()
+			d.discardedFragments++
+			d.stats.BytesDropped += uint64(len(pkt.Payload))
+			return nil, ErrMorePacketsNeeded
+		}
 
-			return nil, fmt.Errorf("invalid fragmentation unit (non-starting)")
+		if (d.fragmentsSize + len(pkt.Payload[3:])) > h265.MaxAccessUnitSize {
+			size := d.fragmentsSize + len(pkt.Payload[3:])
+			d.dropFragments()
+			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", size, h265.MaxAccessUnitSize)
 		}
 
 		d.fragmentsSize += len(pkt.Payload[3:])
-		if d.fragmentsSize > h265.MaxAccessUnitSize {
-			d.fragments = d.fragments[:0]
-			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", d.fragmentsSize, h265.MaxAccessUnitSize)
-		}
 
 		d.fragments = append(d.fragments, pkt.Payload[3:])
+		d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
 
 		if end != 1 {
 			return nil, ErrMorePacketsNeeded
@@ -142,13 +223,11 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 		d.fragments = d.fragments[:0]
 
 	case h265.NALUType_PACI:
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.firstPacketReceived = true
+		d.dropFragments()
 		return nil, fmt.Errorf("PACI packets are not supported (yet)")
 
 	default:
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.firstPacketReceived = true
+		d.dropFragments()
 		nalus = [][]byte{pkt.Payload}
 	}
 
@@ -157,6 +236,16 @@ func (d *Decoder) decodeNALUs(pkt *rtp.Packet) ([][]byte, error) {
 
 // Decode decodes an access unit from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
+	if d.MaxDONDiff != 0 {
+		return nil, fmt.Errorf("streams with sprop-max-don-diff > 0 must be decoded with DecodeInterleaved()")
+	}
+
+	au, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return au, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([][]byte, error) {
 	nalus, err := d.decodeNALUs(pkt)
 	if err != nil {
 		return nil, err
@@ -164,9 +253,7 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	l := len(nalus)
 
 	if (d.frameBufferLen + l) > h265.MaxNALUsPerAccessUnit {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		d.discardAccessUnit()
 		return nil, fmt.Errorf("NALU count exceeds maximum allowed (%d)",
 			h265.MaxNALUsPerAccessUnit)
 	}
@@ -178,11 +265,10 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([][]byte, error) {
 	}
 
 	if (d.frameBufferSize + addSize) > h265.MaxAccessUnitSize {
-		d.frameBuffer = nil
-		d.frameBufferLen = 0
-		d.frameBufferSize = 0
+		size := d.frameBufferSize + addSize
+		d.discardAccessUnit()
 		return nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
-			d.frameBufferSize+addSize, h265.MaxAccessUnitSize)
+			size, h265.MaxAccessUnitSize)
 	}
 
 	d.frameBuffer = append(d.frameBuffer, nalus...)
@@ -0,0 +1,303 @@
+package rtph265
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pion/rtp"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/h265"
+)
+
+const (
+	// maximum supported value of sprop-max-don-diff.
+	maxMaxDONDiff = 1024
+)
+
+// AccessUnit is an access unit decoded by DecodeInterleaved().
+type AccessUnit struct {
+	// RTP timestamp of the access unit.
+	Timestamp uint32
+
+	// NALUs of the access unit.
+	NALUs [][]byte
+}
+
+type interleavedNALU struct {
+	absDON    int64
+	timestamp uint32
+	nalu      []byte
+}
+
+func (d *Decoder) absDON(don uint16) int64 {
+	if !d.donReceived {
+		d.donReceived = true
+		d.lastDON = don
+		d.lastAbsDON = int64(don)
+		d.highestAbsDON = d.lastAbsDON
+		return d.lastAbsDON
+	}
+
+	d.lastAbsDON += int64(int16(don - d.lastDON))
+	d.lastDON = don
+
+	if d.lastAbsDON > d.highestAbsDON {
+		d.highestAbsDON = d.lastAbsDON
+	}
+
+	return d.lastAbsDON
+}
+
+func (d *Decoder) decodeInterleavedNALUs(pkt *rtp.Packet) ([]interleavedNALU, error) {
+	if len(pkt.Payload) < 2 {
+		d.dropFragments()
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	typ := h265.NALUType((pkt.Payload[0] >> 1) & 0b111111)
+	var nalus []interleavedNALU
+
+	switch typ {
+	case h265.NALUType_AggregationUnit:
+		d.dropFragments()
+
+		if len(pkt.Payload) < 4 {
+			return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+		}
+
+		don := uint16(pkt.Payload[2])<<8 | uint16(pkt.Payload[3])
+		payload := pkt.Payload[4:]
+
+		for {
+			if len(payload) < 2 {
+				return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+			}
+
+			size := uint16(payload[0])<<8 | uint16(payload[1])
+			payload = payload[2:]
+
+			if size == 0 || int(size) > len(payload) {
+				return nil, fmt.Errorf("invalid aggregation unit (invalid size)")
+			}
+
+			nalus = append(nalus, interleavedNALU{
+				absDON:    d.absDON(don),
+				timestamp: pkt.Timestamp,
+				nalu:      payload[:size],
+			})
+			payload = payload[size:]
+
+			if len(payload) == 0 {
+				break
+			}
+
+			// DOND
+			don += uint16(payload[0]) + 1
+			payload = payload[1:]
+		}
+
+	case h265.NALUType_FragmentationUnit:
+		if len(pkt.Payload) < 3 {
+			d.dropFragments()
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		start := pkt.Payload[2] >> 7
+		end := (pkt.Payload[2] >> 6) & 0x01
+
+		if start == 1 {
+			d.discardFragments()
+
+			if end != 0 {
+				return nil, fmt.Errorf("invalid fragmentation unit (can't contain both a start and end bit)")
+			}
+
+			if len(pkt.Payload) < 5 {
+				return nil, fmt.Errorf("payload is too short")
+			}
+
+			typ := pkt.Payload[2] & 0b111111
+			head := uint16(pkt.Payload[0]&0b10000001)<<8 | uint16(typ)<<9 | uint16(pkt.Payload[1])
+			d.fragmentsDON = uint16(pkt.Payload[3])<<8 | uint16(pkt.Payload[4])
+			d.fragmentsSize = 2 + len(pkt.Payload[5:])
+			d.fragments = append(d.fragments, []byte{byte(head >> 8), byte(head)}, pkt.Payload[5:])
+			d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
+
+			return nil, ErrMorePacketsNeeded
+		}
+
+		// a previous fragment has been lost:
+		// skip fragments until the next NALU.
+		if len(d.fragments) == 0 || pkt.SequenceNumber != d.fragmentsNextSeqNum {
+			d.discardFragments()
+			d.discardedFragments++
+			d.stats.BytesDropped += uint64(len(pkt.Payload))
+			return nil, ErrMorePacketsNeeded
+		}
+
+		if (d.fragmentsSize + len(pkt.Payload[3:])) > h265.MaxAccessUnitSize {
+			size := d.fragmentsSize + len(pkt.Payload[3:])
+			d.dropFragments()
+			return nil, fmt.Errorf("NALU size (%d) is too big, maximum is %d", size, h265.MaxAccessUnitSize)
+		}
+
+		d.fragmentsSize += len(pkt.Payload[3:])
+
+		d.fragments = append(d.fragments, pkt.Payload[3:])
+		d.fragmentsNextSeqNum = pkt.SequenceNumber + 1
+
+		if end != 1 {
+			return nil, ErrMorePacketsNeeded
+		}
+
+		nalus = []interleavedNALU{{
+			absDON:    d.absDON(d.fragmentsDON),
+			timestamp: pkt.Timestamp,
+			nalu:      joinFragments(d.fragments, d.fragmentsSize),
+		}}
+		d.fragments = d.fragments[:0]
+
+	case h265.NALUType_PACI:
+		d.dropFragments()
+		return nil, fmt.Errorf("PACI packets are not supported (yet)")
+
+	default:
+		d.dropFragments()
+
+		if len(pkt.Payload) < 5 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		// remove DONL
+		nalu := make([]byte, len(pkt.Payload)-2)
+		copy(nalu, pkt.Payload[:2])
+		copy(nalu[2:], pkt.Payload[4:])
+
+		nalus = []interleavedNALU{{
+			absDON:    d.absDON(uint16(pkt.Payload[2])<<8 | uint16(pkt.Payload[3])),
+			timestamp: pkt.Timestamp,
+			nalu:      nalu,
+		}}
+	}
+
+	return nalus, nil
+}
+
+func (d *Decoder) resetAccessUnit() {
+	d.frameBuffer = nil
+	d.frameBufferLen = 0
+	d.frameBufferSize = 0
+}
+
+func (d *Decoder) addToAccessUnit(n interleavedNALU) error {
+	// NALUs are in decoding order, therefore a timestamp change
+	// means that the current access unit is complete.
+	if d.frameBufferLen != 0 && n.timestamp != d.frameBufferTimestamp {
+		d.readyAUs = append(d.readyAUs, AccessUnit{
+			Timestamp: d.frameBufferTimestamp,
+			NALUs:     d.frameBuffer,
+		})
+
+		// do not reuse frameBuffer to avoid race conditions
+		d.resetAccessUnit()
+	}
+
+	if (d.frameBufferLen + 1) > h265.MaxNALUsPerAccessUnit {
+		d.discardAccessUnit()
+		return fmt.Errorf("NALU count exceeds maximum allowed (%d)",
+			h265.MaxNALUsPerAccessUnit)
+	}
+
+	if (d.frameBufferSize + len(n.nalu)) > h265.MaxAccessUnitSize {
+		size := d.frameBufferSize + len(n.nalu)
+		d.discardAccessUnit()
+		return fmt.Errorf("access unit size (%d) is too big, maximum is %d",
+			size, h265.MaxAccessUnitSize)
+	}
+
+	d.frameBuffer = append(d.frameBuffer, n.nalu)
+	d.frameBufferLen++
+	d.frameBufferSize += len(n.nalu)
+	d.frameBufferTimestamp = n.timestamp
+
+	return nil
+}
+
+func (d *Decoder) deinterleave(n interleavedNALU) error {
+	if d.outputReceived && n.absDON <= d.lastOutputAbsDON {
+		return fmt.Errorf("NALU received too late, DON difference exceeds sprop-max-don-diff (%d)",
+			d.MaxDONDiff)
+	}
+
+	if len(d.deintBuffer) >= (d.MaxDONDiff + 1 + h265.MaxNALUsPerAccessUnit) {
+		d.stats.IncompleteDiscarded++
+		for _, n := range d.deintBuffer {
+			d.stats.BytesDropped += uint64(len(n.nalu))
+		}
+		d.deintBuffer = nil
+		return fmt.Errorf("deinterleaving buffer is full")
+	}
+
+	i := sort.Search(len(d.deintBuffer), func(i int) bool {
+		return d.deintBuffer[i].absDON > n.absDON
+	})
+	d.deintBuffer = append(d.deintBuffer, interleavedNALU{})
+	copy(d.deintBuffer[i+1:], d.deintBuffer[i:])
+	d.deintBuffer[i] = n
+
+	// NALUs that follow in decoding order have a DON that is at least
+	// the highest received DON minus sprop-max-don-diff. Since each NALU
+	// has a distinct DON, NALUs up to this value can be output.
+	for len(d.deintBuffer) != 0 &&
+		d.deintBuffer[0].absDON <= (d.highestAbsDON-int64(d.MaxDONDiff)) {
+		out := d.deintBuffer[0]
+		d.deintBuffer = d.deintBuffer[1:]
+
+		d.outputReceived = true
+		d.lastOutputAbsDON = out.absDON
+
+		err := d.addToAccessUnit(out)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeInterleaved decodes access units from a RTP packet of a stream
+// whose NALUs contain decoding order numbers (sprop-max-don-diff > 0).
+// NALUs are put in decoding order, therefore access units are returned
+// with a delay that depends on sprop-max-don-diff.
+func (d *Decoder) DecodeInterleaved(pkt *rtp.Packet) ([]AccessUnit, error) {
+	if d.MaxDONDiff == 0 {
+		return nil, fmt.Errorf("DecodeInterleaved() can be used only when MaxDONDiff is greater than zero")
+	}
+
+	aus, err := d.decodeInterleaved(pkt)
+	d.countRejected(pkt, err)
+	return aus, err
+}
+
+func (d *Decoder) decodeInterleaved(pkt *rtp.Packet) ([]AccessUnit, error) {
+	nalus, err := d.decodeInterleavedNALUs(pkt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range nalus {
+		err = d.deinterleave(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(d.readyAUs) == 0 {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	ret := d.readyAUs
+	d.readyAUs = nil
+
+	return ret, nil
+}
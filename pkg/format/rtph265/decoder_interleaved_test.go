@@ -0,0 +1,258 @@
+package rtph265
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInterleaved(t *testing.T) {
+	d := &Decoder{
+		MaxDONDiff: 1,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// aggregation unit with VPS (DON 0) and IDR (DON 1) of the first access unit
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      1000,
+		},
+		Payload: mergeBytes(
+			[]byte{0x60, 0x01, 0x00, 0x00},
+			[]byte{0x00, 0x02, 0x40, 0x01},
+			[]byte{0x00, 0x00, 0x03, 0x26, 0x01, 0xaa},
+		),
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// single NALU of the third access unit (DON 3)
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      7000,
+		},
+		Payload: []byte{0x02, 0x01, 0x00, 0x03, 0xbb},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// fragmentation units of the second access unit (DON 2)
+	_, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      4000,
+		},
+		Payload: []byte{0x62, 0x01, 0x81, 0x00, 0x02, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	aus, err := d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      4000,
+		},
+		Payload: []byte{0x62, 0x01, 0x41, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AccessUnit{{
+		Timestamp: 1000,
+		NALUs: [][]byte{
+			{0x40, 0x01},
+			{0x26, 0x01, 0xaa},
+		},
+	}}, aus)
+
+	// single NALU of the fourth access unit (DON 4)
+	aus, err = d.DecodeInterleaved(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17649,
+			Timestamp:      10000,
+		},
+		Payload: []byte{0x02, 0x01, 0x00, 0x04, 0xcc},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AccessUnit{{
+		Timestamp: 4000,
+		NALUs:     [][]byte{{0x02, 0x01, 0x01, 0x02, 0x03, 0x04}},
+	}}, aus)
+}
+
+func TestDecodeInterleavedErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		pkts [][]byte
+		err  string
+	}{
+		{
+			"single NALU without DONL",
+			[][]byte{{0x02, 0x01, 0x00}},
+			"payload is too short",
+		},
+		{
+			"aggregation unit invalid size",
+			[][]byte{{0x60, 0x01, 0x00, 0x00, 0x00, 0x05, 0x40}},
+			"invalid aggregation unit (invalid size)",
+		},
+		{
+			"PACI",
+			[][]byte{{0x64, 0x01, 0x00, 0x00}},
+			"PACI packets are not supported (yet)",
+		},
+		{
+			"late NALU",
+			[][]byte{
+				{0x02, 0x01, 0x00, 0x05, 0x01},
+				{0x02, 0x01, 0x00, 0x06, 0x02},
+				{0x02, 0x01, 0x00, 0x04, 0x03},
+			},
+			"NALU received too late, DON difference exceeds sprop-max-don-diff (1)",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				MaxDONDiff: 1,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			for _, payload := range ca.pkts {
+				_, err = d.DecodeInterleaved(&rtp.Packet{
+					Header: rtp.Header{
+						Version:        2,
+						PayloadType:    96,
+						SequenceNumber: 17645,
+						Timestamp:      2289527317,
+					},
+					Payload: payload,
+				})
+			}
+
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
+func TestDecodeInterleavedUnsupportedMaxDONDiff(t *testing.T) {
+	d := &Decoder{
+		MaxDONDiff: 1025,
+	}
+	err := d.Init()
+	require.EqualError(t, err, "sprop-max-don-diff of 1025 is not supported (maximum is 1024)")
+}
+
+func TestDecodeInterleavedWrongMethod(t *testing.T) {
+	d := &Decoder{
+		MaxDONDiff: 1,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{Payload: []byte{0x02, 0x01, 0x00, 0x00, 0x01}})
+	require.EqualError(t, err, "streams with sprop-max-don-diff > 0 must be decoded with DecodeInterleaved()")
+
+	d = &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	_, err = d.DecodeInterleaved(&rtp.Packet{Payload: []byte{0x02, 0x01}})
+	require.EqualError(t, err, "DecodeInterleaved() can be used only when MaxDONDiff is greater than zero")
+}
+
+func TestEncodeDecodeInterleaved(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		MaxDONDiff:            1,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	d := &Decoder{
+		MaxDONDiff: 1,
+	}
+	err = d.Init()
+	require.NoError(t, err)
+
+	in := []AccessUnit{
+		{
+			Timestamp: 1000,
+			NALUs: [][]byte{
+				{0x40, 0x01, 0x01},
+				{0x42, 0x01, 0x02},
+				{0x44, 0x01, 0x03},
+				mergeBytes([]byte{0x26, 0x01}, bytes.Repeat([]byte{0x04}, 4000)),
+			},
+		},
+		{
+			Timestamp: 4000,
+			NALUs:     [][]byte{{0x02, 0x01, 0x05}},
+		},
+		{
+			Timestamp: 7000,
+			NALUs:     [][]byte{{0x02, 0x01, 0x06}},
+		},
+		{
+			Timestamp: 10000,
+			NALUs:     [][]byte{{0x02, 0x01, 0x07}},
+		},
+	}
+
+	var out []AccessUnit
+
+	for _, au := range in {
+		var pkts []*rtp.Packet
+		pkts, err = e.Encode(au.NALUs)
+		require.NoError(t, err)
+
+		for _, pkt := range pkts {
+			require.LessOrEqual(t, len(pkt.Payload), e.PayloadMaxSize)
+			pkt.Timestamp = au.Timestamp
+
+			var aus []AccessUnit
+			aus, err = d.DecodeInterleaved(pkt)
+			if err == ErrMorePacketsNeeded {
+				continue
+			}
+			require.NoError(t, err)
+			out = append(out, aus...)
+		}
+	}
+
+	// the last access units are still waiting to be put in decoding order
+	require.Equal(t, in[:2], out)
+}
+
+func FuzzDecoderInterleaved(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
+		d := &Decoder{
+			MaxDONDiff: 2,
+		}
+		d.Init() //nolint:errcheck
+
+		for _, payload := range [][]byte{a, b} {
+			d.DecodeInterleaved(&rtp.Packet{ //nolint:errcheck
+				Header: rtp.Header{
+					Version:        2,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					Timestamp:      2289527317,
+				},
+				Payload: payload,
+			})
+		}
+	})
+}
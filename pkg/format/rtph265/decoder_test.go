@@ -1,6 +1,7 @@
 package rtph265
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
@@ -39,6 +40,145 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeFragmentLoss(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// non-starting fragment without any previous fragment
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x62, 0x01, 0x13, 0x01},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+	require.Equal(t, uint64(1), d.DiscardedFragments())
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x62, 0x01, 0x93, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// ending fragment after a lost one
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17648,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x62, 0x01, 0x53, 0x03, 0x04},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+	require.Equal(t, uint64(3), d.DiscardedFragments())
+
+	nalus, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17649,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x02, 0x01, 0x05},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x02, 0x01, 0x05}}, nalus)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17650,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x62, 0x01, 0x93, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	nalus, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17651,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x62, 0x01, 0x53, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{0x26, 0x01, 0x01, 0x02, 0x03, 0x04}}, nalus)
+	require.Equal(t, uint64(3), d.DiscardedFragments())
+	require.Equal(t, DecoderStats{
+		IncompleteDiscarded: 1,
+		BytesDropped:        13,
+	}, d.Stats())
+}
+
+func TestDecodeMissingFirstFragment(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	d := &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{
+		mergeBytes([]byte{0x26, 0x01}, bytes.Repeat([]byte{0x01}, 4000)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(pkts))
+
+	// drop the starting fragment
+	for _, pkt := range pkts[1:] {
+		_, err = d.Decode(pkt)
+		require.Equal(t, ErrMorePacketsNeeded, err)
+	}
+	require.Equal(t, uint64(2), d.DiscardedFragments())
+
+	au := [][]byte{
+		{0x02, 0x01, 0x02},
+		mergeBytes([]byte{0x26, 0x01}, bytes.Repeat([]byte{0x03}, 4000)),
+	}
+
+	pkts, err = e.Encode(au)
+	require.NoError(t, err)
+
+	var nalus [][]byte
+	for _, pkt := range pkts {
+		nalus, err = d.Decode(pkt)
+		if err == ErrMorePacketsNeeded {
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.Equal(t, au, nalus)
+	require.Equal(t, uint64(2), d.DiscardedFragments())
+}
+
 func TestDecoderErrorLimit(t *testing.T) {
 	d := &Decoder{}
 	err := d.Init()
@@ -59,6 +199,11 @@ func TestDecoderErrorLimit(t *testing.T) {
 	}
 
 	require.EqualError(t, err, "NALU count exceeds maximum allowed (21)")
+	require.Equal(t, DecoderStats{
+		PacketsRejected:     1,
+		IncompleteDiscarded: 1,
+		BytesDropped:        88,
+	}, d.Stats())
 }
 
 func FuzzDecoder(f *testing.F) {
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpfragment"
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -21,14 +24,6 @@ func randUint32() (uint32, error) {
 	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
 }
 
-func packetCount(avail, le int) int {
-	n := le / avail
-	if (le % avail) != 0 {
-		n++
-	}
-	return n
-}
-
 // Encoder is a RTP/H265 encoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc7798
 type Encoder struct {
@@ -50,13 +45,28 @@ type Encoder struct {
 	// indicates that NALUs have an additional field that specifies the decoding order.
 	MaxDONDiff int
 
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
+	don            uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
-	if e.MaxDONDiff != 0 {
-		return fmt.Errorf("MaxDONDiff != 0 is not supported (yet)")
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
+	if e.MaxDONDiff < 0 {
+		return fmt.Errorf("invalid sprop-max-don-diff (%d)", e.MaxDONDiff)
 	}
 
 	if e.SSRC == nil {
@@ -84,6 +94,17 @@ func (e *Encoder) Init() error {
 
 // Encode encodes an access unit into RTP/H265 packets.
 func (e *Encoder) Encode(au [][]byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(au)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(au [][]byte) ([]*rtp.Packet, error) {
 	var rets []*rtp.Packet
 	var batch [][]byte
 
@@ -121,7 +142,7 @@ func (e *Encoder) Encode(au [][]byte) ([]*rtp.Packet, error) {
 func (e *Encoder) writeBatch(nalus [][]byte, marker bool) ([]*rtp.Packet, error) {
 	if len(nalus) == 1 {
 		// the NALU fits into a single RTP packet
-		if len(nalus[0]) < e.PayloadMaxSize {
+		if (len(nalus[0]) + e.lenDONL()) < e.PayloadMaxSize {
 			return e.writeSingle(nalus[0], marker)
 		}
 
@@ -132,7 +153,25 @@ func (e *Encoder) writeBatch(nalus [][]byte, marker bool) ([]*rtp.Packet, error)
 	return e.writeAggregationUnit(nalus, marker)
 }
 
+// lenDONL returns the size of the DONL field, that is present only when MaxDONDiff > 0.
+func (e *Encoder) lenDONL() int {
+	if e.MaxDONDiff != 0 {
+		return 2
+	}
+	return 0
+}
+
 func (e *Encoder) writeSingle(nalu []byte, marker bool) ([]*rtp.Packet, error) {
+	if e.MaxDONDiff != 0 {
+		payload := make([]byte, len(nalu)+2)
+		copy(payload, nalu[:2])
+		payload[2] = byte(e.don >> 8)
+		payload[3] = byte(e.don)
+		copy(payload[4:], nalu[2:])
+		nalu = payload
+		e.don++
+	}
+
 	pkt := &rtp.Packet{
 		Header: rtp.Header{
 			Version:        rtpVersion,
@@ -150,38 +189,45 @@ func (e *Encoder) writeSingle(nalu []byte, marker bool) ([]*rtp.Packet, error) {
 }
 
 func (e *Encoder) writeFragmentationUnits(nalu []byte, marker bool) ([]*rtp.Packet, error) {
-	avail := e.PayloadMaxSize - 3
-	le := len(nalu) - 2
-	packetCount := packetCount(avail, le)
-
-	ret := make([]*rtp.Packet, packetCount)
+	// the first fragment contains the DONL field
+	f := rtpfragment.Fragmenter{
+		PayloadMaxSize: e.PayloadMaxSize,
+		FirstPrefixLen: 3 + e.lenDONL(),
+		PrefixLen:      3,
+	}
 
 	head := nalu[:2]
-	nalu = nalu[2:]
-	le = avail
+
+	fragments, err := f.Fragment(nil, nalu[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	ret := rtpfragment.NewPackets(len(fragments))
 	start := uint8(1)
 	end := uint8(0)
 
-	for i := range ret {
-		if i == (packetCount - 1) {
-			le = len(nalu)
+	for i, data := range fragments {
+		if i == (len(fragments) - 1) {
 			end = 1
 		}
 
-		data := make([]byte, 3+le)
 		data[0] = head[0]&0b10000001 | 49<<1
 		data[1] = head[1]
 		data[2] = (start << 7) | (end << 6) | (head[0]>>1)&0b111111
-		copy(data[3:], nalu)
-		nalu = nalu[le:]
+		if i == 0 && e.MaxDONDiff != 0 {
+			data[3] = byte(e.don >> 8)
+			data[4] = byte(e.don)
+			e.don++
+		}
 
-		ret[i] = &rtp.Packet{
+		*ret[i] = rtp.Packet{
 			Header: rtp.Header{
 				Version:        rtpVersion,
 				PayloadType:    e.PayloadType,
 				SequenceNumber: e.sequenceNumber,
 				SSRC:           *e.SSRC,
-				Marker:         (i == (packetCount-1) && marker),
+				Marker:         (i == (len(fragments)-1) && marker),
 			},
 			Payload: data,
 		}
@@ -196,12 +242,22 @@ func (e *Encoder) writeFragmentationUnits(nalu []byte, marker bool) ([]*rtp.Pack
 func (e *Encoder) lenAggregationUnit(nalus [][]byte, addNALU []byte) int {
 	ret := 2 // header
 
-	for _, nalu := range nalus {
+	if e.MaxDONDiff != 0 {
+		ret += 2 // DONL
+	}
+
+	for i, nalu := range nalus {
+		if e.MaxDONDiff != 0 && i != 0 {
+			ret++ // DOND
+		}
 		ret += 2         // size
 		ret += len(nalu) // nalu
 	}
 
 	if addNALU != nil {
+		if e.MaxDONDiff != 0 && len(nalus) != 0 {
+			ret++ // DOND
+		}
 		ret += 2            // size
 		ret += len(addNALU) // nalu
 	}
@@ -218,7 +274,23 @@ func (e *Encoder) writeAggregationUnit(nalus [][]byte, marker bool) ([]*rtp.Pack
 	payload[1] = byte(h)
 	pos := 2
 
-	for _, nalu := range nalus {
+	if e.MaxDONDiff != 0 {
+		// DONL
+		payload[pos] = byte(e.don >> 8)
+		payload[pos+1] = byte(e.don)
+		pos += 2
+	}
+
+	for i, nalu := range nalus {
+		if e.MaxDONDiff != 0 {
+			if i != 0 {
+				// DOND (NALUs are sent in decoding order)
+				payload[pos] = 0
+				pos++
+			}
+			e.don++
+		}
+
 		// size
 		naluLen := len(nalu)
 		payload[pos] = uint8(naluLen >> 8)
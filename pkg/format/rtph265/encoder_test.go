@@ -185,3 +185,34 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func BenchmarkEncode(b *testing.B) {
+	idr := make([]byte, 100000)
+	idr[0] = 0x26
+	idr[1] = 0x01
+
+	au := [][]byte{
+		{0x40, 0x01, 0x0c, 0x01},
+		{0x42, 0x01, 0x01, 0x01},
+		{0x44, 0x01, 0xc1, 0x72},
+		idr,
+	}
+
+	e := &Encoder{
+		PayloadType: 96,
+	}
+	err := e.Init()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err = e.Encode(au)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package rtplpcm
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pion/rtp"
 )
@@ -12,13 +13,18 @@ type Decoder struct {
 	BitDepth     int
 	ChannelCount int
 
+	// sample rate (optional).
+	// It is required by PacketDuration().
+	SampleRate int
+
 	sampleSize int
 }
 
 // Init initializes the decoder.
 func (d *Decoder) Init() error {
-	d.sampleSize = d.BitDepth * d.ChannelCount / 8
-	return nil
+	var err error
+	d.sampleSize, err = sampleSize(d.BitDepth, d.ChannelCount)
+	return err
 }
 
 // Decode decodes audio samples from a RTP packet.
@@ -31,3 +37,14 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 
 	return pkt.Payload, nil
 }
+
+// PacketDuration returns the duration of the samples contained in a RTP packet.
+// It can be used to size jitter buffers.
+func (d *Decoder) PacketDuration(pkt *rtp.Packet) time.Duration {
+	if d.SampleRate <= 0 {
+		return 0
+	}
+
+	sampleCount := len(pkt.Payload) / d.sampleSize
+	return time.Duration(sampleCount) * time.Second / time.Duration(d.SampleRate)
+}
@@ -2,6 +2,7 @@ package rtplpcm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -30,6 +31,47 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeUnalignedPayload(t *testing.T) {
+	d := &Decoder{
+		BitDepth:     24,
+		ChannelCount: 2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04, 0x05},
+	})
+	require.EqualError(t, err, "received payload of wrong size")
+}
+
+func TestDecodePacketDuration(t *testing.T) {
+	d := &Decoder{
+		BitDepth:     16,
+		ChannelCount: 1,
+		SampleRate:   8000,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	dur := d.PacketDuration(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: make([]byte, 640),
+	})
+	require.Equal(t, 40*time.Millisecond, dur)
+}
+
 func FuzzDecoder(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, b []byte) {
 		d := &Decoder{
@@ -3,8 +3,11 @@ package rtplpcm
 import (
 	"crypto/rand"
 	"fmt"
+	"time"
 
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -45,6 +48,24 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// sample rate (optional).
+	// It is required when PacketDuration is set.
+	SampleRate int
+
+	// duration of each packet (optional).
+	// When set, samples are split into packets of this duration,
+	// as long as they fit into PayloadMaxSize.
+	PacketDuration time.Duration
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 	sampleSize     int
 	maxPayloadSize int
@@ -52,6 +73,11 @@ type Encoder struct {
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -71,9 +97,33 @@ func (e *Encoder) Init() error {
 		e.PayloadMaxSize = defaultPayloadMaxSize
 	}
 
-	e.sequenceNumber = *e.InitialSequenceNumber
-	e.sampleSize = e.BitDepth * e.ChannelCount / 8
+	e.sampleSize, err = sampleSize(e.BitDepth, e.ChannelCount)
+	if err != nil {
+		return err
+	}
+
+	// packets always contain whole samples of all channels
 	e.maxPayloadSize = (e.PayloadMaxSize / e.sampleSize) * e.sampleSize
+	if e.maxPayloadSize == 0 {
+		return fmt.Errorf("payload max size is too small")
+	}
+
+	if e.PacketDuration != 0 {
+		if e.SampleRate <= 0 {
+			return fmt.Errorf("sample rate is required when packet duration is set")
+		}
+
+		sampleCount := int(int64(e.PacketDuration) * int64(e.SampleRate) / int64(time.Second))
+		if sampleCount == 0 {
+			return fmt.Errorf("packet duration is too small")
+		}
+
+		if v := sampleCount * e.sampleSize; v < e.maxPayloadSize {
+			e.maxPayloadSize = v
+		}
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
 	return nil
 }
 
@@ -87,6 +137,17 @@ func (e *Encoder) packetCount(slen int) int {
 
 // Encode encodes audio samples into RTP packets.
 func (e *Encoder) Encode(samples []byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(samples)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(samples []byte) ([]*rtp.Packet, error) {
 	slen := len(samples)
 	if (slen % e.sampleSize) != 0 {
 		return nil, fmt.Errorf("invalid samples")
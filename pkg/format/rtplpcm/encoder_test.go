@@ -3,6 +3,7 @@ package rtplpcm
 import (
 	"bytes"
 	"testing"
+	"time"
 
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -97,3 +98,74 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func TestEncodePacketDuration(t *testing.T) {
+	// 24-bit stereo at 48 kHz, 1ms per packet
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		BitDepth:              24,
+		ChannelCount:          2,
+		SampleRate:            48000,
+		PacketDuration:        time.Millisecond,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	samples := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 48*3+24)
+
+	pkts, err := e.Encode(samples)
+	require.NoError(t, err)
+	require.Len(t, pkts, 4)
+
+	for i, pkt := range pkts {
+		require.Equal(t, uint16(0x44ed+i), pkt.SequenceNumber)
+		require.Equal(t, uint32(48*i), pkt.Timestamp)
+
+		if i < 3 {
+			require.Equal(t, bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 48), pkt.Payload)
+		} else {
+			require.Equal(t, bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}, 24), pkt.Payload)
+		}
+	}
+}
+
+func TestEncoderInitErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		e    Encoder
+		err  string
+	}{
+		{
+			"unsupported bit depth",
+			Encoder{BitDepth: 12, ChannelCount: 2},
+			"unsupported bit depth: 12",
+		},
+		{
+			"invalid channel count",
+			Encoder{BitDepth: 24},
+			"invalid channel count: 0",
+		},
+		{
+			"payload max size too small",
+			Encoder{BitDepth: 24, ChannelCount: 2, PayloadMaxSize: 5},
+			"payload max size is too small",
+		},
+		{
+			"packet duration without sample rate",
+			Encoder{BitDepth: 24, ChannelCount: 2, PacketDuration: time.Millisecond},
+			"sample rate is required when packet duration is set",
+		},
+		{
+			"packet duration too small",
+			Encoder{BitDepth: 24, ChannelCount: 2, SampleRate: 48000, PacketDuration: time.Microsecond},
+			"packet duration is too small",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			err := ca.e.Init()
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
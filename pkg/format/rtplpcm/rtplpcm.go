@@ -1,2 +1,20 @@
 // Package rtplpcm contains a RTP/LPCM decoder and encoder.
 package rtplpcm
+
+import (
+	"fmt"
+)
+
+func sampleSize(bitDepth int, channelCount int) (int, error) {
+	switch bitDepth {
+	case 8, 16, 24:
+	default:
+		return 0, fmt.Errorf("unsupported bit depth: %d", bitDepth)
+	}
+
+	if channelCount <= 0 {
+		return 0, fmt.Errorf("invalid channel count: %d", channelCount)
+	}
+
+	return bitDepth * channelCount / 8, nil
+}
@@ -162,11 +162,43 @@ func joinFragments(fragments [][]byte, size int) []byte {
 // Decoder is a RTP/M-JPEG decoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc2435
 type Decoder struct {
-	firstPacketReceived bool
-	fragmentsSize       int
-	fragments           [][]byte
-	firstJpegHeader     *headerJPEG
-	quantizationTables  [][]byte
+	// quantization tables transmitted out-of-band (optional).
+	// They are used when packets signal the presence of quantization tables
+	// but do not contain them.
+	// Tables with 16-bit precision are 128 bytes long.
+	QuantizationTables [][]byte
+
+	// image size transmitted out-of-band (optional).
+	// It is used when the image size can't be represented in packets,
+	// since it is greater than 2040 pixels.
+	Width  int
+	Height int
+
+	firstPacketReceived    bool
+	frameStarted           bool
+	fragmentsSize          int
+	fragments              [][]byte
+	fragmentsTimestamp     uint32
+	fragmentsNextOffset    uint32
+	completeFragmentsCount int
+	completeFragmentsSize  int
+	firstJpegHeader        *headerJPEG
+	restartInterval        uint16
+	quantizationTables     [][]byte
+	staticTables           map[uint8][][]byte
+	stats                  DecoderStats
+}
+
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete images or restart intervals that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
 }
 
 // Init initializes the decoder.
@@ -174,8 +206,91 @@ func (d *Decoder) Init() error {
 	return nil
 }
 
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) resolveQuantizationTables(q uint8, tables [][]byte) ([][]byte, error) {
+	if tables != nil {
+		// Q values between 128 and 254 are bound to static tables,
+		// that may be omitted from subsequent frames.
+		if q != 255 {
+			if d.staticTables == nil {
+				d.staticTables = make(map[uint8][][]byte)
+			}
+			d.staticTables[q] = tables
+		}
+		return tables, nil
+	}
+
+	if q == 255 {
+		return nil, fmt.Errorf("quantization tables are missing")
+	}
+
+	if tables, ok := d.staticTables[q]; ok {
+		return tables, nil
+	}
+
+	if d.QuantizationTables != nil {
+		return d.QuantizationTables, nil
+	}
+
+	return nil, fmt.Errorf("quantization tables are not available")
+}
+
+func (d *Decoder) resetFragments() {
+	d.frameStarted = false
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+	d.completeFragmentsCount = 0
+	d.completeFragmentsSize = 0
+}
+
+func (d *Decoder) discardFragments() {
+	if d.frameStarted {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+	}
+	d.resetFragments()
+}
+
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+
+		// this is normal when decoding a stream that is already running
+		if !errors.Is(err, ErrNonStartingPacketAndNoPrevious) {
+			d.stats.PacketsRejected++
+		}
+	}
+}
+
+func (d *Decoder) addFragment(byts []byte, jh *headerJPEG, hrm *headerRestartMarker) {
+	d.fragments = append(d.fragments, byts)
+	d.fragmentsSize += len(byts)
+	d.fragmentsNextOffset = jh.FragmentOffset + uint32(len(byts))
+
+	// keep track of the last complete restart interval,
+	// in order to be able to recover from packet losses.
+	if hrm != nil && hrm.Last {
+		d.completeFragmentsCount = len(d.fragments)
+		d.completeFragmentsSize = d.fragmentsSize
+	}
+}
+
 // Decode decodes an image from a RTP packet.
+// When images contain restart markers and the encoder splits them
+// at restart interval boundaries, lost packets cause the related restart
+// intervals to be omitted from the image, instead of causing the whole image
+// to be discarded.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	image, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return image, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([]byte, error) {
 	byts := pkt.Payload
 
 	var jh headerJPEG
@@ -185,17 +300,35 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 	}
 	byts = byts[n:]
 
-	if jh.Width > maxDimension {
-		return nil, fmt.Errorf("width of %d is not supported", jh.Width)
+	var hrm *headerRestartMarker
+
+	if jh.Type >= 64 {
+		hrm = &headerRestartMarker{}
+		n, err = hrm.unmarshal(byts)
+		if err != nil {
+			return nil, err
+		}
+		byts = byts[n:]
+
+		jh.Type -= 64
 	}
 
-	if jh.Height > maxDimension {
-		return nil, fmt.Errorf("height of %d is not supported", jh.Height)
+	if jh.Width == 0 {
+		if d.Width == 0 {
+			return nil, fmt.Errorf("width is not available")
+		}
+		jh.Width = d.Width
+	}
+
+	if jh.Height == 0 {
+		if d.Height == 0 {
+			return nil, fmt.Errorf("height is not available")
+		}
+		jh.Height = d.Height
 	}
 
 	if jh.FragmentOffset == 0 {
-		d.fragments = d.fragments[:0] // discard pending fragments
-		d.fragmentsSize = 0
+		d.discardFragments()
 		d.firstPacketReceived = true
 
 		if jh.Quantization >= 128 {
@@ -204,28 +337,59 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 			if err != nil {
 				return nil, err
 			}
-			d.quantizationTables = hqt.Tables
 			byts = byts[n:]
+
+			tables, err := d.resolveQuantizationTables(jh.Quantization, hqt.Tables)
+			if err != nil {
+				return nil, err
+			}
+			d.quantizationTables = tables
 		} else {
 			d.quantizationTables = makeQuantizationTables(jh.Quantization)
 		}
 
-		d.fragments = append(d.fragments, byts)
-		d.fragmentsSize = len(byts)
+		if hrm != nil {
+			d.restartInterval = hrm.Interval
+		} else {
+			d.restartInterval = 0
+		}
+
+		d.frameStarted = true
+		d.fragmentsTimestamp = pkt.Timestamp
 		d.firstJpegHeader = &jh
+		d.addFragment(byts, &jh, hrm)
 	} else {
-		if int(jh.FragmentOffset) != d.fragmentsSize {
+		if !d.frameStarted || jh.FragmentOffset != d.fragmentsNextOffset {
 			if !d.firstPacketReceived {
 				return nil, ErrNonStartingPacketAndNoPrevious
 			}
 
-			d.fragments = d.fragments[:0] // discard pending fragments
-			d.fragmentsSize = 0
-			return nil, fmt.Errorf("received wrong fragment")
+			// packets of the current image have been lost, but the image
+			// can be recovered by skipping the incomplete restart interval.
+			if hrm != nil && hrm.Count != restartCountWholeFrame &&
+				d.frameStarted &&
+				pkt.Timestamp == d.fragmentsTimestamp &&
+				jh.FragmentOffset > d.fragmentsNextOffset {
+				if d.fragmentsSize != d.completeFragmentsSize {
+					d.stats.IncompleteDiscarded++
+					d.stats.BytesDropped += uint64(d.fragmentsSize - d.completeFragmentsSize)
+				}
+				d.fragments = d.fragments[:d.completeFragmentsCount]
+				d.fragmentsSize = d.completeFragmentsSize
+
+				// continue from a packet that starts a restart interval.
+				if hrm.First {
+					d.addFragment(byts, &jh, hrm)
+				} else {
+					d.stats.BytesDropped += uint64(len(pkt.Payload))
+				}
+			} else {
+				d.discardFragments()
+				return nil, fmt.Errorf("received wrong fragment")
+			}
+		} else {
+			d.addFragment(byts, &jh, hrm)
 		}
-
-		d.fragmentsSize += len(byts)
-		d.fragments = append(d.fragments, byts)
 	}
 
 	if !pkt.Marker {
@@ -233,27 +397,27 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 	}
 
 	if d.fragmentsSize < 2 {
+		d.resetFragments()
 		return nil, fmt.Errorf("invalid data")
 	}
 
 	data := joinFragments(d.fragments, d.fragmentsSize)
-	d.fragments = d.fragments[:0]
-	d.fragmentsSize = 0
+	d.resetFragments()
 
 	var buf []byte
 
 	buf = jpeg.StartOfImage{}.Marshal(buf)
 
-	var dqt jpeg.DefineQuantizationTable
+	var dqt defineQuantizationTable
 	id := uint8(0)
 	for _, table := range d.quantizationTables {
-		dqt.Tables = append(dqt.Tables, jpeg.QuantizationTable{
+		dqt.Tables = append(dqt.Tables, quantizationTable{
 			ID:   id,
 			Data: table,
 		})
 		id++
 	}
-	buf = dqt.Marshal(buf)
+	buf = dqt.marshal(buf)
 
 	buf = jpeg.StartOfFrame1{
 		Type:                   d.firstJpegHeader.Type,
@@ -290,6 +454,13 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 		TableClass:  1,
 	}.Marshal(buf)
 
+	if d.restartInterval != 0 {
+		buf = append(buf, []byte{
+			0xFF, jpeg.MarkerDefineRestartInterval, 0x00, 0x04,
+			byte(d.restartInterval >> 8), byte(d.restartInterval),
+		}...)
+	}
+
 	buf = jpeg.StartOfScan{}.Marshal(buf)
 
 	buf = append(buf, data...)
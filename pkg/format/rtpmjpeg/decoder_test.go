@@ -1,6 +1,7 @@
 package rtpmjpeg
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 
@@ -130,6 +131,111 @@ func TestDecodeFixedQuantizationTable(t *testing.T) {
 	}, image)
 }
 
+func TestDecodeOutOfBandParams(t *testing.T) {
+	tables := [][]byte{
+		bytes.Repeat([]byte{0x01}, 64),
+		bytes.Repeat([]byte{0x02}, 64),
+	}
+
+	inBand := &Decoder{}
+	err := inBand.Init()
+	require.NoError(t, err)
+
+	expected, err := inBand.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    26,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: append(append([]byte{
+			// JPEG header
+			0x00, 0x00, 0x00, 0x00, 0x01, 0xff, 0xf0, 0x87,
+			// quantization table header
+			0x00, 0x00, 0x00, 0x80,
+		}, bytes.Join(tables, nil)...),
+			// JPEG data
+			1, 2),
+	})
+	require.NoError(t, err)
+
+	outOfBand := &Decoder{
+		QuantizationTables: tables,
+		Width:              1920,
+		Height:             1080,
+	}
+	err = outOfBand.Init()
+	require.NoError(t, err)
+
+	image, err := outOfBand.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    26,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{
+			// JPEG header
+			0x00, 0x00, 0x00, 0x00, 0x01, 0x80, 0x00, 0x00,
+			// quantization table header
+			0x00, 0x00, 0x00, 0x00,
+			// JPEG data
+			1, 2,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, image)
+}
+
+func TestDecodeOutOfBandParamsErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name    string
+		payload []byte
+		err     string
+	}{
+		{
+			"missing width",
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x3f, 0x00, 0x87, 1, 2},
+			"width is not available",
+		},
+		{
+			"missing height",
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x3f, 0xf0, 0x00, 1, 2},
+			"height is not available",
+		},
+		{
+			"missing static tables",
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x80, 0xf0, 0x87, 0x00, 0x00, 0x00, 0x00, 1, 2},
+			"quantization tables are not available",
+		},
+		{
+			"missing dynamic tables",
+			[]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0xff, 0xf0, 0x87, 0x00, 0x00, 0x00, 0x00, 1, 2},
+			"quantization tables are missing",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err := d.Init()
+			require.NoError(t, err)
+
+			_, err = d.Decode(&rtp.Packet{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    26,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: ca.payload,
+			})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
 func FuzzDecoder(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{}
@@ -160,3 +266,134 @@ func FuzzDecoder(f *testing.F) {
 		})
 	})
 }
+
+// imageWithRestartMarkers returns an image that contains restart markers,
+// and its scan data.
+func imageWithRestartMarkers(t *testing.T) ([]byte, []byte) {
+	scan := bytes.Join([][]byte{
+		bytes.Repeat([]byte{0x01}, 10), {0xff, 0xd0},
+		bytes.Repeat([]byte{0x02}, 10), {0xff, 0xd1},
+		bytes.Repeat([]byte{0x03}, 300), {0xff, 0xd2},
+		bytes.Repeat([]byte{0x04}, 10), {0xff, 0xd9},
+	}, nil)
+
+	var payload []byte
+	payload = headerJPEG{
+		Type:         1 + 64,
+		Quantization: 255,
+		Width:        64,
+		Height:       32,
+	}.marshal(payload)
+	payload = headerRestartMarker{
+		Interval: 1,
+		First:    true,
+		Last:     true,
+		Count:    0x3fff,
+	}.marshal(payload)
+	payload = headerQuantizationTable{
+		Tables: [][]byte{
+			bytes.Repeat([]byte{0x05}, 64),
+			bytes.Repeat([]byte{0x06}, 64),
+		},
+	}.marshal(payload)
+	payload = append(payload, scan...)
+
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	image, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    26,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: payload,
+	})
+	require.NoError(t, err)
+
+	return image, scan
+}
+
+func TestDecodeRestartMarkers(t *testing.T) {
+	image, scan := imageWithRestartMarkers(t)
+
+	// DRI is inserted before SOS
+	require.True(t, bytes.Contains(image, []byte{
+		0xff, 0xdd, 0x00, 0x04, 0x00, 0x01, 0xff, 0xda,
+	}))
+	require.True(t, bytes.HasSuffix(image, scan))
+}
+
+func TestDecodeRestartMarkersPacketLoss(t *testing.T) {
+	image, scan := imageWithRestartMarkers(t)
+
+	e := &Encoder{
+		SSRC:                       uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber:      uint16Ptr(0x44ed),
+		PayloadMaxSize:             160,
+		FragmentAtRestartIntervals: true,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(image)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(pkts))
+
+	for _, ca := range []struct {
+		name       string
+		lost       int
+		partial    []byte
+		incomplete uint64
+	}{
+		{
+			"fragmented interval",
+			3,
+			bytes.Join([][]byte{
+				bytes.Repeat([]byte{0x01}, 10), {0xff, 0xd0},
+				bytes.Repeat([]byte{0x02}, 10), {0xff, 0xd1},
+				bytes.Repeat([]byte{0x04}, 10), {0xff, 0xd9},
+			}, nil),
+			1,
+		},
+		{
+			"entire interval",
+			1,
+			bytes.Join([][]byte{
+				bytes.Repeat([]byte{0x01}, 10), {0xff, 0xd0},
+				bytes.Repeat([]byte{0x03}, 300), {0xff, 0xd2},
+				bytes.Repeat([]byte{0x04}, 10), {0xff, 0xd9},
+			}, nil),
+			0,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{}
+			err = d.Init()
+			require.NoError(t, err)
+
+			var dec []byte
+
+			for i, pkt := range pkts {
+				if i == ca.lost {
+					continue
+				}
+
+				dec, err = d.Decode(pkt)
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, append(image[:len(image)-len(scan)], ca.partial...), dec)
+
+			stats := d.Stats()
+			require.Equal(t, uint64(0), stats.PacketsRejected)
+			require.Equal(t, ca.incomplete, stats.IncompleteDiscarded)
+		})
+	}
+}
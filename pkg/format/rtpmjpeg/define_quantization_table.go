@@ -0,0 +1,72 @@
+package rtpmjpeg
+
+import (
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/jpeg"
+)
+
+// quantizationTable is a JPEG quantization table.
+// It is 64 bytes long with 8-bit precision, 128 bytes long with 16-bit precision.
+type quantizationTable struct {
+	ID   uint8
+	Data []byte
+}
+
+// defineQuantizationTable is a DQT marker.
+// Unlike the one provided by mediacommon, it supports 16-bit precision.
+type defineQuantizationTable struct {
+	Tables []quantizationTable
+}
+
+func (m *defineQuantizationTable) unmarshal(buf []byte) error {
+	for len(buf) != 0 {
+		id := buf[0] & 0x0F
+		precision := buf[0] >> 4
+		buf = buf[1:]
+
+		var size int
+		switch precision {
+		case 0:
+			size = 64
+		case 1:
+			size = 128
+		default:
+			return fmt.Errorf("precision %d is not supported", precision)
+		}
+
+		if len(buf) < size {
+			return fmt.Errorf("image is too short")
+		}
+
+		m.Tables = append(m.Tables, quantizationTable{
+			ID:   id,
+			Data: buf[:size],
+		})
+		buf = buf[size:]
+	}
+
+	return nil
+}
+
+func (m defineQuantizationTable) marshal(buf []byte) []byte {
+	buf = append(buf, []byte{0xFF, jpeg.MarkerDefineQuantizationTable}...)
+
+	// length
+	s := 2
+	for _, t := range m.Tables {
+		s += 1 + len(t.Data)
+	}
+	buf = append(buf, []byte{byte(s >> 8), byte(s)}...)
+
+	for _, t := range m.Tables {
+		b := t.ID
+		if len(t.Data) == 128 {
+			b |= 1 << 4
+		}
+		buf = append(buf, b)
+		buf = append(buf, t.Data...)
+	}
+
+	return buf
+}
@@ -0,0 +1,63 @@
+package rtpmjpeg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesDefineQuantizationTable = []struct {
+	name string
+	enc  []byte
+	dec  defineQuantizationTable
+}{
+	{
+		"8-bit precision",
+		append([]byte{0x00}, bytes.Repeat([]byte{0x01}, 64)...),
+		defineQuantizationTable{
+			Tables: []quantizationTable{{
+				ID:   0,
+				Data: bytes.Repeat([]byte{0x01}, 64),
+			}},
+		},
+	},
+	{
+		"mixed precision",
+		append(append([]byte{0x00}, bytes.Repeat([]byte{0x01}, 64)...),
+			append([]byte{0x11}, bytes.Repeat([]byte{0x00, 0x02}, 64)...)...),
+		defineQuantizationTable{
+			Tables: []quantizationTable{
+				{
+					ID:   0,
+					Data: bytes.Repeat([]byte{0x01}, 64),
+				},
+				{
+					ID:   1,
+					Data: bytes.Repeat([]byte{0x00, 0x02}, 64),
+				},
+			},
+		},
+	},
+}
+
+func TestDefineQuantizationTableUnmarshal(t *testing.T) {
+	for _, ca := range casesDefineQuantizationTable {
+		t.Run(ca.name, func(t *testing.T) {
+			var h defineQuantizationTable
+			err := h.unmarshal(ca.enc)
+			require.NoError(t, err)
+			require.Equal(t, ca.dec, h)
+		})
+	}
+}
+
+func TestDefineQuantizationTableMarshal(t *testing.T) {
+	for _, ca := range casesDefineQuantizationTable {
+		t.Run(ca.name, func(t *testing.T) {
+			buf := ca.dec.marshal(nil)
+			require.Equal(t, append([]byte{0xff, 0xdb, byte((2 + len(ca.enc)) >> 8), byte(2 + len(ca.enc))},
+				ca.enc...), buf)
+		})
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/pion/rtp"
 
 	"github.com/bluenviron/mediacommon/pkg/codecs/jpeg"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -39,11 +41,30 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// whether to split images at restart interval boundaries (optional).
+	// It is used only when images contain a DRI segment, and allows
+	// receivers to decode partial images when packets are lost.
+	FragmentAtRestartIntervals bool
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -67,8 +88,40 @@ func (e *Encoder) Init() error {
 	return nil
 }
 
+// restartIntervalSizes returns the size of each restart interval of scan data.
+// Each restart interval ends after a RST marker.
+func restartIntervalSizes(data []byte) []int {
+	var ret []int
+	start := 0
+
+	for i := 0; i < (len(data) - 1); i++ {
+		if data[i] == 0xFF && data[i+1] >= 0xD0 && data[i+1] <= 0xD7 {
+			ret = append(ret, i+2-start)
+			start = i + 2
+			i++
+		}
+	}
+
+	if start != len(data) {
+		ret = append(ret, len(data)-start)
+	}
+
+	return ret
+}
+
 // Encode encodes an image into RTP/M-JPEG packets.
 func (e *Encoder) Encode(image []byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(image)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(image []byte) ([]*rtp.Packet, error) {
 	l := len(image)
 	if l < 2 || image[0] != 0xFF || image[1] != jpeg.MarkerStartOfImage {
 		return nil, fmt.Errorf("SOI not found")
@@ -109,8 +162,8 @@ outer:
 				return nil, fmt.Errorf("image is too short")
 			}
 
-			var dqt jpeg.DefineQuantizationTable
-			err := dqt.Unmarshal(image[2:mlen])
+			var dqt defineQuantizationTable
+			err := dqt.unmarshal(image[2:mlen])
 			if err != nil {
 				return nil, err
 			}
@@ -207,48 +260,102 @@ outer:
 		jh.Type += 64
 	}
 
+	qth := headerQuantizationTable{}
+
+	// gather and sort tables IDs
+	ids := make([]uint8, len(quantizationTables))
+	i := 0
+	for id := range quantizationTables {
+		ids[i] = id
+		i++
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	// add tables sorted by ID
+	for i, id := range ids {
+		if len(quantizationTables[id]) == 128 {
+			qth.Precision |= 1 << i
+		}
+		qth.Tables = append(qth.Tables, quantizationTables[id])
+	}
+
+	qthBuf := qth.marshal(nil)
+
+	var intervals []int
+	if dri != nil && e.FragmentAtRestartIntervals {
+		intervals = restartIntervalSizes(data)
+	}
+	curInterval := 0
+	curIntervalOffset := 0
+
 	first := true
 	offset := 0
 	var ret []*rtp.Packet
 
 	for {
+		headerSize := 8
+		if dri != nil {
+			headerSize += 4
+		}
+		if first {
+			headerSize += len(qthBuf)
+		}
+
+		remaining := e.PayloadMaxSize - headerSize
+
+		hrm := headerRestartMarker{
+			First: true,
+			Last:  true,
+			Count: restartCountWholeFrame,
+		}
+
+		if intervals != nil {
+			hrm.Count = uint16(curInterval) & 0x3FFF
+
+			if curIntervalOffset != 0 || intervals[curInterval] > remaining {
+				// restart interval is too big and must be fragmented
+				hrm.First = (curIntervalOffset == 0)
+
+				n := intervals[curInterval] - curIntervalOffset
+				if n < remaining {
+					remaining = n
+				}
+
+				curIntervalOffset += remaining
+				hrm.Last = (curIntervalOffset == intervals[curInterval])
+
+				if hrm.Last {
+					curInterval++
+					curIntervalOffset = 0
+				}
+			} else {
+				// fill the packet with entire restart intervals
+				n := 0
+				for curInterval < len(intervals) && (n+intervals[curInterval]) <= remaining {
+					n += intervals[curInterval]
+					curInterval++
+				}
+				remaining = n
+			}
+		}
+
 		var buf []byte
 
 		jh.FragmentOffset = uint32(offset)
 		buf = jh.marshal(buf)
 
 		if dri != nil {
-			buf = headerRestartMarker{
-				Interval: dri.Interval,
-				Count:    0xFFFF,
-			}.marshal(buf)
+			hrm.Interval = dri.Interval
+			buf = hrm.marshal(buf)
 		}
 
 		if first {
 			first = false
-
-			qth := headerQuantizationTable{}
-
-			// gather and sort tables IDs
-			ids := make([]uint8, len(quantizationTables))
-			i := 0
-			for id := range quantizationTables {
-				ids[i] = id
-				i++
-			}
-			sort.Slice(ids, func(i, j int) bool {
-				return ids[i] < ids[j]
-			})
-
-			// add tables sorted by ID
-			for _, id := range ids {
-				qth.Tables = append(qth.Tables, quantizationTables[id])
-			}
-
-			buf = qth.marshal(buf)
+			buf = append(buf, qthBuf...)
 		}
 
-		remaining := e.PayloadMaxSize - len(buf)
 		ldata := len(data)
 		if remaining > ldata {
 			remaining = ldata
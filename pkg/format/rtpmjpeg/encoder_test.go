@@ -1,6 +1,7 @@
 package rtpmjpeg
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/pion/rtp"
@@ -527,3 +528,105 @@ func TestEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeRestartIntervals(t *testing.T) {
+	image, _ := imageWithRestartMarkers(t)
+
+	e := &Encoder{
+		SSRC:                       uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber:      uint16Ptr(0x44ed),
+		PayloadMaxSize:             160,
+		FragmentAtRestartIntervals: true,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(image)
+	require.NoError(t, err)
+
+	var hrms []headerRestartMarker
+	var sizes []int
+
+	for _, pkt := range pkts {
+		var jh headerJPEG
+		n, err := jh.unmarshal(pkt.Payload)
+		require.NoError(t, err)
+		require.Equal(t, uint8(1+64), jh.Type)
+
+		var hrm headerRestartMarker
+		n2, err := hrm.unmarshal(pkt.Payload[n:])
+		require.NoError(t, err)
+
+		hrms = append(hrms, hrm)
+		sizes = append(sizes, len(pkt.Payload)-n-n2)
+	}
+
+	require.Equal(t, []headerRestartMarker{
+		{Interval: 1, First: true, Last: true, Count: 0},
+		{Interval: 1, First: true, Last: true, Count: 1},
+		{Interval: 1, First: true, Last: false, Count: 2},
+		{Interval: 1, First: false, Last: false, Count: 2},
+		{Interval: 1, First: false, Last: true, Count: 2},
+		{Interval: 1, First: true, Last: true, Count: 3},
+	}, hrms)
+
+	// first packet contains quantization tables and a restart interval
+	require.Equal(t, []int{4 + 128 + 12, 12, 148, 148, 6, 12}, sizes)
+	require.True(t, pkts[len(pkts)-1].Marker)
+}
+
+func TestEncodeDecode16BitQuantizationTables(t *testing.T) {
+	tables := [][]byte{
+		bytes.Repeat([]byte{0x00, 0x05}, 64),
+		bytes.Repeat([]byte{0x06}, 64),
+	}
+
+	var payload []byte
+	payload = headerJPEG{
+		Type:         1,
+		Quantization: 255,
+		Width:        64,
+		Height:       32,
+	}.marshal(payload)
+	payload = headerQuantizationTable{
+		Precision: 1,
+		Tables:    tables,
+	}.marshal(payload)
+	payload = append(payload, []byte{0x01, 0x02, 0xff, 0xd9}...)
+
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	image, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    26,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: payload,
+	})
+	require.NoError(t, err)
+
+	// DQT contains a 16-bit table with ID 0 and a 8-bit table with ID 1
+	require.True(t, bytes.HasPrefix(image, bytes.Join([][]byte{
+		{0xff, 0xd8, 0xff, 0xdb, 0x00, 0xc4, 0x10},
+		tables[0],
+		{0x01},
+		tables[1],
+	}, nil)))
+
+	e := &Encoder{
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+	}
+	err = e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(image)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pkts))
+	require.Equal(t, payload, pkts[0].Payload)
+}
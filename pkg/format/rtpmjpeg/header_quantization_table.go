@@ -17,28 +17,40 @@ func (h *headerQuantizationTable) unmarshal(byts []byte) (int, error) {
 
 	h.MBZ = byts[0]
 	h.Precision = byts[1]
-	if h.Precision != 0 {
-		return 0, fmt.Errorf("precision %d is not supported", h.Precision)
-	}
 
+	// 0 means that tables are transmitted out-of-band
 	length := int(byts[2])<<8 | int(byts[3])
-	switch length {
-	case 64, 128:
-	default:
-		return 0, fmt.Errorf("table length %d is not supported", length)
-	}
 
 	if (len(byts) - 4) < length {
 		return 0, fmt.Errorf("buffer is too short")
 	}
 
-	tableCount := length / 64
-	h.Tables = make([][]byte, tableCount)
+	if length == 0 {
+		h.Tables = nil
+		return 4, nil
+	}
+
+	h.Tables = nil
 	n := 0
 
-	for i := 0; i < tableCount; i++ {
-		h.Tables[i] = byts[4+n : 4+64+n]
-		n += 64
+	// each bit of precision tells whether the related table
+	// has 16-bit precision (128 bytes) or 8-bit precision (64 bytes).
+	for n < length {
+		if len(h.Tables) >= 8 {
+			return 0, fmt.Errorf("table length %d is not supported", length)
+		}
+
+		size := 64
+		if (h.Precision & (1 << len(h.Tables))) != 0 {
+			size = 128
+		}
+
+		if (length - n) < size {
+			return 0, fmt.Errorf("table length %d is not supported", length)
+		}
+
+		h.Tables = append(h.Tables, byts[4+n:4+n+size])
+		n += size
 	}
 
 	return 4 + length, nil
@@ -48,7 +60,10 @@ func (h headerQuantizationTable) marshal(byts []byte) []byte {
 	byts = append(byts, h.MBZ)
 	byts = append(byts, h.Precision)
 
-	l := len(h.Tables) * 64
+	l := 0
+	for _, t := range h.Tables {
+		l += len(t)
+	}
 	byts = append(byts, []byte{byte(l >> 8), byte(l)}...)
 
 	for i := 0; i < len(h.Tables); i++ {
@@ -31,6 +31,25 @@ var casesQuantizationTable = []struct {
 			Tables:    [][]byte{bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 64/4)},
 		},
 	},
+	{
+		"16-bit precision",
+		append(append([]byte{0x0, 0x2, 0x0, 0xc0},
+			bytes.Repeat([]byte{0x01}, 64)...),
+			bytes.Repeat([]byte{0x00, 0x02}, 64)...),
+		headerQuantizationTable{
+			MBZ:       0,
+			Precision: 2,
+			Tables: [][]byte{
+				bytes.Repeat([]byte{0x01}, 64),
+				bytes.Repeat([]byte{0x00, 0x02}, 64),
+			},
+		},
+	},
+	{
+		"out-of-band",
+		[]byte{0x0, 0x0, 0x0, 0x0},
+		headerQuantizationTable{},
+	},
 }
 
 func TestHeaderQuantizationTableUnmarshal(t *testing.T) {
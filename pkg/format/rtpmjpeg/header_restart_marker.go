@@ -4,8 +4,15 @@ import (
 	"fmt"
 )
 
+const (
+	// restart count that signals that restart intervals can't be decoded independently.
+	restartCountWholeFrame = 0x3FFF
+)
+
 type headerRestartMarker struct {
 	Interval uint16
+	First    bool
+	Last     bool
 	Count    uint16
 }
 
@@ -15,12 +22,22 @@ func (h *headerRestartMarker) unmarshal(byts []byte) (int, error) {
 	}
 
 	h.Interval = uint16(byts[0])<<8 | uint16(byts[1])
-	h.Count = uint16(byts[2])<<8 | uint16(byts[3])
+	h.First = (byts[2] & 0x80) != 0
+	h.Last = (byts[2] & 0x40) != 0
+	h.Count = uint16(byts[2]&0x3F)<<8 | uint16(byts[3])
 	return 4, nil
 }
 
 func (h headerRestartMarker) marshal(byts []byte) []byte {
+	b := byte(h.Count>>8) & 0x3F
+	if h.First {
+		b |= 0x80
+	}
+	if h.Last {
+		b |= 0x40
+	}
+
 	byts = append(byts, []byte{byte(h.Interval >> 8), byte(h.Interval)}...)
-	byts = append(byts, []byte{byte(h.Count >> 8), byte(h.Count)}...)
+	byts = append(byts, []byte{b, byte(h.Count)}...)
 	return byts
 }
@@ -18,7 +18,21 @@ var casesRestartMarker = []struct {
 		},
 		headerRestartMarker{
 			Interval: 1234,
-			Count:    0xffff,
+			First:    true,
+			Last:     true,
+			Count:    0x3fff,
+		},
+	},
+	{
+		"fragment",
+		[]byte{
+			0x0, 0x10, 0x81, 0x02,
+		},
+		headerRestartMarker{
+			Interval: 16,
+			First:    true,
+			Last:     false,
+			Count:    258,
 		},
 	},
 }
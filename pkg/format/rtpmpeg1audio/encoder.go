@@ -2,14 +2,18 @@ package rtpmpeg1audio
 
 import (
 	"crypto/rand"
+	"time"
 
 	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg1audio"
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
 	rtpVersion            = 2
 	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+	clockRate             = 90000
 )
 
 func randUint32() (uint32, error) {
@@ -29,6 +33,14 @@ func lenAggregated(frames [][]byte, frame []byte) int {
 	return n
 }
 
+// timestamp converts a sample count into RTP time units.
+func timestamp(samples int, sampleRate int) uint32 {
+	if samples == 0 {
+		return 0
+	}
+	return uint32(int64(samples) * clockRate / int64(sampleRate))
+}
+
 func packetCount(avail, le int) int {
 	n := le / avail
 	if (le % avail) != 0 {
@@ -52,11 +64,30 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// maximum duration of packets (optional).
+	// It allows to limit the number of frames that are aggregated together.
+	// By default, frames are aggregated until PayloadMaxSize is reached.
+	PacketMaxDuration time.Duration
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -82,40 +113,61 @@ func (e *Encoder) Init() error {
 
 // Encode encodes frames into RTP packets.
 func (e *Encoder) Encode(frames [][]byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(frames)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frames [][]byte) ([]*rtp.Packet, error) {
 	var rets []*rtp.Packet
 	var batch [][]byte
-	timestamp := uint32(0)
+	var batchSampleCount int
+	var batchDuration time.Duration
+	sampleRate := 0
+	samples := 0
 
 	for _, frame := range frames {
-		if lenAggregated(batch, frame) <= e.PayloadMaxSize {
+		var h mpeg1audio.FrameHeader
+		err := h.Unmarshal(frame)
+		if err != nil {
+			return nil, err
+		}
+
+		duration := time.Duration(h.SampleCount()) * time.Second / time.Duration(h.SampleRate)
+
+		if lenAggregated(batch, frame) <= e.PayloadMaxSize &&
+			(e.PacketMaxDuration == 0 || (batchDuration+duration) <= e.PacketMaxDuration) {
 			batch = append(batch, frame)
 		} else {
 			// write current batch
 			if batch != nil {
-				pkts, err := e.writeBatch(batch, timestamp)
+				pkts, err := e.writeBatch(batch, timestamp(samples, sampleRate))
 				if err != nil {
 					return nil, err
 				}
 				rets = append(rets, pkts...)
 
-				for _, frame := range batch {
-					var h mpeg1audio.FrameHeader
-					err := h.Unmarshal(frame)
-					if err != nil {
-						return nil, err
-					}
-
-					timestamp += uint32(h.SampleCount())
-				}
+				samples += batchSampleCount
 			}
 
 			// initialize new batch
 			batch = [][]byte{frame}
+			batchSampleCount = 0
+			batchDuration = 0
 		}
+
+		batchSampleCount += h.SampleCount()
+		batchDuration += duration
+		sampleRate = h.SampleRate
 	}
 
 	// write last batch
-	pkts, err := e.writeBatch(batch, timestamp)
+	pkts, err := e.writeBatch(batch, timestamp(samples, sampleRate))
 	if err != nil {
 		return nil, err
 	}
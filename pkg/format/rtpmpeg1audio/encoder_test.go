@@ -2,7 +2,9 @@ package rtpmpeg1audio
 
 import (
 	"testing"
+	"time"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg1audio"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
@@ -481,6 +483,38 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodePacketMaxDuration(t *testing.T) {
+	frames := cases[1].frames
+
+	var h mpeg1audio.FrameHeader
+	err := h.Unmarshal(frames[0])
+	require.NoError(t, err)
+
+	e := &Encoder{
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		PacketMaxDuration:     time.Duration(h.SampleCount()) * time.Second / time.Duration(h.SampleRate),
+	}
+	err = e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(frames)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(pkts))
+	require.Equal(t, uint32(0), pkts[0].Timestamp)
+	require.Equal(t, uint32(h.SampleCount()*90000/h.SampleRate), pkts[1].Timestamp)
+
+	d := &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	for i, pkt := range pkts {
+		dec, err := d.Decode(pkt)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{frames[i]}, dec)
+	}
+}
+
 func TestEncodeRandomInitialState(t *testing.T) {
 	e := &Encoder{}
 	err := e.Init()
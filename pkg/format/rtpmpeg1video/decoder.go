@@ -30,6 +30,23 @@ func joinFragments(fragments [][]byte, size int) []byte {
 	return ret
 }
 
+func startsWithPictureHeader(buf []byte) bool {
+	return isStartCode(buf) &&
+		(buf[3] == startCodePicture || buf[3] == startCodeSequenceHeader || buf[3] == startCodeGOP)
+}
+
+// FrameInfo contains informations about a decoded frame.
+type FrameInfo struct {
+	// whether all packets of the frame have been received.
+	Complete bool
+
+	// temporal reference of the frame.
+	TemporalReference uint16
+
+	// picture type of the frame (1 = I, 2 = P, 3 = B, 4 = D).
+	PictureType uint8
+}
+
 // Decoder is a RTP/MPEG-1/2 Video decoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc2250
 type Decoder struct {
@@ -38,6 +55,12 @@ type Decoder struct {
 
 	sliceBuffer     [][]byte
 	sliceBufferSize int
+
+	firstPacketReceived bool
+	lastSequenceNumber  uint16
+	frameStarted        bool
+	frameIncomplete     bool
+	frameInfo           FrameInfo
 }
 
 // Init initializes the decoder.
@@ -83,12 +106,17 @@ func (d *Decoder) decodeSlice(pkt *rtp.Packet) ([]byte, error) {
 	b := (pkt.Payload[2] >> 4) & 0x01
 	e := (pkt.Payload[2] >> 3) & 0x01
 
+	if b == 1 && d.fragmentsSize != 0 {
+		d.fragments = d.fragments[:0] // discard pending fragments
+		d.fragmentsSize = 0
+		d.frameIncomplete = true
+	}
+
 	switch {
 	case b == 1 && e == 1:
 		return pkt.Payload[4:], nil
 
 	case b == 1:
-		d.fragments = d.fragments[:0] // discard pending fragments
 		d.fragments = append(d.fragments, pkt.Payload[4:])
 		d.fragmentsSize = len(pkt.Payload[4:])
 		return nil, ErrMorePacketsNeeded
@@ -119,6 +147,65 @@ func (d *Decoder) decodeSlice(pkt *rtp.Packet) ([]byte, error) {
 
 // Decode decodes frames from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	frame, _, err := d.DecodeWithInfo(pkt)
+	return frame, err
+}
+
+// DecodeWithInfo decodes frames from a RTP packet.
+// It also returns informations about the frame, including whether it has been
+// reassembled completely.
+func (d *Decoder) DecodeWithInfo(pkt *rtp.Packet) ([]byte, FrameInfo, error) {
+	if d.firstPacketReceived && pkt.SequenceNumber != d.lastSequenceNumber+1 {
+		d.frameIncomplete = true
+	}
+	d.lastSequenceNumber = pkt.SequenceNumber
+
+	if !d.frameStarted {
+		d.frameStarted = true
+
+		// when the previous frame is unknown, check that the frame begins
+		// with a picture header.
+		if !d.firstPacketReceived &&
+			(len(pkt.Payload) < 4 || !startsWithPictureHeader(pkt.Payload[4:])) {
+			d.frameIncomplete = true
+		}
+
+		if len(pkt.Payload) >= 4 {
+			d.frameInfo.TemporalReference = uint16(pkt.Payload[0]&0b11)<<8 | uint16(pkt.Payload[1])
+			d.frameInfo.PictureType = pkt.Payload[2] & 0b111
+		}
+	}
+	d.firstPacketReceived = true
+
+	frame, err := d.decode(pkt)
+	if err != nil {
+		if !errors.Is(err, ErrMorePacketsNeeded) {
+			d.frameIncomplete = true
+
+			if pkt.Marker {
+				d.resetFrame()
+			}
+		}
+		return nil, FrameInfo{}, err
+	}
+
+	info := d.frameInfo
+	info.Complete = !d.frameIncomplete
+	d.resetFrame()
+
+	return frame, info, nil
+}
+
+func (d *Decoder) resetFrame() {
+	// do not reuse sliceBuffer to avoid race conditions
+	d.sliceBuffer = nil
+	d.sliceBufferSize = 0
+	d.frameStarted = false
+	d.frameIncomplete = false
+	d.frameInfo = FrameInfo{}
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([]byte, error) {
 	slice, err := d.decodeSlice(pkt)
 	if err != nil {
 		return nil, err
@@ -127,10 +214,11 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 	addSize := len(slice)
 
 	if (d.sliceBufferSize + addSize) > maxFrameSize {
+		size := d.sliceBufferSize + addSize
 		d.sliceBuffer = nil
 		d.sliceBufferSize = 0
 		return nil, fmt.Errorf("frame size (%d) is too big, maximum is %d",
-			d.sliceBufferSize+addSize, maxFrameSize)
+			size, maxFrameSize)
 	}
 
 	d.sliceBuffer = append(d.sliceBuffer, slice)
@@ -140,11 +228,5 @@ func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
 		return nil, ErrMorePacketsNeeded
 	}
 
-	ret := joinFragments(d.sliceBuffer, d.sliceBufferSize)
-
-	// do not reuse sliceBuffer to avoid race conditions
-	d.sliceBuffer = nil
-	d.sliceBufferSize = 0
-
-	return ret, nil
+	return joinFragments(d.sliceBuffer, d.sliceBufferSize), nil
 }
@@ -26,6 +26,69 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecodeWithInfo(t *testing.T) {
+	ca := cases[len(cases)-1]
+
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	for _, pkt := range ca.pkts[:2] {
+		_, _, err = d.DecodeWithInfo(pkt)
+		require.Equal(t, ErrMorePacketsNeeded, err)
+	}
+
+	frame, info, err := d.DecodeWithInfo(ca.pkts[2])
+	require.NoError(t, err)
+	require.Equal(t, ca.frame, frame)
+	require.Equal(t, FrameInfo{
+		Complete:          true,
+		TemporalReference: 5,
+		PictureType:       3,
+	}, info)
+
+	// lose the second packet of the next frame
+	for i, pkt := range ca.pkts {
+		pkt2 := *pkt
+		pkt2.SequenceNumber += uint16(len(ca.pkts))
+		if i == 1 {
+			continue
+		}
+
+		frame, info, err = d.DecodeWithInfo(&pkt2)
+	}
+
+	require.NoError(t, err)
+	require.NotEqual(t, ca.frame, frame)
+	require.Equal(t, false, info.Complete)
+
+	// decode the next frame
+	for _, pkt := range ca.pkts {
+		pkt2 := *pkt
+		pkt2.SequenceNumber += uint16(len(ca.pkts) * 2)
+		frame, info, err = d.DecodeWithInfo(&pkt2)
+	}
+
+	require.NoError(t, err)
+	require.Equal(t, ca.frame, frame)
+	require.Equal(t, true, info.Complete)
+}
+
+func TestDecodeWithInfoMissingStart(t *testing.T) {
+	ca := cases[len(cases)-1]
+
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, _, err = d.DecodeWithInfo(ca.pkts[1])
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+
+	_, info, err := d.DecodeWithInfo(ca.pkts[2])
+	require.NoError(t, err)
+	require.Equal(t, false, info.Complete)
+}
+
 func FuzzDecoder(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a []byte, b []byte) {
 		d := &Decoder{}
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -21,14 +23,80 @@ func randUint32() (uint32, error) {
 	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
 }
 
-func lenAggregated(slices [][]byte, slice []byte) int {
-	n := 4 + len(slice)
-	for _, fr := range slices {
-		n += len(fr)
+func lenAggregated(units []unit, u unit) int {
+	n := 4 + len(u.buf)
+	for _, u2 := range units {
+		n += len(u2.buf)
 	}
 	return n
 }
 
+// unit is a slice, together with the headers that precede it.
+type unit struct {
+	buf            []byte
+	sequenceHeader bool
+}
+
+func isStartCode(buf []byte) bool {
+	return len(buf) >= 4 && buf[0] == 0 && buf[1] == 0 && buf[2] == 1
+}
+
+// splitFrame splits a frame at slice start codes.
+// Sequence, GOP and picture headers are kept together with the slice that
+// follows them, as required by RFC2250.
+func splitFrame(frame []byte) ([]unit, pictureHeader, error) {
+	var units []unit
+	var hdr pictureHeader
+	cur := unit{}
+	unitStart := 0
+	pos := 0
+
+	for {
+		end := len(frame)
+		if len(frame)-pos > 4 {
+			i := bytes.Index(frame[pos+4:], []byte{0, 0, 1})
+			if i >= 0 {
+				end = pos + 4 + i
+			}
+		}
+
+		piece := frame[pos:end]
+		isHeader := false
+
+		if isStartCode(piece) {
+			switch code := piece[3]; {
+			case code == startCodePicture:
+				err := hdr.unmarshal(piece[4:])
+				if err != nil {
+					return nil, pictureHeader{}, err
+				}
+				isHeader = true
+
+			case code == startCodeSequenceHeader:
+				cur.sequenceHeader = true
+				isHeader = true
+
+			case code > startCodeSliceMax:
+				isHeader = true
+			}
+		}
+
+		if !isHeader || end == len(frame) {
+			cur.buf = frame[unitStart:end]
+			units = append(units, cur)
+			cur = unit{}
+			unitStart = end
+		}
+
+		if end == len(frame) {
+			break
+		}
+		pos = end
+	}
+
+	return units, hdr, nil
+}
+
 func packetCount(avail, le int) int {
 	n := le / avail
 	if (le % avail) != 0 {
@@ -52,11 +120,25 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -82,111 +164,77 @@ func (e *Encoder) Init() error {
 
 // Encode encodes frames into RTP packets.
 func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
-	var rets []*rtp.Packet
-	var batch [][]byte
+	pkts, err := e.encode(frame)
+	if err != nil {
+		return nil, err
+	}
 
-	var temporalReference uint16
-	beginOfSequence := uint8(0)
-	var frameType uint8
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
 
-	for {
-		var slice []byte
-		end := bytes.Index(frame[4:], []byte{0, 0, 1})
-		if end >= 0 {
-			slice, frame = frame[:end+4], frame[end+4:]
-		} else {
-			slice, frame = frame, nil
-		}
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frame []byte) ([]*rtp.Packet, error) {
+	units, hdr, err := splitFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	var rets []*rtp.Packet
+	var batch []unit
 
-		if lenAggregated(batch, slice) <= e.PayloadMaxSize {
-			batch = append(batch, slice)
+	for _, u := range units {
+		if lenAggregated(batch, u) <= e.PayloadMaxSize {
+			batch = append(batch, u)
 		} else {
 			// write current batch
 			if batch != nil {
-				pkts, err := e.writeBatch(batch,
-					temporalReference,
-					beginOfSequence,
-					frameType)
-				if err != nil {
-					return nil, err
-				}
-				rets = append(rets, pkts...)
-				beginOfSequence = 0
+				rets = append(rets, e.writeBatch(batch, hdr)...)
 			}
 
 			// initialize new batch
-			batch = [][]byte{slice}
-		}
-
-		switch slice[3] {
-		case 0:
-			temporalReference = uint16(slice[4])<<2 | uint16(slice[5])>>6
-			frameType = (slice[5] >> 3) & 0b111
-
-		case 0xB8:
-			beginOfSequence = 1
-		}
-
-		if frame == nil {
-			break
+			batch = []unit{u}
 		}
 	}
 
 	// write last batch
-	pkts, err := e.writeBatch(batch,
-		temporalReference,
-		beginOfSequence,
-		frameType)
-	if err != nil {
-		return nil, err
-	}
-	rets = append(rets, pkts...)
+	rets = append(rets, e.writeBatch(batch, hdr)...)
 
 	rets[len(rets)-1].Marker = true
 
 	return rets, nil
 }
 
-func (e *Encoder) writeBatch(
-	slices [][]byte,
-	temporalReference uint16,
-	beginOfSequence uint8,
-	frameType uint8,
-) ([]*rtp.Packet, error) {
-	if len(slices) != 1 || lenAggregated(slices, nil) < e.PayloadMaxSize {
-		return e.writeAggregated(slices, temporalReference, beginOfSequence, frameType)
+func (e *Encoder) writeBatch(units []unit, hdr pictureHeader) []*rtp.Packet {
+	if len(units) != 1 || lenAggregated(units, unit{}) < e.PayloadMaxSize {
+		return e.writeAggregated(units, hdr)
 	}
 
-	return e.writeFragmented(slices[0], temporalReference, beginOfSequence, frameType)
+	return e.writeFragmented(units[0], hdr)
 }
 
-func (e *Encoder) writeFragmented(
-	slice []byte,
-	temporalReference uint16,
-	beginOfSequence uint8,
-	frameType uint8,
-) ([]*rtp.Packet, error) {
+func (e *Encoder) writeFragmented(u unit, hdr pictureHeader) []*rtp.Packet {
 	avail := e.PayloadMaxSize - 4
-	le := len(slice)
+	le := len(u.buf)
 	packetCount := packetCount(avail, le)
 
 	ret := make([]*rtp.Packet, packetCount)
+	buf := u.buf
 	le = avail
-	start := uint8(1)
-	end := uint8(0)
+	sequenceHeader := u.sequenceHeader
+	start := true
+	end := false
 
 	for i := range ret {
 		if i == (packetCount - 1) {
-			le = len(slice)
-			end = 1
+			le = len(buf)
+			end = true
 		}
 
 		payload := make([]byte, 4+le)
-		payload[0] = byte(temporalReference >> 8)
-		payload[1] = byte(temporalReference)
-		payload[2] = beginOfSequence<<5 | start<<4 | end<<3 | frameType
-		copy(payload[4:], slice)
-		slice = slice[le:]
+		hdr.marshalTo(payload, sequenceHeader, start, end)
+		copy(payload[4:], buf)
+		buf = buf[le:]
 
 		ret[i] = &rtp.Packet{
 			Header: rtp.Header{
@@ -199,28 +247,29 @@ func (e *Encoder) writeFragmented(
 		}
 
 		e.sequenceNumber++
-		start = 0
-		beginOfSequence = 0
+		start = false
+		sequenceHeader = false
 	}
 
-	return ret, nil
+	return ret
 }
 
-func (e *Encoder) writeAggregated(
-	slices [][]byte,
-	temporalReference uint16,
-	beginOfSequence uint8,
-	frameType uint8,
-) ([]*rtp.Packet, error) {
-	payload := make([]byte, lenAggregated(slices, nil))
+func (e *Encoder) writeAggregated(units []unit, hdr pictureHeader) []*rtp.Packet {
+	payload := make([]byte, lenAggregated(units, unit{}))
+
+	sequenceHeader := false
+	for _, u := range units {
+		if u.sequenceHeader {
+			sequenceHeader = true
+		}
+	}
 
-	payload[0] = byte(temporalReference >> 8)
-	payload[1] = byte(temporalReference)
-	payload[2] = beginOfSequence<<5 | 1<<4 | 1<<3 | frameType
+	// each packet starts and ends at a slice boundary.
+	hdr.marshalTo(payload, sequenceHeader, true, true)
 
 	n := 4
-	for _, slice := range slices {
-		n += copy(payload[n:], slice)
+	for _, u := range units {
+		n += copy(payload[n:], u.buf)
 	}
 
 	pkt := &rtp.Packet{
@@ -235,5 +284,5 @@ func (e *Encoder) writeAggregated(
 
 	e.sequenceNumber++
 
-	return []*rtp.Packet{pkt}, nil
+	return []*rtp.Packet{pkt}
 }
@@ -151,6 +151,64 @@ var cases = []struct {
 			},
 		},
 	},
+	{
+		"headers",
+		mergeBytes(
+			[]byte{0, 0, 1, 0xb3, 0x16, 0x00, 0xf0, 0x15, 0xff, 0xff, 0xe0, 0xa0},
+			[]byte{0, 0, 1, 0xb8, 0x00, 0x08, 0x00, 0x00},
+			[]byte{0, 0, 1, 0x00, 0x01, 0x5f, 0xff, 0xfd, 0x90},
+			[]byte{0, 0, 1, 0x01},
+			bytes.Repeat([]byte{2}, 1500),
+			[]byte{0, 0, 1, 0x02},
+			bytes.Repeat([]byte{3}, 100),
+		),
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    32,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0, 5, 0x33, 0x2b},
+					[]byte{0, 0, 1, 0xb3, 0x16, 0x00, 0xf0, 0x15, 0xff, 0xff, 0xe0, 0xa0},
+					[]byte{0, 0, 1, 0xb8, 0x00, 0x08, 0x00, 0x00},
+					[]byte{0, 0, 1, 0x00, 0x01, 0x5f, 0xff, 0xfd, 0x90},
+					[]byte{0, 0, 1, 0x01},
+					bytes.Repeat([]byte{2}, 1423),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    32,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0, 5, 0x0b, 0x2b},
+					bytes.Repeat([]byte{2}, 77),
+				),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    32,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: mergeBytes(
+					[]byte{0, 5, 0x1b, 0x2b},
+					[]byte{0, 0, 1, 0x02},
+					bytes.Repeat([]byte{3}, 100),
+				),
+			},
+		},
+	},
 }
 
 func TestEncode(t *testing.T) {
@@ -0,0 +1,81 @@
+package rtpmpeg1video
+
+import (
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+)
+
+const (
+	startCodePicture        = 0x00
+	startCodeSliceMax       = 0xAF
+	startCodeSequenceHeader = 0xB3
+	startCodeGOP            = 0xB8
+)
+
+const (
+	pictureTypeP = 2
+	pictureTypeB = 3
+)
+
+// picture header fields that are copied into the video-specific header.
+// Specification: ISO 13818-2, section 6.2.3
+type pictureHeader struct {
+	temporalReference     uint16
+	pictureType           uint8
+	fullPelForwardVector  uint8
+	forwardFCode          uint8
+	fullPelBackwardVector uint8
+	backwardFCode         uint8
+}
+
+func (h *pictureHeader) unmarshal(buf []byte) error {
+	pos := 0
+
+	tmp, err := bits.ReadBits(buf, &pos, 13)
+	if err != nil {
+		return fmt.Errorf("invalid picture header")
+	}
+	h.temporalReference = uint16(tmp >> 3)
+	h.pictureType = uint8(tmp & 0b111)
+
+	pos += 16 // vbv_delay
+
+	if h.pictureType == pictureTypeP || h.pictureType == pictureTypeB {
+		tmp, err = bits.ReadBits(buf, &pos, 4)
+		if err != nil {
+			return fmt.Errorf("invalid picture header")
+		}
+		h.fullPelForwardVector = uint8(tmp >> 3)
+		h.forwardFCode = uint8(tmp & 0b111)
+	}
+
+	if h.pictureType == pictureTypeB {
+		tmp, err = bits.ReadBits(buf, &pos, 4)
+		if err != nil {
+			return fmt.Errorf("invalid picture header")
+		}
+		h.fullPelBackwardVector = uint8(tmp >> 3)
+		h.backwardFCode = uint8(tmp & 0b111)
+	}
+
+	return nil
+}
+
+// marshalTo writes a MPEG video-specific header.
+// Specification: RFC2250, section 3.4
+func (h pictureHeader) marshalTo(buf []byte, sequenceHeader bool, beginOfSlice bool, endOfSlice bool) {
+	buf[0] = byte(h.temporalReference >> 8)
+	buf[1] = byte(h.temporalReference)
+	buf[2] = h.pictureType
+	if sequenceHeader {
+		buf[2] |= 1 << 5
+	}
+	if beginOfSlice {
+		buf[2] |= 1 << 4
+	}
+	if endOfSlice {
+		buf[2] |= 1 << 3
+	}
+	buf[3] = h.fullPelBackwardVector<<7 | h.backwardFCode<<4 | h.fullPelForwardVector<<3 | h.forwardFCode
+}
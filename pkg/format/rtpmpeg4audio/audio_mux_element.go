@@ -0,0 +1,164 @@
+package rtpmpeg4audio
+
+import (
+	"fmt"
+
+	"github.com/bluenviron/mediacommon/pkg/bits"
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
+)
+
+// maximum size of an AudioMuxElement, that can contain up to 64 subframes.
+const maxAudioMuxElementSize = 64 * mpeg4audio.MaxAccessUnitSize
+
+// readStreamMuxConfig reads a StreamMuxConfig starting at an arbitrary bit position,
+// as it happens when the StreamMuxConfig is transmitted in-band.
+func readStreamMuxConfig(buf []byte, pos *int) (*mpeg4audio.StreamMuxConfig, error) {
+	err := bits.HasSpace(buf, *pos, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	audioMuxVersion := bits.ReadFlagUnsafe(buf, pos)
+	if audioMuxVersion {
+		return nil, fmt.Errorf("audioMuxVersion = 1 is not supported")
+	}
+
+	allStreamsSameTimeFraming := bits.ReadFlagUnsafe(buf, pos)
+	if !allStreamsSameTimeFraming {
+		return nil, fmt.Errorf("allStreamsSameTimeFraming = 0 is not supported")
+	}
+
+	c := &mpeg4audio.StreamMuxConfig{}
+	c.NumSubFrames = uint(bits.ReadBitsUnsafe(buf, pos, 6))
+	numProgram := uint(bits.ReadBitsUnsafe(buf, pos, 4))
+
+	c.Programs = make([]*mpeg4audio.StreamMuxConfigProgram, numProgram+1)
+
+	for prog := uint(0); prog <= numProgram; prog++ {
+		p := &mpeg4audio.StreamMuxConfigProgram{}
+		c.Programs[prog] = p
+
+		numLayer, err := bits.ReadBits(buf, pos, 3)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Layers = make([]*mpeg4audio.StreamMuxConfigLayer, numLayer+1)
+
+		for lay := uint(0); lay <= uint(numLayer); lay++ {
+			l := &mpeg4audio.StreamMuxConfigLayer{}
+			p.Layers[lay] = l
+
+			useSameConfig := false
+
+			if prog != 0 || lay != 0 {
+				useSameConfig, err = bits.ReadFlag(buf, pos)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if !useSameConfig {
+				l.AudioSpecificConfig = &mpeg4audio.AudioSpecificConfig{}
+				err = l.AudioSpecificConfig.UnmarshalFromPos(buf, pos)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			tmp, err := bits.ReadBits(buf, pos, 3)
+			if err != nil {
+				return nil, err
+			}
+			l.FrameLengthType = uint(tmp)
+
+			switch l.FrameLengthType {
+			case 0:
+				tmp, err = bits.ReadBits(buf, pos, 8)
+				l.LatmBufferFullness = uint(tmp)
+
+			case 1:
+				tmp, err = bits.ReadBits(buf, pos, 9)
+				l.FrameLength = uint(tmp)
+
+			case 4, 5, 3:
+				tmp, err = bits.ReadBits(buf, pos, 6)
+				l.CELPframeLengthTableIndex = uint(tmp)
+
+			case 6, 7:
+				l.HVXCframeLengthTableIndex, err = bits.ReadFlag(buf, pos)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c.OtherDataPresent, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.OtherDataPresent {
+		for {
+			c.OtherDataLenBits *= 256
+
+			err = bits.HasSpace(buf, *pos, 9)
+			if err != nil {
+				return nil, err
+			}
+
+			otherDataLenEsc := bits.ReadFlagUnsafe(buf, pos)
+			c.OtherDataLenBits += uint32(bits.ReadBitsUnsafe(buf, pos, 8))
+
+			if !otherDataLenEsc {
+				break
+			}
+		}
+	}
+
+	c.CRCCheckPresent, err = bits.ReadFlag(buf, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.CRCCheckPresent {
+		tmp, err := bits.ReadBits(buf, pos, 8)
+		if err != nil {
+			return nil, err
+		}
+		c.CRCCheckSum = uint8(tmp)
+	}
+
+	return c, nil
+}
+
+// marshalStreamMuxConfig encodes a StreamMuxConfig and returns its exact size in bits.
+func marshalStreamMuxConfig(c *mpeg4audio.StreamMuxConfig) ([]byte, int, error) {
+	enc, err := c.Marshal()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	n := 0
+	_, err = readStreamMuxConfig(enc, &n)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return enc, n, nil
+}
+
+// checkStreamMuxConfig checks whether a StreamMuxConfig can be used to
+// decode or encode AudioMuxElements.
+func checkStreamMuxConfig(c *mpeg4audio.StreamMuxConfig) error {
+	if len(c.Programs) != 1 || len(c.Programs[0].Layers) != 1 {
+		return fmt.Errorf("StreamMuxConfig with multiple programs or layers is not supported")
+	}
+
+	if c.Programs[0].Layers[0].FrameLengthType != 0 {
+		return fmt.Errorf("frameLengthType = %d is not supported", c.Programs[0].Layers[0].FrameLengthType)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package rtpmpeg4audio
 import (
 	"errors"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 	"github.com/pion/rtp"
 )
 
@@ -33,11 +34,25 @@ type Decoder struct {
 	// The number of bits in which the AU-Index-delta field is encoded in any non-first AU-header.
 	IndexDeltaLength int
 
+	// LATM-only
+	// whether the StreamMuxConfig is transmitted in-band.
+	CPresent bool
+	// StreamMuxConfig (optional).
+	// It is needed to decode packets that contain multiple subframes.
+	// When CPresent is true, it is replaced by the in-band StreamMuxConfig.
+	StreamMuxConfig *mpeg4audio.StreamMuxConfig
+
 	firstAUParsed     bool
 	adtsMode          bool
 	fragments         [][]byte
 	fragmentsSize     int
 	fragmentsExpected int
+
+	// interleaving
+	outputReceived  bool
+	nextTimestamp   uint32
+	maxDisplacement int32
+	deintBuffer     []AccessUnit
 }
 
 // Init initializes the decoder.
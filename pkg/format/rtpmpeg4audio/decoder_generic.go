@@ -9,24 +9,36 @@ import (
 )
 
 func (d *Decoder) decodeGeneric(pkt *rtp.Packet) ([][]byte, error) {
+	aus, _, err := d.decodeGenericAUs(pkt, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.removeADTS(aus)
+}
+
+// decodeGenericAUs decodes AUs from a RTP packet.
+// When interleaved is true, it also returns the position of each AU
+// relative to the first one, computed from AU-index-delta fields.
+func (d *Decoder) decodeGenericAUs(pkt *rtp.Packet, interleaved bool) ([][]byte, []uint64, error) {
 	if len(pkt.Payload) < 2 {
 		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, fmt.Errorf("payload is too short")
+		return nil, nil, fmt.Errorf("payload is too short")
 	}
 
 	// AU-headers-length (16 bits)
 	headersLen := int(uint16(pkt.Payload[0])<<8 | uint16(pkt.Payload[1]))
 	if headersLen == 0 {
 		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, fmt.Errorf("invalid AU-headers-length")
+		return nil, nil, fmt.Errorf("invalid AU-headers-length")
 	}
 	payload := pkt.Payload[2:]
 
 	// AU-headers
-	dataLens, err := d.readAUHeaders(payload, headersLen)
+	dataLens, positions, err := d.readAUHeaders(payload, headersLen, interleaved)
 	if err != nil {
 		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, err
+		return nil, nil, err
 	}
 
 	pos := (headersLen / 8)
@@ -43,7 +55,7 @@ func (d *Decoder) decodeGeneric(pkt *rtp.Packet) ([][]byte, error) {
 			aus = make([][]byte, len(dataLens))
 			for i, dataLen := range dataLens {
 				if len(payload) < int(dataLen) {
-					return nil, fmt.Errorf("payload is too short")
+					return nil, nil, fmt.Errorf("payload is too short")
 				}
 
 				aus[i] = payload[:dataLen]
@@ -51,50 +63,50 @@ func (d *Decoder) decodeGeneric(pkt *rtp.Packet) ([][]byte, error) {
 			}
 		} else {
 			if len(dataLens) != 1 {
-				return nil, fmt.Errorf("a fragmented packet can only contain one AU")
+				return nil, nil, fmt.Errorf("a fragmented packet can only contain one AU")
 			}
 
 			if len(payload) < int(dataLens[0]) {
-				return nil, fmt.Errorf("payload is too short")
+				return nil, nil, fmt.Errorf("payload is too short")
 			}
 
 			d.fragmentsSize = int(dataLens[0])
 			d.fragments = append(d.fragments, payload[:dataLens[0]])
-			return nil, ErrMorePacketsNeeded
+			return nil, nil, ErrMorePacketsNeeded
 		}
 	} else {
 		// we are decoding a fragmented AU
 		if len(dataLens) != 1 {
 			d.fragments = d.fragments[:0] // discard pending fragments
-			return nil, fmt.Errorf("a fragmented packet can only contain one AU")
+			return nil, nil, fmt.Errorf("a fragmented packet can only contain one AU")
 		}
 
 		if len(payload) < int(dataLens[0]) {
 			d.fragments = d.fragments[:0] // discard pending fragments
-			return nil, fmt.Errorf("payload is too short")
+			return nil, nil, fmt.Errorf("payload is too short")
 		}
 
 		d.fragmentsSize += int(dataLens[0])
 		if d.fragmentsSize > mpeg4audio.MaxAccessUnitSize {
 			d.fragments = d.fragments[:0] // discard pending fragments
-			return nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
+			return nil, nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
 				d.fragmentsSize, mpeg4audio.MaxAccessUnitSize)
 		}
 
 		d.fragments = append(d.fragments, payload[:dataLens[0]])
 
 		if !pkt.Marker {
-			return nil, ErrMorePacketsNeeded
+			return nil, nil, ErrMorePacketsNeeded
 		}
 
 		aus = [][]byte{joinFragments(d.fragments, d.fragmentsSize)}
 		d.fragments = d.fragments[:0]
 	}
 
-	return d.removeADTS(aus)
+	return aus, positions, nil
 }
 
-func (d *Decoder) readAUHeaders(buf []byte, headersLen int) ([]uint64, error) {
+func (d *Decoder) readAUHeaders(buf []byte, headersLen int, interleaved bool) ([]uint64, []uint64, error) {
 	firstRead := false
 
 	count := 0
@@ -111,13 +123,18 @@ func (d *Decoder) readAUHeaders(buf []byte, headersLen int) ([]uint64, error) {
 
 	dataLens := make([]uint64, count)
 
+	var positions []uint64
+	if interleaved {
+		positions = make([]uint64, count)
+	}
+
 	pos := 0
 	i := 0
 
 	for headersLen > 0 {
 		dataLen, err := bits.ReadBits(buf, &pos, d.SizeLength)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		headersLen -= d.SizeLength
 
@@ -126,31 +143,35 @@ func (d *Decoder) readAUHeaders(buf []byte, headersLen int) ([]uint64, error) {
 			if d.IndexLength > 0 {
 				auIndex, err := bits.ReadBits(buf, &pos, d.IndexLength)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				headersLen -= d.IndexLength
 
-				if auIndex != 0 {
-					return nil, fmt.Errorf("AU-index different than zero is not supported")
+				if !interleaved && auIndex != 0 {
+					return nil, nil, fmt.Errorf("AU-index different than zero is not supported")
 				}
 			}
 		} else if d.IndexDeltaLength > 0 {
 			auIndexDelta, err := bits.ReadBits(buf, &pos, d.IndexDeltaLength)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			headersLen -= d.IndexDeltaLength
 
-			if auIndexDelta != 0 {
-				return nil, fmt.Errorf("AU-index-delta different than zero is not supported")
+			if interleaved {
+				positions[i] = positions[i-1] + auIndexDelta + 1
+			} else if auIndexDelta != 0 {
+				return nil, nil, fmt.Errorf("AU-index-delta different than zero is not supported")
 			}
+		} else if interleaved {
+			positions[i] = positions[i-1] + 1
 		}
 
 		dataLens[i] = dataLen
 		i++
 	}
 
-	return dataLens, nil
+	return dataLens, positions, nil
 }
 
 // some cameras wrap AUs into ADTS
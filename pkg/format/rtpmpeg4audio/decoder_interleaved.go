@@ -0,0 +1,132 @@
+package rtpmpeg4audio
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
+	"github.com/pion/rtp"
+)
+
+const (
+	// maximum number of AUs that can be waiting to be put in order.
+	maxDeinterleavingBufferSize = 64
+)
+
+// AccessUnit is an access unit decoded by DecodeInterleaved().
+type AccessUnit struct {
+	// RTP timestamp of the access unit.
+	Timestamp uint32
+
+	// access unit.
+	AU []byte
+}
+
+func (d *Decoder) deinterleave(au AccessUnit) {
+	if d.outputReceived {
+		diff := int32(au.Timestamp - d.nextTimestamp)
+
+		if diff < -(maxDeinterleavingBufferSize * mpeg4audio.SamplesPerAccessUnit) {
+			// timestamp discontinuity: reset state
+			d.outputReceived = false
+			d.deintBuffer = nil
+		} else if diff < 0 {
+			// AU has been received too late or twice
+			return
+		}
+	}
+
+	i := sort.Search(len(d.deintBuffer), func(i int) bool {
+		return int32(d.deintBuffer[i].Timestamp-au.Timestamp) >= 0
+	})
+	if i < len(d.deintBuffer) && d.deintBuffer[i].Timestamp == au.Timestamp {
+		return
+	}
+
+	d.deintBuffer = append(d.deintBuffer, AccessUnit{})
+	copy(d.deintBuffer[i+1:], d.deintBuffer[i:])
+	d.deintBuffer[i] = au
+}
+
+func (d *Decoder) flushDeinterleavingBuffer() []AccessUnit {
+	var ret []AccessUnit
+
+	for len(d.deintBuffer) != 0 {
+		first := d.deintBuffer[0]
+		last := d.deintBuffer[len(d.deintBuffer)-1]
+
+		// output the first AU when it is the next one, or when it is not possible
+		// to wait for missing AUs anymore, since they would be outside the maximum
+		// displacement seen so far, or the buffer is full.
+		if d.outputReceived &&
+			first.Timestamp != d.nextTimestamp &&
+			int32(last.Timestamp-d.nextTimestamp) <= d.maxDisplacement &&
+			len(d.deintBuffer) < maxDeinterleavingBufferSize {
+			break
+		}
+
+		ret = append(ret, first)
+		d.deintBuffer = d.deintBuffer[1:]
+
+		d.outputReceived = true
+		d.nextTimestamp = first.Timestamp + mpeg4audio.SamplesPerAccessUnit
+	}
+
+	return ret
+}
+
+// DecodeInterleaved decodes AUs from a RTP packet of a stream
+// whose AUs may be interleaved, by using the AU-index-delta field.
+// AUs are returned in order, together with their timestamp,
+// with a delay that depends on the interleaving pattern.
+func (d *Decoder) DecodeInterleaved(pkt *rtp.Packet) ([]AccessUnit, error) {
+	if d.LATM {
+		return nil, fmt.Errorf("DecodeInterleaved() can't be used with LATM")
+	}
+
+	aus, positions, err := d.decodeGenericAUs(pkt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	aus, err = d.removeADTS(aus)
+	if err != nil {
+		return nil, err
+	}
+
+	// fast path: AUs are not interleaved
+	if len(d.deintBuffer) == 0 && (!d.outputReceived || pkt.Timestamp == d.nextTimestamp) &&
+		positions[len(positions)-1] == uint64(len(positions)-1) {
+		ret := make([]AccessUnit, len(aus))
+		for i, au := range aus {
+			ret[i] = AccessUnit{
+				Timestamp: pkt.Timestamp + uint32(i)*mpeg4audio.SamplesPerAccessUnit,
+				AU:        au,
+			}
+		}
+
+		d.outputReceived = true
+		d.nextTimestamp = pkt.Timestamp + uint32(len(aus))*mpeg4audio.SamplesPerAccessUnit
+
+		return ret, nil
+	}
+
+	displacement := int32(positions[len(positions)-1] * mpeg4audio.SamplesPerAccessUnit)
+	if displacement > d.maxDisplacement {
+		d.maxDisplacement = displacement
+	}
+
+	for i, au := range aus {
+		d.deinterleave(AccessUnit{
+			Timestamp: pkt.Timestamp + uint32(positions[i]*mpeg4audio.SamplesPerAccessUnit),
+			AU:        au,
+		})
+	}
+
+	ret := d.flushDeinterleavingBuffer()
+	if ret == nil {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,161 @@
+package rtpmpeg4audio
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInterleavedNonInterleaved(t *testing.T) {
+	for _, ca := range casesGeneric {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				SizeLength:       ca.sizeLength,
+				IndexLength:      ca.indexLength,
+				IndexDeltaLength: ca.indexDeltaLength,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var aus [][]byte
+
+			for _, pkt := range ca.pkts {
+				addAUs, err := d.DecodeInterleaved(pkt)
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+				require.NoError(t, err)
+
+				for _, au := range addAUs {
+					aus = append(aus, au.AU)
+				}
+			}
+
+			require.Equal(t, ca.aus, aus)
+		})
+	}
+}
+
+func TestDecodeInterleaved(t *testing.T) {
+	e := &Encoder{
+		PayloadType:       96,
+		SizeLength:        13,
+		IndexLength:       3,
+		IndexDeltaLength:  3,
+		InterleavingDepth: 3,
+		PayloadMaxSize:    42,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	var aus [][]byte
+	for i := 0; i < 24; i++ {
+		aus = append(aus, []byte{byte(i), byte(i), byte(i), byte(i), byte(i), byte(i), byte(i), byte(i)})
+	}
+
+	pkts, err := e.Encode(aus)
+	require.NoError(t, err)
+	require.Equal(t, 6, len(pkts))
+
+	for _, ca := range []struct {
+		name string
+		lost int
+		aus  []int
+	}{
+		{
+			"no loss",
+			-1,
+			[]int{
+				0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
+				12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23,
+			},
+		},
+		{
+			"loss",
+			2,
+			[]int{
+				0, 1, 3, 4, 6, 7, 9, 10,
+				12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23,
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				SizeLength:       13,
+				IndexLength:      3,
+				IndexDeltaLength: 3,
+			}
+			err = d.Init()
+			require.NoError(t, err)
+
+			var decAUs []AccessUnit
+
+			for i, pkt := range pkts {
+				if i == ca.lost {
+					continue
+				}
+
+				pkt.Timestamp += 1000
+
+				addAUs, err := d.DecodeInterleaved(pkt)
+
+				pkt.Timestamp -= 1000
+
+				if errors.Is(err, ErrMorePacketsNeeded) {
+					continue
+				}
+				require.NoError(t, err)
+
+				decAUs = append(decAUs, addAUs...)
+			}
+
+			require.Equal(t, len(ca.aus), len(decAUs))
+
+			for i, n := range ca.aus {
+				require.Equal(t, AccessUnit{
+					Timestamp: 1000 + uint32(n)*mpeg4audio.SamplesPerAccessUnit,
+					AU:        aus[n],
+				}, decAUs[i])
+			}
+		})
+	}
+}
+
+func FuzzDecoderInterleaved(f *testing.F) {
+	f.Fuzz(func(t *testing.T, a []byte, at uint32, b []byte, bt uint32) {
+		d := &Decoder{
+			SizeLength:       13,
+			IndexLength:      3,
+			IndexDeltaLength: 3,
+		}
+		err := d.Init()
+		require.NoError(t, err)
+
+		d.DecodeInterleaved(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      at,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.DecodeInterleaved(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      bt,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
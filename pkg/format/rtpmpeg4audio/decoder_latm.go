@@ -3,11 +3,16 @@ package rtpmpeg4audio
 import (
 	"fmt"
 
+	"github.com/bluenviron/mediacommon/pkg/bits"
 	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 	"github.com/pion/rtp"
 )
 
 func (d *Decoder) decodeLATM(pkt *rtp.Packet) ([][]byte, error) {
+	if d.CPresent || (d.StreamMuxConfig != nil && d.StreamMuxConfig.NumSubFrames != 0) {
+		return d.decodeLATMElement(pkt)
+	}
+
 	var au []byte
 	buf := pkt.Payload
 
@@ -53,3 +58,100 @@ func (d *Decoder) decodeLATM(pkt *rtp.Packet) ([][]byte, error) {
 
 	return [][]byte{au}, nil
 }
+
+// decodeLATMElement decodes a whole AudioMuxElement, whose end is signaled by the marker bit.
+func (d *Decoder) decodeLATMElement(pkt *rtp.Packet) ([][]byte, error) {
+	d.fragmentsSize += len(pkt.Payload)
+
+	if d.fragmentsSize > maxAudioMuxElementSize {
+		d.fragments = d.fragments[:0] // discard pending fragments
+		errSize := d.fragmentsSize
+		d.fragmentsSize = 0
+		return nil, fmt.Errorf("AudioMuxElement size (%d) is too big, maximum is %d",
+			errSize, maxAudioMuxElementSize)
+	}
+
+	d.fragments = append(d.fragments, pkt.Payload)
+
+	if !pkt.Marker {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	buf := joinFragments(d.fragments, d.fragmentsSize)
+	d.fragments = d.fragments[:0]
+	d.fragmentsSize = 0
+
+	return d.decodeAudioMuxElement(buf)
+}
+
+func (d *Decoder) decodeAudioMuxElement(buf []byte) ([][]byte, error) {
+	pos := 0
+
+	if d.CPresent {
+		useSameStreamMux, err := bits.ReadFlag(buf, &pos)
+		if err != nil {
+			return nil, err
+		}
+
+		if !useSameStreamMux {
+			conf, err := readStreamMuxConfig(buf, &pos)
+			if err != nil {
+				return nil, fmt.Errorf("invalid StreamMuxConfig: %w", err)
+			}
+
+			err = checkStreamMuxConfig(conf)
+			if err != nil {
+				return nil, err
+			}
+
+			d.StreamMuxConfig = conf
+		}
+	}
+
+	if d.StreamMuxConfig == nil {
+		return nil, fmt.Errorf("StreamMuxConfig has not been received yet")
+	}
+
+	err := checkStreamMuxConfig(d.StreamMuxConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	aus := make([][]byte, d.StreamMuxConfig.NumSubFrames+1)
+
+	for i := range aus {
+		pl := 0
+
+		for {
+			b, err := bits.ReadBits(buf, &pos, 8)
+			if err != nil {
+				return nil, err
+			}
+			pl += int(b)
+
+			if b != 255 {
+				break
+			}
+		}
+
+		if pl > mpeg4audio.MaxAccessUnitSize {
+			return nil, fmt.Errorf("access unit size (%d) is too big, maximum is %d",
+				pl, mpeg4audio.MaxAccessUnitSize)
+		}
+
+		err := bits.HasSpace(buf, pos, pl*8)
+		if err != nil {
+			return nil, err
+		}
+
+		au := make([]byte, pl)
+		for j := range au {
+			au[j] = byte(bits.ReadBitsUnsafe(buf, &pos, 8))
+		}
+		aus[i] = au
+	}
+
+	// there could be other data, due to otherDataPresent. Ignore it.
+
+	return aus, nil
+}
@@ -63,6 +63,59 @@ func TestDecodeLATMOtherData(t *testing.T) {
 	require.Equal(t, []byte{1, 2, 3, 4}, aus[0])
 }
 
+func TestDecodeLATMSubFrames(t *testing.T) {
+	conf := *testStreamMuxConfig
+	conf.NumSubFrames = 1
+
+	d := &Decoder{
+		LATM:            true,
+		StreamMuxConfig: &conf,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			Marker:      false,
+			PayloadType: 96,
+		},
+		Payload: []byte{0x02, 0x01},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	aus, err := d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			Marker:      true,
+			PayloadType: 96,
+		},
+		Payload: []byte{0x02, 0x01, 0x03},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1, 2}, {3}}, aus)
+}
+
+func TestDecodeLATMCPresentErrors(t *testing.T) {
+	d := &Decoder{
+		LATM:     true,
+		CPresent: true,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// useSameStreamMux = 1 before any StreamMuxConfig
+	_, err = d.Decode(&rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			Marker:      true,
+			PayloadType: 96,
+		},
+		Payload: []byte{0x80, 0x01, 0x02},
+	})
+	require.EqualError(t, err, "StreamMuxConfig has not been received yet")
+}
+
 func FuzzDecoderLATM(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{
@@ -95,3 +148,37 @@ func FuzzDecoderLATM(f *testing.F) {
 		})
 	})
 }
+
+func FuzzDecoderLATMCPresent(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
+		d := &Decoder{
+			LATM:     true,
+			CPresent: true,
+		}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         am,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         bm,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
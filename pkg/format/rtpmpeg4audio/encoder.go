@@ -2,8 +2,12 @@ package rtpmpeg4audio
 
 import (
 	"crypto/rand"
+	"fmt"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -20,6 +24,24 @@ func randUint32() (uint32, error) {
 	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
 }
 
+func removeADTS(aus [][]byte) ([][]byte, error) {
+	ret := make([][]byte, 0, len(aus))
+
+	for _, au := range aus {
+		var pkts mpeg4audio.ADTSPackets
+		err := pkts.Unmarshal(au)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode ADTS: %w", err)
+		}
+
+		for _, pkt := range pkts {
+			ret = append(ret, pkt.AU)
+		}
+	}
+
+	return ret, nil
+}
+
 // Encoder is a RTP/MPEG-4 audio encoder.
 // Specification: https://datatracker.ietf.org/doc/html/rfc3640
 // Specification: https://datatracker.ietf.org/doc/html/rfc6416#section-7.3
@@ -39,6 +61,25 @@ type Encoder struct {
 	// The number of bits in which the AU-Index-delta field is encoded in any non-first AU-header.
 	IndexDeltaLength int
 
+	// Generic-only
+	// number of packets among which consecutive AUs are distributed (optional).
+	// When greater than 1, AUs are interleaved, and IndexDeltaLength
+	// must be big enough to contain InterleavingDepth-1.
+	InterleavingDepth int
+
+	// whether AUs are wrapped into ADTS.
+	// When true, ADTS headers are removed before encoding.
+	ADTS bool
+
+	// LATM-only
+	// whether to transmit the StreamMuxConfig in-band.
+	CPresent bool
+
+	// LATM-only
+	// StreamMuxConfig (optional).
+	// It is needed to encode multiple subframes per packet, or when CPresent is true.
+	StreamMuxConfig *mpeg4audio.StreamMuxConfig
+
 	// SSRC of packets (optional).
 	// It defaults to a random value.
 	SSRC *uint32
@@ -51,11 +92,28 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
-	sequenceNumber uint16
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
+	sequenceNumber      uint16
+	auIndex             uint64
+	streamMuxConfigEnc  []byte
+	streamMuxConfigBits int
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -75,12 +133,61 @@ func (e *Encoder) Init() error {
 		e.PayloadMaxSize = defaultPayloadMaxSize
 	}
 
+	if e.InterleavingDepth > 1 {
+		if e.LATM {
+			return fmt.Errorf("interleaving can't be used with LATM")
+		}
+
+		if e.IndexLength == 0 || e.IndexDeltaLength == 0 ||
+			e.IndexDeltaLength < 64 && uint64(e.InterleavingDepth-1) >= (uint64(1)<<e.IndexDeltaLength) {
+			return fmt.Errorf("IndexLength and IndexDeltaLength are too small for an InterleavingDepth of %d",
+				e.InterleavingDepth)
+		}
+	}
+
+	if e.LATM {
+		if e.CPresent && e.StreamMuxConfig == nil {
+			return fmt.Errorf("StreamMuxConfig is required when CPresent is true")
+		}
+
+		if e.StreamMuxConfig != nil && (e.CPresent || e.StreamMuxConfig.NumSubFrames != 0) {
+			err := checkStreamMuxConfig(e.StreamMuxConfig)
+			if err != nil {
+				return err
+			}
+
+			e.streamMuxConfigEnc, e.streamMuxConfigBits, err = marshalStreamMuxConfig(e.StreamMuxConfig)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	e.sequenceNumber = *e.InitialSequenceNumber
 	return nil
 }
 
 // Encode encodes AUs into RTP packets.
 func (e *Encoder) Encode(aus [][]byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(aus)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(aus [][]byte) ([]*rtp.Packet, error) {
+	if e.ADTS {
+		var err error
+		aus, err = removeADTS(aus)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if !e.LATM {
 		return e.encodeGeneric(aus)
 	}
@@ -16,9 +16,67 @@ func packetCountGeneric(avail, le int) int {
 }
 
 func (e *Encoder) encodeGeneric(aus [][]byte) ([]*rtp.Packet, error) {
+	if e.InterleavingDepth > 1 {
+		return e.encodeGenericInterleaved(aus)
+	}
+
+	return e.encodeGenericAUs(aus, 0, 1)
+}
+
+// encodeGenericInterleaved distributes consecutive AUs among InterleavingDepth sequences,
+// whose packets are sent alternately.
+func (e *Encoder) encodeGenericInterleaved(aus [][]byte) ([]*rtp.Packet, error) {
+	initialSequenceNumber := e.sequenceNumber
+	seqs := make([][]*rtp.Packet, 0, e.InterleavingDepth)
+	count := 0
+
+	for i := 0; i < e.InterleavingDepth && i < len(aus); i++ {
+		var seq [][]byte
+		for j := i; j < len(aus); j += e.InterleavingDepth {
+			seq = append(seq, aus[j])
+		}
+
+		pkts, err := e.encodeGenericAUs(seq, i, e.InterleavingDepth)
+		if err != nil {
+			return nil, err
+		}
+		seqs = append(seqs, pkts)
+		count += len(pkts)
+	}
+
+	rets := make([]*rtp.Packet, 0, count)
+
+	// fragments of an AU must be sent consecutively
+	for len(rets) != count {
+		for i, seq := range seqs {
+			for len(seq) != 0 {
+				pkt := seq[0]
+				seq = seq[1:]
+				rets = append(rets, pkt)
+
+				if pkt.Marker {
+					break
+				}
+			}
+			seqs[i] = seq
+		}
+	}
+
+	for i, pkt := range rets {
+		pkt.SequenceNumber = initialSequenceNumber + uint16(i)
+	}
+
+	e.auIndex += uint64(len(aus))
+
+	return rets, nil
+}
+
+// encodeGenericAUs encodes a sequence of AUs.
+// The first AU has position first, and subsequent AUs are spaced by step positions.
+func (e *Encoder) encodeGenericAUs(aus [][]byte, first int, step int) ([]*rtp.Packet, error) {
 	var rets []*rtp.Packet
 	var batch [][]byte
-	timestamp := uint32(0)
+	pos := first
 
 	// split AUs into batches
 	for _, au := range aus {
@@ -28,12 +86,12 @@ func (e *Encoder) encodeGeneric(aus [][]byte) ([]*rtp.Packet, error) {
 		} else {
 			// write current batch
 			if batch != nil {
-				pkts, err := e.writeGenericBatch(batch, timestamp)
+				pkts, err := e.writeGenericBatch(batch, pos, step)
 				if err != nil {
 					return nil, err
 				}
 				rets = append(rets, pkts...)
-				timestamp += uint32(len(batch)) * mpeg4audio.SamplesPerAccessUnit
+				pos += len(batch) * step
 			}
 
 			// initialize new batch
@@ -42,7 +100,7 @@ func (e *Encoder) encodeGeneric(aus [][]byte) ([]*rtp.Packet, error) {
 	}
 
 	// write last batch
-	pkts, err := e.writeGenericBatch(batch, timestamp)
+	pkts, err := e.writeGenericBatch(batch, pos, step)
 	if err != nil {
 		return nil, err
 	}
@@ -51,15 +109,30 @@ func (e *Encoder) encodeGeneric(aus [][]byte) ([]*rtp.Packet, error) {
 	return rets, nil
 }
 
-func (e *Encoder) writeGenericBatch(aus [][]byte, timestamp uint32) ([]*rtp.Packet, error) {
+func (e *Encoder) auIndexAt(pos int) uint64 {
+	// AU-index is zero when AUs are not interleaved
+	if e.InterleavingDepth <= 1 {
+		return 0
+	}
+
+	v := e.auIndex + uint64(pos)
+	if e.IndexLength < 64 {
+		v &= (uint64(1) << e.IndexLength) - 1
+	}
+	return v
+}
+
+func (e *Encoder) writeGenericBatch(aus [][]byte, pos int, step int) ([]*rtp.Packet, error) {
+	timestamp := uint32(pos) * mpeg4audio.SamplesPerAccessUnit
+
 	if len(aus) != 1 || e.lenGenericAggregated(aus, nil) < e.PayloadMaxSize {
-		return e.writeGenericAggregated(aus, timestamp)
+		return e.writeGenericAggregated(aus, timestamp, e.auIndexAt(pos), uint64(step-1))
 	}
 
-	return e.writeGenericFragmented(aus[0], timestamp)
+	return e.writeGenericFragmented(aus[0], timestamp, e.auIndexAt(pos))
 }
 
-func (e *Encoder) writeGenericFragmented(au []byte, timestamp uint32) ([]*rtp.Packet, error) {
+func (e *Encoder) writeGenericFragmented(au []byte, timestamp uint32, auIndex uint64) ([]*rtp.Packet, error) {
 	auHeadersLen := e.SizeLength + e.IndexLength
 	auHeadersLenBytes := auHeadersLen / 8
 	if (auHeadersLen % 8) != 0 {
@@ -87,7 +160,7 @@ func (e *Encoder) writeGenericFragmented(au []byte, timestamp uint32) ([]*rtp.Pa
 		// AU-headers
 		pos := 0
 		bits.WriteBits(payload[2:], &pos, uint64(le), e.SizeLength)
-		bits.WriteBits(payload[2:], &pos, 0, e.IndexLength)
+		bits.WriteBits(payload[2:], &pos, auIndex, e.IndexLength)
 
 		// AU
 		copy(payload[2+auHeadersLenBytes:], au)
@@ -146,7 +219,12 @@ func (e *Encoder) lenGenericAggregated(aus [][]byte, addAU []byte) int {
 	return n
 }
 
-func (e *Encoder) writeGenericAggregated(aus [][]byte, timestamp uint32) ([]*rtp.Packet, error) {
+func (e *Encoder) writeGenericAggregated(
+	aus [][]byte,
+	timestamp uint32,
+	auIndex uint64,
+	auIndexDelta uint64,
+) ([]*rtp.Packet, error) {
 	payload := make([]byte, e.lenGenericAggregated(aus, nil))
 
 	// AU-headers
@@ -156,10 +234,10 @@ func (e *Encoder) writeGenericAggregated(aus [][]byte, timestamp uint32) ([]*rtp
 		bits.WriteBits(payload[2:], &pos, uint64(len(au)), e.SizeLength)
 		written += e.SizeLength
 		if i == 0 {
-			bits.WriteBits(payload[2:], &pos, 0, e.IndexLength)
+			bits.WriteBits(payload[2:], &pos, auIndex, e.IndexLength)
 			written += e.IndexLength
 		} else {
-			bits.WriteBits(payload[2:], &pos, 0, e.IndexDeltaLength)
+			bits.WriteBits(payload[2:], &pos, auIndexDelta, e.IndexDeltaLength)
 			written += e.IndexDeltaLength
 		}
 	}
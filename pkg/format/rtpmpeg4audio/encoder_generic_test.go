@@ -2,8 +2,10 @@ package rtpmpeg4audio
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
@@ -518,3 +520,141 @@ func TestEncodeGeneric(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeGenericADTS(t *testing.T) {
+	aus := [][]byte{
+		bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 8),
+		bytes.Repeat([]byte{0x05, 0x06, 0x07, 0x08}, 100),
+		bytes.Repeat([]byte{0x09, 0x0a, 0x0b, 0x0c}, 16),
+	}
+
+	var adts [][]byte
+
+	for _, au := range aus {
+		byts, err := mpeg4audio.ADTSPackets{{
+			Type:         mpeg4audio.ObjectTypeAACLC,
+			SampleRate:   48000,
+			ChannelCount: 2,
+			AU:           au,
+		}}.Marshal()
+		require.NoError(t, err)
+		adts = append(adts, byts)
+	}
+
+	e := &Encoder{
+		PayloadType:      96,
+		SizeLength:       13,
+		IndexLength:      3,
+		IndexDeltaLength: 3,
+		ADTS:             true,
+		PayloadMaxSize:   200,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode(adts)
+	require.NoError(t, err)
+
+	d := &Decoder{
+		SizeLength:       13,
+		IndexLength:      3,
+		IndexDeltaLength: 3,
+	}
+	err = d.Init()
+	require.NoError(t, err)
+
+	var decoded [][]byte
+	var timestamps []uint32
+
+	for _, pkt := range pkts {
+		daus, err := d.Decode(pkt)
+		if errors.Is(err, ErrMorePacketsNeeded) {
+			continue
+		}
+		require.NoError(t, err)
+		decoded = append(decoded, daus...)
+		timestamps = append(timestamps, pkt.Timestamp)
+	}
+
+	require.Equal(t, aus, decoded)
+	require.Equal(t, []uint32{0, 1024, 2048}, timestamps)
+}
+
+func TestEncodeGenericADTSError(t *testing.T) {
+	e := &Encoder{
+		PayloadType:      96,
+		SizeLength:       13,
+		IndexLength:      3,
+		IndexDeltaLength: 3,
+		ADTS:             true,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	_, err = e.Encode([][]byte{{0x01, 0x02, 0x03}})
+	require.Error(t, err)
+}
+
+func TestEncodeGenericInterleaved(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SizeLength:            13,
+		IndexLength:           3,
+		IndexDeltaLength:      3,
+		InterleavingDepth:     2,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{{0x01}, {0x02}, {0x03}, {0x04}})
+	require.NoError(t, err)
+
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      0,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{
+				0x00, 0x20, 0x00, 0x08, 0x00, 0x09, 0x01, 0x03,
+			},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      1024,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{
+				0x00, 0x20, 0x00, 0x09, 0x00, 0x09, 0x02, 0x04,
+			},
+		},
+	}, pkts)
+
+	// AU-index is incremented between calls
+	pkts, err = e.Encode([][]byte{{0x05}, {0x06}})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x10, 0x00, 0x0c, 0x05}, pkts[0].Payload)
+	require.Equal(t, []byte{0x00, 0x10, 0x00, 0x0d, 0x06}, pkts[1].Payload)
+}
+
+func TestEncodeGenericInterleavedInitError(t *testing.T) {
+	e := &Encoder{
+		PayloadType:       96,
+		SizeLength:        13,
+		IndexLength:       3,
+		IndexDeltaLength:  1,
+		InterleavingDepth: 3,
+	}
+	err := e.Init()
+	require.EqualError(t, err, "IndexLength and IndexDeltaLength are too small for an InterleavingDepth of 3")
+}
@@ -1,8 +1,11 @@
 package rtpmpeg4audio
 
 import (
+	"fmt"
+
 	"github.com/pion/rtp"
 
+	"github.com/bluenviron/mediacommon/pkg/bits"
 	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 )
 
@@ -16,6 +19,10 @@ func (e *Encoder) packetCountLATM(auLen int, plil int) int {
 }
 
 func (e *Encoder) encodeLATM(aus [][]byte) ([]*rtp.Packet, error) {
+	if e.streamMuxConfigEnc != nil {
+		return e.encodeLATMElements(aus)
+	}
+
 	var rets []*rtp.Packet
 
 	for i, au := range aus {
@@ -74,3 +81,85 @@ func (e *Encoder) encodeLATMSingle(au []byte, timestamp uint32) ([]*rtp.Packet,
 
 	return ret, nil
 }
+
+func (e *Encoder) encodeLATMElements(aus [][]byte) ([]*rtp.Packet, error) {
+	subFrameCount := int(e.StreamMuxConfig.NumSubFrames) + 1
+
+	if (len(aus) % subFrameCount) != 0 {
+		return nil, fmt.Errorf("AU count (%d) is not a multiple of subframe count (%d)",
+			len(aus), subFrameCount)
+	}
+
+	var rets []*rtp.Packet
+
+	for i := 0; i < len(aus); i += subFrameCount {
+		timestamp := uint32(i) * mpeg4audio.SamplesPerAccessUnit
+
+		add := e.encodeLATMElement(aus[i:i+subFrameCount], timestamp)
+		rets = append(rets, add...)
+	}
+
+	return rets, nil
+}
+
+func (e *Encoder) encodeLATMElement(aus [][]byte, timestamp uint32) []*rtp.Packet {
+	n := 0
+	if e.CPresent {
+		n += 1 + e.streamMuxConfigBits
+	}
+	for _, au := range aus {
+		n += (payloadLengthInfoEncodeSize(len(au)) + len(au)) * 8
+	}
+
+	buf := make([]byte, (n+7)/8)
+	pos := 0
+
+	if e.CPresent {
+		// useSameStreamMux = 0, in order to allow readers to join at any time
+		bits.WriteBits(buf, &pos, 0, 1)
+
+		for i := 0; i < e.streamMuxConfigBits; i++ {
+			bits.WriteBits(buf, &pos, uint64(e.streamMuxConfigEnc[i/8]>>(7-(i%8)))&1, 1)
+		}
+	}
+
+	for _, au := range aus {
+		plil := payloadLengthInfoEncodeSize(len(au))
+		pli := make([]byte, plil)
+		payloadLengthInfoEncode(plil, len(au), pli)
+
+		for _, b := range pli {
+			bits.WriteBits(buf, &pos, uint64(b), 8)
+		}
+		for _, b := range au {
+			bits.WriteBits(buf, &pos, uint64(b), 8)
+		}
+	}
+
+	packetCount := (len(buf) + e.PayloadMaxSize - 1) / e.PayloadMaxSize
+	ret := make([]*rtp.Packet, packetCount)
+
+	for i := range ret {
+		le := e.PayloadMaxSize
+		if le > len(buf) {
+			le = len(buf)
+		}
+
+		ret[i] = &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				Timestamp:      timestamp,
+				SSRC:           *e.SSRC,
+				Marker:         (i == packetCount-1),
+			},
+			Payload: buf[:le],
+		}
+		buf = buf[le:]
+
+		e.sequenceNumber++
+	}
+
+	return ret
+}
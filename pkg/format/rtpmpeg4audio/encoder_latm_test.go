@@ -2,8 +2,10 @@ package rtpmpeg4audio
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/mpeg4audio"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
 )
@@ -131,3 +133,111 @@ func TestEncodeLATM(t *testing.T) {
 		})
 	}
 }
+
+var testStreamMuxConfig = &mpeg4audio.StreamMuxConfig{
+	Programs: []*mpeg4audio.StreamMuxConfigProgram{{
+		Layers: []*mpeg4audio.StreamMuxConfigLayer{{
+			AudioSpecificConfig: &mpeg4audio.AudioSpecificConfig{
+				Type:         2,
+				SampleRate:   48000,
+				ChannelCount: 2,
+			},
+			LatmBufferFullness: 255,
+		}},
+	}},
+}
+
+func TestEncodeLATMSubFrames(t *testing.T) {
+	conf := *testStreamMuxConfig
+	conf.NumSubFrames = 1
+
+	e := &Encoder{
+		LATM:                  true,
+		StreamMuxConfig:       &conf,
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([][]byte{{1, 2}, {3}, {4, 5, 6}, {7}})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 0x44ed,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x02, 0x01, 0x02, 0x01, 0x03},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 0x44ee,
+				Timestamp:      2048,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x03, 0x04, 0x05, 0x06, 0x01, 0x07},
+		},
+	}, pkts)
+
+	_, err = e.Encode([][]byte{{1, 2}})
+	require.EqualError(t, err, "AU count (1) is not a multiple of subframe count (2)")
+}
+
+func TestEncodeLATMCPresent(t *testing.T) {
+	e := &Encoder{
+		LATM:        true,
+		CPresent:    true,
+		PayloadType: 96,
+	}
+	err := e.Init()
+	require.EqualError(t, err, "StreamMuxConfig is required when CPresent is true")
+
+	e = &Encoder{
+		LATM:            true,
+		CPresent:        true,
+		StreamMuxConfig: testStreamMuxConfig,
+		PayloadType:     96,
+		PayloadMaxSize:  100,
+	}
+	err = e.Init()
+	require.NoError(t, err)
+
+	aus := [][]byte{
+		bytes.Repeat([]byte{1, 2, 3}, 100),
+		{4, 5, 6},
+	}
+
+	pkts, err := e.Encode(aus)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(pkts))
+
+	d := &Decoder{
+		LATM:     true,
+		CPresent: true,
+	}
+	err = d.Init()
+	require.NoError(t, err)
+
+	var decoded [][]byte
+
+	for _, pkt := range pkts {
+		var tmp [][]byte
+		tmp, err = d.Decode(pkt)
+		if errors.Is(err, ErrMorePacketsNeeded) {
+			continue
+		}
+		require.NoError(t, err)
+		decoded = append(decoded, tmp...)
+	}
+
+	require.Equal(t, aus, decoded)
+	require.Equal(t, testStreamMuxConfig, d.StreamMuxConfig)
+}
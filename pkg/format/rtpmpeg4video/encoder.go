@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -46,11 +48,25 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -76,6 +92,17 @@ func (e *Encoder) Init() error {
 
 // Encode encodes a frame into RTP packets.
 func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frame []byte) ([]*rtp.Packet, error) {
 	avail := e.PayloadMaxSize
 	le := len(frame)
 	packetCount := packetCount(avail, le)
@@ -0,0 +1,147 @@
+package rtprawvideo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// ErrMorePacketsNeeded is returned when more packets are needed.
+var ErrMorePacketsNeeded = errors.New("need more packets")
+
+// ErrNonStartingPacketAndNoPrevious is returned when we received a non-starting
+// packet of a frame and we didn't received anything before.
+// It's normal to receive this when decoding a stream that has been already
+// running for some time.
+var ErrNonStartingPacketAndNoPrevious = errors.New(
+	"received a non-starting packet without any previous starting packet")
+
+type lineHeader struct {
+	length int
+	field  int
+	line   int
+	offset int // in pixels
+}
+
+// Decoder is a RTP/raw video decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+type Decoder struct {
+	// color sampling, e.g. "YCbCr-4:2:2".
+	Sampling string
+
+	// bit depth of each color component.
+	BitDepth int
+
+	// frame width.
+	Width int
+
+	// frame height.
+	Height int
+
+	// whether frames are interlaced.
+	Interlaced bool
+
+	layout              frameLayout
+	firstPacketReceived bool
+	frame               []byte
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return d.layout.init(d.Sampling, d.BitDepth, d.Width, d.Height, d.Interlaced)
+}
+
+func (d *Decoder) parseHeaders(payload []byte) ([]lineHeader, []byte, error) {
+	if len(payload) < 2 {
+		return nil, nil, fmt.Errorf("payload is too short")
+	}
+	payload = payload[2:] // extended sequence number
+
+	var headers []lineHeader
+
+	for {
+		if len(payload) < lineHeaderSize {
+			return nil, nil, fmt.Errorf("payload is too short")
+		}
+
+		h := lineHeader{
+			length: int(payload[0])<<8 | int(payload[1]),
+			field:  int(payload[2] >> 7),
+			line:   int(payload[2]&0x7F)<<8 | int(payload[3]),
+			offset: int(payload[4]&0x7F)<<8 | int(payload[5]),
+		}
+		continuation := (payload[4] >> 7) != 0
+		payload = payload[lineHeaderSize:]
+
+		if h.field != 0 && !d.Interlaced {
+			return nil, nil, fmt.Errorf("received a second field in a progressive stream")
+		}
+
+		if h.line >= d.layout.lineCount {
+			return nil, nil, fmt.Errorf("invalid line number: %d", h.line)
+		}
+
+		if (h.offset%d.layout.pgroupPixels) != 0 ||
+			(h.length%d.layout.pgroupSize) != 0 ||
+			((h.offset/d.layout.pgroupPixels)*d.layout.pgroupSize+h.length) > d.layout.lineSize {
+			return nil, nil, fmt.Errorf("invalid segment")
+		}
+
+		headers = append(headers, h)
+
+		if !continuation {
+			break
+		}
+	}
+
+	return headers, payload, nil
+}
+
+// Decode decodes a frame from RTP packets.
+// The frame is made of lines of pixel groups, from top to bottom.
+// When frames are interlaced, the two fields are merged into a single frame.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	headers, data, err := d.parseHeaders(pkt.Payload)
+	if err != nil {
+		d.frame = nil // discard pending frame
+		return nil, err
+	}
+
+	if headers[0].field == 0 && headers[0].line == 0 && headers[0].offset == 0 {
+		d.frame = make([]byte, d.layout.lineSize*d.Height)
+		d.firstPacketReceived = true
+	} else if d.frame == nil {
+		if !d.firstPacketReceived {
+			return nil, ErrNonStartingPacketAndNoPrevious
+		}
+
+		return nil, fmt.Errorf("received a non-starting packet")
+	}
+
+	fieldCount := 1
+	if d.Interlaced {
+		fieldCount = 2
+	}
+
+	for _, h := range headers {
+		if len(data) < h.length {
+			d.frame = nil // discard pending frame
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		pos := (h.line*fieldCount+h.field)*d.layout.lineSize + (h.offset/d.layout.pgroupPixels)*d.layout.pgroupSize
+		copy(d.frame[pos:], data[:h.length])
+		data = data[h.length:]
+	}
+
+	// with interlaced frames, the marker bit is set at the end of each field.
+	if !pkt.Marker || headers[len(headers)-1].field != (fieldCount-1) {
+		return nil, ErrMorePacketsNeeded
+	}
+
+	frame := d.frame
+	d.frame = nil
+
+	return frame, nil
+}
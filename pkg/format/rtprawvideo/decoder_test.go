@@ -0,0 +1,87 @@
+package rtprawvideo
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				Sampling:   ca.sampling,
+				BitDepth:   ca.bitDepth,
+				Width:      ca.width,
+				Height:     ca.height,
+				Interlaced: ca.interlaced,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var frame []byte
+
+			for i, pkt := range ca.pkts {
+				frame, err = d.Decode(pkt)
+				if i != (len(ca.pkts) - 1) {
+					require.Equal(t, ErrMorePacketsNeeded, err)
+				}
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, ca.frame, frame)
+		})
+	}
+}
+
+func TestDecodeNonStarting(t *testing.T) {
+	d := &Decoder{
+		Sampling: "YCbCr-4:2:2",
+		BitDepth: 10,
+		Width:    4,
+		Height:   2,
+	}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, err = d.Decode(cases[1].pkts[1])
+	require.Equal(t, ErrNonStartingPacketAndNoPrevious, err)
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool, interlaced bool) {
+		d := &Decoder{
+			Sampling:   "YCbCr-4:2:2",
+			BitDepth:   10,
+			Width:      4,
+			Height:     4,
+			Interlaced: interlaced,
+		}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         am,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         bm,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
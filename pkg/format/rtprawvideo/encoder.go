@@ -0,0 +1,225 @@
+package rtprawvideo
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
+)
+
+const (
+	rtpVersion            = 2
+	defaultPayloadMaxSize = 1460 // 1500 (UDP MTU) - 20 (IP header) - 8 (UDP header) - 12 (RTP header)
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+type segment struct {
+	line   int
+	offset int // in bytes
+	size   int
+}
+
+// Encoder is a RTP/raw video encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4175
+type Encoder struct {
+	// payload type of packets.
+	PayloadType uint8
+
+	// color sampling, e.g. "YCbCr-4:2:2".
+	Sampling string
+
+	// bit depth of each color component.
+	BitDepth int
+
+	// frame width.
+	Width int
+
+	// frame height.
+	Height int
+
+	// whether frames are interlaced.
+	Interlaced bool
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	// maximum size of packet payloads (optional).
+	// It defaults to 1460.
+	PayloadMaxSize int
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
+	layout         frameLayout
+	sequenceNumber uint32
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.PayloadMaxSize == 0 {
+		e.PayloadMaxSize = defaultPayloadMaxSize
+	}
+
+	err = e.layout.init(e.Sampling, e.BitDepth, e.Width, e.Height, e.Interlaced)
+	if err != nil {
+		return err
+	}
+
+	if e.PayloadMaxSize < (2 + lineHeaderSize + e.layout.pgroupSize) {
+		return fmt.Errorf("payload max size is too small")
+	}
+
+	e.sequenceNumber = uint32(*e.InitialSequenceNumber)
+	return nil
+}
+
+// Encode encodes a frame into RTP packets.
+// The frame is made of lines of pixel groups, from top to bottom.
+// When frames are interlaced, the first field (even lines) is encoded first,
+// followed by the second field (odd lines); the last packet of each field
+// has the marker bit set.
+func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frame []byte) ([]*rtp.Packet, error) {
+	fieldCount := 1
+	if e.Interlaced {
+		fieldCount = 2
+	}
+
+	if len(frame) != e.layout.lineSize*e.layout.lineCount*fieldCount {
+		return nil, fmt.Errorf("invalid frame size: %d", len(frame))
+	}
+
+	var ret []*rtp.Packet
+
+	for field := 0; field < fieldCount; field++ {
+		ret = append(ret, e.encodeField(frame, field, fieldCount)...)
+	}
+
+	return ret, nil
+}
+
+func (e *Encoder) encodeField(frame []byte, field int, fieldCount int) []*rtp.Packet {
+	var ret []*rtp.Packet
+	line := 0
+	offset := 0
+
+	for line < e.layout.lineCount {
+		var segments []segment
+		avail := e.PayloadMaxSize - 2
+		payloadSize := 2
+
+		// fill the packet with segments, that may span multiple lines
+		for line < e.layout.lineCount {
+			size := ((avail - lineHeaderSize) / e.layout.pgroupSize) * e.layout.pgroupSize
+			if size <= 0 {
+				break
+			}
+
+			if rem := e.layout.lineSize - offset; size > rem {
+				size = rem
+			}
+
+			segments = append(segments, segment{line: line, offset: offset, size: size})
+			avail -= lineHeaderSize + size
+			payloadSize += lineHeaderSize + size
+
+			offset += size
+			if offset == e.layout.lineSize {
+				line++
+				offset = 0
+			}
+		}
+
+		payload := make([]byte, payloadSize)
+		payload[0] = byte(e.sequenceNumber >> 24)
+		payload[1] = byte(e.sequenceNumber >> 16)
+		n := 2
+
+		for i, seg := range segments {
+			payload[n] = byte(seg.size >> 8)
+			payload[n+1] = byte(seg.size)
+			payload[n+2] = byte(field<<7) | byte(seg.line>>8)
+			payload[n+3] = byte(seg.line)
+
+			pixelOffset := (seg.offset / e.layout.pgroupSize) * e.layout.pgroupPixels
+			payload[n+4] = byte(pixelOffset >> 8)
+			if i != (len(segments) - 1) {
+				payload[n+4] |= 0x80
+			}
+			payload[n+5] = byte(pixelOffset)
+
+			n += lineHeaderSize
+		}
+
+		for _, seg := range segments {
+			pos := (seg.line*fieldCount+field)*e.layout.lineSize + seg.offset
+			n += copy(payload[n:], frame[pos:pos+seg.size])
+		}
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: uint16(e.sequenceNumber),
+				SSRC:           *e.SSRC,
+				Marker:         line == e.layout.lineCount,
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+	}
+
+	return ret
+}
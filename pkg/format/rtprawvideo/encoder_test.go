@@ -0,0 +1,268 @@
+package rtprawvideo
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+var cases = []struct {
+	name           string
+	sampling       string
+	bitDepth       int
+	width          int
+	height         int
+	interlaced     bool
+	payloadMaxSize int
+	frame          []byte
+	pkts           []*rtp.Packet
+}{
+	{
+		"progressive, multiple lines",
+		"YCbCr-4:2:2",
+		10,
+		4,
+		2,
+		false,
+		1460,
+		[]byte{
+			0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a,
+			0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14,
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x0a, 0x00, 0x00, 0x80, 0x00,
+					0x00, 0x0a, 0x00, 0x01, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a,
+					0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14,
+				},
+			},
+		},
+	},
+	{
+		"progressive, fragmented",
+		"YCbCr-4:2:2",
+		10,
+		4,
+		2,
+		false,
+		13,
+		[]byte{
+			0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a,
+			0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10, 0x11, 0x12, 0x13, 0x14,
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x00, 0x00, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x00, 0x00, 0x00, 0x02,
+					0x06, 0x07, 0x08, 0x09, 0x0a,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    96,
+					SequenceNumber: 17647,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x00, 0x01, 0x00, 0x00,
+					0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17648,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x00, 0x01, 0x00, 0x02,
+					0x10, 0x11, 0x12, 0x13, 0x14,
+				},
+			},
+		},
+	},
+	{
+		"interlaced",
+		"YCbCr-4:2:2",
+		10,
+		2,
+		4,
+		true,
+		1460,
+		[]byte{
+			0x01, 0x02, 0x03, 0x04, 0x05,
+			0x06, 0x07, 0x08, 0x09, 0x0a,
+			0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+			0x10, 0x11, 0x12, 0x13, 0x14,
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x00, 0x00, 0x80, 0x00,
+					0x00, 0x05, 0x00, 0x01, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05,
+					0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17646,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x05, 0x80, 0x00, 0x80, 0x00,
+					0x00, 0x05, 0x80, 0x01, 0x00, 0x00,
+					0x06, 0x07, 0x08, 0x09, 0x0a,
+					0x10, 0x11, 0x12, 0x13, 0x14,
+				},
+			},
+		},
+	},
+	{
+		"rgb 8 bit",
+		"RGB",
+		8,
+		2,
+		1,
+		false,
+		1460,
+		[]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    96,
+					SequenceNumber: 17645,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0x00, 0x00,
+					0x00, 0x06, 0x00, 0x00, 0x00, 0x00,
+					0x01, 0x02, 0x03, 0x04, 0x05, 0x06,
+				},
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           96,
+				Sampling:              ca.sampling,
+				BitDepth:              ca.bitDepth,
+				Width:                 ca.width,
+				Height:                ca.height,
+				Interlaced:            ca.interlaced,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+				PayloadMaxSize:        ca.payloadMaxSize,
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			pkts, err := e.Encode(ca.frame)
+			require.NoError(t, err)
+			require.Equal(t, ca.pkts, pkts)
+		})
+	}
+}
+
+func TestEncodeExtendedSequenceNumber(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		Sampling:              "RGB",
+		BitDepth:              8,
+		Width:                 1,
+		Height:                1,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0xffff),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([]byte{0x01, 0x02, 0x03})
+	require.NoError(t, err)
+	require.Equal(t, uint16(0xffff), pkts[0].SequenceNumber)
+	require.Equal(t, []byte{0x00, 0x00}, pkts[0].Payload[:2])
+
+	pkts, err = e.Encode([]byte{0x01, 0x02, 0x03})
+	require.NoError(t, err)
+	require.Equal(t, uint16(0), pkts[0].SequenceNumber)
+	require.Equal(t, []byte{0x00, 0x01}, pkts[0].Payload[:2])
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 96,
+		Sampling:    "YCbCr-4:2:2",
+		BitDepth:    10,
+		Width:       1920,
+		Height:      1080,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
@@ -0,0 +1,91 @@
+// Package rtprawvideo contains a RTP/raw video decoder and encoder.
+package rtprawvideo
+
+import (
+	"fmt"
+)
+
+const (
+	// size of a line header.
+	lineHeaderSize = 6
+)
+
+// pgroup returns the size in bytes and the width in pixels of a pixel group.
+func pgroup(sampling string, bitDepth int) (int, int, error) {
+	switch sampling {
+	case "YCbCr-4:2:2":
+		switch bitDepth {
+		case 8:
+			return 4, 2, nil
+		case 10:
+			return 5, 2, nil
+		case 12:
+			return 6, 2, nil
+		case 16:
+			return 8, 2, nil
+		}
+
+	case "YCbCr-4:4:4", "RGB", "BGR":
+		switch bitDepth {
+		case 8:
+			return 3, 1, nil
+		case 10:
+			return 15, 4, nil
+		case 12:
+			return 9, 2, nil
+		case 16:
+			return 6, 1, nil
+		}
+
+	case "RGBA", "BGRA":
+		switch bitDepth {
+		case 8:
+			return 4, 1, nil
+		case 10:
+			return 5, 1, nil
+		case 12:
+			return 6, 1, nil
+		case 16:
+			return 8, 1, nil
+		}
+
+	default:
+		return 0, 0, fmt.Errorf("unsupported sampling: %v", sampling)
+	}
+
+	return 0, 0, fmt.Errorf("unsupported bit depth: %v", bitDepth)
+}
+
+// frameLayout is the layout of a frame, shared by the decoder and the encoder.
+type frameLayout struct {
+	pgroupSize   int
+	pgroupPixels int
+	lineSize     int
+	lineCount    int // lines of each field
+}
+
+func (l *frameLayout) init(sampling string, bitDepth int, width int, height int, interlaced bool) error {
+	var err error
+	l.pgroupSize, l.pgroupPixels, err = pgroup(sampling, bitDepth)
+	if err != nil {
+		return err
+	}
+
+	if width <= 0 || width > 0x7FFF || (width%l.pgroupPixels) != 0 {
+		return fmt.Errorf("invalid width: %d", width)
+	}
+
+	if height <= 0 || height > 0x7FFF || (interlaced && (height%2) != 0) {
+		return fmt.Errorf("invalid height: %d", height)
+	}
+
+	l.lineSize = (width / l.pgroupPixels) * l.pgroupSize
+
+	if interlaced {
+		l.lineCount = height / 2
+	} else {
+		l.lineCount = height
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package rtpred
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+type blockHeader struct {
+	payloadType     uint8
+	timestampOffset uint32
+	length          int
+}
+
+// Decoder is a RTP/RED decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc2198
+type Decoder struct{}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	return nil
+}
+
+// Decode decodes the blocks contained in a RED packet.
+// It returns a RTP packet for each block, ordered from the oldest redundant block
+// to the primary block, which is always the last one.
+// Timestamps of redundant blocks are computed from their timestamp offset.
+// Sequence numbers of redundant blocks are inferred by assuming that
+// they carry the packets that immediately precede the primary one.
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]*rtp.Packet, error) {
+	payload := pkt.Payload
+	var headers []blockHeader
+
+	for {
+		if len(payload) < 1 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		// primary block
+		if (payload[0] & 0x80) == 0 {
+			headers = append(headers, blockHeader{
+				payloadType: payload[0] & 0x7F,
+			})
+			payload = payload[1:]
+			break
+		}
+
+		if len(payload) < 4 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		headers = append(headers, blockHeader{
+			payloadType:     payload[0] & 0x7F,
+			timestampOffset: uint32(payload[1])<<6 | uint32(payload[2])>>2,
+			length:          int(payload[2]&0x03)<<8 | int(payload[3]),
+		})
+		payload = payload[4:]
+	}
+
+	redundantCount := len(headers) - 1
+	ret := make([]*rtp.Packet, len(headers))
+
+	for i, bh := range headers {
+		h := pkt.Header
+		h.PayloadType = bh.payloadType
+		h.Padding = false
+
+		if i == redundantCount {
+			ret[i] = &rtp.Packet{
+				Header:  h,
+				Payload: payload,
+			}
+			break
+		}
+
+		if len(payload) < bh.length {
+			return nil, fmt.Errorf("payload is too short")
+		}
+
+		h.Marker = false
+		h.Timestamp -= bh.timestampOffset
+		h.SequenceNumber -= uint16(redundantCount - i)
+
+		ret[i] = &rtp.Packet{
+			Header:  h,
+			Payload: payload[:bh.length],
+		}
+		payload = payload[bh.length:]
+	}
+
+	return ret, nil
+}
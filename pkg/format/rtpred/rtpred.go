@@ -0,0 +1,2 @@
+// Package rtpred contains a RTP/RED decoder.
+package rtpred
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -38,11 +40,25 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is not set.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -85,5 +101,7 @@ func (e *Encoder) Encode(frame []byte) (*rtp.Packet, error) {
 
 	e.sequenceNumber++
 
+	rtpoptions.Apply([]*rtp.Packet{pkt}, e.MarkerFunc, e.PaddingAlignment)
+
 	return pkt, nil
 }
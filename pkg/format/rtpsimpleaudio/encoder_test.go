@@ -54,6 +54,35 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+func TestEncodeMarkerAndPadding(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           0,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		MarkerFunc: func(_ int, _ int, _ bool) bool {
+			return true
+		},
+		PaddingAlignment: 16,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkt, err := e.Encode([]byte{0x01, 0x02, 0x03, 0x04})
+	require.NoError(t, err)
+	require.Equal(t, &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Padding:        true,
+			Marker:         true,
+			PayloadType:    0,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload:     []byte{0x01, 0x02, 0x03, 0x04},
+		PaddingSize: 12,
+	}, pkt)
+}
+
 func TestEncodeRandomInitialState(t *testing.T) {
 	e := &Encoder{
 		PayloadType: 0,
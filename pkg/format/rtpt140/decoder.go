@@ -0,0 +1,85 @@
+package rtpt140
+
+import (
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtpred"
+)
+
+// missing text marker (U+FFFD), inserted in place of text that can't be recovered.
+var missingTextMarker = []byte{0xEF, 0xBF, 0xBD}
+
+// Decoder is a RTP/T.140 decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type Decoder struct {
+	// payload type of RED packets (optional).
+	// If set, redundant text is used to recover lost packets.
+	REDPayloadType *uint8
+
+	redDecoder          *rtpred.Decoder
+	firstPacketReceived bool
+	expectedSeqNum      uint16
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	if d.REDPayloadType != nil {
+		d.redDecoder = &rtpred.Decoder{}
+		return d.redDecoder.Init()
+	}
+	return nil
+}
+
+// Decode decodes text from a RTP packet.
+// Packets without text (keep-alives) produce empty text.
+// Text of lost packets that can't be recovered from redundant data is replaced
+// by the missing text marker (U+FFFD).
+func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	blocks := []*rtp.Packet{pkt}
+
+	if d.REDPayloadType != nil && pkt.PayloadType == *d.REDPayloadType {
+		var err error
+		blocks, err = d.redDecoder.Decode(pkt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	primary := blocks[len(blocks)-1]
+
+	if !d.firstPacketReceived {
+		d.firstPacketReceived = true
+		d.expectedSeqNum = pkt.SequenceNumber + 1
+		return primary.Payload, nil
+	}
+
+	lost := int16(pkt.SequenceNumber - d.expectedSeqNum)
+
+	// packet is a duplicate or has been already recovered
+	if lost < 0 {
+		return nil, nil
+	}
+
+	d.expectedSeqNum = pkt.SequenceNumber + 1
+
+	if lost == 0 {
+		return primary.Payload, nil
+	}
+
+	var text []byte
+	redundant := blocks[:len(blocks)-1]
+
+	if int(lost) > len(redundant) {
+		text = append(text, missingTextMarker...)
+	} else {
+		redundant = redundant[len(redundant)-int(lost):]
+	}
+
+	for _, b := range redundant {
+		text = append(text, b.Payload...)
+	}
+
+	text = append(text, primary.Payload...)
+
+	return text, nil
+}
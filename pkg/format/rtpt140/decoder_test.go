@@ -0,0 +1,115 @@
+package rtpt140
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				REDPayloadType: ca.redPayloadType,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var text []byte
+			var expected string
+
+			for i, pkt := range ca.pkts {
+				partial, err := d.Decode(pkt)
+				require.NoError(t, err)
+				text = append(text, partial...)
+				expected += ca.texts[i].text
+			}
+
+			require.Equal(t, expected, string(text))
+		})
+	}
+}
+
+func TestDecodeLost(t *testing.T) {
+	for _, ca := range []struct {
+		name           string
+		redPayloadType *uint8
+		pkts           []*rtp.Packet
+		text           string
+	}{
+		{
+			"plain, not recovered",
+			nil,
+			[]*rtp.Packet{cases[0].pkts[0], cases[0].pkts[2]},
+			"hello�",
+		},
+		{
+			"red, one packet lost",
+			uint8Ptr(100),
+			[]*rtp.Packet{cases[1].pkts[0], cases[1].pkts[2], cases[1].pkts[3]},
+			"ab",
+		},
+		{
+			"red, two packets lost",
+			uint8Ptr(100),
+			[]*rtp.Packet{cases[1].pkts[0], cases[1].pkts[3]},
+			"ab",
+		},
+		{
+			"red, reordered",
+			uint8Ptr(100),
+			[]*rtp.Packet{cases[1].pkts[0], cases[1].pkts[2], cases[1].pkts[1], cases[1].pkts[3]},
+			"ab",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			d := &Decoder{
+				REDPayloadType: ca.redPayloadType,
+			}
+			err := d.Init()
+			require.NoError(t, err)
+
+			var text []byte
+
+			for _, pkt := range ca.pkts {
+				partial, err := d.Decode(pkt)
+				require.NoError(t, err)
+				text = append(text, partial...)
+			}
+
+			require.Equal(t, ca.text, string(text))
+		})
+	}
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte, b []byte, bs uint16) {
+		d := &Decoder{
+			REDPayloadType: uint8Ptr(100),
+		}
+		d.Init() //nolint:errcheck
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    100,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    100,
+				SequenceNumber: bs,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: b,
+		})
+	})
+}
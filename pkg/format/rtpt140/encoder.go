@@ -0,0 +1,186 @@
+package rtpt140
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	rtpVersion                   = 2
+	defaultRedundancyGenerations = 2
+)
+
+func randUint32() (uint32, error) {
+	var b [4]byte
+	_, err := rand.Read(b[:])
+	if err != nil {
+		return 0, err
+	}
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3]), nil
+}
+
+type block struct {
+	timestamp uint32
+	payload   []byte
+}
+
+// Encoder is a RTP/T.140 encoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type Encoder struct {
+	// payload type of T.140 packets.
+	PayloadType uint8
+
+	// payload type of RED packets (optional).
+	// If set, packets carry redundant text of previous packets.
+	REDPayloadType *uint8
+
+	// number of redundant generations (optional).
+	// It defaults to 2.
+	RedundancyGenerations int
+
+	// SSRC of packets (optional).
+	// It defaults to a random value.
+	SSRC *uint32
+
+	// initial sequence number of packets (optional).
+	// It defaults to a random value.
+	InitialSequenceNumber *uint16
+
+	sequenceNumber uint16
+	history        []block
+	idle           bool
+}
+
+// Init initializes the encoder.
+func (e *Encoder) Init() error {
+	if e.SSRC == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		e.SSRC = &v
+	}
+	if e.InitialSequenceNumber == nil {
+		v, err := randUint32()
+		if err != nil {
+			return err
+		}
+		v2 := uint16(v)
+		e.InitialSequenceNumber = &v2
+	}
+	if e.RedundancyGenerations == 0 {
+		e.RedundancyGenerations = defaultRedundancyGenerations
+	}
+
+	e.sequenceNumber = *e.InitialSequenceNumber
+	e.idle = true
+	return nil
+}
+
+// PendingRedundancy returns whether text of previous packets still has to be
+// sent as redundant data.
+// RFC4103 requires the sender to keep sending packets, eventually with empty text,
+// until all text has been sent in every redundant generation.
+func (e *Encoder) PendingRedundancy() bool {
+	if e.REDPayloadType == nil {
+		return false
+	}
+
+	for _, b := range e.history {
+		if len(b.payload) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Encode encodes text into a RTP packet.
+// Text can be empty: in this case the packet carries only redundant data
+// of previous packets, or acts as keep-alive.
+func (e *Encoder) Encode(text []byte, timestamp uint32) (*rtp.Packet, error) {
+	// RFC4103: the marker bit is set in the first packet after an idle period.
+	marker := false
+	if len(text) != 0 {
+		marker = e.idle
+		e.idle = false
+	} else {
+		e.idle = true
+	}
+
+	var payloadType uint8
+	var payload []byte
+
+	if e.REDPayloadType == nil {
+		payloadType = e.PayloadType
+		payload = text
+	} else {
+		if len(text) > maxBlockSize {
+			return nil, fmt.Errorf("text is too big")
+		}
+
+		payloadType = *e.REDPayloadType
+		payload = e.marshalRED(text, timestamp)
+
+		e.history = append(e.history, block{timestamp: timestamp, payload: text})
+		if len(e.history) > e.RedundancyGenerations {
+			e.history = e.history[1:]
+		}
+	}
+
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        rtpVersion,
+			PayloadType:    payloadType,
+			SequenceNumber: e.sequenceNumber,
+			Timestamp:      timestamp,
+			SSRC:           *e.SSRC,
+			Marker:         marker,
+		},
+		Payload: payload,
+	}
+
+	e.sequenceNumber++
+
+	return pkt, nil
+}
+
+func (e *Encoder) marshalRED(text []byte, timestamp uint32) []byte {
+	n := len(e.history)*4 + 1 + len(text)
+	for _, b := range e.history {
+		n += len(b.payload)
+	}
+
+	payload := make([]byte, n)
+	pos := 0
+
+	for i, b := range e.history {
+		offset := timestamp - b.timestamp
+
+		// redundant text that is too old can't be sent anymore.
+		if offset > maxTimestampOffset {
+			offset = maxTimestampOffset
+			e.history[i].payload = nil
+		}
+
+		length := len(e.history[i].payload)
+
+		payload[pos] = 0x80 | e.PayloadType
+		payload[pos+1] = byte(offset >> 6)
+		payload[pos+2] = byte(offset<<2) | byte(length>>8)
+		payload[pos+3] = byte(length)
+		pos += 4
+	}
+
+	payload[pos] = e.PayloadType
+	pos++
+
+	for _, b := range e.history {
+		pos += copy(payload[pos:], b.payload)
+	}
+
+	pos += copy(payload[pos:], text)
+
+	return payload[:pos]
+}
@@ -0,0 +1,204 @@
+package rtpt140
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func uint8Ptr(v uint8) *uint8 {
+	return &v
+}
+
+func uint16Ptr(v uint16) *uint16 {
+	return &v
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+type textEntry struct {
+	text      string
+	timestamp uint32
+}
+
+var cases = []struct {
+	name           string
+	redPayloadType *uint8
+	texts          []textEntry
+	pkts           []*rtp.Packet
+}{
+	{
+		"plain",
+		nil,
+		[]textEntry{
+			{"hello", 1000},
+			{" world", 1300},
+			{"", 1600},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    98,
+					SequenceNumber: 17645,
+					Timestamp:      1000,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte("hello"),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    98,
+					SequenceNumber: 17646,
+					Timestamp:      1300,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte(" world"),
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    98,
+					SequenceNumber: 17647,
+					Timestamp:      1600,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{},
+			},
+		},
+	},
+	{
+		"red",
+		uint8Ptr(100),
+		[]textEntry{
+			{"a", 1000},
+			{"b", 1300},
+			{"", 1600},
+			{"", 1900},
+		},
+		[]*rtp.Packet{
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         true,
+					PayloadType:    100,
+					SequenceNumber: 17645,
+					Timestamp:      1000,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{0x62, 'a'},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    100,
+					SequenceNumber: 17646,
+					Timestamp:      1300,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0xe2, 0x04, 0xb0, 0x01,
+					0x62,
+					'a', 'b',
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    100,
+					SequenceNumber: 17647,
+					Timestamp:      1600,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0xe2, 0x09, 0x60, 0x01,
+					0xe2, 0x04, 0xb0, 0x01,
+					0x62,
+					'a', 'b',
+				},
+			},
+			{
+				Header: rtp.Header{
+					Version:        2,
+					Marker:         false,
+					PayloadType:    100,
+					SequenceNumber: 17648,
+					Timestamp:      1900,
+					SSRC:           0x9dbb7812,
+				},
+				Payload: []byte{
+					0xe2, 0x09, 0x60, 0x01,
+					0xe2, 0x04, 0xb0, 0x00,
+					0x62,
+					'b',
+				},
+			},
+		},
+	},
+}
+
+func TestEncode(t *testing.T) {
+	for _, ca := range cases {
+		t.Run(ca.name, func(t *testing.T) {
+			e := &Encoder{
+				PayloadType:           98,
+				REDPayloadType:        ca.redPayloadType,
+				SSRC:                  uint32Ptr(0x9dbb7812),
+				InitialSequenceNumber: uint16Ptr(0x44ed),
+			}
+			err := e.Init()
+			require.NoError(t, err)
+
+			var pkts []*rtp.Packet
+
+			for _, entry := range ca.texts {
+				pkt, err := e.Encode([]byte(entry.text), entry.timestamp)
+				require.NoError(t, err)
+				pkts = append(pkts, pkt)
+			}
+
+			require.Equal(t, ca.pkts, pkts)
+			require.Equal(t, false, e.PendingRedundancy())
+		})
+	}
+}
+
+func TestEncodePendingRedundancy(t *testing.T) {
+	e := &Encoder{
+		PayloadType:    98,
+		REDPayloadType: uint8Ptr(100),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	_, err = e.Encode([]byte("a"), 1000)
+	require.NoError(t, err)
+	require.Equal(t, true, e.PendingRedundancy())
+
+	_, err = e.Encode(nil, 1300)
+	require.NoError(t, err)
+	require.Equal(t, true, e.PendingRedundancy())
+
+	_, err = e.Encode(nil, 1600)
+	require.NoError(t, err)
+	require.Equal(t, false, e.PendingRedundancy())
+}
+
+func TestEncodeRandomInitialState(t *testing.T) {
+	e := &Encoder{
+		PayloadType: 98,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+	require.NotEqual(t, nil, e.SSRC)
+	require.NotEqual(t, nil, e.InitialSequenceNumber)
+}
@@ -0,0 +1,10 @@
+// Package rtpt140 contains a RTP/T.140 decoder and encoder.
+package rtpt140
+
+const (
+	// maximum size of a redundant block, imposed by the length field of RED headers.
+	maxBlockSize = 1023
+
+	// maximum timestamp offset of a redundant block, imposed by the offset field of RED headers.
+	maxTimestampOffset = 0x3FFF
+)
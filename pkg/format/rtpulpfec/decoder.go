@@ -0,0 +1,168 @@
+package rtpulpfec
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+const (
+	// mask of the long FEC level header covers 48 packets.
+	bufferSize = 64
+)
+
+// ErrNothingToRecover is returned when a FEC packet can't be used to recover
+// a packet, since none or more than one of the protected packets are missing.
+var ErrNothingToRecover = errors.New("nothing to recover")
+
+// Decoder is a RTP/ULPFEC decoder.
+// Specification: https://datatracker.ietf.org/doc/html/rfc5109
+type Decoder struct {
+	buffer []*rtp.Packet
+}
+
+// Init initializes the decoder.
+func (d *Decoder) Init() error {
+	d.buffer = make([]*rtp.Packet, bufferSize)
+	return nil
+}
+
+// ProcessMedia stores a media packet, in order to use it to recover missing packets.
+func (d *Decoder) ProcessMedia(pkt *rtp.Packet) {
+	d.buffer[pkt.SequenceNumber&(bufferSize-1)] = pkt
+}
+
+func (d *Decoder) find(seqNum uint16) *rtp.Packet {
+	pkt := d.buffer[seqNum&(bufferSize-1)]
+	if pkt == nil || pkt.SequenceNumber != seqNum {
+		return nil
+	}
+	return pkt
+}
+
+// Decode decodes a FEC packet.
+// If exactly one of the packets protected by the FEC packet is missing,
+// it is recovered and returned.
+func (d *Decoder) Decode(fecPkt *rtp.Packet) (*rtp.Packet, error) {
+	buf := fecPkt.Payload
+
+	if len(buf) < 14 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	if (buf[0] >> 7) != 0 {
+		return nil, fmt.Errorf("FEC header extensions are not supported")
+	}
+
+	longMask := ((buf[0] >> 6) & 0x01) == 1
+	recoveryPXCC := buf[0] & 0x3F
+	recoveryMPT := buf[1]
+	snBase := uint16(buf[2])<<8 | uint16(buf[3])
+	recoveryTS := uint32(buf[4])<<24 | uint32(buf[5])<<16 | uint32(buf[6])<<8 | uint32(buf[7])
+	recoveryLength := uint16(buf[8])<<8 | uint16(buf[9])
+	protectionLength := int(buf[10])<<8 | int(buf[11])
+	buf = buf[12:]
+
+	var mask uint64
+	var maskLen int
+
+	if longMask {
+		if len(buf) < 6 {
+			return nil, fmt.Errorf("payload is too short")
+		}
+		mask = uint64(buf[0])<<40 | uint64(buf[1])<<32 | uint64(buf[2])<<24 |
+			uint64(buf[3])<<16 | uint64(buf[4])<<8 | uint64(buf[5])
+		maskLen = 48
+		buf = buf[6:]
+	} else {
+		mask = uint64(buf[0])<<8 | uint64(buf[1])
+		maskLen = 16
+		buf = buf[2:]
+	}
+
+	if len(buf) < protectionLength {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	var received []*rtp.Packet
+	missingCount := 0
+	var missingSeqNum uint16
+
+	for i := 0; i < maskLen; i++ {
+		if ((mask >> (maskLen - 1 - i)) & 0x01) == 0 {
+			continue
+		}
+
+		seqNum := snBase + uint16(i)
+		pkt := d.find(seqNum)
+		if pkt == nil {
+			missingCount++
+			missingSeqNum = seqNum
+		} else {
+			received = append(received, pkt)
+		}
+	}
+
+	if missingCount != 1 {
+		return nil, ErrNothingToRecover
+	}
+
+	payload := make([]byte, protectionLength)
+	copy(payload, buf[:protectionLength])
+
+	for _, pkt := range received {
+		byts, err := pkt.Marshal()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryPXCC ^= byts[0] & 0x3F
+		recoveryMPT ^= byts[1]
+		recoveryTS ^= pkt.Timestamp
+		recoveryLength ^= uint16(len(byts) - 12)
+
+		byts = byts[12:]
+		if len(byts) > protectionLength {
+			byts = byts[:protectionLength]
+		}
+
+		for i, b := range byts {
+			payload[i] ^= b
+		}
+	}
+
+	if int(recoveryLength) > protectionLength {
+		return nil, fmt.Errorf("recovered packet is larger than the protection length")
+	}
+
+	ssrc := fecPkt.SSRC
+	if len(received) != 0 {
+		ssrc = received[0].SSRC
+	}
+
+	byts := make([]byte, 12+int(recoveryLength))
+	byts[0] = 0x80 | recoveryPXCC
+	byts[1] = recoveryMPT
+	byts[2] = byte(missingSeqNum >> 8)
+	byts[3] = byte(missingSeqNum)
+	byts[4] = byte(recoveryTS >> 24)
+	byts[5] = byte(recoveryTS >> 16)
+	byts[6] = byte(recoveryTS >> 8)
+	byts[7] = byte(recoveryTS)
+	byts[8] = byte(ssrc >> 24)
+	byts[9] = byte(ssrc >> 16)
+	byts[10] = byte(ssrc >> 8)
+	byts[11] = byte(ssrc)
+	copy(byts[12:], payload)
+
+	var pkt rtp.Packet
+	err := pkt.Unmarshal(byts)
+	if err != nil {
+		return nil, err
+	}
+
+	d.ProcessMedia(&pkt)
+
+	return &pkt, nil
+}
@@ -0,0 +1,165 @@
+package rtpulpfec
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func mustMarshal(pkt *rtp.Packet) []byte {
+	byts, err := pkt.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return byts
+}
+
+// generateFEC generates a FEC packet with a short mask that protects the given packets.
+func generateFEC(pkts []*rtp.Packet, fecSeqNum uint16) *rtp.Packet {
+	snBase := pkts[0].SequenceNumber
+
+	protectionLength := 0
+	for _, pkt := range pkts {
+		if l := len(mustMarshal(pkt)) - 12; l > protectionLength {
+			protectionLength = l
+		}
+	}
+
+	payload := make([]byte, 14+protectionLength)
+	var length uint16
+	var ts uint32
+	var mask uint16
+
+	for _, pkt := range pkts {
+		byts := mustMarshal(pkt)
+		payload[0] ^= byts[0] & 0x3F
+		payload[1] ^= byts[1]
+		ts ^= pkt.Timestamp
+		length ^= uint16(len(byts) - 12)
+		mask |= 1 << (15 - (pkt.SequenceNumber - snBase))
+
+		for i, b := range byts[12:] {
+			payload[14+i] ^= b
+		}
+	}
+
+	payload[2] = byte(snBase >> 8)
+	payload[3] = byte(snBase)
+	payload[4] = byte(ts >> 24)
+	payload[5] = byte(ts >> 16)
+	payload[6] = byte(ts >> 8)
+	payload[7] = byte(ts)
+	payload[8] = byte(length >> 8)
+	payload[9] = byte(length)
+	payload[10] = byte(protectionLength >> 8)
+	payload[11] = byte(protectionLength)
+	payload[12] = byte(mask >> 8)
+	payload[13] = byte(mask)
+
+	return &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    127,
+			SequenceNumber: fecSeqNum,
+			Timestamp:      pkts[len(pkts)-1].Timestamp,
+			SSRC:           pkts[0].SSRC,
+		},
+		Payload: payload,
+	}
+}
+
+func TestDecode(t *testing.T) {
+	pkts := []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 65534,
+				Timestamp:      45343,
+				SSRC:           563423,
+			},
+			Payload: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 65535,
+				Timestamp:      45343,
+				SSRC:           563423,
+			},
+			Payload: []byte{0x05, 0x06},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    97,
+				SequenceNumber: 0,
+				Timestamp:      48343,
+				SSRC:           563423,
+			},
+			Payload: []byte{0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c},
+		},
+	}
+
+	fecPkt := generateFEC(pkts, 1)
+
+	for i := range pkts {
+		d := &Decoder{}
+		err := d.Init()
+		require.NoError(t, err)
+
+		for j, pkt := range pkts {
+			if j != i {
+				d.ProcessMedia(pkt)
+			}
+		}
+
+		rec, err := d.Decode(fecPkt)
+		require.NoError(t, err)
+		require.Equal(t, mustMarshal(pkts[i]), mustMarshal(rec))
+
+		_, err = d.Decode(fecPkt)
+		require.Equal(t, ErrNothingToRecover, err)
+	}
+
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	d.ProcessMedia(pkts[0])
+
+	_, err = d.Decode(fecPkt)
+	require.Equal(t, ErrNothingToRecover, err)
+}
+
+func FuzzDecoder(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a []byte) {
+		d := &Decoder{}
+		d.Init() //nolint:errcheck
+
+		d.ProcessMedia(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x01, 0x02},
+		})
+
+		d.Decode(&rtp.Packet{ //nolint:errcheck
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    127,
+				SequenceNumber: 17646,
+				Timestamp:      2289527317,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: a,
+		})
+	})
+}
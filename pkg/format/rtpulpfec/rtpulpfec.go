@@ -0,0 +1,2 @@
+// Package rtpulpfec contains a RTP/ULPFEC decoder.
+package rtpulpfec
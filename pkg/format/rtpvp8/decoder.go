@@ -34,6 +34,20 @@ type Decoder struct {
 	firstPacketReceived bool
 	fragmentsSize       int
 	fragments           [][]byte
+	descriptor          Descriptor
+	stats               DecoderStats
+}
+
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete frames that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
 }
 
 // Init initializes the decoder.
@@ -41,58 +55,132 @@ func (d *Decoder) Init() error {
 	return nil
 }
 
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) discardFragments() {
+	if len(d.fragments) != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+		d.fragments = d.fragments[:0]
+	}
+}
+
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+
+		// this is normal when decoding a stream that is already running
+		if !errors.Is(err, ErrNonStartingPacketAndNoPrevious) {
+			d.stats.PacketsRejected++
+		}
+	}
+}
+
+func unmarshalDescriptor(vpkt *codecs.VP8Packet) Descriptor {
+	desc := Descriptor{
+		NonReference: vpkt.N == 1,
+	}
+
+	if vpkt.I == 1 {
+		v := vpkt.PictureID
+		desc.PictureID = &v
+	}
+
+	if vpkt.L == 1 {
+		v := vpkt.TL0PICIDX
+		desc.TL0PICIDX = &v
+	}
+
+	if vpkt.T == 1 {
+		v := vpkt.TID
+		desc.TID = &v
+	}
+
+	if vpkt.T == 1 || vpkt.K == 1 {
+		desc.LayerSync = vpkt.Y == 1
+	}
+
+	if vpkt.K == 1 {
+		v := vpkt.KEYIDX
+		desc.KeyIndex = &v
+	}
+
+	// P bit of the VP8 payload header
+	desc.KeyFrame = len(vpkt.Payload) != 0 && (vpkt.Payload[0]&0x01) == 0
+
+	return desc
+}
+
 // Decode decodes a VP8 frame from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
+	frame, _, err := d.DecodeWithDescriptor(pkt)
+	return frame, err
+}
+
+// DecodeWithDescriptor decodes a VP8 frame from a RTP packet.
+// It also returns the payload descriptor of the first packet of the frame.
+func (d *Decoder) DecodeWithDescriptor(pkt *rtp.Packet) ([]byte, Descriptor, error) {
+	frame, desc, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return frame, desc, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([]byte, Descriptor, error) {
 	var vpkt codecs.VP8Packet
 	_, err := vpkt.Unmarshal(pkt.Payload)
 	if err != nil {
-		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, err
+		d.discardFragments()
+		return nil, Descriptor{}, err
 	}
 
 	if vpkt.PID != 0 {
-		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, fmt.Errorf("packets containing single partitions are not supported")
+		d.discardFragments()
+		return nil, Descriptor{}, fmt.Errorf("packets containing single partitions are not supported")
 	}
 
 	var frame []byte
 
 	if vpkt.S == 1 {
-		d.fragments = d.fragments[:0] // discard pending fragments
+		d.discardFragments()
 		d.firstPacketReceived = true
+		d.descriptor = unmarshalDescriptor(&vpkt)
 
 		if !pkt.Marker {
 			d.fragmentsSize = len(vpkt.Payload)
 			d.fragments = append(d.fragments, vpkt.Payload)
-			return nil, ErrMorePacketsNeeded
+			return nil, Descriptor{}, ErrMorePacketsNeeded
 		}
 
 		frame = vpkt.Payload
 	} else {
 		if len(d.fragments) == 0 {
 			if !d.firstPacketReceived {
-				return nil, ErrNonStartingPacketAndNoPrevious
+				return nil, Descriptor{}, ErrNonStartingPacketAndNoPrevious
 			}
 
-			return nil, fmt.Errorf("received a non-starting fragment")
+			return nil, Descriptor{}, fmt.Errorf("received a non-starting fragment")
 		}
 
-		d.fragmentsSize += len(vpkt.Payload)
-
-		if d.fragmentsSize > vp8.MaxFrameSize {
-			d.fragments = d.fragments[:0] // discard pending fragments
-			return nil, fmt.Errorf("frame size (%d) is too big, maximum is %d", d.fragmentsSize, vp8.MaxFrameSize)
+		if (d.fragmentsSize + len(vpkt.Payload)) > vp8.MaxFrameSize {
+			size := d.fragmentsSize + len(vpkt.Payload)
+			d.discardFragments()
+			return nil, Descriptor{}, fmt.Errorf("frame size (%d) is too big, maximum is %d", size, vp8.MaxFrameSize)
 		}
 
+		d.fragmentsSize += len(vpkt.Payload)
+
 		d.fragments = append(d.fragments, vpkt.Payload)
 
 		if !pkt.Marker {
-			return nil, ErrMorePacketsNeeded
+			return nil, Descriptor{}, ErrMorePacketsNeeded
 		}
 
 		frame = joinFragments(d.fragments, d.fragmentsSize)
 		d.fragments = d.fragments[:0]
 	}
 
-	return frame, nil
+	return frame, d.descriptor, nil
 }
@@ -26,6 +26,109 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecoderStats(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// starting fragment
+	_, err = d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: false},
+		Payload: []byte{0x10, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// new frame before the end of the previous one
+	frame, err := d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: true},
+		Payload: []byte{0x10, 0x03},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x03}, frame)
+
+	// single partition
+	_, err = d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: true},
+		Payload: []byte{0x11, 0x04},
+	})
+	require.Error(t, err)
+
+	// non-starting fragment
+	_, err = d.Decode(&rtp.Packet{
+		Header:  rtp.Header{Marker: true},
+		Payload: []byte{0x00, 0x05},
+	})
+	require.Error(t, err)
+
+	require.Equal(t, DecoderStats{
+		PacketsRejected:     2,
+		IncompleteDiscarded: 1,
+		BytesDropped:        6,
+	}, d.Stats())
+}
+
+func uint8Ptr(v uint8) *uint8 {
+	return &v
+}
+
+func TestDecodeWithDescriptor(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	_, _, err = d.DecodeWithDescriptor(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         false,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x90, 0xf0, 0x81, 0x23, 0x05, 0xa3, 0x50, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	frame, desc, err := d.DecodeWithDescriptor(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17646,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x80, 0x80, 0x81, 0x23, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x50, 0x02, 0x03, 0x04}, frame)
+	require.Equal(t, Descriptor{
+		PictureID: uint16Ptr(0x123),
+		TL0PICIDX: uint8Ptr(5),
+		TID:       uint8Ptr(2),
+		LayerSync: true,
+		KeyIndex:  uint8Ptr(3),
+		KeyFrame:  true,
+	}, desc)
+
+	frame, desc, err = d.DecodeWithDescriptor(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 17647,
+			Timestamp:      2289527317,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{0x30, 0x51, 0x02},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x51, 0x02}, frame)
+	require.Equal(t, Descriptor{
+		NonReference: true,
+	}, desc)
+}
+
 func FuzzDecoder(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{}
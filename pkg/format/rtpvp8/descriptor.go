@@ -0,0 +1,27 @@
+package rtpvp8
+
+// Descriptor contains the fields of the VP8 payload descriptor of a frame.
+// Specification: https://datatracker.ietf.org/doc/html/rfc7741#section-4.2
+type Descriptor struct {
+	// whether the frame can be discarded without affecting any other frame.
+	NonReference bool
+
+	// picture ID (optional).
+	PictureID *uint16
+
+	// temporal level zero index (optional).
+	TL0PICIDX *uint8
+
+	// temporal layer index (optional).
+	TID *uint8
+
+	// layer sync bit.
+	LayerSync bool
+
+	// temporal key frame index (optional).
+	KeyIndex *uint8
+
+	// whether the frame is a key frame.
+	// It is read from the VP8 payload header.
+	KeyFrame bool
+}
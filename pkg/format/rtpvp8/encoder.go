@@ -5,7 +5,8 @@ import (
 	"fmt"
 
 	"github.com/pion/rtp"
-	"github.com/pion/rtp/codecs"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -40,12 +41,34 @@ type Encoder struct {
 	// It defaults to 1460.
 	PayloadMaxSize int
 
+	// whether to insert the picture ID into packets (optional).
+	PictureIDEnabled bool
+
+	// initial picture ID (optional).
+	// It defaults to a random value.
+	// It can be used to continue the picture ID sequence of another stream.
+	InitialPictureID *uint16
+
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
-	vp             codecs.VP8Payloader
+	pictureID      uint16
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -65,17 +88,89 @@ func (e *Encoder) Init() error {
 		e.PayloadMaxSize = defaultPayloadMaxSize
 	}
 
+	if e.PictureIDEnabled {
+		if e.InitialPictureID == nil {
+			v, err := randUint32()
+			if err != nil {
+				return err
+			}
+			v2 := uint16(v) & 0x7FFF
+			e.InitialPictureID = &v2
+		}
+
+		if *e.InitialPictureID > 0x7FFF {
+			return fmt.Errorf("invalid InitialPictureID (%d)", *e.InitialPictureID)
+		}
+
+		e.pictureID = *e.InitialPictureID
+	}
+
 	e.sequenceNumber = *e.InitialSequenceNumber
 	return nil
 }
 
+// NextPictureID returns the picture ID that is assigned to the next frame.
+func (e *Encoder) NextPictureID() uint16 {
+	return e.pictureID
+}
+
+func (e *Encoder) marshalDescriptor(start bool) []byte {
+	var buf []byte
+
+	if e.PictureIDEnabled {
+		// always use the 15-bit picture ID
+		buf = []byte{0x80, 0x80, 0x80 | byte(e.pictureID>>8), byte(e.pictureID)}
+	} else {
+		buf = []byte{0}
+	}
+
+	if start {
+		buf[0] |= 0x10
+	}
+
+	return buf
+}
+
 // Encode encodes a VP8 frame into RTP/VP8 packets.
 func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
-	payloads := e.vp.Payload(uint16(e.PayloadMaxSize), frame)
-	if payloads == nil {
+	pkts, err := e.encode(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frame []byte) ([]*rtp.Packet, error) {
+	headerSize := len(e.marshalDescriptor(false))
+	maxFragmentSize := e.PayloadMaxSize - headerSize
+
+	if len(frame) == 0 || maxFragmentSize <= 0 {
 		return nil, fmt.Errorf("payloader failed")
 	}
 
+	var payloads [][]byte
+
+	for start := true; len(frame) != 0; start = false {
+		le := len(frame)
+		if le > maxFragmentSize {
+			le = maxFragmentSize
+		}
+
+		payload := make([]byte, headerSize+le)
+		copy(payload, e.marshalDescriptor(start))
+		copy(payload[headerSize:], frame[:le])
+		frame = frame[le:]
+
+		payloads = append(payloads, payload)
+	}
+
+	if e.PictureIDEnabled {
+		e.pictureID = (e.pictureID + 1) & 0x7FFF
+	}
+
 	plen := len(payloads)
 	ret := make([]*rtp.Packet, plen)
 
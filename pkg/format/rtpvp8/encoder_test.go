@@ -118,3 +118,56 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func TestEncodePictureID(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		PayloadMaxSize:        10,
+		PictureIDEnabled:      true,
+		InitialPictureID:      uint16Ptr(0x7fff),
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	pkts, err := e.Encode([]byte{0x50, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08})
+	require.NoError(t, err)
+	require.Equal(t, []*rtp.Packet{
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         false,
+				PayloadType:    96,
+				SequenceNumber: 17645,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x90, 0x80, 0xff, 0xff, 0x50, 0x02, 0x03, 0x04, 0x05, 0x06},
+		},
+		{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: 17646,
+				SSRC:           0x9dbb7812,
+			},
+			Payload: []byte{0x80, 0x80, 0xff, 0xff, 0x07, 0x08},
+		},
+	}, pkts)
+
+	// picture ID wraps around
+	require.Equal(t, uint16(0), e.NextPictureID())
+
+	pkts, err = e.Encode([]byte{0x51, 0x02})
+	require.NoError(t, err)
+
+	d := &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	frame, desc, err := d.DecodeWithDescriptor(pkts[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x51, 0x02}, frame)
+	require.Equal(t, Descriptor{PictureID: uint16Ptr(0)}, desc)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/bluenviron/mediacommon/pkg/codecs/vp9"
 	"github.com/pion/rtp"
-	"github.com/pion/rtp/codecs"
 )
 
 // ErrMorePacketsNeeded is returned when more packets are needed.
@@ -34,6 +33,20 @@ type Decoder struct {
 	firstPacketReceived bool
 	fragmentsSize       int
 	fragments           [][]byte
+	descriptor          Descriptor
+	stats               DecoderStats
+}
+
+// DecoderStats are statistics about malformed or incomplete data.
+type DecoderStats struct {
+	// number of packets that have been rejected.
+	PacketsRejected uint64
+
+	// number of incomplete frames that have been discarded.
+	IncompleteDiscarded uint64
+
+	// number of payload bytes that have been dropped.
+	BytesDropped uint64
 }
 
 // Init initializes the decoder.
@@ -41,53 +54,94 @@ func (d *Decoder) Init() error {
 	return nil
 }
 
+// Stats returns decoder statistics.
+func (d *Decoder) Stats() DecoderStats {
+	return d.stats
+}
+
+func (d *Decoder) discardFragments() {
+	if len(d.fragments) != 0 {
+		d.stats.IncompleteDiscarded++
+		d.stats.BytesDropped += uint64(d.fragmentsSize)
+		d.fragments = d.fragments[:0]
+	}
+}
+
+func (d *Decoder) countRejected(pkt *rtp.Packet, err error) {
+	if err != nil && !errors.Is(err, ErrMorePacketsNeeded) {
+		d.stats.BytesDropped += uint64(len(pkt.Payload))
+
+		// this is normal when decoding a stream that is already running
+		if !errors.Is(err, ErrNonStartingPacketAndNoPrevious) {
+			d.stats.PacketsRejected++
+		}
+	}
+}
+
 // Decode decodes a VP9 frame from a RTP packet.
 func (d *Decoder) Decode(pkt *rtp.Packet) ([]byte, error) {
-	var vpkt codecs.VP9Packet
-	_, err := vpkt.Unmarshal(pkt.Payload)
+	frame, _, err := d.DecodeWithDescriptor(pkt)
+	return frame, err
+}
+
+// DecodeWithDescriptor decodes a VP9 frame from a RTP packet.
+// It also returns the payload descriptor of the first packet of the frame,
+// that contains the scalability structure, if present.
+func (d *Decoder) DecodeWithDescriptor(pkt *rtp.Packet) ([]byte, Descriptor, error) {
+	frame, desc, err := d.decode(pkt)
+	d.countRejected(pkt, err)
+	return frame, desc, err
+}
+
+func (d *Decoder) decode(pkt *rtp.Packet) ([]byte, Descriptor, error) {
+	var desc Descriptor
+	n, err := desc.Unmarshal(pkt.Payload)
 	if err != nil {
-		d.fragments = d.fragments[:0] // discard pending fragments
-		return nil, err
+		d.discardFragments()
+		return nil, Descriptor{}, err
 	}
 
+	payload := pkt.Payload[n:]
 	var frame []byte
 
-	if vpkt.B {
-		d.fragments = d.fragments[:0] // discard pending fragments
+	if desc.StartOfFrame {
+		d.discardFragments()
 		d.firstPacketReceived = true
+		d.descriptor = desc
 
-		if !vpkt.E {
-			d.fragmentsSize = len(vpkt.Payload)
-			d.fragments = append(d.fragments, vpkt.Payload)
-			return nil, ErrMorePacketsNeeded
+		if !desc.EndOfFrame {
+			d.fragmentsSize = len(payload)
+			d.fragments = append(d.fragments, payload)
+			return nil, Descriptor{}, ErrMorePacketsNeeded
 		}
 
-		frame = vpkt.Payload
+		frame = payload
 	} else {
 		if len(d.fragments) == 0 {
 			if !d.firstPacketReceived {
-				return nil, ErrNonStartingPacketAndNoPrevious
+				return nil, Descriptor{}, ErrNonStartingPacketAndNoPrevious
 			}
 
-			return nil, fmt.Errorf("received a non-starting fragment")
+			return nil, Descriptor{}, fmt.Errorf("received a non-starting fragment")
 		}
 
-		d.fragmentsSize += len(vpkt.Payload)
-
-		if d.fragmentsSize > vp9.MaxFrameSize {
-			d.fragments = d.fragments[:0] // discard pending fragments
-			return nil, fmt.Errorf("frame size (%d) is too big, maximum is %d", d.fragmentsSize, vp9.MaxFrameSize)
+		if (d.fragmentsSize + len(payload)) > vp9.MaxFrameSize {
+			size := d.fragmentsSize + len(payload)
+			d.discardFragments()
+			return nil, Descriptor{}, fmt.Errorf("frame size (%d) is too big, maximum is %d", size, vp9.MaxFrameSize)
 		}
 
-		d.fragments = append(d.fragments, vpkt.Payload)
+		d.fragmentsSize += len(payload)
+
+		d.fragments = append(d.fragments, payload)
 
-		if !vpkt.E {
-			return nil, ErrMorePacketsNeeded
+		if !desc.EndOfFrame {
+			return nil, Descriptor{}, ErrMorePacketsNeeded
 		}
 
 		frame = joinFragments(d.fragments, d.fragmentsSize)
 		d.fragments = d.fragments[:0]
 	}
 
-	return frame, nil
+	return frame, d.descriptor, nil
 }
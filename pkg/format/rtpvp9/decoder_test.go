@@ -26,6 +26,41 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecoderStats(t *testing.T) {
+	d := &Decoder{}
+	err := d.Init()
+	require.NoError(t, err)
+
+	// starting fragment
+	_, err = d.Decode(&rtp.Packet{
+		Payload: []byte{0x08, 0x01, 0x02},
+	})
+	require.Equal(t, ErrMorePacketsNeeded, err)
+
+	// new frame before the end of the previous one
+	frame, err := d.Decode(&rtp.Packet{
+		Payload: []byte{0x0c, 0x03},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x03}, frame)
+
+	// non-starting fragment
+	_, err = d.Decode(&rtp.Packet{
+		Payload: []byte{0x04, 0x05},
+	})
+	require.Error(t, err)
+
+	// empty payload
+	_, err = d.Decode(&rtp.Packet{})
+	require.Error(t, err)
+
+	require.Equal(t, DecoderStats{
+		PacketsRejected:     2,
+		IncompleteDiscarded: 1,
+		BytesDropped:        4,
+	}, d.Stats())
+}
+
 func FuzzDecoder(f *testing.F) {
 	f.Fuzz(func(_ *testing.T, a []byte, am bool, b []byte, bm bool) {
 		d := &Decoder{}
@@ -0,0 +1,440 @@
+package rtpvp9
+
+import (
+	"fmt"
+)
+
+const (
+	maxSpatialLayers  = 8
+	maxReferenceIndex = 3
+)
+
+// LayerIndices are the layer indices of a VP9 payload descriptor.
+type LayerIndices struct {
+	// temporal layer ID.
+	TID uint8
+
+	// switching up point.
+	U bool
+
+	// spatial layer ID.
+	SID uint8
+
+	// inter-layer dependency used.
+	D bool
+
+	// temporal layer zero index.
+	// It is present in non-flexible mode only.
+	TL0PICIDX uint8
+}
+
+// SpatialLayerResolution is the resolution of a spatial layer.
+type SpatialLayerResolution struct {
+	Width  uint16
+	Height uint16
+}
+
+// PictureGroupEntry is the description of a picture of a picture group.
+type PictureGroupEntry struct {
+	// temporal layer ID.
+	TID uint8
+
+	// switching up point.
+	U bool
+
+	// reference indices.
+	PDiff []uint8
+}
+
+// ScalabilityStructure is the scalability structure (SS) of a VP9 payload descriptor.
+type ScalabilityStructure struct {
+	// number of spatial layers.
+	SpatialLayerCount int
+
+	// resolution of each spatial layer (optional).
+	Resolutions []SpatialLayerResolution
+
+	// description of the pictures of the picture group (optional).
+	PictureGroup []PictureGroupEntry
+}
+
+// Descriptor is a VP9 payload descriptor.
+// Specification: https://datatracker.ietf.org/doc/html/draft-ietf-payload-vp9-16#section-4.2
+type Descriptor struct {
+	// picture ID (optional).
+	PictureID *uint16
+
+	// whether the picture ID is written with 7 bits instead of 15.
+	ShortPictureID bool
+
+	// inter-picture predicted frame.
+	InterPicturePredicted bool
+
+	// flexible mode.
+	FlexibleMode bool
+
+	// start of a frame.
+	StartOfFrame bool
+
+	// end of a frame.
+	EndOfFrame bool
+
+	// not a reference frame for upper spatial layers.
+	NotReferenceForUpperLayers bool
+
+	// layer indices (optional).
+	LayerIndices *LayerIndices
+
+	// reference indices.
+	// They are present in flexible mode only, when InterPicturePredicted is true.
+	PDiff []uint8
+
+	// scalability structure (optional).
+	ScalabilityStructure *ScalabilityStructure
+}
+
+func (d *Descriptor) unmarshalScalabilityStructure(buf []byte, pos int) (int, error) {
+	if len(buf) <= pos {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	ss := &ScalabilityStructure{
+		SpatialLayerCount: int(buf[pos]>>5) + 1,
+	}
+	y := (buf[pos] & 0x10) != 0
+	g := (buf[pos] & 0x08) != 0
+	pos++
+
+	if y {
+		if len(buf) < (pos + 4*ss.SpatialLayerCount) {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		ss.Resolutions = make([]SpatialLayerResolution, ss.SpatialLayerCount)
+
+		for i := range ss.Resolutions {
+			ss.Resolutions[i].Width = uint16(buf[pos])<<8 | uint16(buf[pos+1])
+			ss.Resolutions[i].Height = uint16(buf[pos+2])<<8 | uint16(buf[pos+3])
+			pos += 4
+		}
+	}
+
+	if g {
+		if len(buf) <= pos {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		ng := int(buf[pos])
+		pos++
+
+		ss.PictureGroup = make([]PictureGroupEntry, ng)
+
+		for i := range ss.PictureGroup {
+			if len(buf) <= pos {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			e := &ss.PictureGroup[i]
+			e.TID = buf[pos] >> 5
+			e.U = (buf[pos] & 0x10) != 0
+			r := int((buf[pos] >> 2) & 0x03)
+			pos++
+
+			if len(buf) < (pos + r) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			e.PDiff = make([]uint8, r)
+			copy(e.PDiff, buf[pos:])
+			pos += r
+		}
+	}
+
+	d.ScalabilityStructure = ss
+
+	return pos, nil
+}
+
+// Unmarshal decodes a descriptor.
+// It returns the size of the descriptor, that is, the position of the payload.
+func (d *Descriptor) Unmarshal(buf []byte) (int, error) {
+	if len(buf) < 1 {
+		return 0, fmt.Errorf("payload is too short")
+	}
+
+	i := (buf[0] & 0x80) != 0
+	d.InterPicturePredicted = (buf[0] & 0x40) != 0
+	l := (buf[0] & 0x20) != 0
+	d.FlexibleMode = (buf[0] & 0x10) != 0
+	d.StartOfFrame = (buf[0] & 0x08) != 0
+	d.EndOfFrame = (buf[0] & 0x04) != 0
+	v := (buf[0] & 0x02) != 0
+	d.NotReferenceForUpperLayers = (buf[0] & 0x01) != 0
+	pos := 1
+
+	d.PictureID = nil
+	d.ShortPictureID = false
+	d.LayerIndices = nil
+	d.PDiff = nil
+	d.ScalabilityStructure = nil
+
+	if i {
+		if len(buf) <= pos {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		var id uint16
+
+		if (buf[pos] & 0x80) != 0 {
+			if len(buf) <= (pos + 1) {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			id = uint16(buf[pos]&0x7F)<<8 | uint16(buf[pos+1])
+			pos += 2
+		} else {
+			id = uint16(buf[pos])
+			d.ShortPictureID = true
+			pos++
+		}
+
+		d.PictureID = &id
+	}
+
+	if l {
+		if len(buf) <= pos {
+			return 0, fmt.Errorf("payload is too short")
+		}
+
+		li := &LayerIndices{
+			TID: buf[pos] >> 5,
+			U:   (buf[pos] & 0x10) != 0,
+			SID: (buf[pos] >> 1) & 0x07,
+			D:   (buf[pos] & 0x01) != 0,
+		}
+		pos++
+
+		if !d.FlexibleMode {
+			if len(buf) <= pos {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			li.TL0PICIDX = buf[pos]
+			pos++
+		}
+
+		d.LayerIndices = li
+	}
+
+	if d.FlexibleMode && d.InterPicturePredicted {
+		for {
+			if len(buf) <= pos {
+				return 0, fmt.Errorf("payload is too short")
+			}
+
+			if len(d.PDiff) >= maxReferenceIndex {
+				return 0, fmt.Errorf("too many reference indices")
+			}
+
+			d.PDiff = append(d.PDiff, buf[pos]>>1)
+			n := (buf[pos] & 0x01) != 0
+			pos++
+
+			if !n {
+				break
+			}
+		}
+	}
+
+	if v {
+		var err error
+		pos, err = d.unmarshalScalabilityStructure(buf, pos)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return pos, nil
+}
+
+// MarshalSize returns the size of the descriptor.
+func (d Descriptor) MarshalSize() int {
+	n := 1
+
+	if d.PictureID != nil {
+		if d.ShortPictureID {
+			n++
+		} else {
+			n += 2
+		}
+	}
+
+	if d.LayerIndices != nil {
+		n++
+		if !d.FlexibleMode {
+			n++
+		}
+	}
+
+	if d.FlexibleMode && d.InterPicturePredicted {
+		n += len(d.PDiff)
+	}
+
+	if ss := d.ScalabilityStructure; ss != nil {
+		n++
+		n += 4 * len(ss.Resolutions)
+
+		if ss.PictureGroup != nil {
+			n++
+			for _, e := range ss.PictureGroup {
+				n += 1 + len(e.PDiff)
+			}
+		}
+	}
+
+	return n
+}
+
+// MarshalTo writes the descriptor into a buffer.
+func (d Descriptor) MarshalTo(buf []byte) (int, error) {
+	if d.PictureID != nil && (*d.PictureID > 0x7FFF || (d.ShortPictureID && *d.PictureID > 0x7F)) {
+		return 0, fmt.Errorf("invalid picture ID (%d)", *d.PictureID)
+	}
+
+	if d.FlexibleMode && d.InterPicturePredicted &&
+		(len(d.PDiff) == 0 || len(d.PDiff) > maxReferenceIndex) {
+		return 0, fmt.Errorf("invalid reference index count (%d)", len(d.PDiff))
+	}
+
+	if ss := d.ScalabilityStructure; ss != nil {
+		if ss.SpatialLayerCount < 1 || ss.SpatialLayerCount > maxSpatialLayers {
+			return 0, fmt.Errorf("invalid spatial layer count (%d)", ss.SpatialLayerCount)
+		}
+
+		if ss.Resolutions != nil && len(ss.Resolutions) != ss.SpatialLayerCount {
+			return 0, fmt.Errorf("resolution count (%d) and spatial layer count (%d) do not match",
+				len(ss.Resolutions), ss.SpatialLayerCount)
+		}
+
+		if len(ss.PictureGroup) > 255 {
+			return 0, fmt.Errorf("invalid picture group size (%d)", len(ss.PictureGroup))
+		}
+
+		for _, e := range ss.PictureGroup {
+			if len(e.PDiff) > maxReferenceIndex {
+				return 0, fmt.Errorf("invalid reference index count (%d)", len(e.PDiff))
+			}
+		}
+	}
+
+	buf[0] = 0
+
+	if d.PictureID != nil {
+		buf[0] |= 0x80
+	}
+	if d.InterPicturePredicted {
+		buf[0] |= 0x40
+	}
+	if d.LayerIndices != nil {
+		buf[0] |= 0x20
+	}
+	if d.FlexibleMode {
+		buf[0] |= 0x10
+	}
+	if d.StartOfFrame {
+		buf[0] |= 0x08
+	}
+	if d.EndOfFrame {
+		buf[0] |= 0x04
+	}
+	if d.ScalabilityStructure != nil {
+		buf[0] |= 0x02
+	}
+	if d.NotReferenceForUpperLayers {
+		buf[0] |= 0x01
+	}
+	pos := 1
+
+	if d.PictureID != nil {
+		if d.ShortPictureID {
+			buf[pos] = byte(*d.PictureID)
+			pos++
+		} else {
+			buf[pos] = 0x80 | byte(*d.PictureID>>8)
+			buf[pos+1] = byte(*d.PictureID)
+			pos += 2
+		}
+	}
+
+	if li := d.LayerIndices; li != nil {
+		buf[pos] = (li.TID&0x07)<<5 | (li.SID&0x07)<<1
+		if li.U {
+			buf[pos] |= 0x10
+		}
+		if li.D {
+			buf[pos] |= 0x01
+		}
+		pos++
+
+		if !d.FlexibleMode {
+			buf[pos] = li.TL0PICIDX
+			pos++
+		}
+	}
+
+	if d.FlexibleMode && d.InterPicturePredicted {
+		for i, pdiff := range d.PDiff {
+			buf[pos] = pdiff << 1
+			if i != (len(d.PDiff) - 1) {
+				buf[pos] |= 0x01
+			}
+			pos++
+		}
+	}
+
+	if ss := d.ScalabilityStructure; ss != nil {
+		buf[pos] = byte(ss.SpatialLayerCount-1) << 5
+		if ss.Resolutions != nil {
+			buf[pos] |= 0x10
+		}
+		if ss.PictureGroup != nil {
+			buf[pos] |= 0x08
+		}
+		pos++
+
+		for _, r := range ss.Resolutions {
+			buf[pos] = byte(r.Width >> 8)
+			buf[pos+1] = byte(r.Width)
+			buf[pos+2] = byte(r.Height >> 8)
+			buf[pos+3] = byte(r.Height)
+			pos += 4
+		}
+
+		if ss.PictureGroup != nil {
+			buf[pos] = byte(len(ss.PictureGroup))
+			pos++
+
+			for _, e := range ss.PictureGroup {
+				buf[pos] = (e.TID&0x07)<<5 | byte(len(e.PDiff))<<2
+				if e.U {
+					buf[pos] |= 0x10
+				}
+				pos++
+
+				pos += copy(buf[pos:], e.PDiff)
+			}
+		}
+	}
+
+	return pos, nil
+}
+
+// Marshal encodes the descriptor.
+func (d Descriptor) Marshal() ([]byte, error) {
+	buf := make([]byte, d.MarshalSize())
+	n, err := d.MarshalTo(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,117 @@
+package rtpvp9
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var casesDescriptor = []struct {
+	name string
+	byts []byte
+	desc Descriptor
+}{
+	{
+		"non-flexible with scalability structure",
+		[]byte{
+			0xaa, 0x81, 0x23, 0x00, 0x05, 0x38, 0x01, 0x40,
+			0x00, 0xb4, 0x02, 0x80, 0x01, 0x68, 0x02, 0x14,
+			0x04, 0x50,
+		},
+		Descriptor{
+			PictureID:    uint16Ptr(0x123),
+			StartOfFrame: true,
+			LayerIndices: &LayerIndices{
+				TL0PICIDX: 5,
+			},
+			ScalabilityStructure: &ScalabilityStructure{
+				SpatialLayerCount: 2,
+				Resolutions: []SpatialLayerResolution{
+					{Width: 320, Height: 180},
+					{Width: 640, Height: 360},
+				},
+				PictureGroup: []PictureGroupEntry{
+					{
+						TID:   0,
+						U:     true,
+						PDiff: []uint8{4},
+					},
+					{
+						TID:   2,
+						U:     true,
+						PDiff: []uint8{},
+					},
+				},
+			},
+		},
+	},
+	{
+		"flexible with reference indices",
+		[]byte{0xf4, 0x12, 0x53, 0x03, 0x04},
+		Descriptor{
+			PictureID:             uint16Ptr(0x12),
+			ShortPictureID:        true,
+			InterPicturePredicted: true,
+			FlexibleMode:          true,
+			EndOfFrame:            true,
+			LayerIndices: &LayerIndices{
+				TID: 2,
+				U:   true,
+				SID: 1,
+				D:   true,
+			},
+			PDiff: []uint8{1, 2},
+		},
+	},
+	{
+		"scalability structure without resolutions",
+		[]byte{0x0f, 0x40},
+		Descriptor{
+			StartOfFrame:               true,
+			EndOfFrame:                 true,
+			NotReferenceForUpperLayers: true,
+			ScalabilityStructure: &ScalabilityStructure{
+				SpatialLayerCount: 3,
+			},
+		},
+	},
+}
+
+func TestDescriptorUnmarshal(t *testing.T) {
+	for _, ca := range casesDescriptor {
+		t.Run(ca.name, func(t *testing.T) {
+			var desc Descriptor
+			n, err := desc.Unmarshal(ca.byts)
+			require.NoError(t, err)
+			require.Equal(t, len(ca.byts), n)
+			require.Equal(t, ca.desc, desc)
+		})
+	}
+}
+
+func TestDescriptorMarshal(t *testing.T) {
+	for _, ca := range casesDescriptor {
+		t.Run(ca.name, func(t *testing.T) {
+			byts, err := ca.desc.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, ca.byts, byts)
+		})
+	}
+}
+
+func FuzzDescriptorUnmarshal(f *testing.F) {
+	for _, ca := range casesDescriptor {
+		f.Add(ca.byts)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var desc Descriptor
+		_, err := desc.Unmarshal(b)
+		if err != nil {
+			return
+		}
+
+		_, err = desc.Marshal()
+		require.NoError(t, err)
+	})
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/pion/rtp"
 	"github.com/pion/rtp/codecs"
+
+	"github.com/voicecom/gortsplib/v4/pkg/rtpoptions"
 )
 
 const (
@@ -44,12 +46,26 @@ type Encoder struct {
 	// It defaults to a random value.
 	InitialPictureID *uint16
 
+	// function that overrides the marker bit of packets (optional).
+	// By default, the marker bit is set by codec rules.
+	MarkerFunc rtpoptions.MarkerFunc
+
+	// pad packets with RTP padding in order to make the size of their payload
+	// a multiple of this value (optional). It can't be greater than 256.
+	// Padding is not taken into account by PayloadMaxSize.
+	PaddingAlignment int
+
 	sequenceNumber uint16
 	vp             codecs.VP9Payloader
 }
 
 // Init initializes the encoder.
 func (e *Encoder) Init() error {
+	err := rtpoptions.ValidatePaddingAlignment(e.PaddingAlignment)
+	if err != nil {
+		return err
+	}
+
 	if e.SSRC == nil {
 		v, err := randUint32()
 		if err != nil {
@@ -88,6 +104,17 @@ func (e *Encoder) Init() error {
 
 // Encode encodes a VP9 frame into RTP/VP9 packets.
 func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
+	pkts, err := e.encode(frame)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encode(frame []byte) ([]*rtp.Packet, error) {
 	payloads := e.vp.Payload(uint16(e.PayloadMaxSize), frame)
 	if payloads == nil {
 		return nil, fmt.Errorf("payloader failed")
@@ -113,3 +140,71 @@ func (e *Encoder) Encode(frame []byte) ([]*rtp.Packet, error) {
 
 	return ret, nil
 }
+
+// EncodeWithDescriptor encodes a VP9 frame into RTP/VP9 packets,
+// by using the given payload descriptor.
+// It allows to preserve the descriptor of a frame when re-packetizing a stream.
+// The scalability structure is written into the first packet only.
+func (e *Encoder) EncodeWithDescriptor(frame []byte, desc Descriptor) ([]*rtp.Packet, error) {
+	pkts, err := e.encodeWithDescriptor(frame, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	rtpoptions.Apply(pkts, e.MarkerFunc, e.PaddingAlignment)
+
+	return pkts, nil
+}
+
+func (e *Encoder) encodeWithDescriptor(frame []byte, desc Descriptor) ([]*rtp.Packet, error) {
+	firstDesc := desc
+	firstDesc.StartOfFrame = true
+
+	otherDesc := desc
+	otherDesc.StartOfFrame = false
+	otherDesc.ScalabilityStructure = nil
+
+	var ret []*rtp.Packet
+
+	for first := true; first || len(frame) != 0; first = false {
+		d := otherDesc
+		if first {
+			d = firstDesc
+		}
+
+		avail := e.PayloadMaxSize - d.MarshalSize()
+		if avail <= 0 {
+			return nil, fmt.Errorf("descriptor is too big")
+		}
+
+		le := len(frame)
+		if le > avail {
+			le = avail
+		}
+
+		d.EndOfFrame = (le == len(frame))
+
+		payload := make([]byte, d.MarshalSize()+le)
+		n, err := d.MarshalTo(payload)
+		if err != nil {
+			return nil, err
+		}
+		copy(payload[n:], frame[:le])
+		frame = frame[le:]
+
+		ret = append(ret, &rtp.Packet{
+			Header: rtp.Header{
+				Version:        rtpVersion,
+				PayloadType:    e.PayloadType,
+				SequenceNumber: e.sequenceNumber,
+				SSRC:           *e.SSRC,
+				Marker:         len(frame) == 0,
+			},
+			Payload: payload,
+		})
+
+		e.sequenceNumber++
+	}
+
+	return ret, nil
+}
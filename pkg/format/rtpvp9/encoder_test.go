@@ -136,3 +136,49 @@ func TestEncodeRandomInitialState(t *testing.T) {
 	require.NotEqual(t, nil, e.SSRC)
 	require.NotEqual(t, nil, e.InitialSequenceNumber)
 }
+
+func TestEncodeWithDescriptor(t *testing.T) {
+	e := &Encoder{
+		PayloadType:           96,
+		SSRC:                  uint32Ptr(0x9dbb7812),
+		InitialSequenceNumber: uint16Ptr(0x44ed),
+		PayloadMaxSize:        20,
+	}
+	err := e.Init()
+	require.NoError(t, err)
+
+	desc := casesDescriptor[0].desc
+	frame := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 10)
+
+	pkts, err := e.EncodeWithDescriptor(frame, desc)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(pkts))
+
+	for i, pkt := range pkts {
+		require.LessOrEqual(t, len(pkt.Payload), e.PayloadMaxSize)
+		require.Equal(t, i == (len(pkts)-1), pkt.Marker)
+	}
+
+	// the scalability structure is in the first packet only
+	require.Equal(t, mergeBytes(casesDescriptor[0].byts, []byte{0x01, 0x02}), pkts[0].Payload)
+	require.Equal(t, mergeBytes(
+		[]byte{0xa0, 0x81, 0x23, 0x00, 0x05},
+		bytes.Repeat([]byte{0x03, 0x04, 0x01, 0x02}, 3),
+		[]byte{0x03, 0x04, 0x01},
+	), pkts[1].Payload)
+
+	d := &Decoder{}
+	err = d.Init()
+	require.NoError(t, err)
+
+	var decFrame []byte
+	var decDesc Descriptor
+
+	for _, pkt := range pkts {
+		decFrame, decDesc, err = d.DecodeWithDescriptor(pkt)
+	}
+
+	require.NoError(t, err)
+	require.Equal(t, frame, decFrame)
+	require.Equal(t, desc, decDesc)
+}
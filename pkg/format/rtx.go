@@ -0,0 +1,120 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+)
+
+// RTX is the RTP format for retransmission packets.
+// Specification: https://datatracker.ietf.org/doc/html/rfc4588
+type RTX struct {
+	PayloadTyp uint8
+	ClockRat   int
+
+	// payload type of the associated (original) format.
+	APT uint8
+
+	// time window, in milliseconds, in which the sender keeps packets
+	// available for retransmission.
+	RTXTime *int
+}
+
+func (f *RTX) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	clockRate, err := strconv.ParseUint(ctx.clock, 10, 31)
+	if err != nil {
+		return fmt.Errorf("invalid clock rate: '%s'", ctx.clock)
+	}
+	f.ClockRat = int(clockRate)
+
+	aptFound := false
+
+	for key, val := range ctx.fmtp {
+		switch key {
+		case "apt":
+			n, err := strconv.ParseUint(val, 10, 7)
+			if err != nil {
+				return fmt.Errorf("invalid apt: %v", val)
+			}
+
+			f.APT = uint8(n)
+			aptFound = true
+
+		case "rtx-time":
+			n, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid rtx-time: %v", val)
+			}
+
+			v2 := int(n)
+			f.RTXTime = &v2
+		}
+	}
+
+	if !aptFound {
+		return fmt.Errorf("apt is missing")
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *RTX) Codec() string {
+	return "RTX"
+}
+
+// ClockRate implements Format.
+func (f *RTX) ClockRate() int {
+	return f.ClockRat
+}
+
+// PayloadType implements Format.
+func (f *RTX) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *RTX) RTPMap() string {
+	return "rtx/" + strconv.FormatInt(int64(f.ClockRat), 10)
+}
+
+// FMTP implements Format.
+func (f *RTX) FMTP() map[string]string {
+	fmtp := map[string]string{
+		"apt": strconv.FormatUint(uint64(f.APT), 10),
+	}
+
+	if f.RTXTime != nil {
+		fmtp["rtx-time"] = strconv.FormatInt(int64(*f.RTXTime), 10)
+	}
+
+	return fmtp
+}
+
+// PTSEqualsDTS implements Format.
+func (f *RTX) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// Unwrap restores the original packet from a retransmission packet,
+// by reading the original sequence number (OSN) from the payload and
+// by replacing the payload type with the associated one.
+// The SSRC is left untouched and must be replaced by the caller.
+func (f *RTX) Unwrap(pkt *rtp.Packet) (*rtp.Packet, error) {
+	if len(pkt.Payload) < 2 {
+		return nil, fmt.Errorf("payload is too short")
+	}
+
+	h := pkt.Header
+	h.PayloadType = f.APT
+	h.SequenceNumber = uint16(pkt.Payload[0])<<8 | uint16(pkt.Payload[1])
+	h.Padding = false
+
+	return &rtp.Packet{
+		Header:  h,
+		Payload: pkt.Payload[2:],
+	}, nil
+}
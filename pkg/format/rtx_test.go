@@ -0,0 +1,87 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTXAttributes(t *testing.T) {
+	format := &RTX{
+		PayloadTyp: 97,
+		ClockRat:   90000,
+		APT:        96,
+	}
+	require.Equal(t, "RTX", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestRTXUnwrap(t *testing.T) {
+	format := &RTX{
+		PayloadTyp: 97,
+		ClockRat:   90000,
+		APT:        96,
+	}
+
+	pkt, err := format.Unwrap(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    97,
+			SequenceNumber: 12,
+			Timestamp:      45343,
+			SSRC:           563423,
+		},
+		Payload: []byte{0x04, 0xd2, 0x01, 0x02, 0x03, 0x04},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 1234,
+			Timestamp:      45343,
+			SSRC:           563423,
+		},
+		Payload: []byte{0x01, 0x02, 0x03, 0x04},
+	}, pkt)
+
+	_, err = format.Unwrap(&rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			PayloadType: 97,
+		},
+		Payload: []byte{0x04},
+	})
+	require.Error(t, err)
+}
+
+func FuzzUnmarshalRTX(f *testing.F) {
+	f.Fuzz(func(
+		_ *testing.T,
+		a string,
+		b bool,
+		c string,
+		d bool,
+		e string,
+	) {
+		ma := map[string]string{}
+
+		if b {
+			ma["apt"] = c
+		}
+
+		if d {
+			ma["rtx-time"] = e
+		}
+
+		fo, err := Unmarshal("video", 96, "rtx/"+a, ma)
+		if err == nil {
+			fo.RTPMap()
+			fo.FMTP()
+		}
+	})
+}
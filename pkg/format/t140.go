@@ -0,0 +1,107 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/pion/rtp"
+
+	"github.com/voicecom/gortsplib/v4/pkg/format/rtpt140"
+)
+
+// T140 is the RTP format for real-time text (T.140).
+// Specification: https://datatracker.ietf.org/doc/html/rfc4103
+type T140 struct {
+	PayloadTyp uint8
+
+	// maximum number of characters per second (optional).
+	CPS *int
+
+	// payload type of the RED format that carries redundant text (optional).
+	// It is filled by description.Media when the media contains a RED format
+	// whose fmtp references this format.
+	REDPayloadType *uint8
+}
+
+func (f *T140) unmarshal(ctx *unmarshalContext) error {
+	f.PayloadTyp = ctx.payloadType
+
+	for key, val := range ctx.fmtp {
+		if key == "cps" {
+			n, err := strconv.ParseUint(val, 10, 31)
+			if err != nil {
+				return fmt.Errorf("invalid cps: %v", val)
+			}
+
+			v2 := int(n)
+			f.CPS = &v2
+		}
+	}
+
+	return nil
+}
+
+// Codec implements Format.
+func (f *T140) Codec() string {
+	return "T.140"
+}
+
+// ClockRate implements Format.
+func (f *T140) ClockRate() int {
+	return 1000
+}
+
+// PayloadType implements Format.
+func (f *T140) PayloadType() uint8 {
+	return f.PayloadTyp
+}
+
+// RTPMap implements Format.
+func (f *T140) RTPMap() string {
+	return "t140/1000"
+}
+
+// FMTP implements Format.
+func (f *T140) FMTP() map[string]string {
+	if f.CPS == nil {
+		return nil
+	}
+
+	return map[string]string{
+		"cps": strconv.FormatInt(int64(*f.CPS), 10),
+	}
+}
+
+// PTSEqualsDTS implements Format.
+func (f *T140) PTSEqualsDTS(*rtp.Packet) bool {
+	return true
+}
+
+// CreateDecoder creates a decoder able to decode the content of the format.
+func (f *T140) CreateDecoder() (*rtpt140.Decoder, error) {
+	d := &rtpt140.Decoder{
+		REDPayloadType: f.REDPayloadType,
+	}
+
+	err := d.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// CreateEncoder creates an encoder able to encode the content of the format.
+func (f *T140) CreateEncoder() (*rtpt140.Encoder, error) {
+	e := &rtpt140.Encoder{
+		PayloadType:    f.PayloadTyp,
+		REDPayloadType: f.REDPayloadType,
+	}
+
+	err := e.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
@@ -0,0 +1,52 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestT140Attributes(t *testing.T) {
+	format := &T140{
+		PayloadTyp: 98,
+	}
+	require.Equal(t, "T.140", format.Codec())
+	require.Equal(t, 1000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+}
+
+func TestT140DecEncoder(t *testing.T) {
+	redPayloadType := uint8(100)
+
+	format := &T140{
+		PayloadTyp:     98,
+		REDPayloadType: &redPayloadType,
+	}
+
+	enc, err := format.CreateEncoder()
+	require.NoError(t, err)
+
+	pkt, err := enc.Encode([]byte("hello"), 1000)
+	require.NoError(t, err)
+	require.Equal(t, redPayloadType, pkt.PayloadType)
+
+	dec, err := format.CreateDecoder()
+	require.NoError(t, err)
+
+	byts, err := dec.Decode(pkt)
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello"), byts)
+}
+
+func FuzzUnmarshalT140(f *testing.F) {
+	f.Fuzz(func(_ *testing.T, a string) {
+		fo, err := Unmarshal("text", 98, "t140/1000", map[string]string{
+			"cps": a,
+		})
+		if err == nil {
+			fo.RTPMap()
+			fo.FMTP()
+		}
+	})
+}
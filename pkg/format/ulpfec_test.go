@@ -0,0 +1,21 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestULPFECAttributes(t *testing.T) {
+	format := &ULPFEC{
+		PayloadTyp: 127,
+		ClockRat:   90000,
+	}
+	require.Equal(t, "ULPFEC", format.Codec())
+	require.Equal(t, 90000, format.ClockRate())
+	require.Equal(t, true, format.PTSEqualsDTS(&rtp.Packet{}))
+
+	_, err := format.CreateDecoder()
+	require.NoError(t, err)
+}
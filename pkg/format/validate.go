@@ -0,0 +1,33 @@
+package format
+
+import (
+	"fmt"
+)
+
+// isDynamicOnly checks whether a format can only use dynamic payload types.
+func isDynamicOnly(forma Format) bool {
+	switch forma.(type) {
+	case *AV1, *VP9, *VP8, *H265, *H264, *RawVideo, *MPEG4Video,
+		*Opus, *Vorbis, *MPEG4Audio, *AC3, *Speex, *G726,
+		*T140, *RTX, *RED, *ULPFEC:
+		return true
+	}
+	return false
+}
+
+// ValidatePayloadType checks whether the payload type of a format is valid.
+// Payload types must be lower than 128, and codecs without a static payload type
+// must use a dynamic one (96-127), as specified in RFC3551.
+func ValidatePayloadType(forma Format) error {
+	pt := forma.PayloadType()
+
+	if pt > 127 {
+		return fmt.Errorf("%s: invalid payload type: %d", forma.Codec(), pt)
+	}
+
+	if isDynamicOnly(forma) && pt < 96 {
+		return fmt.Errorf("%s: payload type %d is not dynamic (96-127)", forma.Codec(), pt)
+	}
+
+	return nil
+}
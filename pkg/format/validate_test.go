@@ -0,0 +1,55 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePayloadType(t *testing.T) {
+	for _, ca := range []struct {
+		name  string
+		forma Format
+		err   string
+	}{
+		{
+			"h264 dynamic",
+			&H264{PayloadTyp: 96},
+			"",
+		},
+		{
+			"h264 static",
+			&H264{PayloadTyp: 26},
+			"H264: payload type 26 is not dynamic (96-127)",
+		},
+		{
+			"vp8 static",
+			&VP8{PayloadTyp: 95},
+			"VP8: payload type 95 is not dynamic (96-127)",
+		},
+		{
+			"opus static",
+			&Opus{PayloadTyp: 0, ChannelCount: 2},
+			"Opus: payload type 0 is not dynamic (96-127)",
+		},
+		{
+			"g711 static",
+			&G711{PayloadTyp: 8, MULaw: false, SampleRate: 8000, ChannelCount: 1},
+			"",
+		},
+		{
+			"out of range",
+			&Generic{PayloadTyp: 200},
+			"Generic: invalid payload type: 200",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			err := ValidatePayloadType(ca.forma)
+			if ca.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, ca.err)
+			}
+		})
+	}
+}
@@ -85,6 +85,15 @@ func (f *VP8) PTSEqualsDTS(*rtp.Packet) bool {
 	return true
 }
 
+// IsRandomAccess checks whether a frame can be decoded
+// independently from previous ones (i.e. it is a key frame).
+func (f *VP8) IsRandomAccess(frame []byte) bool {
+	// Specification: RFC6386, section 9.1
+	return len(frame) >= 10 &&
+		(frame[0]&0x01) == 0 &&
+		frame[3] == 0x9d && frame[4] == 0x01 && frame[5] == 0x2a
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *VP8) CreateDecoder() (*rtpvp8.Decoder, error) {
 	d := &rtpvp8.Decoder{}
@@ -34,6 +34,22 @@ func TestVP8DecEncoder(t *testing.T) {
 	require.Equal(t, []byte{0x01, 0x02, 0x03, 0x04}, byts)
 }
 
+func TestVP8IsRandomAccess(t *testing.T) {
+	format := &VP8{}
+
+	require.Equal(t, true, format.IsRandomAccess([]byte{ // key frame, 640x480
+		0x50, 0x42, 0x00, 0x9d, 0x01, 0x2a, 0x80, 0x02,
+		0xe0, 0x01, 0x00, 0x47, 0x08, 0x85, 0x85,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{ // inter frame
+		0x31, 0x0e, 0x00, 0x11, 0xa0, 0xfe, 0xf8, 0x23,
+		0x0e, 0x35, 0x02, 0xb8,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{0x50, 0x42}))
+}
+
 func FuzzUnmarshalVP8(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/vp9"
 	"github.com/pion/rtp"
 
 	"github.com/voicecom/gortsplib/v4/pkg/format/rtpvp9"
@@ -13,9 +14,16 @@ import (
 // Specification: https://datatracker.ietf.org/doc/html/draft-ietf-payload-vp9-16
 type VP9 struct {
 	PayloadTyp uint8
-	MaxFR      *int
-	MaxFS      *int
-	ProfileID  *int
+
+	// maximum frame rate (optional).
+	MaxFR *int
+
+	// maximum frame size, in macroblocks (optional).
+	MaxFS *int
+
+	// profile (optional).
+	// Profiles 2 and 3 are needed to decode 10 and 12-bit content.
+	ProfileID *int
 }
 
 func (f *VP9) unmarshal(ctx *unmarshalContext) error {
@@ -97,6 +105,14 @@ func (f *VP9) PTSEqualsDTS(*rtp.Packet) bool {
 	return true
 }
 
+// IsRandomAccess checks whether a frame can be decoded
+// independently from previous ones (i.e. it is a key frame).
+func (f *VP9) IsRandomAccess(frame []byte) bool {
+	var h vp9.Header
+	err := h.Unmarshal(frame)
+	return err == nil && !h.ShowExistingFrame && !h.NonKeyFrame
+}
+
 // CreateDecoder creates a decoder able to decode the content of the format.
 func (f *VP9) CreateDecoder() (*rtpvp9.Decoder, error) {
 	d := &rtpvp9.Decoder{}
@@ -34,6 +34,24 @@ func TestVP9DecEncoder(t *testing.T) {
 	require.Equal(t, []byte{0x82, 0x49, 0x83, 0x42, 0x0, 0x77, 0xf0, 0x32, 0x34}, byts)
 }
 
+func TestVP9IsRandomAccess(t *testing.T) {
+	format := &VP9{}
+
+	require.Equal(t, true, format.IsRandomAccess([]byte{ // key frame
+		0x82, 0x49, 0x83, 0x42, 0x00, 0x77, 0xf0, 0x32,
+		0x34, 0x30, 0x38, 0x24, 0x1c, 0x19, 0x40, 0x18,
+		0x03, 0x40, 0x5f, 0xb4,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{ // inter frame
+		0x86, 0x00, 0x40, 0x92, 0x88, 0x2c, 0x49, 0xe0,
+	}))
+
+	require.Equal(t, false, format.IsRandomAccess([]byte{ // show existing frame
+		0x88,
+	}))
+}
+
 func FuzzUnmarshalVP9(f *testing.F) {
 	f.Fuzz(func(
 		_ *testing.T,
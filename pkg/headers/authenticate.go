@@ -62,6 +62,9 @@ type Authenticate struct {
 
 	// algorithm
 	Algorithm *AuthAlgorithm
+
+	// quality of protection options (i.e. "auth" or "auth,auth-int")
+	QOP *string
 }
 
 // Unmarshal decodes a WWW-Authenticate header.
@@ -146,6 +149,9 @@ func (h *Authenticate) Unmarshal(v base.HeaderValue) error {
 					return err
 				}
 				h.Algorithm = &a
+
+			case "qop":
+				h.QOP = &v
 			}
 		}
 
@@ -182,5 +188,9 @@ func (h Authenticate) Marshal() base.HeaderValue {
 		}
 	}
 
+	if h.QOP != nil {
+		ret += ", qop=\"" + *h.QOP + "\""
+	}
+
 	return base.HeaderValue{ret}
 }
@@ -77,6 +77,20 @@ var casesAuthenticate = []struct {
 			Stale:  stringPtr("FALSE"),
 		},
 	},
+	{
+		"digest with qop",
+		base.HeaderValue{`Digest realm="testrealm@host.com", qop="auth,auth-int", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", opaque="5ccc069c403ebaf9f0171e9517f40e41"`},
+		base.HeaderValue{`Digest realm="testrealm@host.com", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", ` +
+			`opaque="5ccc069c403ebaf9f0171e9517f40e41", qop="auth,auth-int"`},
+		Authenticate{
+			Method: AuthMethodDigest,
+			Realm:  "testrealm@host.com",
+			Nonce:  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+			Opaque: stringPtr("5ccc069c403ebaf9f0171e9517f40e41"),
+			QOP:    stringPtr("auth,auth-int"),
+		},
+	},
 	{
 		"digest md5 explicit",
 		base.HeaderValue{`Digest realm="4419b63f5e51", nonce="8b84a3b789283a8bea8da7fa7d41f08b", ` +
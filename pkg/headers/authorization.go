@@ -47,6 +47,15 @@ type Authorization struct {
 
 	// algorithm
 	Algorithm *AuthAlgorithm
+
+	// quality of protection
+	QOP *string
+
+	// nonce count, mandatory when QOP is present
+	NC *string
+
+	// client nonce, mandatory when QOP is present
+	CNonce *string
 }
 
 // Unmarshal decodes an Authorization header.
@@ -135,12 +144,25 @@ func (h *Authorization) Unmarshal(v base.HeaderValue) error {
 					return err
 				}
 				h.Algorithm = &a
+
+			case "qop":
+				h.QOP = &v
+
+			case "nc":
+				h.NC = &v
+
+			case "cnonce":
+				h.CNonce = &v
 			}
 		}
 
 		if !realmReceived || !usernameReceived || !nonceReceived || !uriReceived || !responseReceived {
 			return fmt.Errorf("one or more digest fields are missing")
 		}
+
+		if h.QOP != nil && (h.NC == nil || h.CNonce == nil) {
+			return fmt.Errorf("nc or cnonce is missing")
+		}
 	}
 
 	return nil
@@ -169,5 +191,17 @@ func (h Authorization) Marshal() base.HeaderValue {
 		}
 	}
 
+	if h.QOP != nil {
+		ret += ", qop=" + *h.QOP
+	}
+
+	if h.NC != nil {
+		ret += ", nc=" + *h.NC
+	}
+
+	if h.CNonce != nil {
+		ret += ", cnonce=\"" + *h.CNonce + "\""
+	}
+
 	return base.HeaderValue{ret}
 }
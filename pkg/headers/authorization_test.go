@@ -83,6 +83,29 @@ var casesAuthorization = []struct {
 			Algorithm: algorithmPtr(AuthAlgorithmMD5),
 		},
 	},
+	{
+		"digest with qop",
+		base.HeaderValue{`Digest username="Mufasa", realm="testrealm@host.com", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", ` +
+			`qop=auth, nc=00000001, cnonce="0a4f113b", response="6629fae49393a05397450978507c4ef1", ` +
+			`opaque="5ccc069c403ebaf9f0171e9517f40e41"`},
+		base.HeaderValue{`Digest username="Mufasa", realm="testrealm@host.com", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", ` +
+			`response="6629fae49393a05397450978507c4ef1", opaque="5ccc069c403ebaf9f0171e9517f40e41", ` +
+			`qop=auth, nc=00000001, cnonce="0a4f113b"`},
+		Authorization{
+			Method:   AuthMethodDigest,
+			Username: "Mufasa",
+			Realm:    "testrealm@host.com",
+			Nonce:    "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+			URI:      "/dir/index.html",
+			Response: "6629fae49393a05397450978507c4ef1",
+			Opaque:   stringPtr("5ccc069c403ebaf9f0171e9517f40e41"),
+			QOP:      stringPtr("auth"),
+			NC:       stringPtr("00000001"),
+			CNonce:   stringPtr("0a4f113b"),
+		},
+	},
 	{
 		"digest sha256",
 		base.HeaderValue{`Digest username="admin", realm="IP Camera(AB705)", ` +
@@ -149,4 +172,12 @@ func TestAuthorizationAdditionalErrors(t *testing.T) {
 		err := h.Unmarshal(base.HeaderValue{"a", "b"})
 		require.Error(t, err)
 	}()
+
+	func() {
+		var h Authorization
+		err := h.Unmarshal(base.HeaderValue{`Digest username="Mufasa", realm="testrealm@host.com", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", ` +
+			`qop=auth, response="6629fae49393a05397450978507c4ef1"`})
+		require.EqualError(t, err, "nc or cnonce is missing")
+	}()
 }
@@ -42,12 +42,18 @@ func (t *RangeSMPTETime) unmarshal(s string) error {
 		return err
 	}
 	mins := tmp
+	if mins >= 60 {
+		return fmt.Errorf("invalid minutes (%v)", parts[1])
+	}
 
 	tmp, err = strconv.ParseUint(parts[2], 10, 64)
 	if err != nil {
 		return err
 	}
 	seconds := tmp
+	if seconds >= 60 {
+		return fmt.Errorf("invalid seconds (%v)", parts[2])
+	}
 
 	t.Time = time.Duration(seconds+mins*60+hours*3600) * time.Second
 
@@ -115,6 +121,13 @@ func (r *RangeSMPTE) unmarshal(start string, end string) error {
 		if err != nil {
 			return err
 		}
+
+		if v.Time < r.Start.Time ||
+			(v.Time == r.Start.Time && (v.Frame < r.Start.Frame ||
+				(v.Frame == r.Start.Frame && v.Subframe < r.Start.Subframe))) {
+			return fmt.Errorf("end is before start")
+		}
+
 		r.End = &v
 	}
 
@@ -129,6 +142,27 @@ func (r RangeSMPTE) marshal() string {
 	return ret
 }
 
+// isNPTSeconds checks that s is in the form 1*DIGIT [ "." *DIGIT ].
+func isNPTSeconds(s string) bool {
+	intPart, _, _ := strings.Cut(s, ".")
+	if intPart == "" {
+		return false
+	}
+
+	dotFound := false
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+		case c == '.' && !dotFound:
+			dotFound = true
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
 func unmarshalRangeNPTTime(d *time.Duration, s string) error {
 	parts := strings.Split(s, ":")
 	if len(parts) > 3 {
@@ -146,21 +180,32 @@ func unmarshalRangeNPTTime(d *time.Duration, s string) error {
 	}
 
 	var mins uint64
-	if len(parts) >= 2 {
+	if len(parts) == 2 {
 		tmp, err := strconv.ParseUint(parts[0], 10, 64)
 		if err != nil {
 			return err
 		}
 		mins = tmp
+		if mins >= 60 {
+			return fmt.Errorf("invalid minutes (%v)", parts[0])
+		}
 		parts = parts[1:]
 	}
 
+	if !isNPTSeconds(parts[0]) {
+		return fmt.Errorf("invalid NPT time (%v)", s)
+	}
+
 	tmp, err := strconv.ParseFloat(parts[0], 64)
 	if err != nil {
 		return err
 	}
 	seconds := tmp
 
+	if strings.Contains(s, ":") && seconds >= 60 {
+		return fmt.Errorf("invalid seconds (%v)", parts[0])
+	}
+
 	*d = time.Duration(seconds*float64(time.Second)) +
 		time.Duration(mins*60+hours*3600)*time.Second
 
@@ -175,12 +220,20 @@ func marshalRangeNPTTime(d time.Duration) string {
 type RangeNPT struct {
 	Start time.Duration
 	End   *time.Duration
+
+	// whether the range starts at the current position ("now").
+	// When true, Start is ignored.
+	Now bool
 }
 
 func (r *RangeNPT) unmarshal(start string, end string) error {
-	err := unmarshalRangeNPTTime(&r.Start, start)
-	if err != nil {
-		return err
+	if start == "now" {
+		r.Now = true
+	} else {
+		err := unmarshalRangeNPTTime(&r.Start, start)
+		if err != nil {
+			return err
+		}
 	}
 
 	if end != "" {
@@ -189,6 +242,11 @@ func (r *RangeNPT) unmarshal(start string, end string) error {
 		if err != nil {
 			return err
 		}
+
+		if !r.Now && v < r.Start {
+			return fmt.Errorf("end is before start")
+		}
+
 		r.End = &v
 	}
 
@@ -196,7 +254,13 @@ func (r *RangeNPT) unmarshal(start string, end string) error {
 }
 
 func (r RangeNPT) marshal() string {
-	ret := "npt=" + marshalRangeNPTTime(r.Start) + "-"
+	ret := "npt="
+	if r.Now {
+		ret += "now"
+	} else {
+		ret += marshalRangeNPTTime(r.Start)
+	}
+	ret += "-"
 	if r.End != nil {
 		ret += marshalRangeNPTTime(*r.End)
 	}
@@ -204,6 +268,7 @@ func (r RangeNPT) marshal() string {
 }
 
 func unmarshalRangeUTCTime(t *time.Time, s string) error {
+	// fractional seconds are accepted by time.Parse() even if they're not in the layout
 	tmp, err := time.Parse("20060102T150405Z", s)
 	if err != nil {
 		return err
@@ -213,7 +278,7 @@ func unmarshalRangeUTCTime(t *time.Time, s string) error {
 }
 
 func marshalRangeUTCTime(t time.Time) string {
-	return t.Format("20060102T150405Z")
+	return t.UTC().Format("20060102T150405.999999999Z")
 }
 
 // RangeUTC is a range expressed in UTC units.
@@ -234,6 +299,11 @@ func (r *RangeUTC) unmarshal(start string, end string) error {
 		if err != nil {
 			return err
 		}
+
+		if v.Before(r.Start) {
+			return fmt.Errorf("end is before start")
+		}
+
 		r.End = &v
 	}
 
@@ -352,3 +422,72 @@ func (h Range) Marshal() base.HeaderValue {
 	}
 	return base.HeaderValue{v}
 }
+
+// ParseRange decodes a range, like the ones contained in Range headers
+// and in the SDP "range" attribute.
+func ParseRange(s string) (*Range, error) {
+	var h Range
+	err := h.Unmarshal(base.HeaderValue{s})
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Format returns the format of the range ("npt", "smpte" or "clock").
+func (h Range) Format() string {
+	switch h.Value.(type) {
+	case *RangeSMPTE:
+		return "smpte"
+
+	case *RangeNPT:
+		return "npt"
+
+	case *RangeUTC:
+		return "clock"
+	}
+
+	return ""
+}
+
+// Start returns the start of the range.
+// SMPTE frames are ignored, clock ranges start at zero and
+// NPT ranges starting at "now" start at zero.
+func (h Range) Start() time.Duration {
+	switch v := h.Value.(type) {
+	case *RangeSMPTE:
+		return v.Start.Time
+
+	case *RangeNPT:
+		if v.Now {
+			return 0
+		}
+		return v.Start
+	}
+
+	return 0
+}
+
+// End returns the end of the range, if present.
+// SMPTE frames are ignored, and the end of clock ranges is expressed
+// relatively to their start.
+func (h Range) End() (time.Duration, bool) {
+	switch v := h.Value.(type) {
+	case *RangeSMPTE:
+		if v.End != nil {
+			return v.End.Time, true
+		}
+
+	case *RangeNPT:
+		if v.End != nil {
+			return *v.End, true
+		}
+
+	case *RangeUTC:
+		if v.End != nil {
+			return v.End.Sub(v.Start), true
+		}
+	}
+
+	return 0, false
+}
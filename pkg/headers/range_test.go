@@ -86,6 +86,27 @@ var casesRange = []struct {
 			},
 		},
 	},
+	{
+		"npt now",
+		base.HeaderValue{`npt=now-`},
+		base.HeaderValue{`npt=now-`},
+		Range{
+			Value: &RangeNPT{
+				Now: true,
+			},
+		},
+	},
+	{
+		"npt minutes and seconds",
+		base.HeaderValue{`npt=05:35.25-06:00`},
+		base.HeaderValue{`npt=335.25-360`},
+		Range{
+			Value: &RangeNPT{
+				Start: time.Duration(335.25 * float64(time.Second)),
+				End:   durationPtr(360 * time.Second),
+			},
+		},
+	},
 	{
 		"clock",
 		base.HeaderValue{`clock=19961108T142300Z-19961108T143520Z`},
@@ -107,6 +128,16 @@ var casesRange = []struct {
 			},
 		},
 	},
+	{
+		"clock fractional",
+		base.HeaderValue{`clock=20240101T120000.25Z-`},
+		base.HeaderValue{`clock=20240101T120000.25Z-`},
+		Range{
+			Value: &RangeUTC{
+				Start: time.Date(2024, 1, 1, 12, 0, 0, 250000000, time.UTC),
+			},
+		},
+	},
 	{
 		"time",
 		base.HeaderValue{`clock=19960213T143205Z-;time=19970123T143720Z`},
@@ -148,6 +179,7 @@ func FuzzRangeUnmarshal(f *testing.F) {
 	f.Add("smtpe=")
 	f.Add("npt=")
 	f.Add("clock=")
+	f.Add("npt=now-")
 
 	f.Fuzz(func(_ *testing.T, b string) {
 		var h Range
@@ -158,6 +190,71 @@ func FuzzRangeUnmarshal(f *testing.F) {
 	})
 }
 
+func TestRangeUnmarshalErrors(t *testing.T) {
+	for _, ca := range []struct {
+		name string
+		v    string
+		err  string
+	}{
+		{
+			"missing spec",
+			"time=19970123T143720Z",
+			"value not found (time=19970123T143720Z)",
+		},
+		{
+			"missing dash",
+			"npt=10",
+			"invalid value (10)",
+		},
+		{
+			"npt invalid seconds",
+			"npt=1e3-",
+			"invalid NPT time (1e3)",
+		},
+		{
+			"npt invalid minutes",
+			"npt=0:60:00-",
+			"invalid minutes (60)",
+		},
+		{
+			"npt seconds out of range",
+			"npt=0:10:60-",
+			"invalid seconds (60)",
+		},
+		{
+			"npt end before start",
+			"npt=20-10",
+			"end is before start",
+		},
+		{
+			"smpte invalid minutes",
+			"smpte=10:61:00-",
+			"invalid minutes (61)",
+		},
+		{
+			"smpte end before start",
+			"smpte=10:07:00:05-10:07:00:04",
+			"end is before start",
+		},
+		{
+			"clock invalid",
+			"clock=20240101T1200Z-",
+			"parsing time \"20240101T1200Z\" as \"20060102T150405Z\": cannot parse \"Z\" as \"05\"",
+		},
+		{
+			"clock end before start",
+			"clock=20240101T120000Z-20240101T110000Z",
+			"end is before start",
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			var h Range
+			err := h.Unmarshal(base.HeaderValue{ca.v})
+			require.EqualError(t, err, ca.err)
+		})
+	}
+}
+
 func TestRangeAdditionalErrors(t *testing.T) {
 	func() {
 		var h Range
@@ -171,3 +268,88 @@ func TestRangeAdditionalErrors(t *testing.T) {
 		require.Error(t, err)
 	}()
 }
+
+func TestParseRange(t *testing.T) {
+	for _, ca := range []struct {
+		name   string
+		v      string
+		format string
+		start  time.Duration
+		end    *time.Duration
+	}{
+		{
+			"npt open",
+			"npt=3.52-",
+			"npt",
+			3520 * time.Millisecond,
+			nil,
+		},
+		{
+			"npt closed",
+			"npt=123.45-125",
+			"npt",
+			123450 * time.Millisecond,
+			durationPtr(125 * time.Second),
+		},
+		{
+			"npt hhmmss",
+			"npt=12:05:35.3-",
+			"npt",
+			(12*3600+5*60+35)*time.Second + 300*time.Millisecond,
+			nil,
+		},
+		{
+			"npt now",
+			"npt=now-",
+			"npt",
+			0,
+			nil,
+		},
+		{
+			"smpte open",
+			"smpte=10:12:33:20-",
+			"smpte",
+			(10*3600 + 12*60 + 33) * time.Second,
+			nil,
+		},
+		{
+			"smpte closed",
+			"smpte=10:07:00-10:07:33:05.01",
+			"smpte",
+			(10*3600 + 7*60) * time.Second,
+			durationPtr((10*3600 + 7*60 + 33) * time.Second),
+		},
+		{
+			"clock closed",
+			"clock=19961108T142300Z-19961108T143520Z",
+			"clock",
+			0,
+			durationPtr((12*60 + 20) * time.Second),
+		},
+		{
+			"clock with time",
+			"clock=19960213T143205Z-;time=19970123T143720Z",
+			"clock",
+			0,
+			nil,
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			h, err := ParseRange(ca.v)
+			require.NoError(t, err)
+			require.Equal(t, ca.format, h.Format())
+			require.Equal(t, ca.start, h.Start())
+
+			end, ok := h.End()
+			if ca.end == nil {
+				require.Equal(t, false, ok)
+			} else {
+				require.Equal(t, true, ok)
+				require.Equal(t, *ca.end, end)
+			}
+		})
+	}
+
+	_, err := ParseRange("npt=10-5")
+	require.Error(t, err)
+}
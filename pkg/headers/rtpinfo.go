@@ -18,7 +18,91 @@ type RTPInfoEntry struct {
 // RTPInfo is a RTP-Info header.
 type RTPInfo []*RTPInfoEntry
 
+// rtpInfoParamKey returns the key of a parameter of a RTP-Info entry,
+// if the key is among the ones that are supported.
+func rtpInfoParamKey(param string) (string, bool) {
+	param = strings.TrimLeft(param, " ")
+
+	k, _, ok := strings.Cut(param, "=")
+	if !ok {
+		return "", false
+	}
+
+	k = strings.ToLower(k)
+
+	switch k {
+	case "url", "seq", "rtptime", "ssrc":
+		return k, true
+	}
+
+	return "", false
+}
+
+// splitRTPInfo splits a header into parts, with the given separator.
+// Since URLs can contain separators (i.e. in queries),
+// a part is considered valid only if it starts with a supported key,
+// otherwise it is appended to the previous part.
+func splitRTPInfo(v string, separator string) []string {
+	var ret []string
+
+	for _, part := range strings.Split(v, separator) {
+		if _, ok := rtpInfoParamKey(part); !ok && len(ret) != 0 {
+			ret[len(ret)-1] += separator + part
+			continue
+		}
+		ret = append(ret, part)
+	}
+
+	return ret
+}
+
+func (e *RTPInfoEntry) unmarshal(v string) error {
+	urlReceived := false
+
+	for _, param := range splitRTPInfo(v, ";") {
+		// remove leading spaces
+		param = strings.TrimLeft(param, " ")
+
+		k, ok := rtpInfoParamKey(param)
+		if !ok {
+			// ignore non-standard keys
+			continue
+		}
+
+		val := param[len(k)+1:]
+
+		switch k {
+		case "url":
+			e.URL = strings.Trim(val, `"`)
+			urlReceived = true
+
+		case "seq":
+			vi, err := strconv.ParseUint(val, 10, 16)
+			if err != nil {
+				return err
+			}
+			vi2 := uint16(vi)
+			e.SequenceNumber = &vi2
+
+		case "rtptime":
+			vi, err := strconv.ParseUint(val, 10, 32)
+			if err != nil {
+				return err
+			}
+			vi2 := uint32(vi)
+			e.Timestamp = &vi2
+		}
+	}
+
+	if !urlReceived {
+		return fmt.Errorf("URL is missing")
+	}
+
+	return nil
+}
+
 // Unmarshal decodes a RTP-Info header.
+// URLs can be relative and can contain queries.
 func (h *RTPInfo) Unmarshal(v base.HeaderValue) error {
 	if len(v) == 0 {
 		return fmt.Errorf("value not provided")
@@ -28,50 +112,13 @@ func (h *RTPInfo) Unmarshal(v base.HeaderValue) error {
 		return fmt.Errorf("value provided multiple times (%v)", v)
 	}
 
-	for _, part := range strings.Split(v[0], ",") {
+	for _, part := range splitRTPInfo(v[0], ",") {
 		e := &RTPInfoEntry{}
-
-		// remove leading spaces
-		part = strings.TrimLeft(part, " ")
-
-		kvs, err := keyValParse(part, ';')
+		err := e.unmarshal(part)
 		if err != nil {
 			return err
 		}
 
-		urlReceived := false
-
-		for k, v := range kvs {
-			switch k {
-			case "url":
-				e.URL = v
-				urlReceived = true
-
-			case "seq":
-				vi, err := strconv.ParseUint(v, 10, 16)
-				if err != nil {
-					return err
-				}
-				vi2 := uint16(vi)
-				e.SequenceNumber = &vi2
-
-			case "rtptime":
-				vi, err := strconv.ParseUint(v, 10, 32)
-				if err != nil {
-					return err
-				}
-				vi2 := uint32(vi)
-				e.Timestamp = &vi2
-
-			default:
-				// ignore non-standard keys
-			}
-		}
-
-		if !urlReceived {
-			return fmt.Errorf("URL is missing")
-		}
-
 		*h = append(*h, e)
 	}
 
@@ -129,6 +129,36 @@ var casesRTPInfo = []struct {
 			},
 		},
 	},
+	{
+		"url with query",
+		base.HeaderValue{`url=rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=0;seq=1;rtptime=2,` +
+			`url=rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=1;seq=3`},
+		base.HeaderValue{`url=rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=0;seq=1;rtptime=2,` +
+			`url=rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=1;seq=3`},
+		RTPInfo{
+			{
+				URL:            "rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=0",
+				SequenceNumber: uint16Ptr(1),
+				Timestamp:      uint32Ptr(2),
+			},
+			{
+				URL:            "rtsp://127.0.0.1/stream?codecs=h264,aac;profile=1/trackID=1",
+				SequenceNumber: uint16Ptr(3),
+			},
+		},
+	},
+	{
+		"quoted url",
+		base.HeaderValue{`url="rtsp://127.0.0.1/test/trackID=0";seq=1;rtptime=2`},
+		base.HeaderValue{`url=rtsp://127.0.0.1/test/trackID=0;seq=1;rtptime=2`},
+		RTPInfo{
+			{
+				URL:            "rtsp://127.0.0.1/test/trackID=0",
+				SequenceNumber: uint16Ptr(1),
+				Timestamp:      uint32Ptr(2),
+			},
+		},
+	},
 }
 
 func TestRTPInfoUnmarshal(t *testing.T) {
@@ -144,6 +144,10 @@ type Transport struct {
 
 	// (optional) mode
 	Mode *TransportMode
+
+	// whether RTP and RTCP are multiplexed on the same port.
+	// Specification: https://datatracker.ietf.org/doc/html/rfc5761
+	RTCPMux bool
 }
 
 // Unmarshal decodes a Transport header.
@@ -268,6 +272,9 @@ func (h *Transport) Unmarshal(v base.HeaderValue) error {
 			}
 			h.Mode = &m
 
+		case "RTCP-mux", "rtcp-mux":
+			h.RTCPMux = true
+
 		default:
 			// ignore non-standard keys
 		}
@@ -335,6 +342,10 @@ func (h Transport) Marshal() base.HeaderValue {
 		rets = append(rets, "mode="+h.Mode.String())
 	}
 
+	if h.RTCPMux {
+		rets = append(rets, "RTCP-mux")
+	}
+
 	return base.HeaderValue{strings.Join(rets, ";")}
 }
 
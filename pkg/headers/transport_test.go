@@ -156,6 +156,17 @@ var casesTransport = []struct {
 			ServerPorts: &[2]int{3046, 3047},
 		},
 	},
+	{
+		"udp unicast play request with rtcp-mux",
+		base.HeaderValue{`RTP/AVP;unicast;client_port=3456-3456;RTCP-mux`},
+		base.HeaderValue{`RTP/AVP;unicast;client_port=3456-3456;RTCP-mux`},
+		Transport{
+			Protocol:    TransportProtocolUDP,
+			Delivery:    deliveryPtr(TransportDeliveryUnicast),
+			ClientPorts: &[2]int{3456, 3456},
+			RTCPMux:     true,
+		},
+	},
 	{
 		"invalid ssrc",
 		base.HeaderValue{`RTP/AVP;unicast;client_port=14236;source=172.16.8.2;server_port=56002;ssrc=1449463210`},
@@ -73,6 +73,14 @@ func (e ErrClientCannotSetupMediasDifferentURLs) Error() string {
 	return "cannot setup medias with different base URLs"
 }
 
+// ErrClientNoMediasAfterFilter is an error that can be returned by a client.
+type ErrClientNoMediasAfterFilter struct{}
+
+// Error implements the error interface.
+func (e ErrClientNoMediasAfterFilter) Error() string {
+	return "all medias have been discarded by MediaFilter"
+}
+
 // ErrClientUDPPortsZero is an error that can be returned by a client.
 type ErrClientUDPPortsZero struct{}
 
@@ -97,6 +105,24 @@ func (e ErrClientServerPortsNotProvided) Error() string {
 	return "server ports have not been provided. Use AnyPortEnable to communicate with this server"
 }
 
+// ErrClientRTCPMuxNotSupported is an error that can be returned by a client.
+type ErrClientRTCPMuxNotSupported struct{}
+
+// Error implements the error interface.
+func (e ErrClientRTCPMuxNotSupported) Error() string {
+	return "server does not support multiplexing RTP and RTCP on the same port"
+}
+
+// ErrClientRTPInfoHeaderInvalid is an error that can be returned by a client.
+type ErrClientRTPInfoHeaderInvalid struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrClientRTPInfoHeaderInvalid) Error() string {
+	return fmt.Sprintf("invalid RTP-Info header: %v", e.Err)
+}
+
 // ErrClientTransportHeaderInvalid is an error that can be returned by a client.
 type ErrClientTransportHeaderInvalid struct {
 	Err error
@@ -330,6 +356,17 @@ func (e ErrClientAuthSetup) Error() string {
 	return fmt.Sprintf("unable to setup authentication: %s", e.Err)
 }
 
+// ErrClientAuthFailed is an error that can be returned by a client.
+type ErrClientAuthFailed struct {
+	StatusCode base.StatusCode
+	Realm      string
+}
+
+// Error implements the error interface.
+func (e ErrClientAuthFailed) Error() string {
+	return fmt.Sprintf("authentication failed (status code %d, realm '%s')", e.StatusCode, e.Realm)
+}
+
 // ErrClientSDPInvalid is an error that can be returned by a client.
 type ErrClientSDPInvalid struct {
 	Err error
@@ -339,3 +376,71 @@ type ErrClientSDPInvalid struct {
 func (e ErrClientSDPInvalid) Error() string {
 	return fmt.Sprintf("invalid SDP: %v", e.Err)
 }
+
+// ErrClientSDPLineSkipped is an error that can be returned by a client.
+type ErrClientSDPLineSkipped struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrClientSDPLineSkipped) Error() string {
+	return fmt.Sprintf("skipped invalid SDP line: %v", e.Err)
+}
+
+// ErrClientAllURLsFailed is an error that can be returned by a client.
+type ErrClientAllURLsFailed struct {
+	URLs []*base.URL
+	Errs []error
+}
+
+// Error implements the error interface.
+func (e ErrClientAllURLsFailed) Error() string {
+	ret := "all URLs failed:"
+	for i, u := range e.URLs {
+		if i != 0 {
+			ret += ";"
+		}
+		ret += " " + u.CloneWithoutCredentials().String() + ": " + e.Errs[i].Error()
+	}
+	return ret
+}
+
+// Unwrap returns the errors of each URL.
+func (e ErrClientAllURLsFailed) Unwrap() []error {
+	return e.Errs
+}
+
+// ErrClientRTCPFeedbackNotSupported is an error that can be returned by a client.
+type ErrClientRTCPFeedbackNotSupported struct {
+	Feedback string
+}
+
+// Error implements the error interface.
+func (e ErrClientRTCPFeedbackNotSupported) Error() string {
+	return fmt.Sprintf("RTCP feedback '%s' is not supported by the format", e.Feedback)
+}
+
+// ErrClientSenderSSRCUnknown is an error that can be returned by a client.
+type ErrClientSenderSSRCUnknown struct{}
+
+// Error implements the error interface.
+func (e ErrClientSenderSSRCUnknown) Error() string {
+	return "SSRC of the sender is not known yet"
+}
+
+// ErrClientSessionMediaFailed is an error that can be returned by a client.
+type ErrClientSessionMediaFailed struct {
+	Session int
+	Media   int
+	Err     error
+}
+
+// Error implements the error interface.
+func (e ErrClientSessionMediaFailed) Error() string {
+	return fmt.Sprintf("session %d, media %d: %v", e.Session, e.Media, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e ErrClientSessionMediaFailed) Unwrap() error {
+	return e.Err
+}
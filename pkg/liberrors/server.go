@@ -112,6 +112,26 @@ func (e ErrServerTransportHeaderInvalidInterleavedIDs) Error() string {
 	return "invalid interleaved IDs"
 }
 
+// ErrServerRangeHeaderInvalid is an error that can be returned by a server.
+type ErrServerRangeHeaderInvalid struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e ErrServerRangeHeaderInvalid) Error() string {
+	return fmt.Sprintf("invalid range header: %v", e.Err)
+}
+
+// ErrServerScaleHeaderInvalid is an error that can be returned by a server.
+type ErrServerScaleHeaderInvalid struct {
+	Value string
+}
+
+// Error implements the error interface.
+func (e ErrServerScaleHeaderInvalid) Error() string {
+	return fmt.Sprintf("invalid scale header: '%s'", e.Value)
+}
+
 // ErrServerTransportHeaderInterleavedIDsInUse is an error that can be returned by a server.
 type ErrServerTransportHeaderInterleavedIDsInUse struct{}
 
@@ -136,6 +156,16 @@ func (e ErrServerMediasDifferentProtocols) Error() string {
 	return "can't setup medias with different protocols"
 }
 
+// ErrServerCannotForceTransport is an error that can be returned by a server.
+type ErrServerCannotForceTransport struct {
+	Transport fmt.Stringer
+}
+
+// Error implements the error interface.
+func (e ErrServerCannotForceTransport) Error() string {
+	return fmt.Sprintf("unable to force transport %v", e.Transport)
+}
+
 // ErrServerNoMediasSetup is an error that can be returned by a server.
 type ErrServerNoMediasSetup struct{}
 
@@ -152,6 +182,16 @@ func (e ErrServerNotAllAnnouncedMediasSetup) Error() string {
 	return "not all announced medias have been setup"
 }
 
+// ErrServerMediaTypeNotAllowed is an error that can be returned by a server.
+type ErrServerMediaTypeNotAllowed struct {
+	MediaType string
+}
+
+// Error implements the error interface.
+func (e ErrServerMediaTypeNotAllowed) Error() string {
+	return fmt.Sprintf("media type '%s' is not allowed", e.MediaType)
+}
+
 // ErrServerLinkedToOtherSession is an error that can be returned by a server.
 type ErrServerLinkedToOtherSession struct{}
 
@@ -270,3 +310,41 @@ func (ErrServerPathNoSlash) Error() string {
 		"This typically happens when VLC fails a request, and then switches to an " +
 		"unsupported RTSP dialect"
 }
+
+// ErrServerSessionNotReading is an error that can be returned by a server.
+type ErrServerSessionNotReading struct{}
+
+// Error implements the error interface.
+func (e ErrServerSessionNotReading) Error() string {
+	return "session is not reading a stream with a unicast transport"
+}
+
+// ErrServerRTCPFeedbackNotSupported is an error that can be returned by a server.
+type ErrServerRTCPFeedbackNotSupported struct {
+	Feedback string
+}
+
+// Error implements the error interface.
+func (e ErrServerRTCPFeedbackNotSupported) Error() string {
+	return fmt.Sprintf("RTCP feedback '%s' is not supported by the format", e.Feedback)
+}
+
+// ErrServerSenderSSRCUnknown is an error that can be returned by a server.
+type ErrServerSenderSSRCUnknown struct{}
+
+// Error implements the error interface.
+func (e ErrServerSenderSSRCUnknown) Error() string {
+	return "SSRC of the sender is not known yet"
+}
+
+// ErrServerPacketTooLarge is an error that can be returned by a server.
+type ErrServerPacketTooLarge struct {
+	L   int
+	Max int
+}
+
+// Error implements the error interface.
+func (e ErrServerPacketTooLarge) Error() string {
+	return fmt.Sprintf("packet size (%d) is greater than maximum allowed (%d)",
+		e.L, e.Max)
+}
@@ -60,6 +60,8 @@ type RTCPReceiver struct {
 }
 
 // New allocates a RTCPReceiver.
+// If period is zero, reports are not sent automatically
+// and can be generated with GenerateRR().
 func New(
 	clockRate int,
 	receiverSSRC *uint32,
@@ -89,7 +91,11 @@ func New(
 		done:            make(chan struct{}),
 	}
 
-	go rr.run()
+	if period != 0 {
+		go rr.run()
+	} else {
+		close(rr.done)
+	}
 
 	return rr, nil
 }
@@ -120,7 +126,7 @@ func (rr *RTCPReceiver) run() {
 	}
 }
 
-func (rr *RTCPReceiver) report() rtcp.Packet {
+func (rr *RTCPReceiver) report() *rtcp.ReceiverReport {
 	rr.mutex.Lock()
 	defer rr.mutex.Unlock()
 
@@ -161,6 +167,16 @@ func (rr *RTCPReceiver) report() rtcp.Packet {
 	return report
 }
 
+// GenerateRR generates a receiver report from the packets processed so far.
+// Loss statistics are computed since the previous report.
+func (rr *RTCPReceiver) GenerateRR() (*rtcp.ReceiverReport, error) {
+	report := rr.report()
+	if report == nil {
+		return nil, fmt.Errorf("no RTP packets have been processed yet")
+	}
+	return report, nil
+}
+
 // ProcessPacket extracts the needed data from RTP packets.
 func (rr *RTCPReceiver) ProcessPacket(pkt *rtp.Packet, system time.Time, ptsEqualsDTS bool) error {
 	rr.mutex.Lock()
@@ -262,3 +278,8 @@ func (rr *RTCPReceiver) SenderSSRC() (uint32, bool) {
 	defer rr.mutex.RUnlock()
 	return rr.senderSSRC, rr.firstRTPPacketReceived
 }
+
+// ReceiverSSRC returns the SSRC used in outgoing RTCP packets.
+func (rr *RTCPReceiver) ReceiverSSRC() uint32 {
+	return rr.receiverSSRC
+}
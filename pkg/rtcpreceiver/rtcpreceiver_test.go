@@ -390,3 +390,48 @@ func TestRTCPReceiverJitter(t *testing.T) {
 
 	<-done
 }
+
+func TestRTCPReceiverGenerateRR(t *testing.T) {
+	rr, err := New(
+		90000,
+		uint32Ptr(0x65f83afb),
+		0,
+		func() time.Time {
+			return time.Date(2008, 0o5, 20, 22, 15, 22, 0, time.UTC)
+		},
+		nil)
+	require.NoError(t, err)
+	defer rr.Close()
+
+	_, err = rr.GenerateRR()
+	require.Error(t, err)
+
+	for _, seqNum := range []uint16{946, 948} {
+		err = rr.ProcessPacket(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				Marker:         true,
+				PayloadType:    96,
+				SequenceNumber: seqNum,
+				Timestamp:      0xafb45733,
+				SSRC:           0xba9da416,
+			},
+			Payload: []byte("\x00\x00"),
+		}, time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC), true)
+		require.NoError(t, err)
+	}
+
+	report, err := rr.GenerateRR()
+	require.NoError(t, err)
+	require.Equal(t, &rtcp.ReceiverReport{
+		SSRC: 0x65f83afb,
+		Reports: []rtcp.ReceptionReport{
+			{
+				SSRC:               0xba9da416,
+				LastSequenceNumber: 948,
+				FractionLost:       85,
+				TotalLost:          1,
+			},
+		},
+	}, report)
+}
@@ -2,6 +2,7 @@
 package rtcpsender
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -39,6 +40,8 @@ type RTCPSender struct {
 }
 
 // New allocates a RTCPSender.
+// If period is zero, reports are not sent automatically
+// and can be generated with GenerateSR().
 func New(
 	clockRate int,
 	period time.Duration,
@@ -58,7 +61,11 @@ func New(
 		done:            make(chan struct{}),
 	}
 
-	go rs.run()
+	if period != 0 {
+		go rs.run()
+	} else {
+		close(rs.done)
+	}
 
 	return rs
 }
@@ -89,7 +96,7 @@ func (rs *RTCPSender) run() {
 	}
 }
 
-func (rs *RTCPSender) report() rtcp.Packet {
+func (rs *RTCPSender) report() *rtcp.SenderReport {
 	rs.mutex.Lock()
 	defer rs.mutex.Unlock()
 
@@ -110,6 +117,15 @@ func (rs *RTCPSender) report() rtcp.Packet {
 	}
 }
 
+// GenerateSR generates a sender report from the packets processed so far.
+func (rs *RTCPSender) GenerateSR() (*rtcp.SenderReport, error) {
+	report := rs.report()
+	if report == nil {
+		return nil, fmt.Errorf("no RTP packets have been processed yet")
+	}
+	return report, nil
+}
+
 // ProcessPacket extracts data from RTP packets.
 func (rs *RTCPSender) ProcessPacket(pkt *rtp.Packet, ntp time.Time, ptsEqualsDTS bool) {
 	rs.mutex.Lock()
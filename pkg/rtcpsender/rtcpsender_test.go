@@ -96,3 +96,43 @@ func TestRTCPSender(t *testing.T) {
 
 	<-sent
 }
+
+func TestRTCPSenderGenerateSR(t *testing.T) {
+	rs := New(
+		90000,
+		0,
+		func() time.Time {
+			return time.Date(2008, 5, 20, 22, 16, 22, 0, time.UTC)
+		},
+		nil)
+	defer rs.Close()
+
+	_, err := rs.GenerateSR()
+	require.Error(t, err)
+
+	rs.ProcessPacket(&rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: 946,
+			Timestamp:      1287987768,
+			SSRC:           0xba9da416,
+		},
+		Payload: []byte("\x00\x00"),
+	}, time.Date(2008, 0o5, 20, 22, 15, 20, 0, time.UTC), true)
+
+	report, err := rs.GenerateSR()
+	require.NoError(t, err)
+	require.Equal(t, &rtcp.SenderReport{
+		SSRC: 0xba9da416,
+		NTPTime: func() uint64 {
+			d := time.Date(2008, 5, 20, 22, 15, 20, 0, time.UTC)
+			s := uint64(d.UnixNano()) + 2208988800*1000000000
+			return (s/1000000000)<<32 | (s % 1000000000)
+		}(),
+		RTPTime:     1287987768,
+		PacketCount: 1,
+		OctetCount:  2,
+	}, report)
+}
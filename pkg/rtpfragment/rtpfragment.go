@@ -0,0 +1,100 @@
+// Package rtpfragment contains utilities to split payloads into RTP packets
+// with a low number of allocations.
+package rtpfragment
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// Fragmenter splits payloads into fragments.
+// Each fragment is preceded by a prefix, that is left empty
+// and can be filled by the caller with codec-specific headers.
+type Fragmenter struct {
+	// maximum size of fragments, prefix included.
+	PayloadMaxSize int
+
+	// size of the prefix of the first fragment.
+	FirstPrefixLen int
+
+	// size of the prefix of the other fragments.
+	PrefixLen int
+}
+
+func (f Fragmenter) valid() bool {
+	return f.PayloadMaxSize > f.FirstPrefixLen && f.PayloadMaxSize > f.PrefixLen
+}
+
+// Count returns the number of fragments needed to split a payload of the given length.
+// It returns zero when PayloadMaxSize is too small to contain any payload.
+func (f Fragmenter) Count(payloadLen int) int {
+	if !f.valid() {
+		return 0
+	}
+
+	firstAvail := f.PayloadMaxSize - f.FirstPrefixLen
+	if payloadLen <= firstAvail {
+		return 1
+	}
+
+	avail := f.PayloadMaxSize - f.PrefixLen
+	payloadLen -= firstAvail
+
+	return 1 + (payloadLen+avail-1)/avail
+}
+
+// Size returns the size of the buffer needed to store
+// all fragments of a payload of the given length.
+// It returns zero when PayloadMaxSize is too small to contain any payload.
+func (f Fragmenter) Size(payloadLen int) int {
+	if !f.valid() {
+		return 0
+	}
+
+	return payloadLen + f.FirstPrefixLen + (f.Count(payloadLen)-1)*f.PrefixLen
+}
+
+// Fragment splits a payload into fragments.
+// Fragments are written into buf when it is large enough,
+// otherwise they are written into a single, newly-allocated buffer.
+func (f Fragmenter) Fragment(buf []byte, payload []byte) ([][]byte, error) {
+	if !f.valid() {
+		return nil, fmt.Errorf("PayloadMaxSize is too small")
+	}
+
+	size := f.Size(len(payload))
+	if len(buf) < size {
+		buf = make([]byte, size)
+	}
+
+	ret := make([][]byte, f.Count(len(payload)))
+	prefixLen := f.FirstPrefixLen
+
+	for i := range ret {
+		le := f.PayloadMaxSize - prefixLen
+		if le > len(payload) {
+			le = len(payload)
+		}
+
+		n := prefixLen + le
+		copy(buf[prefixLen:n], payload)
+		ret[i] = buf[:n:n]
+
+		buf = buf[n:]
+		payload = payload[le:]
+		prefixLen = f.PrefixLen
+	}
+
+	return ret, nil
+}
+
+// NewPackets allocates n RTP packets with a constant number of allocations.
+func NewPackets(n int) []*rtp.Packet {
+	pkts := make([]rtp.Packet, n)
+	ret := make([]*rtp.Packet, n)
+	for i := range pkts {
+		ret[i] = &pkts[i]
+	}
+	return ret
+}
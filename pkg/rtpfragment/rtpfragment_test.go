@@ -0,0 +1,94 @@
+package rtpfragment
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFragment(t *testing.T) {
+	for _, ca := range []struct {
+		name       string
+		fragmenter Fragmenter
+		payload    []byte
+		fragments  [][]byte
+	}{
+		{
+			"single",
+			Fragmenter{PayloadMaxSize: 10, FirstPrefixLen: 2, PrefixLen: 2},
+			[]byte{1, 2, 3, 4},
+			[][]byte{
+				{0, 0, 1, 2, 3, 4},
+			},
+		},
+		{
+			"multiple",
+			Fragmenter{PayloadMaxSize: 5, FirstPrefixLen: 2, PrefixLen: 2},
+			[]byte{1, 2, 3, 4, 5, 6, 7},
+			[][]byte{
+				{0, 0, 1, 2, 3},
+				{0, 0, 4, 5, 6},
+				{0, 0, 7},
+			},
+		},
+		{
+			"different first prefix",
+			Fragmenter{PayloadMaxSize: 5, FirstPrefixLen: 4, PrefixLen: 2},
+			[]byte{1, 2, 3, 4, 5, 6, 7},
+			[][]byte{
+				{0, 0, 0, 0, 1},
+				{0, 0, 2, 3, 4},
+				{0, 0, 5, 6, 7},
+			},
+		},
+	} {
+		t.Run(ca.name, func(t *testing.T) {
+			require.Equal(t, len(ca.fragments), ca.fragmenter.Count(len(ca.payload)))
+
+			fragments, err := ca.fragmenter.Fragment(nil, ca.payload)
+			require.NoError(t, err)
+			require.Equal(t, ca.fragments, fragments)
+
+			buf := make([]byte, ca.fragmenter.Size(len(ca.payload)))
+			fragments, err = ca.fragmenter.Fragment(buf, ca.payload)
+			require.NoError(t, err)
+			require.Equal(t, ca.fragments, fragments)
+			require.Equal(t, len(buf), len(bytes.Join(fragments, nil)))
+			require.Same(t, &buf[0], &fragments[0][0])
+		})
+	}
+}
+
+func TestFragmentPayloadMaxSizeTooSmall(t *testing.T) {
+	f := Fragmenter{PayloadMaxSize: 2, FirstPrefixLen: 2, PrefixLen: 2}
+	_, err := f.Fragment(nil, []byte{1, 2, 3})
+	require.EqualError(t, err, "PayloadMaxSize is too small")
+
+	require.Equal(t, 0, f.Count(3))
+	require.Equal(t, 0, f.Size(3))
+}
+
+func TestNewPackets(t *testing.T) {
+	pkts := NewPackets(3)
+	require.Len(t, pkts, 3)
+	for _, pkt := range pkts {
+		require.NotNil(t, pkt)
+	}
+}
+
+func BenchmarkFragment(b *testing.B) {
+	f := Fragmenter{PayloadMaxSize: 1460, FirstPrefixLen: 2, PrefixLen: 2}
+	payload := make([]byte, 100000)
+	buf := make([]byte, f.Size(len(payload)))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := f.Fragment(buf, payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Package rtpoptions contains options that can be applied
+// to RTP packets generated by encoders.
+package rtpoptions
+
+import (
+	"fmt"
+
+	"github.com/pion/rtp"
+)
+
+// maximum padding size, since its length is stored in a single byte.
+const maxPaddingSize = 255
+
+// MarkerFunc is a function that sets the marker bit of a packet.
+// It is called with the index of the packet inside the access unit,
+// the packet count of the access unit and the marker bit set by codec rules.
+type MarkerFunc func(i int, count int, marker bool) bool
+
+// ValidatePaddingAlignment checks whether a padding alignment can be used.
+func ValidatePaddingAlignment(alignment int) error {
+	if alignment < 0 || alignment > (maxPaddingSize+1) {
+		return fmt.Errorf("invalid PaddingAlignment (%d)", alignment)
+	}
+	return nil
+}
+
+// Apply applies options to the packets of an access unit.
+func Apply(pkts []*rtp.Packet, markerFunc MarkerFunc, paddingAlignment int) {
+	if markerFunc != nil {
+		for i, pkt := range pkts {
+			pkt.Marker = markerFunc(i, len(pkts), pkt.Marker)
+		}
+	}
+
+	if paddingAlignment > 1 {
+		for _, pkt := range pkts {
+			Pad(pkt, paddingAlignment)
+		}
+	}
+}
+
+// Pad adds RTP padding to a packet in order to make the size of its payload,
+// padding included, a multiple of alignment.
+// Padding can't be longer than 255 bytes, therefore
+// alignment can't be greater than 256; other values are ignored.
+func Pad(pkt *rtp.Packet, alignment int) {
+	if alignment <= 1 || alignment > (maxPaddingSize+1) {
+		return
+	}
+
+	le := len(pkt.Payload) + int(pkt.PaddingSize)
+
+	n := (alignment - le%alignment) % alignment
+	if n == 0 || (int(pkt.PaddingSize)+n) > maxPaddingSize {
+		return
+	}
+
+	pkt.Padding = true
+	pkt.PaddingSize += byte(n)
+}
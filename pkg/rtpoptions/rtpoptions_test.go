@@ -0,0 +1,72 @@
+package rtpoptions
+
+import (
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	pkts := []*rtp.Packet{
+		{Payload: []byte{1, 2, 3}},
+		{Payload: []byte{4, 5, 6, 7}},
+		{Header: rtp.Header{Marker: true}, Payload: []byte{8}},
+	}
+
+	Apply(pkts, func(i int, count int, marker bool) bool {
+		require.Equal(t, 3, count)
+		if i == 1 {
+			return true
+		}
+		return marker
+	}, 4)
+
+	require.Equal(t, false, pkts[0].Marker)
+	require.Equal(t, true, pkts[1].Marker)
+	require.Equal(t, true, pkts[2].Marker)
+
+	require.Equal(t, true, pkts[0].Padding)
+	require.Equal(t, byte(1), pkts[0].PaddingSize)
+	require.Equal(t, false, pkts[1].Padding)
+	require.Equal(t, byte(0), pkts[1].PaddingSize)
+	require.Equal(t, true, pkts[2].Padding)
+	require.Equal(t, byte(3), pkts[2].PaddingSize)
+}
+
+func TestPadMarshal(t *testing.T) {
+	pkt := &rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			PayloadType:    96,
+			SequenceNumber: 17645,
+			SSRC:           0x9dbb7812,
+		},
+		Payload: []byte{1, 2, 3, 4, 5},
+	}
+
+	Pad(pkt, 16)
+
+	buf, err := pkt.Marshal()
+	require.NoError(t, err)
+	require.Equal(t, 12+16, len(buf))
+	require.Equal(t, byte(11), buf[len(buf)-1])
+
+	var dec rtp.Packet
+	err = dec.Unmarshal(buf)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3, 4, 5}, dec.Payload)
+}
+
+func TestPadInvalidAlignment(t *testing.T) {
+	pkt := &rtp.Packet{Payload: []byte{1}}
+	Pad(pkt, 1000)
+	require.Equal(t, false, pkt.Padding)
+}
+
+func TestValidatePaddingAlignment(t *testing.T) {
+	require.NoError(t, ValidatePaddingAlignment(0))
+	require.NoError(t, ValidatePaddingAlignment(256))
+	require.EqualError(t, ValidatePaddingAlignment(257), "invalid PaddingAlignment (257)")
+	require.EqualError(t, ValidatePaddingAlignment(-1), "invalid PaddingAlignment (-1)")
+}
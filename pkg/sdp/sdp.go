@@ -306,13 +306,22 @@ func (s *SessionDescription) unmarshalSessionEncryptionKey(value string) error {
 	return nil
 }
 
+// some cameras use uppercase attribute keys (i.e. "RTPMAP").
+// Since all standard attributes are lowercase, convert them.
+func normalizeAttributeKey(key string) string {
+	if strings.ToUpper(key) == key {
+		return strings.ToLower(key)
+	}
+	return key
+}
+
 func (s *SessionDescription) unmarshalSessionAttribute(value string) error {
 	i := strings.IndexRune(value, ':')
 	var a psdp.Attribute
 	if i > 0 {
-		a = psdp.NewAttribute(value[:i], value[i+1:])
+		a = psdp.NewAttribute(normalizeAttributeKey(value[:i]), value[i+1:])
 	} else {
-		a = psdp.NewPropertyAttribute(value)
+		a = psdp.NewPropertyAttribute(normalizeAttributeKey(value))
 	}
 
 	s.Attributes = append(s.Attributes, a)
@@ -518,9 +527,9 @@ func (s *SessionDescription) unmarshalMediaAttribute(value string) error {
 	i := strings.IndexRune(value, ':')
 	var a psdp.Attribute
 	if i > 0 {
-		a = psdp.NewAttribute(value[:i], value[i+1:])
+		a = psdp.NewAttribute(normalizeAttributeKey(value[:i]), value[i+1:])
 	} else {
-		a = psdp.NewPropertyAttribute(value)
+		a = psdp.NewPropertyAttribute(normalizeAttributeKey(value))
 	}
 
 	latestMediaDesc := s.MediaDescriptions[len(s.MediaDescriptions)-1]
@@ -535,6 +544,7 @@ const (
 	stateSession
 	stateMedia
 	stateTimeDescription
+	stateInvalidMedia
 )
 
 func (s *SessionDescription) unmarshalSession(state *unmarshalState, key byte, val string) error {
@@ -671,73 +681,106 @@ func (s *SessionDescription) unmarshalMedia(key byte, val string) error {
 	return nil
 }
 
+func (s *SessionDescription) unmarshalLine(state *unmarshalState, line string) error {
+	if len(line) < 2 || line[1] != '=' {
+		return fmt.Errorf("invalid line: (%s)", line)
+	}
+
+	key := line[0]
+	val := line[2:]
+
+	// some cameras use uppercase keys
+	if key >= 'A' && key <= 'Z' {
+		key += 'a' - 'A'
+	}
+
+	var err error
+
+	switch *state {
+	case stateInitial:
+		switch key {
+		case 'v':
+			err = s.unmarshalProtocolVersion(val)
+			*state = stateSession
+
+		default:
+			*state = stateSession
+			err = s.unmarshalSession(state, key, val)
+		}
+
+	case stateSession:
+		err = s.unmarshalSession(state, key, val)
+
+	case stateMedia:
+		err = s.unmarshalMedia(key, val)
+
+	case stateTimeDescription:
+		switch key {
+		case 'r':
+			err = s.unmarshalRepeatTimes(val)
+
+		default:
+			*state = stateSession
+			err = s.unmarshalSession(state, key, val)
+		}
+
+	case stateInvalidMedia:
+		if key != 'm' {
+			return fmt.Errorf("line of an invalid media: (%s)", line)
+		}
+
+		err = s.unmarshalMediaDescription(val)
+		if err == nil {
+			*state = stateMedia
+		}
+	}
+
+	// skip all lines of a media whose description is invalid,
+	// in order not to attach them to the previous media.
+	if err != nil && key == 'm' {
+		*state = stateInvalidMedia
+	}
+
+	return err
+}
+
 // Unmarshal decodes a SessionDescription.
 // This is rewritten from scratch to guarantee compatibility with most RTSP
 // implementations.
+// Lines that can't be decoded are skipped. Use UnmarshalWithWarnings
+// to retrieve them.
 func (s *SessionDescription) Unmarshal(byts []byte) error {
+	_, err := s.UnmarshalWithWarnings(byts)
+	return err
+}
+
+// UnmarshalWithWarnings decodes a SessionDescription.
+// Lines that can't be decoded are skipped and returned as warnings.
+// An error is returned only when none of the lines can be decoded.
+func (s *SessionDescription) UnmarshalWithWarnings(byts []byte) ([]error, error) {
 	str := string(byts)
 
 	state := stateInitial
+	decoded := false
+	var warnings []error
 
 	for _, line := range strings.Split(strings.ReplaceAll(str, "\r", ""), "\n") {
 		if line == "" {
 			continue
 		}
 
-		if len(line) < 2 || line[1] != '=' {
-			return fmt.Errorf("invalid line: (%s)", line)
-		}
-
-		key := line[0]
-		val := line[2:]
-
-		switch state {
-		case stateInitial:
-			switch key {
-			case 'v':
-				err := s.unmarshalProtocolVersion(val)
-				if err != nil {
-					return err
-				}
-				state = stateSession
-
-			default:
-				state = stateSession
-				err := s.unmarshalSession(&state, key, val)
-				if err != nil {
-					return err
-				}
-			}
-
-		case stateSession:
-			err := s.unmarshalSession(&state, key, val)
-			if err != nil {
-				return err
-			}
-
-		case stateMedia:
-			err := s.unmarshalMedia(key, val)
-			if err != nil {
-				return err
-			}
-
-		case stateTimeDescription:
-			switch key {
-			case 'r':
-				err := s.unmarshalRepeatTimes(val)
-				if err != nil {
-					return err
-				}
-
-			default:
-				state = stateSession
-				err := s.unmarshalSession(&state, key, val)
-				if err != nil {
-					return err
-				}
-			}
+		err := s.unmarshalLine(&state, line)
+		if err != nil {
+			warnings = append(warnings, err)
+			continue
 		}
+
+		decoded = true
 	}
 
-	return nil
+	if !decoded && warnings != nil {
+		return nil, warnings[0]
+	}
+
+	return warnings, nil
 }
@@ -3100,6 +3100,77 @@ var cases = []struct {
 	},
 }
 
+func TestUnmarshalWarnings(t *testing.T) {
+	var desc SessionDescription
+	warnings, err := desc.UnmarshalWithWarnings([]byte("v=0\n" +
+		"s=Stream\n" +
+		"c=IN\n" +
+		"this is not a SDP line\n" +
+		"t=0 0\n" +
+		"m=video 0 RTP/AVP 96\n" +
+		"A=RTPMAP:96 H264/90000\n" +
+		"A=CONTROL:trackID=0\n" +
+		"m=unknown 0 RTP/AVP 97\n" +
+		"a=rtpmap:97 L16/8000\n" +
+		"m=audio 0 RTP/AVP 8\n" +
+		"a=control:trackID=2\n"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"sdp: invalid syntax `c=IN`",
+		"invalid line: (this is not a SDP line)",
+		"sdp: invalid value `unknown`",
+		"line of an invalid media: (a=rtpmap:97 L16/8000)",
+	}, func() []string {
+		var ret []string
+		for _, w := range warnings {
+			ret = append(ret, w.Error())
+		}
+		return ret
+	}())
+	require.Equal(t, SessionDescription{
+		SessionName:      "Stream",
+		TimeDescriptions: []psdp.TimeDescription{{}},
+		MediaDescriptions: []*psdp.MediaDescription{
+			{
+				MediaName: psdp.MediaName{
+					Media:   "video",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"96"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "rtpmap",
+						Value: "96 H264/90000",
+					},
+					{
+						Key:   "control",
+						Value: "trackID=0",
+					},
+				},
+			},
+			{
+				MediaName: psdp.MediaName{
+					Media:   "audio",
+					Protos:  []string{"RTP", "AVP"},
+					Formats: []string{"8"},
+				},
+				Attributes: []psdp.Attribute{
+					{
+						Key:   "control",
+						Value: "trackID=2",
+					},
+				},
+			},
+		},
+	}, desc)
+}
+
+func TestUnmarshalNoValidLines(t *testing.T) {
+	var desc SessionDescription
+	err := desc.Unmarshal([]byte("\x01\x02\x03\x04"))
+	require.EqualError(t, err, "invalid line: (\x01\x02\x03\x04)")
+}
+
 func TestUnmarshal(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
@@ -3,13 +3,19 @@ package gortsplib
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/pion/rtp"
+
 	"github.com/voicecom/gortsplib/v4/pkg/base"
+	"github.com/voicecom/gortsplib/v4/pkg/description"
 	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 )
 
@@ -86,8 +92,31 @@ type Server struct {
 	// This must be less than the UDP MTU (1472 bytes).
 	// It defaults to 1472.
 	MaxPacketSize int
+	// maximum size of incoming RTP / RTCP packets.
+	// UDP packets that exceed it are discarded, while TCP packets that exceed it
+	// cause the session to be closed before their payload is allocated.
+	// Since interleaved frames can't be bigger than 65535 bytes and UDP packets
+	// are read into buffers of 1472 bytes, only values below these sizes have effect.
+	// It defaults to 65535.
+	MaxIncomingPacketSize int
+	// maximum size of request bodies.
+	// Requests with bigger bodies are rejected with 413 Request Entity Too Large.
+	// It defaults to 1 MiB.
+	MaxBodySize int64
 	// disable automatic RTCP sender reports.
 	DisableRTCPSenderReports bool
+	// maximum number of tracks that can be set up by each session.
+	// It defaults to 0 (unlimited).
+	MaxTrackCount int
+	// media types that clients are allowed to record.
+	// RECORD requests of sessions containing other media types are
+	// rejected with 415 Unsupported Media Type, before calling OnRecord().
+	// It defaults to nil (all media types are allowed).
+	AllowedMediaTypes []description.MediaType
+	// update the parameters of H264 and H265 formats of served streams
+	// with the parameter sets found inside outgoing packets, in order to
+	// advertise up-to-date parameters in subsequent DESCRIBE responses.
+	UpdateStreamParams bool
 
 	//
 	// handler (optional)
@@ -127,6 +156,11 @@ type Server struct {
 	sessions        map[string]*ServerSession
 	conns           map[*ServerConn]struct{}
 	closeError      error
+	connCount       atomic.Int64
+	sessionCount    atomic.Int64
+	playerCount     atomic.Int64
+	publisherCount  atomic.Int64
+	rtpBufferPool   sync.Pool
 
 	// in
 	chNewConn        chan net.Conn
@@ -156,6 +190,16 @@ func (s *Server) Start() error {
 	} else if s.MaxPacketSize > udpMaxPayloadSize {
 		return fmt.Errorf("MaxPacketSize must be less than %d", udpMaxPayloadSize)
 	}
+	if s.MaxIncomingPacketSize == 0 {
+		s.MaxIncomingPacketSize = 65535
+	} else if s.MaxIncomingPacketSize < 0 {
+		return fmt.Errorf("MaxIncomingPacketSize must be greater than zero")
+	}
+	if s.MaxBodySize == 0 {
+		s.MaxBodySize = 1024 * 1024
+	} else if s.MaxBodySize < 0 {
+		return fmt.Errorf("MaxBodySize must be greater than zero")
+	}
 
 	// system functions
 	if s.Listen == nil {
@@ -182,6 +226,10 @@ func (s *Server) Start() error {
 		s.checkStreamPeriod = 1 * time.Second
 	}
 
+	s.rtpBufferPool.New = func() interface{} {
+		return make([]byte, s.MaxPacketSize)
+	}
+
 	if s.TLSConfig != nil && s.UDPRTPAddress != "" {
 		return fmt.Errorf("TLS can't be used with UDP")
 	}
@@ -464,6 +512,94 @@ func (s *Server) StartAndWait() error {
 	return s.Wait()
 }
 
+// ListenAndServeRTSPS starts the server on addr with the RTSPS protocol,
+// by using the certificate and key contained in certFile and keyFile,
+// and waits until a fatal error occurs or Close() is called.
+func (s *Server) ListenAndServeRTSPS(addr string, certFile string, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	s.RTSPAddress = addr
+	s.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	return s.StartAndWait()
+}
+
+// ListenAndServeMTLS is like ListenAndServeRTSPS, but additionally
+// requires clients to provide a certificate signed by the CA contained in caCertFile.
+func (s *Server) ListenAndServeMTLS(addr string, certFile string, keyFile string, caCertFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+
+	caCert, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return err
+	}
+
+	clientCAs := x509.NewCertPool()
+	if !clientCAs.AppendCertsFromPEM(caCert) {
+		return fmt.Errorf("unable to parse CA certificate")
+	}
+
+	s.RTSPAddress = addr
+	s.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+
+	return s.StartAndWait()
+}
+
+// WritePacketRTPToSession writes a RTP packet to a single session that is reading a stream,
+// bypassing the fan-out to other readers (i.e. to send decoder configuration to a new reader).
+// medi is a media of the stream. The packet is not taken into account by RTCP sender reports.
+// Readers that use the UDP-multicast transport can't be addressed individually.
+// It can be called from any goroutine.
+func (s *Server) WritePacketRTPToSession(ss *ServerSession, medi *description.Media, pkt *rtp.Packet) error {
+	st := ss.SetuppedStream()
+	if st == nil {
+		return liberrors.ErrServerSessionNotReading{}
+	}
+
+	buf := s.rtpBufferPool.Get().([]byte)
+	n, err := pkt.MarshalTo(buf)
+	if err != nil {
+		s.rtpBufferPool.Put(buf) //nolint:staticcheck
+		return err
+	}
+
+	// the packet is written asynchronously, therefore it can't use the pooled buffer.
+	byts := append([]byte(nil), buf[:n]...)
+	s.rtpBufferPool.Put(buf) //nolint:staticcheck
+
+	return st.writePacketRTPToReader(ss, medi, byts)
+}
+
+// ConnectionCount returns the number of open connections.
+func (s *Server) ConnectionCount() int {
+	return int(s.connCount.Load())
+}
+
+// SessionCount returns the number of open sessions, regardless of their state.
+func (s *Server) SessionCount() int {
+	return int(s.sessionCount.Load())
+}
+
+// PlayerCount returns the number of sessions in state Play.
+func (s *Server) PlayerCount() int {
+	return int(s.playerCount.Load())
+}
+
+// PublisherCount returns the number of sessions in state Record.
+func (s *Server) PublisherCount() int {
+	return int(s.publisherCount.Load())
+}
+
 func (s *Server) getMulticastIP() (net.IP, error) {
 	res := make(chan net.IP)
 	select {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"io"
 	"net"
 	gourl "net/url"
 	"strconv"
@@ -14,41 +16,148 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/bytecounter"
 	"github.com/voicecom/gortsplib/v4/pkg/conn"
 	"github.com/voicecom/gortsplib/v4/pkg/description"
+	"github.com/voicecom/gortsplib/v4/pkg/headers"
 	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 )
 
 func getSessionID(header base.Header) string {
-	if h, ok := header["Session"]; ok && len(h) == 1 {
-		return h[0]
+	if h := header.GetAll("Session"); len(h) == 1 {
+		// strip parameters, like timeout
+		var sx headers.Session
+		err := sx.Unmarshal(h)
+		if err != nil {
+			return h[0]
+		}
+		return sx.Session
 	}
 	return ""
 }
 
+func handlerSupportsDescribe(h ServerHandler) bool {
+	if _, ok := h.(ServerHandlerOnDescribe); ok {
+		return true
+	}
+	_, ok := h.(ServerHandlerOnDescribeReader)
+	return ok
+}
+
+func setDescribeHeaders(res *base.Response, req *base.Request) {
+	if res.Header == nil {
+		res.Header = make(base.Header)
+	}
+
+	res.Header["Content-Base"] = base.HeaderValue{req.URL.String() + "/"}
+	res.Header["Content-Type"] = base.HeaderValue{base.ContentTypeSDP}
+}
+
+// readSDP reads a SDP from a io.Reader.
+// RTSP/1.0 responses require a Content-Length, therefore the SDP is read entirely.
+func readSDP(r io.Reader, maxSize int64) ([]byte, error) {
+	if s, ok := r.(io.Seeker); ok {
+		cur, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+
+		end, err := s.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = s.Seek(cur, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+
+		size := end - cur
+		if size < 0 {
+			return nil, fmt.Errorf("reader position is after the end")
+		}
+		if size > maxSize {
+			return nil, fmt.Errorf("SDP size exceeds %d", maxSize)
+		}
+
+		byts := make([]byte, size)
+		_, err = io.ReadFull(r, byts)
+		if err != nil {
+			return nil, err
+		}
+
+		return byts, nil
+	}
+
+	byts, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(byts)) > maxSize {
+		return nil, fmt.Errorf("SDP size exceeds %d", maxSize)
+	}
+
+	return byts, nil
+}
+
+// attributes of the stream description that are not copied into DESCRIBE responses,
+// since they refer to the transport, the timing or the encryption of the original stream.
+var serverSuppressedAttributes = []string{
+	"control",
+	"range",
+	"rtcp",
+	"rtcp-mux",
+	"ssrc",
+	"ssrc-group",
+	"source-filter",
+	"candidate",
+	"ice-ufrag",
+	"ice-pwd",
+	"ice-options",
+	"fingerprint",
+	"setup",
+	"crypto",
+	"key-mgmt",
+}
+
 func serverSideDescription(d *description.Session) *description.Session {
 	out := &description.Session{
-		Title:     d.Title,
-		FECGroups: d.FECGroups,
-		Medias:    make([]*description.Media, len(d.Medias)),
+		Title:             d.Title,
+		FECGroups:         d.FECGroups,
+		Medias:            make([]*description.Media, len(d.Medias)),
+		Bandwidths:        d.Bandwidths,
+		Attributes:        d.Attributes,
+		Origin:            d.Origin,
+		ConnectionAddress: d.ConnectionAddress,
+		SuppressedAttributes: append(append([]string(nil), serverSuppressedAttributes...),
+			d.SuppressedAttributes...),
 	}
 
 	for i, medi := range d.Medias {
-		out.Medias[i] = &description.Media{
-			Type:          medi.Type,
-			ID:            medi.ID,
-			IsBackChannel: medi.IsBackChannel,
-			// we have to use trackID=number in order to support clients
-			// like the Grandstream GXV3500.
-			Control: "trackID=" + strconv.FormatInt(int64(i), 10),
-			Formats: medi.Formats,
-		}
+		out.Medias[i] = serverSideMedia(medi, i)
 	}
 
 	return out
 }
 
+func serverSideMedia(medi *description.Media, i int) *description.Media {
+	return &description.Media{
+		Type:          medi.Type,
+		ID:            medi.ID,
+		IsBackChannel: medi.IsBackChannel || medi.Direction == description.MediaDirectionSendOnly,
+		Direction:     medi.Direction,
+		// we have to use trackID=number in order to support clients
+		// like the Grandstream GXV3500.
+		Control:      "trackID=" + strconv.FormatInt(int64(i), 10),
+		Formats:      medi.Formats,
+		RTCPFeedback: medi.RTCPFeedback,
+		Bandwidths:   medi.Bandwidths,
+		Attributes:   medi.Attributes,
+	}
+}
+
 type readReq struct {
-	req *base.Request
-	res chan error
+	req     *base.Request
+	readErr error
+	res     chan error
 }
 
 // ServerConn is a server-side RTSP connection.
@@ -60,6 +169,7 @@ type ServerConn struct {
 	ctxCancel  func()
 	userData   interface{}
 	remoteAddr *net.TCPAddr
+	created    time.Time
 	bc         *bytecounter.ByteCounter
 	conn       *conn.Conn
 	session    *ServerSession
@@ -81,6 +191,7 @@ func (sc *ServerConn) initialize() {
 	}
 
 	sc.bc = bytecounter.New(sc.nconn, nil, nil)
+	sc.created = sc.s.timeNow()
 	sc.ctx = ctx
 	sc.ctxCancel = ctxCancel
 	sc.remoteAddr = sc.nconn.RemoteAddr().(*net.TCPAddr)
@@ -89,6 +200,8 @@ func (sc *ServerConn) initialize() {
 	sc.chRemoveSession = make(chan *ServerSession)
 	sc.done = make(chan struct{})
 
+	sc.s.connCount.Add(1)
+
 	sc.s.wg.Add(1)
 	go sc.run()
 }
@@ -142,6 +255,8 @@ func (sc *ServerConn) run() {
 	}
 
 	sc.conn = conn.NewConn(sc.bc)
+	sc.conn.MaxBodySize = sc.s.MaxBodySize
+	sc.conn.MaxPayloadSize = sc.s.MaxIncomingPacketSize
 	cr := &serverConnReader{
 		sc: sc,
 	}
@@ -161,10 +276,15 @@ func (sc *ServerConn) run() {
 
 	sc.s.closeConn(sc)
 
+	sc.s.connCount.Add(-1)
+
 	if h, ok := sc.s.Handler.(ServerHandlerOnConnClose); ok {
 		h.OnConnClose(&ServerHandlerOnConnCloseCtx{
-			Conn:  sc,
-			Error: err,
+			Conn:          sc,
+			Error:         err,
+			BytesReceived: sc.bc.BytesReceived(),
+			BytesSent:     sc.bc.BytesSent(),
+			Duration:      sc.s.timeNow().Sub(sc.created),
 		})
 	}
 }
@@ -173,7 +293,7 @@ func (sc *ServerConn) runInner() error {
 	for {
 		select {
 		case req := <-sc.chReadRequest:
-			req.res <- sc.handleRequestOuter(req.req)
+			req.res <- sc.handleRequestOuter(req.req, req.readErr)
 
 		case err := <-sc.chReadError:
 			return err
@@ -190,7 +310,7 @@ func (sc *ServerConn) runInner() error {
 }
 
 func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, error) {
-	if cseq, ok := req.Header["CSeq"]; !ok || len(cseq) != 1 {
+	if cseq := req.Header.GetAll("CSeq"); len(cseq) != 1 {
 		return &base.Response{
 			StatusCode: base.StatusBadRequest,
 		}, liberrors.ErrServerCSeqMissing{}
@@ -219,7 +339,7 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 		}
 
 		var methods []string
-		if _, ok := sc.s.Handler.(ServerHandlerOnDescribe); ok {
+		if handlerSupportsDescribe(sc.s.Handler) {
 			methods = append(methods, string(base.Describe))
 		}
 		if _, ok := sc.s.Handler.(ServerHandlerOnAnnounce); ok {
@@ -251,21 +371,45 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 		}, nil
 
 	case base.Describe:
-		if h, ok := sc.s.Handler.(ServerHandlerOnDescribe); ok {
-			res, stream, err := h.OnDescribe(&ServerHandlerOnDescribeCtx{
+		if h, ok := sc.s.Handler.(ServerHandlerOnDescribeReader); ok {
+			res, r, err := h.OnDescribeReader(&ServerHandlerOnDescribeCtx{
 				Conn:    sc,
 				Request: req,
 				Path:    path,
 				Query:   query,
 			})
 
+			if c, ok2 := r.(io.Closer); ok2 {
+				defer c.Close()
+			}
+
 			if res.StatusCode == base.StatusOK {
-				if res.Header == nil {
-					res.Header = make(base.Header)
+				setDescribeHeaders(res, req)
+
+				if r != nil {
+					byts, err2 := readSDP(r, sc.s.MaxBodySize)
+					if err2 != nil {
+						return &base.Response{
+							StatusCode: base.StatusInternalServerError,
+						}, err2
+					}
+					res.Body = byts
 				}
+			}
 
-				res.Header["Content-Base"] = base.HeaderValue{req.URL.String() + "/"}
-				res.Header["Content-Type"] = base.HeaderValue{"application/sdp"}
+			return res, err
+		}
+
+		if h, ok := sc.s.Handler.(ServerHandlerOnDescribe); ok {
+			res, stream, err := h.OnDescribe(&ServerHandlerOnDescribeCtx{
+				Conn:    sc,
+				Request: req,
+				Path:    path,
+				Query:   query,
+			})
+
+			if res.StatusCode == base.StatusOK {
+				setDescribeHeaders(res, req)
 
 				// VLC uses multicast if the SDP contains a multicast address.
 				// therefore, we introduce a special query (vlcmulticast) that allows
@@ -358,12 +502,24 @@ func (sc *ServerConn) handleRequestInner(req *base.Request) (*base.Response, err
 	}, nil
 }
 
-func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
+func (sc *ServerConn) handleRequestOuter(req *base.Request, readErr error) error {
 	if h, ok := sc.s.Handler.(ServerHandlerOnRequest); ok {
 		h.OnRequest(sc, req)
 	}
 
-	res, err := sc.handleRequestInner(req)
+	var res *base.Response
+	var err error
+
+	// the body has not been read entirely, therefore the connection
+	// is closed after the response is sent.
+	if readErr != nil {
+		res = &base.Response{
+			StatusCode: base.StatusRequestEntityTooLarge,
+		}
+		err = readErr
+	} else {
+		res, err = sc.handleRequestInner(req)
+	}
 
 	if res.Header == nil {
 		res.Header = make(base.Header)
@@ -372,7 +528,7 @@ func (sc *ServerConn) handleRequestOuter(req *base.Request) error {
 	// add cseq
 	var eerr liberrors.ErrServerCSeqMissing
 	if !errors.As(err, &eerr) {
-		res.Header["CSeq"] = req.Header["CSeq"]
+		res.Header["CSeq"] = req.Header.GetAll("CSeq")
 	}
 
 	// add server
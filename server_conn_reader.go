@@ -60,6 +60,26 @@ func (cr *serverConnReader) run() {
 	}
 }
 
+func (cr *serverConnReader) handleReadError(what interface{}, err error) error {
+	// reply to requests whose body is too large before closing the connection
+	var eerr base.ErrBodyTooLarge
+	if req, ok := what.(*base.Request); ok && errors.As(err, &eerr) {
+		cres := make(chan error)
+		err2 := cr.sc.readRequest(readReq{req: req, readErr: err, res: cres})
+		if err2 != nil {
+			return err2
+		}
+	}
+
+	// the payload of interleaved frames is checked before being allocated
+	var eerr2 base.ErrPayloadTooLarge
+	if errors.As(err, &eerr2) {
+		return liberrors.ErrServerPacketTooLarge{L: eerr2.Length, Max: eerr2.MaxLength}
+	}
+
+	return err
+}
+
 func (cr *serverConnReader) readFuncStandard() error {
 	// reset deadline
 	cr.sc.nconn.SetReadDeadline(time.Time{})
@@ -67,7 +87,7 @@ func (cr *serverConnReader) readFuncStandard() error {
 	for {
 		what, err := cr.sc.conn.Read()
 		if err != nil {
-			return err
+			return cr.handleReadError(what, err)
 		}
 
 		switch what := what.(type) {
@@ -101,7 +121,7 @@ func (cr *serverConnReader) readFuncTCP() error {
 
 		what, err := cr.sc.conn.Read()
 		if err != nil {
-			return err
+			return cr.handleReadError(what, err)
 		}
 
 		switch what := what.(type) {
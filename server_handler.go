@@ -1,8 +1,12 @@
 package gortsplib
 
 import (
+	"io"
+	"time"
+
 	"github.com/voicecom/gortsplib/v4/pkg/base"
 	"github.com/voicecom/gortsplib/v4/pkg/description"
+	"github.com/voicecom/gortsplib/v4/pkg/headers"
 )
 
 // ServerHandler is the interface implemented by all the server handlers.
@@ -23,6 +27,13 @@ type ServerHandlerOnConnOpen interface {
 type ServerHandlerOnConnCloseCtx struct {
 	Conn  *ServerConn
 	Error error
+
+	// number of bytes received and sent by the connection.
+	BytesReceived uint64
+	BytesSent     uint64
+
+	// time elapsed between the opening and the closure of the connection.
+	Duration time.Duration
 }
 
 // ServerHandlerOnConnClose can be implemented by a ServerHandler.
@@ -81,6 +92,21 @@ type ServerHandlerOnDescribe interface {
 	OnDescribe(*ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error)
 }
 
+// ServerHandlerOnDescribeReader can be implemented by a ServerHandler
+// in place of ServerHandlerOnDescribe, in order to provide the SDP of the stream
+// through a io.Reader, without building a description.Session.
+// When a handler implements both interfaces, OnDescribeReader is used.
+// The SDP is sent as is, therefore the control attribute of each media
+// must follow the scheme used by the server, that is "trackID=N",
+// where N is the index of the media.
+// The SDP can't be bigger than Server.MaxBodySize.
+// When the reader implements io.Seeker, the body is allocated in a single step.
+// When the reader implements io.Closer, it is closed after being read.
+type ServerHandlerOnDescribeReader interface {
+	// called when receiving a DESCRIBE request.
+	OnDescribeReader(*ServerHandlerOnDescribeCtx) (*base.Response, io.Reader, error)
+}
+
 // ServerHandlerOnAnnounceCtx is the context of OnAnnounce.
 type ServerHandlerOnAnnounceCtx struct {
 	Session     *ServerSession
@@ -99,12 +125,19 @@ type ServerHandlerOnAnnounce interface {
 
 // ServerHandlerOnSetupCtx is the context of OnSetup.
 type ServerHandlerOnSetupCtx struct {
-	Session   *ServerSession
-	Conn      *ServerConn
-	Request   *base.Request
-	Path      string
-	Query     string
+	Session *ServerSession
+	Conn    *ServerConn
+	Request *base.Request
+	Path    string
+	Query   string
+
+	// transport requested by the client.
 	Transport Transport
+
+	// can be filled by the handler to force a different transport.
+	// If the client didn't offer it, UDP can't be forced,
+	// since client ports are unknown.
+	ForceTransport *Transport
 }
 
 // ServerHandlerOnSetup can be implemented by a ServerHandler.
@@ -124,6 +157,20 @@ type ServerHandlerOnPlayCtx struct {
 	Request *base.Request
 	Path    string
 	Query   string
+
+	// requested playback range.
+	// It is nil when the Range header is not present, that means "current position".
+	Range *headers.Range
+
+	// requested playback speed.
+	// It is nil when the Scale header is not present.
+	Scale *float64
+
+	// position at which the session has been paused.
+	// It is filled when the Range header is not present
+	// and the session has been previously paused, that means that
+	// playback has to be resumed from this position.
+	ResumeFrom *headers.Range
 }
 
 // ServerHandlerOnPlay can be implemented by a ServerHandler.
@@ -139,6 +186,7 @@ type ServerHandlerOnRecordCtx struct {
 	Request *base.Request
 	Path    string
 	Query   string
+	Medias  []*description.Media
 }
 
 // ServerHandlerOnRecord can be implemented by a ServerHandler.
@@ -154,6 +202,13 @@ type ServerHandlerOnPauseCtx struct {
 	Request *base.Request
 	Path    string
 	Query   string
+
+	// current playback position.
+	// It is computed from the start of the last PLAY range, the elapsed time
+	// and the scale. When the session is already paused, it is the position
+	// at which it was paused. It is nil when the session has never been
+	// played or is publishing.
+	CurrentRange *headers.Range
 }
 
 // ServerHandlerOnPause can be implemented by a ServerHandler.
@@ -216,6 +271,17 @@ type ServerHandlerOnDecodeError interface {
 	OnDecodeError(*ServerHandlerOnDecodeErrorCtx)
 }
 
+// ServerHandlerOnStreamCloseCtx is the context of OnStreamClose.
+type ServerHandlerOnStreamCloseCtx struct {
+	Stream *ServerStream
+}
+
+// ServerHandlerOnStreamClose can be implemented by a ServerHandler.
+type ServerHandlerOnStreamClose interface {
+	// called when a ServerStream is closed, after all its readers have been disconnected.
+	OnStreamClose(*ServerHandlerOnStreamCloseCtx)
+}
+
 // ServerHandlerOnStreamWriteErrorCtx is the context of OnStreamWriteError.
 type ServerHandlerOnStreamWriteErrorCtx struct {
 	Session *ServerSession
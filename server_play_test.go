@@ -3,6 +3,7 @@ package gortsplib
 import (
 	"bytes"
 	"crypto/tls"
+	"io"
 	"net"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/bluenviron/mediacommon/pkg/codecs/h265"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 	"github.com/stretchr/testify/require"
@@ -21,6 +23,7 @@ import (
 	"github.com/voicecom/gortsplib/v4/pkg/description"
 	"github.com/voicecom/gortsplib/v4/pkg/format"
 	"github.com/voicecom/gortsplib/v4/pkg/headers"
+	"github.com/voicecom/gortsplib/v4/pkg/liberrors"
 	"github.com/voicecom/gortsplib/v4/pkg/sdp"
 )
 
@@ -314,6 +317,194 @@ func TestServerPlayPath(t *testing.T) {
 	}
 }
 
+func TestServerPlayDescribeAttributesAndBandwidths(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	var ssd sdp.SessionDescription
+	err = ssd.Unmarshal([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"t=0 0\r\n" +
+		"a=control:rtsp://upstream/stream\r\n" +
+		"a=range:npt=0-\r\n" +
+		"a=x-qt-text-aut:author\r\n" +
+		"m=video 0 RTP/AVP 96\r\n" +
+		"b=AS:500\r\n" +
+		"a=control:rtsp://upstream/stream/trackID=0\r\n" +
+		"a=x-qt-text-nam:camera\r\n" +
+		"a=rtpmap:96 H264/90000\r\n" +
+		"a=rtcp:5001 IN IP4 192.168.1.1\r\n" +
+		"a=ssrc:1234 cname:upstream\r\n" +
+		"a=crypto:1 AES_CM_128_HMAC_SHA1_80 inline:WVNfX19zZW1jdGwgKCkgewkyMjA7fQp9CnVubGVz\r\n" +
+		"a=x-vendor:1\r\n"))
+	require.NoError(t, err)
+
+	var upstreamDesc description.Session
+	err = upstreamDesc.Unmarshal(&ssd)
+	require.NoError(t, err)
+	upstreamDesc.SuppressedAttributes = []string{"x-vendor"}
+
+	stream = NewServerStream(s, &upstreamDesc)
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Describe,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"1"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+	require.Equal(t, "v=0\r\n"+
+		"o=- 0 0 IN IP4 127.0.0.1\r\n"+
+		"s=Stream\r\n"+
+		"c=IN IP4 0.0.0.0\r\n"+
+		"t=0 0\r\n"+
+		"a=x-qt-text-aut:author\r\n"+
+		"m=video 0 RTP/AVP 96\r\n"+
+		"b=AS:500\r\n"+
+		"a=control:trackID=0\r\n"+
+		"a=x-qt-text-nam:camera\r\n"+
+		"a=rtpmap:96 H264/90000\r\n", string(res.Body))
+}
+
+type testServerHandlerDescribeReader struct {
+	reader func() io.Reader
+}
+
+func (sh *testServerHandlerDescribeReader) OnDescribeReader(
+	_ *ServerHandlerOnDescribeCtx,
+) (*base.Response, io.Reader, error) {
+	return &base.Response{
+		StatusCode: base.StatusOK,
+	}, sh.reader(), nil
+}
+
+type testReadCloser struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (r *testReadCloser) Close() error {
+	close(r.closed)
+	return nil
+}
+
+func TestServerPlayDescribeReader(t *testing.T) {
+	sdpStr := "v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=Stream\r\n" +
+		"t=0 0\r\n" +
+		"m=video 0 RTP/AVP 96\r\n" +
+		"a=control:trackID=0\r\n" +
+		"a=rtpmap:96 H264/90000\r\n"
+
+	for _, ca := range []string{
+		"seeker",
+		"non seeker",
+		"closer",
+		"seeker too big",
+		"non seeker too big",
+		"seeker after end",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			closed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandlerDescribeReader{
+					reader: func() io.Reader {
+						switch ca {
+						case "seeker", "seeker too big":
+							return strings.NewReader(sdpStr)
+
+						case "closer":
+							return &testReadCloser{
+								Reader: strings.NewReader(sdpStr),
+								closed: closed,
+							}
+
+						case "seeker after end":
+							r := strings.NewReader(sdpStr)
+							_, err := r.Seek(int64(len(sdpStr)+10), io.SeekStart)
+							require.NoError(t, err)
+							return r
+						}
+						return io.MultiReader(strings.NewReader(sdpStr))
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if ca == "seeker too big" || ca == "non seeker too big" {
+				s.MaxBodySize = int64(len(sdpStr)) - 1
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.Options,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: base.Header{
+					"CSeq": base.HeaderValue{"1"},
+				},
+			})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+			require.Contains(t, res.Header["Public"][0], string(base.Describe))
+
+			switch ca {
+			case "seeker too big", "non seeker too big", "seeker after end":
+				res, err = writeReqReadRes(conn, base.Request{
+					Method: base.Describe,
+					URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+					Header: base.Header{
+						"CSeq": base.HeaderValue{"2"},
+					},
+				})
+				require.NoError(t, err)
+				require.Equal(t, base.StatusInternalServerError, res.StatusCode)
+
+			default:
+				desc := doDescribe(t, conn)
+				require.Equal(t, mustParseURL("rtsp://localhost:8554/teststream?param=value/"), desc.BaseURL)
+				require.Equal(t, 1, len(desc.Medias))
+
+				if ca == "closer" {
+					<-closed
+				}
+			}
+		})
+	}
+}
+
 func TestServerPlaySetupErrors(t *testing.T) {
 	for _, ca := range []string{
 		"different paths",
@@ -1262,10 +1453,12 @@ func TestServerPlayTCPResponseBeforeFrames(t *testing.T) {
 	require.NoError(t, err)
 }
 
-func TestServerPlayPlayPlay(t *testing.T) {
+func TestServerPlayStreamClose(t *testing.T) {
 	var stream *ServerStream
+	streamClosed := make(chan struct{})
 
 	s := &Server{
+		RTSPAddress: "localhost:8554",
 		Handler: &testServerHandler{
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
@@ -1282,10 +1475,11 @@ func TestServerPlayPlayPlay(t *testing.T) {
 					StatusCode: base.StatusOK,
 				}, nil
 			},
+			onStreamClose: func(ctx *ServerHandlerOnStreamCloseCtx) {
+				require.Equal(t, stream, ctx.Stream)
+				close(streamClosed)
+			},
 		},
-		UDPRTPAddress:  "127.0.0.1:8000",
-		UDPRTCPAddress: "127.0.0.1:8001",
-		RTSPAddress:    "localhost:8554",
 	}
 
 	err := s.Start()
@@ -1293,7 +1487,6 @@ func TestServerPlayPlayPlay(t *testing.T) {
 	defer s.Close()
 
 	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
-	defer stream.Close()
 
 	nconn, err := net.Dial("tcp", "localhost:8554")
 	require.NoError(t, err)
@@ -1303,10 +1496,10 @@ func TestServerPlayPlayPlay(t *testing.T) {
 	desc := doDescribe(t, conn)
 
 	inTH := &headers.Transport{
-		Protocol:    headers.TransportProtocolUDP,
-		Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
-		Mode:        transportModePtr(headers.TransportModePlay),
-		ClientPorts: &[2]int{30450, 30451},
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
 	}
 
 	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
@@ -1314,145 +1507,76 @@ func TestServerPlayPlayPlay(t *testing.T) {
 	session := readSession(t, res)
 
 	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-}
-
-func TestServerPlayPlayPausePlay(t *testing.T) {
-	var stream *ServerStream
-	writerStarted := false
-	writerDone := make(chan struct{})
-	writerTerminate := make(chan struct{})
-
-	s := &Server{
-		Handler: &testServerHandler{
-			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-				close(writerTerminate)
-				<-writerDone
-			},
-			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, stream, nil
-			},
-			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, stream, nil
-			},
-			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-				if !writerStarted {
-					writerStarted = true
-					go func() {
-						defer close(writerDone)
-
-						ti := time.NewTicker(50 * time.Millisecond)
-						defer ti.Stop()
 
-						for {
-							select {
-							case <-ti.C:
-								err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-								require.NoError(t, err)
-							case <-writerTerminate:
-								return
-							}
-						}
-					}()
-				}
-
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, nil
-			},
-			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, nil
-			},
+	// the SSRC is known after the first IDR
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			PayloadType: 96,
+			SSRC:        0x38F27A2F,
 		},
-		RTSPAddress: "localhost:8554",
-	}
+		Payload: []byte{0x05, 0x01},
+	})
+	require.NoError(t, err)
 
-	err := s.Start()
+	f, err := conn.ReadInterleavedFrame()
 	require.NoError(t, err)
-	defer s.Close()
+	require.Equal(t, 0, f.Channel)
 
-	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
-	defer stream.Close()
+	go stream.Close()
 
-	nconn, err := net.Dial("tcp", "localhost:8554")
-	require.NoError(t, err)
-	defer nconn.Close()
-	conn := conn.NewConn(nconn)
+	for {
+		f, err = conn.ReadInterleavedFrame()
+		require.NoError(t, err)
 
-	desc := doDescribe(t, conn)
+		if f.Channel != 1 {
+			continue
+		}
 
-	inTH := &headers.Transport{
-		Protocol:       headers.TransportProtocolTCP,
-		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-		Mode:           transportModePtr(headers.TransportModePlay),
-		InterleavedIDs: &[2]int{0, 1},
-	}
+		var pkts []rtcp.Packet
+		pkts, err = rtcp.Unmarshal(f.Payload)
+		require.NoError(t, err)
 
-	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+		if bye, ok := pkts[0].(*rtcp.Goodbye); ok {
+			require.Equal(t, []uint32{0x38F27A2F}, bye.Sources)
+			break
+		}
+	}
 
-	session := readSession(t, res)
+	<-streamClosed
 
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
-	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+	require.Equal(t, liberrors.ErrServerStreamClosed{}, err)
 }
 
-func TestServerPlayPlayPausePause(t *testing.T) {
+func TestServerPlayTransportAlternatives(t *testing.T) {
 	var stream *ServerStream
-	writerDone := make(chan struct{})
-	writerTerminate := make(chan struct{})
 
 	s := &Server{
 		Handler: &testServerHandler{
-			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
-				close(writerTerminate)
-				<-writerDone
-			},
 			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, stream, nil
 			},
-			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				require.Equal(t, TransportTCP, ctx.Transport)
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, stream, nil
 			},
 			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
-				go func() {
-					defer close(writerDone)
-
-					ti := time.NewTicker(50 * time.Millisecond)
-					defer ti.Stop()
-
-					for {
-						select {
-						case <-ti.C:
-							err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
-							require.NoError(t, err)
-						case <-writerTerminate:
-							return
-						}
-					}
-				}()
-
-				return &base.Response{
-					StatusCode: base.StatusOK,
-				}, nil
-			},
-			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
 				return &base.Response{
 					StatusCode: base.StatusOK,
 				}, nil
 			},
 		},
-		RTSPAddress: "localhost:8554",
+		UDPRTPAddress:     "127.0.0.1:8000",
+		UDPRTCPAddress:    "127.0.0.1:8001",
+		MulticastIPRange:  "224.1.0.0/16",
+		MulticastRTPPort:  8002,
+		MulticastRTCPPort: 8003,
+		RTSPAddress:       "localhost:8554",
 	}
 
 	err := s.Start()
@@ -1469,18 +1593,560 @@ func TestServerPlayPlayPausePause(t *testing.T) {
 
 	desc := doDescribe(t, conn)
 
-	inTH := &headers.Transport{
+	// the first alternative is supported by the server, but can't be used
+	// since client ports are missing.
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mediaURL(t, desc.BaseURL, desc.Medias[0]),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"1"},
+			"Transport": base.HeaderValue{
+				"RTP/AVP;unicast;mode=record,RTP/AVP;unicast,RTP/AVP/TCP;unicast;interleaved=2-3",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+
+	// only the chosen alternative is returned
+	var th headers.Transports
+	err = th.Unmarshal(res.Header["Transport"])
+	require.NoError(t, err)
+	require.Equal(t, headers.Transports{{
 		Protocol:       headers.TransportProtocolTCP,
 		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
-		Mode:           transportModePtr(headers.TransportModePlay),
-		InterleavedIDs: &[2]int{0, 1},
-	}
-
-	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+		InterleavedIDs: &[2]int{2, 3},
+		SSRC:           th[0].SSRC,
+	}}, th)
+}
 
-	session := readSession(t, res)
+func TestServerPlayForceTransport(t *testing.T) {
+	for _, ca := range []string{
+		"udp to tcp",
+		"tcp to udp",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			var stream *ServerStream
 
-	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						if ca == "udp to tcp" {
+							require.Equal(t, TransportUDP, ctx.Transport)
+							v := TransportTCP
+							ctx.ForceTransport = &v
+						} else {
+							require.Equal(t, TransportTCP, ctx.Transport)
+							v := TransportUDP
+							ctx.ForceTransport = &v
+						}
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+				},
+				UDPRTPAddress:  "127.0.0.1:8000",
+				UDPRTCPAddress: "127.0.0.1:8001",
+				RTSPAddress:    "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:     transportModePtr(headers.TransportModePlay),
+			}
+
+			if ca == "udp to tcp" {
+				inTH.Protocol = headers.TransportProtocolUDP
+				inTH.ClientPorts = &[2]int{35466, 35467}
+			} else {
+				inTH.Protocol = headers.TransportProtocolTCP
+				inTH.InterleavedIDs = &[2]int{0, 1}
+			}
+
+			res, err := writeReqReadRes(conn, base.Request{
+				Method: base.Setup,
+				URL:    mediaURL(t, desc.BaseURL, desc.Medias[0]),
+				Header: base.Header{
+					"CSeq":      base.HeaderValue{"1"},
+					"Transport": inTH.Marshal(),
+				},
+			})
+			require.NoError(t, err)
+
+			if ca == "udp to tcp" {
+				require.Equal(t, base.StatusOK, res.StatusCode)
+
+				var th headers.Transport
+				err = th.Unmarshal(res.Header["Transport"])
+				require.NoError(t, err)
+				require.Equal(t, headers.TransportProtocolTCP, th.Protocol)
+				require.Equal(t, &[2]int{0, 1}, th.InterleavedIDs)
+				require.Nil(t, th.ClientPorts)
+			} else {
+				// UDP can't be forced since client ports are unknown
+				require.Equal(t, base.StatusUnsupportedTransport, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerPlayPlayPlay(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		UDPRTPAddress:  "127.0.0.1:8000",
+		UDPRTCPAddress: "127.0.0.1:8001",
+		RTSPAddress:    "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:    headers.TransportProtocolUDP,
+		Delivery:    deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:        transportModePtr(headers.TransportModePlay),
+		ClientPorts: &[2]int{30450, 30451},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+}
+
+func TestServerPlayRangeScale(t *testing.T) {
+	for _, ca := range []string{
+		"none",
+		"range",
+		"scale",
+		"invalid range",
+		"invalid scale",
+	} {
+		t.Run(ca, func(t *testing.T) {
+			var stream *ServerStream
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						switch ca {
+						case "none":
+							require.Nil(t, ctx.Range)
+							require.Nil(t, ctx.Scale)
+
+						case "range":
+							end := 20 * time.Second
+							require.Equal(t, &headers.Range{
+								Value: &headers.RangeNPT{
+									Start: 10 * time.Second,
+									End:   &end,
+								},
+							}, ctx.Range)
+							require.Nil(t, ctx.Scale)
+
+						case "scale":
+							require.Nil(t, ctx.Range)
+							require.Equal(t, -2.5, *ctx.Scale)
+
+						default:
+							t.Error("should not happen")
+						}
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Protocol:       headers.TransportProtocolTCP,
+				Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:           transportModePtr(headers.TransportModePlay),
+				InterleavedIDs: &[2]int{0, 1},
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			session := readSession(t, res)
+
+			h := base.Header{
+				"CSeq":    base.HeaderValue{"3"},
+				"Session": base.HeaderValue{session},
+			}
+
+			switch ca {
+			case "range":
+				h["Range"] = base.HeaderValue{"npt=10-20"}
+
+			case "scale":
+				h["Scale"] = base.HeaderValue{"-2.5"}
+
+			case "invalid range":
+				h["Range"] = base.HeaderValue{"invalid"}
+
+			case "invalid scale":
+				h["Scale"] = base.HeaderValue{"fast"}
+			}
+
+			res, err = writeReqReadRes(conn, base.Request{
+				Method: base.Play,
+				URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+				Header: h,
+			})
+			require.NoError(t, err)
+
+			switch ca {
+			case "invalid range", "invalid scale":
+				require.Equal(t, base.StatusBadRequest, res.StatusCode)
+
+			default:
+				require.Equal(t, base.StatusOK, res.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServerPlayPlayPausePlay(t *testing.T) {
+	var stream *ServerStream
+	writerStarted := false
+	writerDone := make(chan struct{})
+	writerTerminate := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+				close(writerTerminate)
+				<-writerDone
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				if !writerStarted {
+					writerStarted = true
+					go func() {
+						defer close(writerDone)
+
+						ti := time.NewTicker(50 * time.Millisecond)
+						defer ti.Stop()
+
+						for {
+							select {
+							case <-ti.C:
+								err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+								require.NoError(t, err)
+							case <-writerTerminate:
+								return
+							}
+						}
+					}()
+				}
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+}
+
+func TestServerPlayPausePosition(t *testing.T) {
+	var stream *ServerStream
+	var curTimeMutex sync.Mutex
+	curTime := time.Date(2014, 6, 7, 15, 0, 0, 0, time.UTC)
+
+	advance := func(d time.Duration) {
+		curTimeMutex.Lock()
+		defer curTimeMutex.Unlock()
+		curTime = curTime.Add(d)
+	}
+
+	var resumeFrom *headers.Range
+	var currentRange *headers.Range
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				resumeFrom = ctx.ResumeFrom
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(ctx *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				currentRange = ctx.CurrentRange
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+		timeNow: func() time.Time {
+			curTimeMutex.Lock()
+			defer curTimeMutex.Unlock()
+			return curTime
+		},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.Play,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":    base.HeaderValue{"3"},
+			"Session": base.HeaderValue{session},
+			"Range":   base.HeaderValue{"npt=10-"},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+	require.Nil(t, resumeFrom)
+
+	advance(5 * time.Second)
+
+	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+	require.Equal(t, &headers.Range{Value: &headers.RangeNPT{Start: 15 * time.Second}}, currentRange)
+
+	advance(30 * time.Second)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+	require.Equal(t, &headers.Range{Value: &headers.RangeNPT{Start: 15 * time.Second}}, resumeFrom)
+
+	advance(2 * time.Second)
+
+	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+	require.Equal(t, &headers.Range{Value: &headers.RangeNPT{Start: 17 * time.Second}}, currentRange)
+}
+
+func TestServerPlayPlayPausePause(t *testing.T) {
+	var stream *ServerStream
+	writerDone := make(chan struct{})
+	writerTerminate := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+				close(writerTerminate)
+				<-writerDone
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				go func() {
+					defer close(writerDone)
+
+					ti := time.NewTicker(50 * time.Millisecond)
+					defer ti.Stop()
+
+					for {
+						select {
+						case <-ti.C:
+							err := stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+							require.NoError(t, err)
+						case <-writerTerminate:
+							return
+						}
+					}
+				}()
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
 
 	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
 
@@ -1810,6 +2476,333 @@ func TestServerPlayPartialMedias(t *testing.T) {
 	require.Equal(t, testRTPPacketMarshaled, f.Payload)
 }
 
+func TestServerPlayMaxTrackCount(t *testing.T) {
+	var stream *ServerStream
+	setupCount := 0
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				require.Equal(t, setupCount, ctx.Session.TrackCount())
+				setupCount++
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				require.Equal(t, 2, ctx.Session.TrackCount())
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress:   "localhost:8554",
+		MaxTrackCount: 2,
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	medias := make([]*description.Media, 3)
+	for i := range medias {
+		medias[i] = &description.Media{
+			Type:    description.MediaTypeVideo,
+			Formats: testH264Media.Formats,
+		}
+	}
+
+	stream = NewServerStream(s, &description.Session{Medias: medias})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	var session string
+
+	for i := 0; i < 2; i++ {
+		inTH := &headers.Transport{
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:           transportModePtr(headers.TransportModePlay),
+			Protocol:       headers.TransportProtocolTCP,
+			InterleavedIDs: &[2]int{i * 2, i*2 + 1},
+		}
+
+		res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[i]).String(), inTH, session)
+		session = readSession(t, res)
+	}
+
+	inTH := &headers.Transport{
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{4, 5},
+	}
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Setup,
+		URL:    mediaURL(t, desc.BaseURL, desc.Medias[2]),
+		Header: base.Header{
+			"CSeq":      base.HeaderValue{"1"},
+			"Transport": inTH.Marshal(),
+			"Session":   base.HeaderValue{session},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusNotEnoughBandwidth, res.StatusCode)
+	require.Equal(t, 2, setupCount)
+
+	// the session is still usable
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+}
+
+func TestServerPlayUpdateStreamParams(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress:        "localhost:8554",
+		UpdateStreamParams: true,
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	medi := &description.Media{
+		Type: description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+		}},
+	}
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{medi}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+	require.Equal(t, []byte(nil), desc.Medias[0].Formats[0].(*format.H264).SPS)
+
+	sps := testH264Media.Formats[0].(*format.H264).SPS
+	pps := []byte{0x68, 0xce, 0x3c, 0x80}
+
+	for _, nalu := range [][]byte{sps, pps} {
+		err = stream.WritePacketRTP(medi, &rtp.Packet{
+			Header: rtp.Header{
+				Version:     2,
+				PayloadType: 96,
+			},
+			Payload: nalu,
+		})
+		require.NoError(t, err)
+	}
+
+	desc = doDescribe(t, conn)
+	require.Equal(t, sps, desc.Medias[0].Formats[0].(*format.H264).SPS)
+	require.Equal(t, pps, desc.Medias[0].Formats[0].(*format.H264).PPS)
+}
+
+func TestServerPlayUpdateStreamParamsH265(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress:        "localhost:8554",
+		UpdateStreamParams: true,
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	vps := []byte{
+		0x40, 0x01, 0x0c, 0x01, 0xff, 0xff, 0x01, 0x60,
+		0x00, 0x00, 0x03, 0x00, 0x00, 0x03, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x03, 0x00, 0x96, 0xac, 0x09,
+	}
+	pps := []byte{
+		0x44, 0x01, 0xe0, 0x76, 0xb0, 0x26, 0x40,
+	}
+
+	medi := &description.Media{
+		Type: description.MediaTypeVideo,
+		Formats: []format.Format{&format.H265{
+			PayloadTyp: 96,
+			VPS:        vps,
+			SPS: []byte{
+				0x42, 0x01, 0x01, 0x01, 0x60, 0x00, 0x00, 0x03,
+				0x00, 0x90, 0x00, 0x00, 0x03, 0x00, 0x00, 0x03,
+				0x00, 0x78, 0xa0, 0x03, 0xc0, 0x80, 0x10, 0xe5,
+				0x96, 0x66, 0x69, 0x24, 0xca, 0xe0, 0x10, 0x00,
+				0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x03, 0x01,
+				0xe0, 0x80,
+			},
+			PPS: pps,
+		}},
+	}
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{medi}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	width := func() int {
+		desc := doDescribe(t, conn)
+
+		var sps h265.SPS
+		err2 := sps.Unmarshal(desc.Medias[0].Formats[0].(*format.H265).SPS)
+		require.NoError(t, err2)
+
+		return sps.Width()
+	}
+
+	require.Equal(t, 1920, width())
+
+	// resolution change
+	sps720p := []byte{
+		0x42, 0x01, 0x01, 0x04, 0x08, 0x00, 0x00, 0x03,
+		0x00, 0x98, 0x08, 0x00, 0x00, 0x03, 0x00, 0x00,
+		0x5d, 0x90, 0x00, 0x50, 0x10, 0x05, 0xa2, 0x29,
+		0x4b, 0x74, 0x94, 0x98, 0x5f, 0xfe, 0x00, 0x02,
+		0x00, 0x02, 0xd4, 0x04, 0x04, 0x04, 0x10, 0x00,
+		0x00, 0x03, 0x00, 0x10, 0x00, 0x00, 0x03, 0x01,
+		0xe0, 0x80,
+	}
+
+	payload := []byte{0x60, 0x01}
+	for _, nalu := range [][]byte{vps, sps720p, pps} {
+		payload = append(payload, byte(len(nalu)>>8), byte(len(nalu)))
+		payload = append(payload, nalu...)
+	}
+
+	err = stream.WritePacketRTP(medi, &rtp.Packet{
+		Header: rtp.Header{
+			Version:     2,
+			PayloadType: 96,
+		},
+		Payload: payload,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 1280, width())
+}
+
+func TestServerPlayWritePacketRTPToSession(t *testing.T) {
+	var stream *ServerStream
+	sessions := make(chan *ServerSession, 2)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(ctx *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				err := ctx.Session.s.WritePacketRTPToSession(ctx.Session, stream.Description().Medias[0], &testRTPPacket)
+				require.EqualError(t, err, "session is not reading a stream with a unicast transport")
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				sessions <- ctx.Session
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	var conns [2]*conn.Conn
+
+	for i := 0; i < 2; i++ {
+		nconn, err := net.Dial("tcp", "localhost:8554")
+		require.NoError(t, err)
+		defer nconn.Close()
+		conns[i] = conn.NewConn(nconn)
+
+		desc := doDescribe(t, conns[i])
+
+		inTH := &headers.Transport{
+			Protocol:       headers.TransportProtocolTCP,
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:           transportModePtr(headers.TransportModePlay),
+			InterleavedIDs: &[2]int{0, 1},
+		}
+
+		res, _ := doSetup(t, conns[i], mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+		session := readSession(t, res)
+
+		doPlay(t, conns[i], "rtsp://localhost:8554/teststream", session)
+	}
+
+	firstSession := <-sessions
+
+	targeted := testRTPPacket
+	targeted.Payload = []byte{0x05, 0x06}
+
+	err = s.WritePacketRTPToSession(firstSession, stream.Description().Medias[0], &targeted)
+	require.NoError(t, err)
+
+	err = stream.WritePacketRTP(stream.Description().Medias[0], &testRTPPacket)
+	require.NoError(t, err)
+
+	// the first session belongs to the first connection
+	f, err := conns[0].ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, mustMarshalPacketRTP(&targeted), f.Payload)
+
+	f, err = conns[0].ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, testRTPPacketMarshaled, f.Payload)
+
+	f, err = conns[1].ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, testRTPPacketMarshaled, f.Payload)
+}
+
 func TestServerPlayAdditionalInfos(t *testing.T) {
 	getInfos := func() (*headers.RTPInfo, []*uint32) {
 		nconn, err := net.Dial("tcp", "localhost:8554")
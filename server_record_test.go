@@ -271,6 +271,22 @@ func TestServerRecordPath(t *testing.T) {
 			"/test/stream",
 			"testing=0",
 		},
+		{
+			"absolute control with query",
+			"rtsp://localhost:8554/test/stream?token=abc/trackID=1",
+			"rtsp://localhost:8554/test/stream?token=abc",
+			"rtsp://localhost:8554/test/stream?token=abc/trackID=1",
+			"/test/stream",
+			"token=abc",
+		},
+		{
+			"absolute control with query, different host and escaping",
+			"rtsp://myhost/test/stream?token=a%2Bb/trackID=1",
+			"rtsp://localhost:8554/test/stream?token=a+b",
+			"rtsp://localhost:8554/test/stream?token=a+b/trackID=1",
+			"/test/stream",
+			"token=a+b",
+		},
 		{
 			"no path",
 			"streamid=1",
@@ -377,6 +393,40 @@ func TestServerRecordPath(t *testing.T) {
 	}
 }
 
+func TestServerRecordAnnounceContentTypeWithCharset(t *testing.T) {
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Announce,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":         base.HeaderValue{"1"},
+			"Content-Type": base.HeaderValue{"application/sdp;charset=UTF-8"},
+		},
+		Body: mediasToSDP([]*description.Media{testH264Media}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+}
+
 func TestServerRecordErrorSetupMediaTwice(t *testing.T) {
 	serverErr := make(chan error)
 
@@ -528,6 +578,138 @@ func TestServerRecordErrorRecordPartialMedias(t *testing.T) {
 	require.EqualError(t, err, "not all announced medias have been setup")
 }
 
+func TestServerRecordErrorMediaTypeNotAllowed(t *testing.T) {
+	serverErr := make(chan error)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(ctx *ServerHandlerOnConnCloseCtx) {
+				serverErr <- ctx.Error
+			},
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(_ *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				t.Error("should not happen")
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress:       "localhost:8554",
+		AllowedMediaTypes: []description.MediaType{description.MediaTypeVideo, description.MediaTypeAudio},
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	forma := &format.Generic{
+		PayloadTyp: 96,
+		RTPMa:      "private/90000",
+	}
+	err = forma.Init()
+	require.NoError(t, err)
+
+	medias := []*description.Media{
+		testH264Media,
+		{
+			Type:    "application",
+			Formats: []format.Format{forma},
+		},
+	}
+
+	doAnnounce(t, conn, "rtsp://localhost:8554/teststream", medias)
+
+	var session string
+
+	for i, medi := range medias {
+		inTH := &headers.Transport{
+			Protocol:       headers.TransportProtocolTCP,
+			Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+			Mode:           transportModePtr(headers.TransportModeRecord),
+			InterleavedIDs: &[2]int{i * 2, i*2 + 1},
+		}
+
+		res, _ := doSetup(t, conn, "rtsp://localhost:8554/teststream/"+medi.Control, inTH, session)
+		session = readSession(t, res)
+	}
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Record,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":    base.HeaderValue{"4"},
+			"Session": base.HeaderValue{session},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusUnsupportedMediaType, res.StatusCode)
+
+	err = <-serverErr
+	require.EqualError(t, err, "media type 'application' is not allowed")
+}
+
+func TestServerRecordErrorBodyTooLarge(t *testing.T) {
+	serverErr := make(chan error)
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(ctx *ServerHandlerOnConnCloseCtx) {
+				serverErr <- ctx.Error
+			},
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				t.Error("should not happen")
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+		MaxBodySize: 1024,
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	body := mediasToSDP([]*description.Media{testH264Media})
+	body = append(body, bytes.Repeat([]byte("a=padding\r\n"), 100)...)
+
+	res, err := writeReqReadRes(conn, base.Request{
+		Method: base.Announce,
+		URL:    mustParseURL("rtsp://localhost:8554/teststream"),
+		Header: base.Header{
+			"CSeq":         base.HeaderValue{"1"},
+			"Content-Type": base.HeaderValue{"application/sdp"},
+		},
+		Body: body,
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusRequestEntityTooLarge, res.StatusCode)
+	require.Equal(t, base.HeaderValue{"1"}, res.Header["CSeq"])
+
+	err = <-serverErr
+	require.EqualError(t, err, "Content-Length exceeds 1024 (it's "+strconv.Itoa(len(body))+")")
+}
+
 func TestServerRecord(t *testing.T) {
 	for _, transport := range []string{
 		"udp",
@@ -577,6 +759,7 @@ func TestServerRecord(t *testing.T) {
 
 						require.Equal(t, "param=value", ctx.Session.SetuppedQuery())
 						require.Equal(t, ctx.Session.AnnouncedDescription().Medias, ctx.Session.SetuppedMedias())
+						require.Equal(t, ctx.Session.AnnouncedDescription().Medias, ctx.Medias)
 
 						// queue sending of RTCP packets.
 						// these are sent after the response, only if onRecord returns StatusOK.
@@ -960,6 +1143,96 @@ func TestServerRecordRTCPReport(t *testing.T) {
 	}, rr)
 }
 
+func TestServerRecordKeyFrameRequest(t *testing.T) {
+	s := &Server{
+		Handler: &testServerHandler{
+			onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil, nil
+			},
+			onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+				ctx.Session.OnPacketRTPAny(func(medi *description.Media, forma format.Format, _ *rtp.Packet) {
+					err := ctx.Session.WritePLI(medi, forma)
+					require.NoError(t, err)
+
+					err = ctx.Session.WriteFIR(medi, forma)
+					require.EqualError(t, err, "RTCP feedback 'ccm fir' is not supported by the format")
+				})
+
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	medias := []*description.Media{{
+		Type: description.MediaTypeVideo,
+		Formats: []format.Format{&format.H264{
+			PayloadTyp:        96,
+			PacketizationMode: 1,
+		}},
+		RTCPFeedback: map[uint8][]string{
+			96: {"nack pli"},
+		},
+	}}
+
+	doAnnounce(t, conn, "rtsp://localhost:8554/teststream", medias)
+
+	inTH := &headers.Transport{
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModeRecord),
+		Protocol:       headers.TransportProtocolTCP,
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, "rtsp://localhost:8554/teststream/"+medias[0].Control, inTH, "")
+
+	session := readSession(t, res)
+
+	doRecord(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+		Channel: 0,
+		Payload: mustMarshalPacketRTP(&rtp.Packet{
+			Header: rtp.Header{
+				Version:        2,
+				PayloadType:    96,
+				SequenceNumber: 534,
+				Timestamp:      54352,
+				SSRC:           753621,
+			},
+			Payload: []byte{1, 2, 3, 4},
+		}),
+	}, make([]byte, 1024))
+	require.NoError(t, err)
+
+	f, err := conn.ReadInterleavedFrame()
+	require.NoError(t, err)
+	require.Equal(t, 1, f.Channel)
+	pkts, err := rtcp.Unmarshal(f.Payload)
+	require.NoError(t, err)
+	pli, ok := pkts[0].(*rtcp.PictureLossIndication)
+	require.True(t, ok)
+	require.Equal(t, uint32(753621), pli.MediaSSRC)
+}
+
 func TestServerRecordTimeout(t *testing.T) {
 	for _, transport := range []string{
 		"udp",
@@ -1431,6 +1704,119 @@ func TestServerRecordDecodeErrors(t *testing.T) {
 	}
 }
 
+func TestServerRecordPacketTooLarge(t *testing.T) {
+	for _, proto := range []string{"udp", "tcp"} {
+		t.Run(proto, func(t *testing.T) {
+			errorRecv := make(chan struct{})
+			packetRecv := make(chan struct{})
+			connClosed := make(chan struct{})
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onConnClose: func(ctx *ServerHandlerOnConnCloseCtx) {
+						if proto == "tcp" {
+							require.EqualError(t, ctx.Error, "packet size (1000) is greater than maximum allowed (500)")
+							close(connClosed)
+						}
+					},
+					onAnnounce: func(_ *ServerHandlerOnAnnounceCtx) (*base.Response, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil, nil
+					},
+					onRecord: func(ctx *ServerHandlerOnRecordCtx) (*base.Response, error) {
+						ctx.Session.OnPacketRTPAny(func(_ *description.Media, _ format.Format, _ *rtp.Packet) {
+							close(packetRecv)
+						})
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+					onDecodeError: func(ctx *ServerHandlerOnDecodeErrorCtx) {
+						require.EqualError(t, ctx.Error, "packet size (1000) is greater than maximum allowed (500)")
+						close(errorRecv)
+					},
+				},
+				UDPRTPAddress:         "127.0.0.1:8000",
+				UDPRTCPAddress:        "127.0.0.1:8001",
+				RTSPAddress:           "localhost:8554",
+				MaxIncomingPacketSize: 500,
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			medias := []*description.Media{testH264Media}
+
+			doAnnounce(t, conn, "rtsp://localhost:8554/teststream", medias)
+
+			inTH := &headers.Transport{
+				Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:     transportModePtr(headers.TransportModeRecord),
+			}
+
+			var l1 net.PacketConn
+
+			if proto == "udp" {
+				inTH.Protocol = headers.TransportProtocolUDP
+				inTH.ClientPorts = &[2]int{35466, 35467}
+
+				l1, err = net.ListenPacket("udp", "127.0.0.1:35466")
+				require.NoError(t, err)
+				defer l1.Close()
+			} else {
+				inTH.Protocol = headers.TransportProtocolTCP
+				inTH.InterleavedIDs = &[2]int{0, 1}
+			}
+
+			res, resTH := doSetup(t, conn, "rtsp://localhost:8554/teststream/"+medias[0].Control, inTH, "")
+
+			session := readSession(t, res)
+
+			doRecord(t, conn, "rtsp://localhost:8554/teststream", session)
+
+			writeRTP := func(byts []byte) {
+				if proto == "udp" {
+					_, err = l1.WriteTo(byts, &net.UDPAddr{
+						IP:   net.ParseIP("127.0.0.1"),
+						Port: resTH.ServerPorts[0],
+					})
+				} else {
+					err = conn.WriteInterleavedFrame(&base.InterleavedFrame{
+						Channel: 0,
+						Payload: byts,
+					}, make([]byte, 2048))
+				}
+				require.NoError(t, err)
+			}
+
+			writeRTP(bytes.Repeat([]byte{0x01, 0x02}, 1000/2))
+
+			if proto == "udp" {
+				<-errorRecv
+
+				// the session is still working
+				writeRTP(mustMarshalPacketRTP(&testRTPPacket))
+				<-packetRecv
+			} else {
+				<-connClosed
+			}
+		})
+	}
+}
+
 func TestServerRecordPacketNTP(t *testing.T) {
 	recv := make(chan struct{})
 	first := false
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -83,6 +85,20 @@ func getPathAndQueryAndTrackID(u *base.URL) (string, string, string, error) {
 	return "", "", "", liberrors.ErrServerPathNoSlash{}
 }
 
+func unescapeOrSame(s string) string {
+	if s2, err := url.PathUnescape(s); err == nil {
+		return s2
+	}
+	return s
+}
+
+// urlPathAndQueryEqual compares path and query of two URLs,
+// regardless of their escaping.
+func urlPathAndQueryEqual(u1 *base.URL, u2 *base.URL) bool {
+	return u1.Path == u2.Path &&
+		unescapeOrSame(u1.RawQuery) == unescapeOrSame(u2.RawQuery)
+}
+
 // used for SETUP when recording
 func findMediaByURL(
 	medias []*description.Media,
@@ -93,7 +109,10 @@ func findMediaByURL(
 	for _, media := range medias {
 		if strings.HasPrefix(media.Control, "rtsp://") ||
 			strings.HasPrefix(media.Control, "rtsps://") {
-			if media.Control == u.String() {
+			// the client replaces host and credentials of absolute control
+			// attributes, therefore compare only path and query.
+			ur, err := base.ParseURL(media.Control)
+			if err == nil && urlPathAndQueryEqual(ur, u) {
 				return media
 			}
 		} else {
@@ -147,19 +166,137 @@ func findMediaByTrackID(medias []*description.Media, trackID string) *descriptio
 	return medias[id]
 }
 
-func findFirstSupportedTransportHeader(s *Server, tsh headers.Transports) *headers.Transport {
+func isMediaTypeAllowed(allowed []description.MediaType, typ description.MediaType) bool {
+	for _, t := range allowed {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func transportFromHeader(th *headers.Transport) Transport {
+	if th.Protocol == headers.TransportProtocolUDP {
+		if th.Delivery != nil && *th.Delivery == headers.TransportDeliveryMulticast {
+			return TransportUDPMulticast
+		}
+		return TransportUDP
+	}
+	return TransportTCP
+}
+
+func isTransportHeaderSupported(s *Server, th *headers.Transport) bool {
+	switch transportFromHeader(th) {
+	case TransportUDP:
+		return s.udpRTPListener != nil
+
+	case TransportUDPMulticast:
+		return s.MulticastIPRange != ""
+	}
+	return true
+}
+
+// isTransportHeaderCompatible checks whether a transport can be accepted
+// without errors, given the state of the session.
+func (ss *ServerSession) isTransportHeaderCompatible(th *headers.Transport) bool {
+	transport := transportFromHeader(th)
+
+	if ss.setuppedTransport != nil && *ss.setuppedTransport != transport {
+		return false
+	}
+
+	switch transport {
+	case TransportUDP:
+		if th.ClientPorts == nil {
+			return false
+		}
+
+	case TransportTCP:
+		if th.InterleavedIDs != nil &&
+			((th.InterleavedIDs[0]+1) != th.InterleavedIDs[1] ||
+				ss.isChannelPairInUse(th.InterleavedIDs[0])) {
+			return false
+		}
+	}
+
+	switch ss.state {
+	case ServerSessionStateInitial, ServerSessionStatePrePlay: // play
+		return th.Mode == nil || *th.Mode == headers.TransportModePlay
+
+	default: // record
+		return transport != TransportUDPMulticast &&
+			th.Mode != nil && *th.Mode == headers.TransportModeRecord
+	}
+}
+
+// forcedTransportHeader returns the transport header to use when
+// the handler forces a transport.
+func (ss *ServerSession) forcedTransportHeader(
+	tsh headers.Transports,
+	inTH *headers.Transport,
+	forced Transport,
+) (*headers.Transport, error) {
+	// use an alternative offered by the client, if there's any
+	for i := range tsh {
+		th := &tsh[i]
+
+		if transportFromHeader(th) == forced &&
+			isTransportHeaderSupported(ss.s, th) &&
+			ss.isTransportHeaderCompatible(th) {
+			return th, nil
+		}
+	}
+
+	th := &headers.Transport{
+		Mode: inTH.Mode,
+	}
+
+	switch forced {
+	case TransportUDPMulticast:
+		v := headers.TransportDeliveryMulticast
+		th.Protocol = headers.TransportProtocolUDP
+		th.Delivery = &v
+
+	case TransportTCP:
+		v := headers.TransportDeliveryUnicast
+		th.Protocol = headers.TransportProtocolTCP
+		th.Delivery = &v
+
+	default: // client ports are unknown
+		return nil, liberrors.ErrServerCannotForceTransport{Transport: forced}
+	}
+
+	if !isTransportHeaderSupported(ss.s, th) || !ss.isTransportHeaderCompatible(th) {
+		return nil, liberrors.ErrServerCannotForceTransport{Transport: forced}
+	}
+
+	return th, nil
+}
+
+func (ss *ServerSession) findFirstSupportedTransportHeader(tsh headers.Transports) *headers.Transport {
 	// Per RFC2326 section 12.39, client specifies transports in order of preference.
-	// Filter out the ones we don't support and then pick first supported transport.
-	for _, tr := range tsh {
-		isMulticast := tr.Delivery != nil && *tr.Delivery == headers.TransportDeliveryMulticast
-		if tr.Protocol == headers.TransportProtocolUDP &&
-			((!isMulticast && s.udpRTPListener == nil) ||
-				(isMulticast && s.MulticastIPRange == "")) {
+	// Filter out the ones we don't support and then pick the first one that is
+	// compatible with the session.
+	// If there's none, pick the first supported one, that is then rejected with a proper error.
+	var fallback *headers.Transport
+
+	for i := range tsh {
+		th := &tsh[i]
+
+		if !isTransportHeaderSupported(ss.s, th) {
 			continue
 		}
-		return &tr
+
+		if ss.isTransportHeaderCompatible(th) {
+			return th
+		}
+
+		if fallback == nil {
+			fallback = th
+		}
 	}
-	return nil
+
+	return fallback
 }
 
 func generateRTPInfo(
@@ -192,6 +329,18 @@ func generateRTPInfo(
 	return ri, true
 }
 
+// isPositionRange checks whether the start of a range is a playback position.
+func isPositionRange(ra *headers.Range) bool {
+	switch v := ra.Value.(type) {
+	case *headers.RangeNPT:
+		return !v.Now
+
+	case *headers.RangeSMPTE:
+		return true
+	}
+	return false
+}
+
 // ServerSessionState is a state of a ServerSession.
 type ServerSessionState int
 
@@ -239,6 +388,7 @@ type ServerSession struct {
 	tcpCallbackByChannel  map[int]readFunc
 	setuppedTransport     *Transport
 	setuppedStream        *ServerStream // read
+	setuppedStreamMutex   sync.RWMutex
 	setuppedPath          string
 	setuppedQuery         string
 	lastRequestTime       time.Time
@@ -249,6 +399,10 @@ type ServerSession struct {
 	writer                asyncProcessor
 	timeDecoder           *rtptime.GlobalDecoder
 	timeDecoder2          *rtptime.GlobalDecoder2
+	playStartPosition     time.Duration  // read
+	playStartTime         time.Time      // read
+	playScale             float64        // read
+	pausedRange           *headers.Range // read
 
 	// in
 	chHandleRequest chan sessionRequestReq
@@ -274,6 +428,8 @@ func (ss *ServerSession) initialize() {
 	ss.chRemoveConn = make(chan *ServerConn)
 	ss.chStartWriter = make(chan struct{})
 
+	ss.s.sessionCount.Add(1)
+
 	ss.s.wg.Add(1)
 	go ss.run()
 }
@@ -305,6 +461,8 @@ func (ss *ServerSession) SetuppedTransport() *Transport {
 
 // SetuppedStream returns the stream associated with the session.
 func (ss *ServerSession) SetuppedStream() *ServerStream {
+	ss.setuppedStreamMutex.RLock()
+	defer ss.setuppedStreamMutex.RUnlock()
 	return ss.setuppedStream
 }
 
@@ -332,6 +490,65 @@ func (ss *ServerSession) SetuppedMedias() []*description.Media {
 	return ret
 }
 
+// TrackCount returns the number of setupped tracks.
+func (ss *ServerSession) TrackCount() int {
+	return len(ss.setuppedMedias)
+}
+
+// LocalAddr returns the local addresses of the RTP and RTCP sockets
+// of the first setupped media.
+// With TCP, they are both the local address of the connection.
+func (ss *ServerSession) LocalAddr() (rtpAddr net.Addr, rtcpAddr net.Addr) {
+	if ss.setuppedTransport == nil {
+		return nil, nil
+	}
+
+	switch *ss.setuppedTransport {
+	case TransportUDP:
+		return ss.s.udpRTPListener.localAddr(), ss.s.udpRTCPListener.localAddr()
+
+	case TransportUDPMulticast:
+		mw := ss.setuppedStream.streamMedias[ss.setuppedMediasOrdered[0].media].multicastWriter
+		return mw.rtpl.localAddr(), mw.rtcpl.localAddr()
+
+	default: // TCP
+		addr := ss.tcpConnOrAuthor().NetConn().LocalAddr()
+		return addr, addr
+	}
+}
+
+// RemoteAddr returns the remote addresses of the RTP and RTCP sockets
+// of the first setupped media.
+// With TCP, they are both the remote address of the connection.
+func (ss *ServerSession) RemoteAddr() (rtpAddr net.Addr, rtcpAddr net.Addr) {
+	if ss.setuppedTransport == nil {
+		return nil, nil
+	}
+
+	switch *ss.setuppedTransport {
+	case TransportUDP:
+		sm := ss.setuppedMediasOrdered[0]
+		return sm.udpRTPWriteAddr, sm.udpRTCPWriteAddr
+
+	case TransportUDPMulticast:
+		mw := ss.setuppedStream.streamMedias[ss.setuppedMediasOrdered[0].media].multicastWriter
+		return mw.rtpAddr, mw.rtcpAddr
+
+	default: // TCP
+		addr := ss.tcpConnOrAuthor().NetConn().RemoteAddr()
+		return addr, addr
+	}
+}
+
+// tcpConnOrAuthor returns the connection that is carrying TCP packets,
+// or the connection that created the session if the session is not playing or recording yet.
+func (ss *ServerSession) tcpConnOrAuthor() *ServerConn {
+	if ss.tcpConn != nil {
+		return ss.tcpConn
+	}
+	return ss.author
+}
+
 // SetUserData sets some user data associated with the session.
 func (ss *ServerSession) SetUserData(v interface{}) {
 	ss.userData = v
@@ -428,6 +645,14 @@ func (ss *ServerSession) run() {
 
 	ss.s.closeSession(ss)
 
+	switch ss.state {
+	case ServerSessionStatePlay:
+		ss.s.playerCount.Add(-1)
+	case ServerSessionStateRecord:
+		ss.s.publisherCount.Add(-1)
+	}
+	ss.s.sessionCount.Add(-1)
+
 	if h, ok := ss.s.Handler.(ServerHandlerOnSessionClose); ok {
 		h.OnSessionClose(&ServerHandlerOnSessionCloseCtx{
 			Session: ss,
@@ -540,6 +765,39 @@ func (ss *ServerSession) runInner() error {
 	}
 }
 
+// currentPosition returns the playback position of a reading session.
+func (ss *ServerSession) currentPosition() time.Duration {
+	elapsed := ss.s.timeNow().Sub(ss.playStartTime)
+	pos := ss.playStartPosition + time.Duration(float64(elapsed)*ss.playScale)
+	if pos < 0 {
+		return 0
+	}
+	return pos
+}
+
+func (ss *ServerSession) updatePlayPosition(ra *headers.Range, scale *float64) {
+	var pos time.Duration
+
+	switch {
+	case ra != nil && isPositionRange(ra):
+		pos = ra.Start()
+
+	case ss.state == ServerSessionStatePlay:
+		pos = ss.currentPosition()
+
+	case ss.pausedRange != nil:
+		pos = ss.pausedRange.Start()
+	}
+
+	ss.playStartPosition = pos
+	ss.playStartTime = ss.s.timeNow()
+	ss.playScale = 1
+	if scale != nil {
+		ss.playScale = *scale
+	}
+	ss.pausedRange = nil
+}
+
 func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (*base.Response, error) {
 	if ss.tcpConn != nil && sc != ss.tcpConn {
 		return &base.Response{
@@ -560,7 +818,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 	switch req.Method {
 	case base.Options:
 		var methods []string
-		if _, ok := sc.s.Handler.(ServerHandlerOnDescribe); ok {
+		if handlerSupportsDescribe(sc.s.Handler) {
 			methods = append(methods, string(base.Describe))
 		}
 		if _, ok := sc.s.Handler.(ServerHandlerOnAnnounce); ok {
@@ -608,7 +866,8 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, liberrors.ErrServerContentTypeMissing{}
 		}
 
-		if ct[0] != "application/sdp" {
+		pct, err := base.ParseHeaderContentType(ct)
+		if err != nil || pct.MIMEType != base.ContentTypeSDP {
 			return &base.Response{
 				StatusCode: base.StatusBadRequest,
 			}, liberrors.ErrServerContentTypeUnsupported{CT: ct}
@@ -663,14 +922,14 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		}
 
 		var inTSH headers.Transports
-		err = inTSH.Unmarshal(req.Header["Transport"])
+		err = inTSH.Unmarshal(req.Header.GetAll("Transport"))
 		if err != nil {
 			return &base.Response{
 				StatusCode: base.StatusBadRequest,
 			}, liberrors.ErrServerTransportHeaderInvalid{Err: err}
 		}
 
-		inTH := findFirstSupportedTransportHeader(ss.s, inTSH)
+		inTH := ss.findFirstSupportedTransportHeader(inTSH)
 		if inTH == nil {
 			return &base.Response{
 				StatusCode: base.StatusUnsupportedTransport,
@@ -699,23 +958,17 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			query = ss.setuppedQuery
 		}
 
-		var transport Transport
+		transport := transportFromHeader(inTH)
 
-		if inTH.Protocol == headers.TransportProtocolUDP {
-			if inTH.Delivery != nil && *inTH.Delivery == headers.TransportDeliveryMulticast {
-				transport = TransportUDPMulticast
-			} else {
-				transport = TransportUDP
-
-				if inTH.ClientPorts == nil {
-					return &base.Response{
-						StatusCode: base.StatusBadRequest,
-					}, liberrors.ErrServerTransportHeaderNoClientPorts{}
-				}
+		switch transport {
+		case TransportUDP:
+			if inTH.ClientPorts == nil {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerTransportHeaderNoClientPorts{}
 			}
-		} else {
-			transport = TransportTCP
 
+		case TransportTCP:
 			if inTH.InterleavedIDs != nil {
 				if (inTH.InterleavedIDs[0] + 1) != inTH.InterleavedIDs[1] {
 					return &base.Response{
@@ -759,14 +1012,22 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}
 		}
 
-		res, stream, err := ss.s.Handler.(ServerHandlerOnSetup).OnSetup(&ServerHandlerOnSetupCtx{
+		if ss.s.MaxTrackCount != 0 && len(ss.setuppedMedias) >= ss.s.MaxTrackCount {
+			return &base.Response{
+				StatusCode: base.StatusNotEnoughBandwidth,
+			}, nil
+		}
+
+		ctx := &ServerHandlerOnSetupCtx{
 			Session:   ss,
 			Conn:      sc,
 			Request:   req,
 			Path:      path,
 			Query:     query,
 			Transport: transport,
-		})
+		}
+
+		res, stream, err := ss.s.Handler.(ServerHandlerOnSetup).OnSetup(ctx)
 
 		// workaround to prevent a bug in rtspclientsink
 		// that makes impossible for the client to receive the response
@@ -784,6 +1045,17 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			return res, err
 		}
 
+		if ctx.ForceTransport != nil && *ctx.ForceTransport != transport {
+			inTH, err = ss.forcedTransportHeader(inTSH, inTH, *ctx.ForceTransport)
+			if err != nil {
+				return &base.Response{
+					StatusCode: base.StatusUnsupportedTransport,
+				}, err
+			}
+
+			transport = *ctx.ForceTransport
+		}
+
 		var medi *description.Media
 		switch ss.state {
 		case ServerSessionStateInitial, ServerSessionStatePrePlay: // play
@@ -819,7 +1091,9 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			ss.state = ServerSessionStatePrePlay
 			ss.setuppedPath = path
 			ss.setuppedQuery = query
+			ss.setuppedStreamMutex.Lock()
 			ss.setuppedStream = stream
+			ss.setuppedStreamMutex.Unlock()
 		}
 
 		th := headers.Transport{}
@@ -916,6 +1190,39 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, liberrors.ErrServerPathHasChanged{Prev: ss.setuppedPath, Cur: path}
 		}
 
+		var ra *headers.Range
+		if v, ok := req.Header["Range"]; ok {
+			ra = &headers.Range{}
+			err = ra.Unmarshal(v)
+			if err != nil {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerRangeHeaderInvalid{Err: err}
+			}
+		}
+
+		var scale *float64
+		if v, ok := req.Header["Scale"]; ok {
+			if len(v) != 1 {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerScaleHeaderInvalid{Value: strings.Join(v, ", ")}
+			}
+
+			tmp, err2 := strconv.ParseFloat(strings.TrimSpace(v[0]), 64)
+			if err2 != nil || tmp == 0 {
+				return &base.Response{
+					StatusCode: base.StatusBadRequest,
+				}, liberrors.ErrServerScaleHeaderInvalid{Value: v[0]}
+			}
+			scale = &tmp
+		}
+
+		var resumeFrom *headers.Range
+		if ra == nil && ss.state == ServerSessionStatePrePlay {
+			resumeFrom = ss.pausedRange
+		}
+
 		// allocate writeBuffer before calling OnPlay().
 		// in this way it's possible to call ServerSession.WritePacket*()
 		// inside the callback.
@@ -925,11 +1232,14 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		}
 
 		res, err := sc.s.Handler.(ServerHandlerOnPlay).OnPlay(&ServerHandlerOnPlayCtx{
-			Session: ss,
-			Conn:    sc,
-			Request: req,
-			Path:    path,
-			Query:   query,
+			Session:    ss,
+			Conn:       sc,
+			Request:    req,
+			Path:       path,
+			Query:      query,
+			Range:      ra,
+			Scale:      scale,
+			ResumeFrom: resumeFrom,
 		})
 
 		if res.StatusCode != base.StatusOK {
@@ -939,11 +1249,16 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			return res, err
 		}
 
+		if ss.state != ServerSessionStatePlay || ra != nil || scale != nil {
+			ss.updatePlayPosition(ra, scale)
+		}
+
 		if ss.state == ServerSessionStatePlay {
 			return res, err
 		}
 
 		ss.state = ServerSessionStatePlay
+		ss.s.playerCount.Add(1)
 
 		v := ss.s.timeNow().Unix()
 		ss.udpLastPacketTime = &v
@@ -1009,6 +1324,16 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, liberrors.ErrServerPathHasChanged{Prev: ss.setuppedPath, Cur: path}
 		}
 
+		if ss.s.AllowedMediaTypes != nil {
+			for _, medi := range ss.announcedDesc.Medias {
+				if !isMediaTypeAllowed(ss.s.AllowedMediaTypes, medi.Type) {
+					return &base.Response{
+						StatusCode: base.StatusUnsupportedMediaType,
+					}, liberrors.ErrServerMediaTypeNotAllowed{MediaType: string(medi.Type)}
+				}
+			}
+		}
+
 		// allocate writeBuffer before calling OnRecord().
 		// in this way it's possible to call ServerSession.WritePacket*()
 		// inside the callback.
@@ -1023,6 +1348,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			Request: req,
 			Path:    path,
 			Query:   query,
+			Medias:  ss.announcedDesc.Medias,
 		})
 
 		if res.StatusCode != base.StatusOK {
@@ -1031,6 +1357,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		}
 
 		ss.state = ServerSessionStateRecord
+		ss.s.publisherCount.Add(1)
 
 		v := ss.s.timeNow().Unix()
 		ss.udpLastPacketTime = &v
@@ -1068,18 +1395,36 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}, err
 		}
 
+		var currentRange *headers.Range
+		switch ss.state {
+		case ServerSessionStatePlay:
+			currentRange = &headers.Range{
+				Value: &headers.RangeNPT{
+					Start: ss.currentPosition(),
+				},
+			}
+
+		case ServerSessionStatePrePlay:
+			currentRange = ss.pausedRange
+		}
+
 		res, err := ss.s.Handler.(ServerHandlerOnPause).OnPause(&ServerHandlerOnPauseCtx{
-			Session: ss,
-			Conn:    sc,
-			Request: req,
-			Path:    path,
-			Query:   query,
+			Session:      ss,
+			Conn:         sc,
+			Request:      req,
+			Path:         path,
+			Query:        query,
+			CurrentRange: currentRange,
 		})
 
 		if res.StatusCode != base.StatusOK {
 			return res, err
 		}
 
+		if ss.state == ServerSessionStatePlay {
+			ss.pausedRange = currentRange
+		}
+
 		if ss.setuppedStream != nil {
 			ss.setuppedStream.readerSetInactive(ss)
 		}
@@ -1096,6 +1441,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		switch ss.state {
 		case ServerSessionStatePlay:
 			ss.state = ServerSessionStatePrePlay
+			ss.s.playerCount.Add(-1)
 
 			switch *ss.setuppedTransport {
 			case TransportUDP:
@@ -1120,6 +1466,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 			}
 
 			ss.state = ServerSessionStatePreRecord
+			ss.s.publisherCount.Add(-1)
 		}
 
 		return res, err
@@ -1151,7 +1498,7 @@ func (ss *ServerSession) handleRequestInner(sc *ServerConn, req *base.Request) (
 		return &base.Response{
 			StatusCode: base.StatusOK,
 			Header: base.Header{
-				"Content-Type": base.HeaderValue{"text/parameters"},
+				"Content-Type": base.HeaderValue{base.ContentTypeParams},
 			},
 			Body: []byte{},
 		}, nil
@@ -1220,6 +1567,8 @@ func (ss *ServerSession) OnPacketRTP(medi *description.Media, forma format.Forma
 }
 
 // OnPacketRTCP sets the callback that is called when a RTCP packet is read.
+// It is called for RTCP packets sent by both publishers and readers, with any transport.
+// Sender reports of publishers can be relayed to readers with ServerStream.WritePacketRTCP().
 func (ss *ServerSession) OnPacketRTCP(medi *description.Media, cb OnPacketRTCPFunc) {
 	sm := ss.setuppedMedias[medi]
 	sm.onPacketRTCP = cb
@@ -1257,6 +1606,38 @@ func (ss *ServerSession) WritePacketRTCP(medi *description.Media, pkt rtcp.Packe
 	return ss.writePacketRTCP(medi, byts)
 }
 
+// WritePLI asks the client to send a key frame of a format through a RTCP Picture Loss Indication.
+// The client must have advertised the "nack pli" feedback capability for the format.
+func (ss *ServerSession) WritePLI(medi *description.Media, forma format.Format) error {
+	if !medi.HasRTCPFeedback(forma.PayloadType(), "nack pli") {
+		return liberrors.ErrServerRTCPFeedbackNotSupported{Feedback: "nack pli"}
+	}
+
+	sm := ss.setuppedMedias[medi]
+	sf, ok := sm.formats[forma.PayloadType()]
+	if !ok {
+		return liberrors.ErrServerSenderSSRCUnknown{}
+	}
+
+	return sf.writeKeyFrameRequest(false)
+}
+
+// WriteFIR asks the client to send a key frame of a format through a RTCP Full Intra Request.
+// The client must have advertised the "ccm fir" feedback capability for the format.
+func (ss *ServerSession) WriteFIR(medi *description.Media, forma format.Format) error {
+	if !medi.HasRTCPFeedback(forma.PayloadType(), "ccm fir") {
+		return liberrors.ErrServerRTCPFeedbackNotSupported{Feedback: "ccm fir"}
+	}
+
+	sm := ss.setuppedMedias[medi]
+	sf, ok := sm.formats[forma.PayloadType()]
+	if !ok {
+		return liberrors.ErrServerSenderSSRCUnknown{}
+	}
+
+	return sf.writeKeyFrameRequest(true)
+}
+
 // PacketPTS returns the PTS of an incoming RTP packet.
 // It is computed by decoding the packet timestamp and sychronizing it with other tracks.
 //
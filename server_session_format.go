@@ -1,6 +1,7 @@
 package gortsplib
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/pion/rtcp"
@@ -21,6 +22,7 @@ type serverSessionFormat struct {
 	udpReorderer    *rtpreorderer.Reorderer
 	tcpLossDetector *rtplossdetector.LossDetector
 	rtcpReceiver    *rtcpreceiver.RTCPReceiver
+	firSeqNum       uint32
 }
 
 func (sf *serverSessionFormat) start() {
@@ -90,3 +92,32 @@ func (sf *serverSessionFormat) readRTPTCP(pkt *rtp.Packet) {
 
 	sf.onPacketRTP(pkt)
 }
+
+// writeKeyFrameRequest asks the client to send a key frame,
+// through a RTCP Picture Loss Indication or a Full Intra Request.
+func (sf *serverSessionFormat) writeKeyFrameRequest(fir bool) error {
+	if sf.rtcpReceiver == nil {
+		return liberrors.ErrServerSenderSSRCUnknown{}
+	}
+
+	ssrc, ok := sf.rtcpReceiver.SenderSSRC()
+	if !ok {
+		return liberrors.ErrServerSenderSSRCUnknown{}
+	}
+
+	if fir {
+		return sf.sm.ss.WritePacketRTCP(sf.sm.media, &rtcp.FullIntraRequest{
+			SenderSSRC: sf.rtcpReceiver.ReceiverSSRC(),
+			MediaSSRC:  ssrc,
+			FIR: []rtcp.FIREntry{{
+				SSRC:           ssrc,
+				SequenceNumber: uint8(atomic.AddUint32(&sf.firSeqNum, 1)),
+			}},
+		})
+	}
+
+	return sf.sm.ss.WritePacketRTCP(sf.sm.media, &rtcp.PictureLossIndication{
+		SenderSSRC: sf.rtcpReceiver.ReceiverSSRC(),
+		MediaSSRC:  ssrc,
+	})
+}
@@ -171,6 +171,11 @@ func (sm *serverSessionMedia) readRTCPUDPPlay(payload []byte) {
 		return
 	}
 
+	if plen > sm.ss.s.MaxIncomingPacketSize {
+		sm.ss.onDecodeError(liberrors.ErrServerPacketTooLarge{L: plen, Max: sm.ss.s.MaxIncomingPacketSize})
+		return
+	}
+
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
 		sm.ss.onDecodeError(err)
@@ -195,6 +200,11 @@ func (sm *serverSessionMedia) readRTPUDPRecord(payload []byte) {
 		return
 	}
 
+	if plen > sm.ss.s.MaxIncomingPacketSize {
+		sm.ss.onDecodeError(liberrors.ErrServerPacketTooLarge{L: plen, Max: sm.ss.s.MaxIncomingPacketSize})
+		return
+	}
+
 	pkt := &rtp.Packet{}
 	err := pkt.Unmarshal(payload)
 	if err != nil {
@@ -224,6 +234,11 @@ func (sm *serverSessionMedia) readRTCPUDPRecord(payload []byte) {
 		return
 	}
 
+	if plen > sm.ss.s.MaxIncomingPacketSize {
+		sm.ss.onDecodeError(liberrors.ErrServerPacketTooLarge{L: plen, Max: sm.ss.s.MaxIncomingPacketSize})
+		return
+	}
+
 	packets, err := rtcp.Unmarshal(payload)
 	if err != nil {
 		sm.ss.onDecodeError(err)
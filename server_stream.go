@@ -42,6 +42,9 @@ type ServerStream struct {
 }
 
 // NewServerStream allocates a ServerStream.
+// Attributes of desc are copied into DESCRIBE responses, except the ones
+// related to control, timing, transport and encryption of the original stream
+// (i.e. control, range, rtcp, ssrc, crypto).
 func NewServerStream(s *Server, desc *description.Session) *ServerStream {
 	st := &ServerStream{
 		s:                    s,
@@ -66,11 +69,45 @@ func NewServerStream(s *Server, desc *description.Session) *ServerStream {
 }
 
 // Close closes a ServerStream.
+// Readers are notified with a RTCP BYE packet, then they are disconnected.
+// Close does not block: readers are disconnected in a separate routine,
+// once the RTCP BYE has been written or WriteTimeout has passed.
 func (st *ServerStream) Close() {
 	st.mutex.Lock()
+
+	if st.closed {
+		st.mutex.Unlock()
+		return
+	}
+
 	st.closed = true
+
+	var flushed []chan struct{}
+	for _, sm := range st.streamMedias {
+		flushed = append(flushed, sm.writeGoodbye()...)
+	}
+
 	st.mutex.Unlock()
 
+	go st.disconnectReaders(flushed)
+}
+
+func (st *ServerStream) disconnectReaders(flushed []chan struct{}) {
+	// give readers a chance to receive RTCP BYE before disconnecting them
+	if len(flushed) != 0 {
+		t := time.NewTimer(st.s.WriteTimeout)
+		defer t.Stop()
+
+	outer:
+		for _, done := range flushed {
+			select {
+			case <-done:
+			case <-t.C:
+				break outer
+			}
+		}
+	}
+
 	for ss := range st.readers {
 		ss.Close()
 	}
@@ -78,6 +115,12 @@ func (st *ServerStream) Close() {
 	for _, sm := range st.streamMedias {
 		sm.close()
 	}
+
+	if h, ok := st.s.Handler.(ServerHandlerOnStreamClose); ok {
+		h.OnStreamClose(&ServerHandlerOnStreamCloseCtx{
+			Stream: st,
+		})
+	}
 }
 
 // BytesSent returns the number of written bytes.
@@ -90,6 +133,18 @@ func (st *ServerStream) Description() *description.Session {
 	return st.desc
 }
 
+// ServerSideMedia returns the media that is sent to clients in place of the given one,
+// that contains the control attribute used by the server.
+// It returns false if the media does not belong to the stream.
+func (st *ServerStream) ServerSideMedia(original *description.Media) (*description.Media, bool) {
+	for i, medi := range st.desc.Medias {
+		if medi == original {
+			return serverSideMedia(medi, i), true
+		}
+	}
+	return nil, false
+}
+
 func (st *ServerStream) senderSSRC(medi *description.Media) (uint32, bool) {
 	st.mutex.Lock()
 	defer st.mutex.Unlock()
@@ -279,6 +334,32 @@ func (st *ServerStream) WritePacketRTPWithNTP(medi *description.Media, pkt *rtp.
 	return sf.writePacketRTP(byts, pkt, ntp)
 }
 
+func (st *ServerStream) writePacketRTPToReader(ss *ServerSession, medi *description.Media, byts []byte) error {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	if st.closed {
+		return liberrors.ErrServerStreamClosed{}
+	}
+
+	if _, ok := st.activeUnicastReaders[ss]; !ok {
+		return liberrors.ErrServerSessionNotReading{}
+	}
+
+	sm, ok := ss.setuppedMedias[medi]
+	if !ok {
+		return liberrors.ErrServerMediaNotFound{}
+	}
+
+	err := sm.writePacketRTP(byts)
+	if err != nil {
+		return err
+	}
+
+	atomic.AddUint64(st.bytesSent, uint64(len(byts)))
+	return nil
+}
+
 // WritePacketRTCP writes a RTCP packet to all the readers of the stream.
 func (st *ServerStream) WritePacketRTCP(medi *description.Media, pkt rtcp.Packet) error {
 	byts, err := pkt.Marshal()
@@ -32,6 +32,16 @@ func (sf *serverStreamFormat) initialize() {
 }
 
 func (sf *serverStreamFormat) writePacketRTP(byts []byte, pkt *rtp.Packet, ntp time.Time) error {
+	if sf.sm.st.s.UpdateStreamParams {
+		switch forma := sf.format.(type) {
+		case *format.H264:
+			forma.SafeSetParamsFromRTP(pkt)
+
+		case *format.H265:
+			forma.SafeSetParamsFromRTP(pkt)
+		}
+	}
+
 	sf.rtcpSender.ProcessPacket(pkt, ntp, sf.format.PTSEqualsDTS(pkt))
 
 	le := uint64(len(byts))
@@ -1,6 +1,8 @@
 package gortsplib
 
 import (
+	"github.com/pion/rtcp"
+
 	"github.com/voicecom/gortsplib/v4/pkg/description"
 )
 
@@ -37,6 +39,43 @@ func (sm *serverStreamMedia) close() {
 	}
 }
 
+// writeGoodbye sends a RTCP BYE packet to readers and returns
+// channels that are closed once the packet has been written.
+func (sm *serverStreamMedia) writeGoodbye() []chan struct{} {
+	var ssrcs []uint32
+	for _, sf := range sm.formats {
+		ssrc, ok := sf.rtcpSender.SenderSSRC()
+		if ok {
+			ssrcs = append(ssrcs, ssrc)
+		}
+	}
+
+	if ssrcs == nil {
+		return nil
+	}
+
+	byts, err := (&rtcp.Goodbye{Sources: ssrcs}).Marshal()
+	if err != nil {
+		return nil
+	}
+
+	sm.writePacketRTCP(byts) //nolint:errcheck
+
+	var flushed []chan struct{}
+
+	for r := range sm.st.activeUnicastReaders {
+		if _, ok := r.setuppedMedias[sm.media]; ok {
+			flushed = append(flushed, r.writer.flush())
+		}
+	}
+
+	if sm.multicastWriter != nil {
+		flushed = append(flushed, sm.multicastWriter.writer.flush())
+	}
+
+	return flushed
+}
+
 func (sm *serverStreamMedia) writePacketRTCP(byts []byte) error {
 	// send unicast
 	for r := range sm.st.activeUnicastReaders {
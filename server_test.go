@@ -1,8 +1,12 @@
 package gortsplib
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -96,6 +100,7 @@ type testServerHandler struct {
 	onGetParameter func(*ServerHandlerOnGetParameterCtx) (*base.Response, error)
 	onPacketLost   func(*ServerHandlerOnPacketLostCtx)
 	onDecodeError  func(*ServerHandlerOnDecodeErrorCtx)
+	onStreamClose  func(*ServerHandlerOnStreamCloseCtx)
 }
 
 func (sh *testServerHandler) OnConnOpen(ctx *ServerHandlerOnConnOpenCtx) {
@@ -190,6 +195,12 @@ func (sh *testServerHandler) OnDecodeError(ctx *ServerHandlerOnDecodeErrorCtx) {
 	}
 }
 
+func (sh *testServerHandler) OnStreamClose(ctx *ServerHandlerOnStreamCloseCtx) {
+	if sh.onStreamClose != nil {
+		sh.onStreamClose(ctx)
+	}
+}
+
 func TestServerClose(t *testing.T) {
 	s := &Server{
 		Handler:     &testServerHandler{},
@@ -202,6 +213,134 @@ func TestServerClose(t *testing.T) {
 	s.Close()
 }
 
+func TestServerListenAndServeRTSPS(t *testing.T) {
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "server.crt")
+	err := os.WriteFile(certFile, serverCert, 0o644)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(dir, "server.key")
+	err = os.WriteFile(keyFile, serverKey, 0o644)
+	require.NoError(t, err)
+
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	require.NoError(t, err)
+
+	for _, ca := range []string{"rtsps", "mtls"} {
+		t.Run(ca, func(t *testing.T) {
+			s := &Server{
+				Handler: &testServerHandler{},
+			}
+
+			done := make(chan error)
+			go func() {
+				if ca == "rtsps" {
+					done <- s.ListenAndServeRTSPS("localhost:8554", certFile, keyFile)
+				} else {
+					done <- s.ListenAndServeMTLS("localhost:8554", certFile, keyFile, certFile)
+				}
+			}()
+
+			dial := func(certs []tls.Certificate) (*base.Response, error) {
+				var nconn net.Conn
+				var err error
+
+				for i := 0; i < 50; i++ {
+					nconn, err = tls.Dial("tcp", "localhost:8554", &tls.Config{
+						InsecureSkipVerify: true,
+						Certificates:       certs,
+					})
+					if err == nil {
+						break
+					}
+					time.Sleep(20 * time.Millisecond)
+				}
+				if err != nil {
+					return nil, err
+				}
+				defer nconn.Close()
+
+				return writeReqReadRes(conn.NewConn(nconn), base.Request{
+					Method: base.Options,
+					URL:    mustParseURL("rtsps://localhost:8554/teststream"),
+					Header: base.Header{
+						"CSeq": base.HeaderValue{"1"},
+					},
+				})
+			}
+
+			if ca == "mtls" {
+				_, err = dial(nil)
+				require.Error(t, err)
+			}
+
+			res, err := dial([]tls.Certificate{cert})
+			require.NoError(t, err)
+			require.Equal(t, base.StatusOK, res.StatusCode)
+
+			s.Close()
+			<-done
+		})
+	}
+}
+
+func TestServerListenAndServeRTSPSErrors(t *testing.T) {
+	s := &Server{}
+	err := s.ListenAndServeRTSPS("localhost:8554", "/nonexisting.crt", "/nonexisting.key")
+	require.Error(t, err)
+
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "server.crt")
+	err = os.WriteFile(certFile, serverCert, 0o644)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(dir, "server.key")
+	err = os.WriteFile(keyFile, serverKey, 0o644)
+	require.NoError(t, err)
+
+	err = s.ListenAndServeMTLS("localhost:8554", certFile, keyFile, keyFile)
+	require.EqualError(t, err, "unable to parse CA certificate")
+}
+
+func TestServerStreamServerSideMedia(t *testing.T) {
+	s := &Server{
+		Handler:     &testServerHandler{},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	medi1 := &description.Media{
+		Type:    description.MediaTypeVideo,
+		Control: "streamid=5",
+		Formats: testH264Media.Formats,
+	}
+	medi2 := &description.Media{
+		Type:    description.MediaTypeAudio,
+		ID:      "audio",
+		Formats: testH264Media.Formats,
+	}
+
+	stream := NewServerStream(s, &description.Session{Medias: []*description.Media{medi1, medi2}})
+	defer stream.Close()
+
+	ssm, ok := stream.ServerSideMedia(medi2)
+	require.Equal(t, true, ok)
+	require.Equal(t, &description.Media{
+		Type:    description.MediaTypeAudio,
+		ID:      "audio",
+		Control: "trackID=1",
+		Formats: testH264Media.Formats,
+	}, ssm)
+
+	_, ok = stream.ServerSideMedia(&description.Media{})
+	require.Equal(t, false, ok)
+}
+
 func TestServerErrorInvalidUDPPorts(t *testing.T) {
 	t.Run("non consecutive", func(t *testing.T) {
 		s := &Server{
@@ -289,15 +428,26 @@ func TestServerCSeq(t *testing.T) {
 
 func TestServerErrorCSeqMissing(t *testing.T) {
 	nconnClosed := make(chan struct{})
+	var timeNowCalls int32
 
 	s := &Server{
 		Handler: &testServerHandler{
 			onConnClose: func(ctx *ServerHandlerOnConnCloseCtx) {
 				require.EqualError(t, ctx.Error, "CSeq is missing")
+				require.NotZero(t, ctx.BytesReceived)
+				require.NotZero(t, ctx.BytesSent)
+				require.Equal(t, 5*time.Second, ctx.Duration)
 				close(nconnClosed)
 			},
 		},
 		RTSPAddress: "localhost:8554",
+		timeNow: func() time.Time {
+			t0 := time.Date(2014, 5, 7, 15, 0, 0, 0, time.UTC)
+			if atomic.AddInt32(&timeNowCalls, 1) > 1 {
+				return t0.Add(5 * time.Second)
+			}
+			return t0
+		},
 	}
 	err := s.Start()
 	require.NoError(t, err)
@@ -901,6 +1051,92 @@ func TestServerSessionClose(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestServerSessionAddr(t *testing.T) {
+	for _, transport := range []string{
+		"udp",
+		"tcp",
+	} {
+		t.Run(transport, func(t *testing.T) {
+			var stream *ServerStream
+			var localRTP, localRTCP, remoteRTP, remoteRTCP net.Addr
+
+			s := &Server{
+				Handler: &testServerHandler{
+					onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, stream, nil
+					},
+					onPlay: func(ctx *ServerHandlerOnPlayCtx) (*base.Response, error) {
+						localRTP, localRTCP = ctx.Session.LocalAddr()
+						remoteRTP, remoteRTCP = ctx.Session.RemoteAddr()
+
+						return &base.Response{
+							StatusCode: base.StatusOK,
+						}, nil
+					},
+				},
+				RTSPAddress: "localhost:8554",
+			}
+
+			if transport == "udp" {
+				s.UDPRTPAddress = "127.0.0.1:8000"
+				s.UDPRTCPAddress = "127.0.0.1:8001"
+			}
+
+			err := s.Start()
+			require.NoError(t, err)
+			defer s.Close()
+
+			stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+			defer stream.Close()
+
+			nconn, err := net.Dial("tcp", "localhost:8554")
+			require.NoError(t, err)
+			defer nconn.Close()
+			conn := conn.NewConn(nconn)
+
+			desc := doDescribe(t, conn)
+
+			inTH := &headers.Transport{
+				Delivery: deliveryPtr(headers.TransportDeliveryUnicast),
+				Mode:     transportModePtr(headers.TransportModePlay),
+			}
+
+			if transport == "udp" {
+				inTH.Protocol = headers.TransportProtocolUDP
+				inTH.ClientPorts = &[2]int{35466, 35467}
+			} else {
+				inTH.Protocol = headers.TransportProtocolTCP
+				inTH.InterleavedIDs = &[2]int{0, 1}
+			}
+
+			res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+			session := readSession(t, res)
+
+			doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+			if transport == "udp" {
+				require.Equal(t, "127.0.0.1:8000", localRTP.String())
+				require.Equal(t, "127.0.0.1:8001", localRTCP.String())
+				require.Equal(t, "127.0.0.1:35466", remoteRTP.String())
+				require.Equal(t, "127.0.0.1:35467", remoteRTCP.String())
+			} else {
+				require.Equal(t, nconn.RemoteAddr(), localRTP)
+				require.Equal(t, nconn.RemoteAddr(), localRTCP)
+				require.Equal(t, nconn.LocalAddr(), remoteRTP)
+				require.Equal(t, nconn.LocalAddr(), remoteRTCP)
+			}
+		})
+	}
+}
+
 func TestServerSessionAutoClose(t *testing.T) {
 	for _, ca := range []string{
 		"200", "400",
@@ -1034,6 +1270,160 @@ func TestServerSessionTeardown(t *testing.T) {
 	require.Equal(t, base.StatusOK, res.StatusCode)
 }
 
+func TestServerSessionHeaderWithParameters(t *testing.T) {
+	var stream *ServerStream
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	defer nconn.Close()
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	// some clients send back the Session header with its parameters
+	v := uint(60)
+
+	res, err = writeReqReadRes(conn, base.Request{
+		Method: base.Options,
+		URL:    mustParseURL("rtsp://localhost:8554/"),
+		Header: base.Header{
+			"CSeq": base.HeaderValue{"3"},
+			"Session": headers.Session{
+				Session: session,
+				Timeout: &v,
+			}.Marshal(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, base.StatusOK, res.StatusCode)
+}
+
+func TestServerCounts(t *testing.T) {
+	var stream *ServerStream
+	sessionClosed := make(chan struct{})
+	connClosed := make(chan struct{})
+
+	s := &Server{
+		Handler: &testServerHandler{
+			onConnClose: func(_ *ServerHandlerOnConnCloseCtx) {
+				close(connClosed)
+			},
+			onSessionClose: func(_ *ServerHandlerOnSessionCloseCtx) {
+				close(sessionClosed)
+			},
+			onDescribe: func(_ *ServerHandlerOnDescribeCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onSetup: func(_ *ServerHandlerOnSetupCtx) (*base.Response, *ServerStream, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, stream, nil
+			},
+			onPlay: func(_ *ServerHandlerOnPlayCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+			onPause: func(_ *ServerHandlerOnPauseCtx) (*base.Response, error) {
+				return &base.Response{
+					StatusCode: base.StatusOK,
+				}, nil
+			},
+		},
+		RTSPAddress: "localhost:8554",
+	}
+
+	require.Equal(t, 0, s.ConnectionCount())
+	require.Equal(t, 0, s.SessionCount())
+
+	err := s.Start()
+	require.NoError(t, err)
+	defer s.Close()
+
+	stream = NewServerStream(s, &description.Session{Medias: []*description.Media{testH264Media}})
+	defer stream.Close()
+
+	nconn, err := net.Dial("tcp", "localhost:8554")
+	require.NoError(t, err)
+	conn := conn.NewConn(nconn)
+
+	desc := doDescribe(t, conn)
+
+	require.Equal(t, 1, s.ConnectionCount())
+	require.Equal(t, 0, s.SessionCount())
+
+	inTH := &headers.Transport{
+		Protocol:       headers.TransportProtocolTCP,
+		Delivery:       deliveryPtr(headers.TransportDeliveryUnicast),
+		Mode:           transportModePtr(headers.TransportModePlay),
+		InterleavedIDs: &[2]int{0, 1},
+	}
+
+	res, _ := doSetup(t, conn, mediaURL(t, desc.BaseURL, desc.Medias[0]).String(), inTH, "")
+
+	session := readSession(t, res)
+
+	require.Equal(t, 1, s.SessionCount())
+	require.Equal(t, 0, s.PlayerCount())
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	require.Equal(t, 1, s.PlayerCount())
+	require.Equal(t, 0, s.PublisherCount())
+
+	doPause(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	require.Equal(t, 0, s.PlayerCount())
+
+	doPlay(t, conn, "rtsp://localhost:8554/teststream", session)
+
+	require.Equal(t, 1, s.PlayerCount())
+
+	nconn.Close()
+	<-sessionClosed
+	<-connClosed
+
+	require.Equal(t, 0, s.ConnectionCount())
+	require.Equal(t, 0, s.SessionCount())
+	require.Equal(t, 0, s.PlayerCount())
+}
+
 func TestServerAuth(t *testing.T) {
 	nonce, err := auth.GenerateNonce()
 	require.NoError(t, err)
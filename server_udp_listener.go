@@ -117,6 +117,10 @@ func (u *serverUDPListener) ip() net.IP {
 	return u.listenIP
 }
 
+func (u *serverUDPListener) localAddr() net.Addr {
+	return u.pc.LocalAddr()
+}
+
 func (u *serverUDPListener) port() int {
 	return u.pc.LocalAddr().(*net.UDPAddr).Port
 }